@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 
+	"app_server/domain/aijob"
+	"app_server/domain/msgevent"
+	"app_server/domain/report"
+	"app_server/domain/sessiontitle"
+	"app_server/domain/summary"
+	"app_server/domain/trash"
 	"app_server/http/docs"
 	"app_server/http/file"
+	"app_server/model"
 	"app_server/pkg/cbind"
 	"app_server/pkg/cfg"
 	"app_server/pkg/db"
@@ -18,16 +26,24 @@ import (
 	"app_server/service/chat"
 	"app_server/service/config"
 	"app_server/service/ctx"
+	"app_server/service/idempotency"
+	jobsvc "app_server/service/job"
 	"app_server/service/message"
 	"app_server/service/profile"
 	"app_server/service/translate"
+	trashsvc "app_server/service/trash"
 	"app_server/service/user"
 
 	"app_server/proto/chat/chatconnect"
 	"app_server/proto/config/configconnect"
+	jobpb "app_server/proto/job"
+	"app_server/proto/job/jobconnect"
+	messagepb "app_server/proto/message"
 	"app_server/proto/message/messageconnect"
 	"app_server/proto/profile/profileconnect"
+	translatepb "app_server/proto/translate"
 	"app_server/proto/translate/translateconnect"
+	"app_server/proto/trash/trashconnect"
 	"app_server/proto/user/userconnect"
 
 	connect "connectrpc.com/connect"
@@ -36,6 +52,7 @@ import (
 )
 
 func main() {
+	flag.Parse()
 	cfg.Init(*cfgFile)
 	lo.Must0(db.Init(cfg.Viper().GetString("db.dsn"), cfg.Viper().GetBool("db.debug")))
 	lo.Must0(ossc.Init(ossc.Cfg{
@@ -46,7 +63,24 @@ func main() {
 		UserFileBucket:  cfg.Viper().GetString("aliyun.oss.user_file_bucket"),
 	}))
 	openaic.Init(cfg.UnmarshalKey[openaic.Config]("ai.volces"))
+	summary.Init(cfg.UnmarshalKey[summary.Config]("ai.summary"))
 	jwt.Init([]byte(cfg.Viper().GetString("jwt.secret")))
+	auth.InitAdmins(cfg.UnmarshalKey[[]uint]("admin.user_ids"))
+	trash.Init(cfg.UnmarshalKey[trash.Config]("trash"))
+	trash.StartPurgeJob(context.Background())
+	msgevent.Init(context.Background(), cfg.UnmarshalKey[msgevent.Config]("message_event"))
+	idempotency.Init(cfg.UnmarshalKey[idempotency.Config]("idempotency"))
+	idempotency.StartCleanupJob(context.Background())
+	report.Init(cfg.UnmarshalKey[report.Config]("session_report"))
+	report.StartScheduleJob(context.Background())
+	sessiontitle.Init(cfg.UnmarshalKey[sessiontitle.Config]("session_title"))
+	sessiontitle.StartRefreshJob(context.Background())
+	message.StartImagePreviewCleanupJob(context.Background())
+	aijob.Init(cfg.UnmarshalKey[aijob.Config]("ai_job"))
+	aijob.Register(model.AIJobTypeConsult, jobsvc.UnaryHandler((&message.ChatMessageService{}).SendConsultMessage))
+	aijob.Register(model.AIJobTypeTranslate, jobsvc.UnaryHandler((&translate.TranslateService{}).TranslateV2))
+	aijob.Register(model.AIJobTypeParseImage, jobsvc.UnaryHandler((&message.ChatMessageService{}).ParseImageMessages))
+	aijob.StartWorkers(context.Background())
 	log.Fatal(route().Run(lo.Ternary(*port != "", fmt.Sprintf(":%s", *port), cfg.Viper().GetString("server.address"))))
 }
 
@@ -57,7 +91,7 @@ func route() *gin.Engine {
 	root.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, connect-protocol-version, connect-timeout-ms, X-App-Platform, X-App-Env, X-App-Version")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, connect-protocol-version, connect-timeout-ms, X-App-Platform, X-App-Env, X-App-Version, Idempotency-Key")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -71,6 +105,7 @@ func route() *gin.Engine {
 	fileGroup := root.Group("/file")
 	fileGroup.POST("/wx_upload", file.WxFileUpload)
 	fileGroup.POST("/upload", file.WxFileUpload)
+	fileGroup.POST("/voice_message", file.VoiceMessageUpload)
 
 	docs.Register(root)
 
@@ -83,8 +118,14 @@ func route() *gin.Engine {
 	// 注册新的统一消息服务
 	binder.Bind(messageconnect.NewChatMessageServiceHandler(&message.ChatMessageService{},
 		connect.WithInterceptors(
-			connect.UnaryInterceptorFunc(auth.AuthInterceptor),
+			auth.NewInterceptor(),
 			connect.UnaryInterceptorFunc(ctx.CtxInterceptor),
+			idempotency.NewInterceptor(
+				idempotency.For[messagepb.CreateChatMessageResponse](messageconnect.ChatMessageServiceCreateChatMessageProcedure),
+				idempotency.For[messagepb.SendConsultMessageResponse](messageconnect.ChatMessageServiceSendConsultMessageProcedure),
+				idempotency.For[messagepb.SaveSuggestedReplyResponse](messageconnect.ChatMessageServiceSaveSuggestedReplyProcedure),
+				idempotency.For[messagepb.ParseImageMessagesResponse](messageconnect.ChatMessageServiceParseImageMessagesBatchProcedure),
+			),
 		),
 	))
 	binder.Bind(chatconnect.NewChatServiceHandler(&chat.ChatService{},
@@ -95,8 +136,11 @@ func route() *gin.Engine {
 	))
 	binder.Bind(translateconnect.NewTranslateServiceHandler(&translate.TranslateService{},
 		connect.WithInterceptors(
-			connect.UnaryInterceptorFunc(auth.AuthInterceptor),
+			auth.NewInterceptor(),
 			connect.UnaryInterceptorFunc(ctx.CtxInterceptor),
+			idempotency.NewInterceptor(
+				idempotency.For[translatepb.TranslateV2Response](translateconnect.TranslateServiceTranslateV2Procedure),
+			),
 		),
 	))
 	binder.Bind(profileconnect.NewProfileServiceHandler(&profile.ProfileService{},
@@ -106,6 +150,22 @@ func route() *gin.Engine {
 		),
 	))
 
+	binder.Bind(trashconnect.NewTrashServiceHandler(&trashsvc.TrashService{},
+		connect.WithInterceptors(
+			connect.UnaryInterceptorFunc(auth.AuthInterceptor),
+			connect.UnaryInterceptorFunc(ctx.CtxInterceptor),
+		),
+	))
+	binder.Bind(jobconnect.NewJobServiceHandler(&jobsvc.JobService{},
+		connect.WithInterceptors(
+			connect.UnaryInterceptorFunc(auth.AuthInterceptor),
+			connect.UnaryInterceptorFunc(ctx.CtxInterceptor),
+			idempotency.NewInterceptor(
+				idempotency.For[jobpb.SubmitJobResponse](jobconnect.JobServiceSubmitJobProcedure),
+			),
+		),
+	))
+
 	root.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
 
 	return root
@@ -115,7 +175,3 @@ var (
 	cfgFile = flag.String("c", "config.yaml", "config file")
 	port    = flag.String("p", "", "port")
 )
-
-func init() {
-	flag.Parse()
-}
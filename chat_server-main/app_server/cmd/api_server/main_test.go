@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app_server/pkg/jwt"
+	translatepb "app_server/proto/translate"
+	"app_server/proto/translate/translateconnect"
+
+	connect "connectrpc.com/connect"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTranslateSessionAuth 测试流式接口经过路由上的拦截器完成鉴权
+func TestTranslateSessionAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt.Init([]byte("test-secret"))
+	server := httptest.NewServer(route())
+	defer server.Close()
+	client := translateconnect.NewTranslateServiceClient(server.Client(), server.URL)
+
+	token, err := jwt.Get().GenerateToken("1", time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  connect.Code
+	}{
+		{name: "no_token", token: "", want: connect.CodeUnauthenticated},
+		{name: "invalid_token", token: "invalid", want: connect.CodeUnauthenticated},
+		// 鉴权通过后进入接口，缺少会话 id 在查询数据库之前返回
+		{name: "valid_token", token: token, want: connect.CodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := connect.NewRequest(&translatepb.TranslateSessionRequest{})
+			if tt.token != "" {
+				req.Header().Set("Authorization", "Bearer "+tt.token)
+			}
+			stream, err := client.TranslateSession(context.Background(), req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer stream.Close()
+
+			for stream.Receive() {
+			}
+			assert.Equal(t, tt.want, connect.CodeOf(stream.Err()))
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package aijob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"app_server/model"
+	"app_server/pkg/db"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultMaxPerUser   = 2
+	defaultMaxPending   = 20
+	defaultMaxAttempts  = 3
+	defaultTimeout      = 5 * time.Minute
+	defaultPollInterval = time.Second
+	defaultRetention    = 7 * 24 * time.Hour
+
+	baseBackoff         = 5 * time.Second
+	maxBackoff          = 5 * time.Minute
+	lockGrace           = time.Minute // 执行超时后再等待多久视为执行者已退出
+	cancelCheckInterval = 2 * time.Second
+	claimBatchSize      = 50
+)
+
+var (
+	ErrNotFound    = errors.New("job not found")
+	ErrTooManyJobs = errors.New("too many unfinished jobs")
+)
+
+var conf Config
+
+type Config struct {
+	Workers      int           `mapstructure:"workers"`       // 每个实例同时执行的任务数
+	MaxPerUser   int           `mapstructure:"max_per_user"`  // 每个用户同时执行的任务数
+	MaxPending   int           `mapstructure:"max_pending"`   // 每个用户未结束的任务数
+	MaxAttempts  int           `mapstructure:"max_attempts"`  // 失败后最多执行的次数
+	Timeout      time.Duration `mapstructure:"timeout"`       // 每次执行的超时时间
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 查询待执行任务的间隔
+	Retention    time.Duration `mapstructure:"retention"`     // 已结束的任务保留时长
+}
+
+func Init(cfg Config) {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.MaxPerUser <= 0 {
+		cfg.MaxPerUser = defaultMaxPerUser
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = defaultMaxPending
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultRetention
+	}
+	conf = cfg
+}
+
+// Handler 执行一种任务，payload 和返回值为 proto 编码的请求和响应
+type Handler func(ctx context.Context, userID uint, payload []byte) ([]byte, error)
+
+var handlers = make(map[string]Handler)
+
+// Register 注册任务类型的执行函数，需要在 StartWorkers 之前调用
+func Register(jobType string, handler Handler) {
+	handlers[jobType] = handler
+}
+
+// permanentError 重试也不会成功的错误，例如参数错误
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记错误不需要重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// 唤醒调度，有新任务或空闲的执行者时不必等到下次轮询
+var wake = make(chan struct{}, 1)
+
+func notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// 本实例正在执行的任务，取消任务时中断执行
+var (
+	runningMu sync.Mutex
+	running   = make(map[uint]context.CancelFunc)
+)
+
+// Submit 提交任务，用户未结束的任务过多时返回 ErrTooManyJobs
+func Submit(ctx context.Context, userID uint, jobType string, payload []byte) (model.AIJob, error) {
+	if _, ok := handlers[jobType]; !ok {
+		return model.AIJob{}, fmt.Errorf("unknown job type: %s", jobType)
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	var unfinished int64
+	if err := database.Model(&model.AIJob{}).
+		Where("user_id = ? AND status IN ?", userID, []string{model.AIJobStatusPending, model.AIJobStatusRunning}).
+		Count(&unfinished).Error; err != nil {
+		return model.AIJob{}, err
+	}
+	if unfinished >= int64(conf.MaxPending) {
+		return model.AIJob{}, ErrTooManyJobs
+	}
+
+	job := model.AIJob{
+		UserID:      userID,
+		Type:        jobType,
+		Status:      model.AIJobStatusPending,
+		Payload:     payload,
+		MaxAttempts: conf.MaxAttempts,
+		NextRunAt:   time.Now(),
+	}
+	if err := database.Create(&job).Error; err != nil {
+		return model.AIJob{}, err
+	}
+	notify()
+	return job, nil
+}
+
+// Get 查询用户的任务
+func Get(ctx context.Context, userID, id uint) (model.AIJob, error) {
+	var job model.AIJob
+	err := db.GetDB().WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return job, ErrNotFound
+	}
+	return job, err
+}
+
+// Cancel 取消等待中或执行中的任务，执行中的任务会被中断，已结束的任务不变
+func Cancel(ctx context.Context, userID, id uint) (model.AIJob, error) {
+	if err := db.GetDB().WithContext(ctx).Model(&model.AIJob{}).
+		Where("id = ? AND user_id = ? AND status IN ?", id, userID, []string{model.AIJobStatusPending, model.AIJobStatusRunning}).
+		Updates(map[string]any{
+			"status":       model.AIJobStatusCanceled,
+			"locked_until": nil,
+			"finished_at":  time.Now(),
+		}).Error; err != nil {
+		return model.AIJob{}, err
+	}
+
+	// 在其他实例执行的任务由执行者轮询状态后中断
+	runningMu.Lock()
+	if cancel, ok := running[id]; ok {
+		cancel()
+	}
+	runningMu.Unlock()
+
+	return Get(ctx, userID, id)
+}
+
+// backoff 第 attempt 次执行失败后等待多久重试，每次翻倍
+func backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// shouldRetry 失败的任务是否重试
+func shouldRetry(attempts, maxAttempts int, err error) bool {
+	var permanent permanentError
+	return !errors.As(err, &permanent) && attempts < maxAttempts
+}
+
+// StartWorkers 在后台执行任务并定期删除过期的任务，ctx 取消后不再执行新的任务
+// 多个实例可以同时执行，每个任务只会被一个实例领取；每个用户同时执行的任务数在领取时检查，多个实例同时领取时可能略微超出
+func StartWorkers(ctx context.Context) {
+	go func() {
+		slots := make(chan struct{}, conf.Workers)
+		ticker := time.NewTicker(conf.PollInterval)
+		defer ticker.Stop()
+		for {
+			if err := recoverExpired(ctx); err != nil {
+				slog.Error("recover expired ai jobs error", "error", err)
+			}
+			if err := dispatch(ctx, slots); err != nil {
+				slog.Error("dispatch ai jobs error", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := db.GetDB().WithContext(ctx).Where("finished_at < ?", time.Now().Add(-conf.Retention)).
+				Delete(&model.AIJob{}).Error; err != nil {
+				slog.Error("cleanup ai jobs error", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// dispatch 领取到期的任务交给空闲的执行者，跳过同时执行的任务已达上限的用户
+func dispatch(ctx context.Context, slots chan struct{}) error {
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return nil
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	var candidates []model.AIJob
+	if err := database.Select("id", "user_id").
+		Where("status = ? AND next_run_at <= ?", model.AIJobStatusPending, time.Now()).
+		Order("id ASC").
+		Limit(claimBatchSize).
+		Find(&candidates).Error; err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	type userCount struct {
+		UserID uint
+		Count  int
+	}
+	var counts []userCount
+	if err := database.Model(&model.AIJob{}).
+		Select("user_id, COUNT(*) AS count").
+		Where("status = ? AND user_id IN ?", model.AIJobStatusRunning, lo.Uniq(lo.Map(candidates, func(job model.AIJob, _ int) uint { return job.UserID }))).
+		Group("user_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+	runningCount := lo.SliceToMap(counts, func(c userCount) (uint, int) { return c.UserID, c.Count })
+
+	for _, candidate := range candidates {
+		if free == 0 {
+			break
+		}
+		if runningCount[candidate.UserID] >= conf.MaxPerUser {
+			continue
+		}
+		job, ok, err := claim(ctx, candidate.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		runningCount[job.UserID]++
+		free--
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				notify()
+			}()
+			run(ctx, job)
+		}()
+	}
+	return nil
+}
+
+// claim 领取任务，已被其他实例领取或取消时返回 false
+func claim(ctx context.Context, id uint) (model.AIJob, bool, error) {
+	database := db.GetDB().WithContext(ctx)
+	now := time.Now()
+	result := database.Model(&model.AIJob{}).
+		Where("id = ? AND status = ?", id, model.AIJobStatusPending).
+		Updates(map[string]any{
+			"status":       model.AIJobStatusRunning,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"started_at":   now,
+			"locked_until": now.Add(conf.Timeout + lockGrace),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return model.AIJob{}, false, result.Error
+	}
+
+	var job model.AIJob
+	if err := database.Where("id = ?", id).First(&job).Error; err != nil {
+		return job, false, err
+	}
+	return job, true, nil
+}
+
+// run 执行一次任务并保存结果
+func run(ctx context.Context, job model.AIJob) {
+	jobCtx, cancel := context.WithTimeout(ctx, conf.Timeout)
+	defer cancel()
+
+	runningMu.Lock()
+	running[job.ID] = cancel
+	runningMu.Unlock()
+	defer func() {
+		runningMu.Lock()
+		delete(running, job.ID)
+		runningMu.Unlock()
+	}()
+
+	go watchCancel(jobCtx, job.ID, cancel)
+
+	result, err := execute(jobCtx, job)
+	if errors.Is(jobCtx.Err(), context.Canceled) && ctx.Err() == nil {
+		slog.Info("ai job canceled", "jobID", job.ID)
+		return
+	}
+	// 任务 ctx 可能已超时，保存结果时需要脱离
+	if err := finish(context.WithoutCancel(ctx), job, result, err); err != nil {
+		slog.Error("save ai job result error", "error", err, "jobID", job.ID)
+	}
+}
+
+func execute(ctx context.Context, job model.AIJob) (result []byte, err error) {
+	handler, ok := handlers[job.Type]
+	if !ok {
+		return nil, Permanent(fmt.Errorf("unknown job type: %s", job.Type))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = Permanent(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	return handler(ctx, job.UserID, job.Payload)
+}
+
+// watchCancel 定期检查任务状态，任务在其他实例被取消时中断执行
+func watchCancel(ctx context.Context, id uint, cancel context.CancelFunc) {
+	ticker := time.NewTicker(cancelCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var statuses []string
+		if err := db.GetDB().WithContext(ctx).Model(&model.AIJob{}).
+			Where("id = ?", id).
+			Pluck("status", &statuses).Error; err != nil {
+			continue
+		}
+		if lo.FirstOrEmpty(statuses) != model.AIJobStatusRunning {
+			cancel()
+			return
+		}
+	}
+}
+
+// finish 保存执行结果，失败时按退避时间重新等待执行；任务已被取消时不更新
+func finish(ctx context.Context, job model.AIJob, result []byte, err error) error {
+	now := time.Now()
+	updates := map[string]any{
+		"locked_until": nil,
+	}
+	switch {
+	case err == nil:
+		updates["status"] = model.AIJobStatusSucceeded
+		updates["result"] = result
+		updates["error"] = ""
+		updates["finished_at"] = now
+	case shouldRetry(job.Attempts, job.MaxAttempts, err):
+		slog.Warn("ai job failed, will retry", "error", err, "jobID", job.ID, "attempts", job.Attempts)
+		updates["status"] = model.AIJobStatusPending
+		updates["error"] = err.Error()
+		updates["next_run_at"] = now.Add(backoff(job.Attempts))
+	default:
+		slog.Error("ai job failed", "error", err, "jobID", job.ID, "attempts", job.Attempts)
+		updates["status"] = model.AIJobStatusFailed
+		updates["error"] = err.Error()
+		updates["finished_at"] = now
+	}
+	return db.GetDB().WithContext(ctx).Model(&model.AIJob{}).
+		Where("id = ? AND status = ?", job.ID, model.AIJobStatusRunning).
+		Updates(updates).Error
+}
+
+// recoverExpired 执行者退出后遗留的任务，还有重试次数的重新等待执行，否则标记为失败
+func recoverExpired(ctx context.Context) error {
+	database := db.GetDB().WithContext(ctx)
+	now := time.Now()
+	if err := database.Model(&model.AIJob{}).
+		Where("status = ? AND locked_until < ? AND attempts < max_attempts", model.AIJobStatusRunning, now).
+		Updates(map[string]any{
+			"status":       model.AIJobStatusPending,
+			"error":        "worker exited",
+			"locked_until": nil,
+			"next_run_at":  now,
+		}).Error; err != nil {
+		return err
+	}
+	return database.Model(&model.AIJob{}).
+		Where("status = ? AND locked_until < ?", model.AIJobStatusRunning, now).
+		Updates(map[string]any{
+			"status":       model.AIJobStatusFailed,
+			"error":        "worker exited",
+			"locked_until": nil,
+			"finished_at":  now,
+		}).Error
+}
@@ -0,0 +1,29 @@
+package aijob
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, backoff(1))
+	assert.Equal(t, 10*time.Second, backoff(2))
+	assert.Equal(t, 20*time.Second, backoff(3))
+	assert.Equal(t, maxBackoff, backoff(10))
+	assert.Equal(t, maxBackoff, backoff(100))
+}
+
+func TestShouldRetry(t *testing.T) {
+	err := errors.New("upstream timeout")
+	assert.True(t, shouldRetry(1, 3, err))
+	assert.True(t, shouldRetry(2, 3, err))
+	assert.False(t, shouldRetry(3, 3, err))
+
+	permanent := fmt.Errorf("call: %w", Permanent(errors.New("invalid argument")))
+	assert.False(t, shouldRetry(1, 3, permanent))
+	assert.Nil(t, Permanent(nil))
+}
@@ -0,0 +1,153 @@
+package attachment
+
+import (
+	"errors"
+
+	"app_server/model"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// MaxPerMessage 单条消息最多的附件数
+const MaxPerMessage = 9
+
+var ErrFileNotFound = errors.New("attachment file not found")
+
+// Attach 将文件作为附件添加到每条消息，临时上传的文件改为聊天图片用途，被消息引用期间不会过期
+func Attach(tx *gorm.DB, userID uint, messageIDs, fileIDs []uint) error {
+	fileIDs = lo.Uniq(fileIDs)
+	if len(messageIDs) == 0 || len(fileIDs) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := tx.Model(&model.UserFile{}).
+		Where("id IN ? AND user_id = ? AND status = ?", fileIDs, userID, model.FileStatusNormal).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(fileIDs) {
+		return ErrFileNotFound
+	}
+	// 头像本身就不会过期，聊天图片和语音保留原来的用途
+	if err := tx.Model(&model.UserFile{}).
+		Where("id IN ? AND usage_type NOT IN ?", fileIDs, []string{model.UsageTypeAvatar, model.UsageTypeChatAudio}).
+		Update("usage_type", model.UsageTypeChatImage).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&model.UserFile{}).
+		Where("id IN ? AND usage_type <> ?", fileIDs, model.UsageTypeAvatar).
+		Update("expires_at", nil).Error; err != nil {
+		return err
+	}
+
+	attachments := make([]model.ChatMessageAttachment, 0, len(messageIDs)*len(fileIDs))
+	for _, messageID := range messageIDs {
+		for i, fileID := range fileIDs {
+			attachments = append(attachments, model.ChatMessageAttachment{
+				UserID:    userID,
+				MessageID: messageID,
+				FileID:    fileID,
+				Sort:      i,
+			})
+		}
+	}
+	return tx.Create(&attachments).Error
+}
+
+// List 查询消息的附件，返回 messageID -> 按顺序排列的文件，已删除的文件不返回
+func List(tx *gorm.DB, userID uint, messageIDs []uint) (map[uint][]model.UserFile, error) {
+	result := make(map[uint][]model.UserFile)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	var attachments []model.ChatMessageAttachment
+	if err := tx.Where("user_id = ? AND message_id IN ?", userID, messageIDs).
+		Order("message_id, sort").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return result, nil
+	}
+
+	var files []model.UserFile
+	if err := tx.Where("id IN ? AND status = ?", lo.Uniq(lo.Map(attachments, func(a model.ChatMessageAttachment, _ int) uint {
+		return a.FileID
+	})), model.FileStatusNormal).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	fileMap := lo.KeyBy(files, func(file model.UserFile) uint { return file.ID })
+
+	for _, a := range attachments {
+		if file, ok := fileMap[a.FileID]; ok {
+			result[a.MessageID] = append(result[a.MessageID], file)
+		}
+	}
+	return result, nil
+}
+
+// Copy 为复制出的消息复制附件，idMap 为原消息 id -> 新消息 id
+func Copy(tx *gorm.DB, idMap map[uint]uint) error {
+	if len(idMap) == 0 {
+		return nil
+	}
+	var attachments []model.ChatMessageAttachment
+	if err := tx.Where("message_id IN ?", lo.Keys(idMap)).Find(&attachments).Error; err != nil {
+		return err
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+	copied := lo.Map(attachments, func(a model.ChatMessageAttachment, _ int) model.ChatMessageAttachment {
+		return model.ChatMessageAttachment{
+			UserID:    a.UserID,
+			MessageID: idMap[a.MessageID],
+			FileID:    a.FileID,
+			Sort:      a.Sort,
+		}
+	})
+	return tx.CreateInBatches(&copied, 500).Error
+}
+
+// Release 删除消息的附件，不再被任何消息引用的文件恢复聊天图片、语音的过期时间
+func Release(tx *gorm.DB, messageIDs []uint) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	var fileIDs []uint
+	if err := tx.Model(&model.ChatMessageAttachment{}).
+		Where("message_id IN ?", messageIDs).
+		Distinct().
+		Pluck("file_id", &fileIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("message_id IN ?", messageIDs).Delete(&model.ChatMessageAttachment{}).Error; err != nil {
+		return err
+	}
+	if len(fileIDs) == 0 {
+		return nil
+	}
+
+	var usedIDs []uint
+	if err := tx.Model(&model.ChatMessageAttachment{}).
+		Where("file_id IN ?", fileIDs).
+		Distinct().
+		Pluck("file_id", &usedIDs).Error; err != nil {
+		return err
+	}
+	released := lo.Without(fileIDs, usedIDs...)
+	if len(released) == 0 {
+		return nil
+	}
+	for _, usageType := range []string{model.UsageTypeChatImage, model.UsageTypeChatAudio} {
+		if err := tx.Model(&model.UserFile{}).
+			Where("id IN ? AND usage_type = ?", released, usageType).
+			Update("expires_at", model.GetExpirationTime(usageType)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 )
 
@@ -34,3 +35,28 @@ func CreateChatSession(ctx context.Context, userID uint, profile *model.Profile)
 	})
 	return chatSession, err
 }
+
+// FillChatSessionProfiles 用好友 profile 的名称和头像覆盖会话的名称和头像
+func FillChatSessionProfiles(chatSessions []model.ChatSession) {
+	profileIds := make([]uint, 0)
+	for _, chatSession := range chatSessions {
+		if chatSession.ProfileID != 0 {
+			profileIds = append(profileIds, chatSession.ProfileID)
+		}
+	}
+	if len(profileIds) == 0 {
+		return
+	}
+
+	profiles := make([]model.Profile, 0)
+	db.GetDB().Where("id IN (?)", profileIds).Find(&profiles)
+	profilesMap := lo.SliceToMap(profiles, func(profile model.Profile) (uint, model.Profile) {
+		return profile.ID, profile
+	})
+	for i, chatSession := range chatSessions {
+		if profile, ok := profilesMap[chatSession.ProfileID]; ok {
+			chatSessions[i].Name = profile.Name
+			chatSessions[i].Avatar = profile.Avatar
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package msgevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"app_server/model"
+
+	"gorm.io/gorm"
+)
+
+var errBackendFull = errors.New("message event backend is full")
+
+// MemoryBackend 进程内的后端，只分发本实例发布的事件
+type MemoryBackend struct {
+	ch chan Event
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{ch: make(chan Event, 1024)}
+}
+
+func (m *MemoryBackend) Publish(ctx context.Context, event Event) error {
+	select {
+	case m.ch <- event:
+		return nil
+	default:
+		return errBackendFull
+	}
+}
+
+func (m *MemoryBackend) Run(ctx context.Context, deliver func(Event)) error {
+	for {
+		select {
+		case event := <-m.ch:
+			deliver(event)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+const (
+	dbEventRetention = 10 * time.Minute // 数据库后端事件的保留时长，超过后删除
+	dbEventLookback  = 5 * time.Second  // 轮询时回看的时长，容忍事务提交延迟和实例间的时钟偏差
+)
+
+// DBBackend 通过数据库表在多个实例之间分发事件，每个实例轮询新写入的事件
+type DBBackend struct {
+	db       *gorm.DB
+	interval time.Duration
+}
+
+func NewDBBackend(db *gorm.DB, interval time.Duration) *DBBackend {
+	return &DBBackend{db: db, interval: interval}
+}
+
+func (d *DBBackend) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return d.db.WithContext(ctx).Create(&model.ChatMessageEvent{
+		UserID:  event.UserID,
+		Payload: string(payload),
+	}).Error
+}
+
+func (d *DBBackend) Run(ctx context.Context, deliver func(Event)) error {
+	// 事件 id 由各实例分别生成，不保证按写入顺序递增，按写入时间回看一段时间并跳过已分发的事件
+	since := time.Now()
+	seen := make(map[uint]time.Time)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		pollAt := time.Now()
+		var rows []model.ChatMessageEvent
+		if err := d.db.WithContext(ctx).Where("created_at >= ?", since.Add(-dbEventLookback)).
+			Order("created_at ASC, id ASC").
+			Find(&rows).Error; err != nil {
+			slog.Error("poll message events error", "error", err)
+			continue
+		}
+		for _, row := range rows {
+			if _, ok := seen[row.ID]; ok {
+				continue
+			}
+			seen[row.ID] = row.CreatedAt
+			var event Event
+			if err := json.Unmarshal([]byte(row.Payload), &event); err != nil {
+				slog.Error("unmarshal message event error", "error", err, "id", row.ID)
+				continue
+			}
+			deliver(event)
+		}
+		since = pollAt
+		for id, createdAt := range seen {
+			if createdAt.Before(since.Add(-2 * dbEventLookback)) {
+				delete(seen, id)
+			}
+		}
+
+		if time.Since(lastCleanup) > dbEventRetention {
+			lastCleanup = time.Now()
+			if err := d.db.WithContext(ctx).Where("created_at < ?", lastCleanup.Add(-dbEventRetention)).
+				Delete(&model.ChatMessageEvent{}).Error; err != nil {
+				slog.Error("cleanup message events error", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package msgevent
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"app_server/model"
+)
+
+// 消息变更事件类型
+const (
+	EventCreated = "CREATED"
+	EventUpdated = "UPDATED"
+	EventDeleted = "DELETED"
+)
+
+// 每个订阅者缓冲的事件数量，缓冲满时断开订阅，客户端重连后重新拉取
+const subscriberBuffer = 64
+
+// Event 一次操作产生的消息变更，同一事件中的消息属于同一个用户
+type Event struct {
+	Type       string              `json:"type"`
+	UserID     uint                `json:"user_id"`
+	Messages   []model.ChatMessage `json:"messages"`
+	OccurredAt time.Time           `json:"occurred_at"`
+}
+
+// Backend 事件的传输方式，多实例部署时通过共享的后端把事件分发到所有实例
+type Backend interface {
+	// Publish 发布事件
+	Publish(ctx context.Context, event Event) error
+	// Run 接收所有实例发布的事件并交给 deliver 分发，ctx 取消后返回
+	Run(ctx context.Context, deliver func(Event)) error
+}
+
+// Subscription 一个客户端连接的订阅
+type Subscription struct {
+	C <-chan Event
+
+	ch         chan Event
+	userID     uint
+	sessionIDs []uint // 为空时订阅全部会话
+	closed     bool
+}
+
+// Bus 把后端收到的事件分发给本实例上对应用户的订阅者
+type Bus struct {
+	backend Backend
+
+	mu   sync.Mutex
+	subs map[uint]map[*Subscription]struct{}
+}
+
+func NewBus(backend Backend) *Bus {
+	return &Bus{
+		backend: backend,
+		subs:    make(map[uint]map[*Subscription]struct{}),
+	}
+}
+
+// Run 开始从后端接收事件，ctx 取消后返回
+func (b *Bus) Run(ctx context.Context) error {
+	return b.backend.Run(ctx, b.deliver)
+}
+
+// Publish 发布消息变更事件，没有消息时忽略
+func (b *Bus) Publish(ctx context.Context, eventType string, userID uint, msgs ...model.ChatMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	return b.backend.Publish(ctx, Event{
+		Type:       eventType,
+		UserID:     userID,
+		Messages:   msgs,
+		OccurredAt: time.Now(),
+	})
+}
+
+// Subscribe 订阅用户的消息变更，sessionIDs 为空时订阅全部会话
+// 订阅者处理过慢时 C 会被关闭
+func (b *Bus) Subscribe(userID uint, sessionIDs []uint) *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, userID: userID, sessionIDs: sessionIDs}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe 取消订阅并关闭 C
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+	delete(b.subs[sub.userID], sub)
+	if len(b.subs[sub.userID]) == 0 {
+		delete(b.subs, sub.userID)
+	}
+}
+
+// deliver 把事件按会话过滤后发送给用户的每个订阅者
+func (b *Bus) deliver(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs[event.UserID] {
+		filtered := sub.filter(event)
+		if len(filtered.Messages) == 0 {
+			continue
+		}
+		select {
+		case sub.ch <- filtered:
+		default:
+			slog.Warn("message event subscriber too slow, closed", "userID", event.UserID)
+			b.remove(sub)
+		}
+	}
+}
+
+func (sub *Subscription) filter(event Event) Event {
+	if len(sub.sessionIDs) == 0 {
+		return event
+	}
+	filtered := event
+	filtered.Messages = nil
+	for _, msg := range event.Messages {
+		if slices.Contains(sub.sessionIDs, msg.SessionID) {
+			filtered.Messages = append(filtered.Messages, msg)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,55 @@
+package msgevent
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func msg(id, sessionID uint) model.ChatMessage {
+	return model.ChatMessage{Model: gorm.Model{ID: id}, SessionID: sessionID}
+}
+
+func TestBusDeliver(t *testing.T) {
+	bus := NewBus(NewMemoryBackend())
+	all := bus.Subscribe(1, nil)
+	session2 := bus.Subscribe(1, []uint{2})
+	other := bus.Subscribe(9, nil)
+
+	bus.deliver(Event{Type: EventCreated, UserID: 1, Messages: []model.ChatMessage{msg(10, 1), msg(11, 2)}})
+
+	event := <-all.C
+	assert.Len(t, event.Messages, 2)
+	event = <-session2.C
+	assert.Equal(t, []uint{11}, []uint{event.Messages[0].ID})
+	assert.Len(t, other.C, 0)
+
+	// 过滤后没有消息的事件不推送
+	bus.deliver(Event{Type: EventDeleted, UserID: 1, Messages: []model.ChatMessage{msg(12, 1)}})
+	assert.Len(t, session2.C, 0)
+	<-all.C
+
+	bus.Unsubscribe(all)
+	_, ok := <-all.C
+	assert.False(t, ok)
+	bus.Unsubscribe(all)
+}
+
+func TestBusSlowSubscriber(t *testing.T) {
+	bus := NewBus(NewMemoryBackend())
+	sub := bus.Subscribe(1, nil)
+	for i := 0; i <= subscriberBuffer; i++ {
+		bus.deliver(Event{Type: EventUpdated, UserID: 1, Messages: []model.ChatMessage{msg(uint(i+1), 1)}})
+	}
+
+	// 缓冲满后订阅被关闭，已缓冲的事件仍可读取
+	count := 0
+	for range sub.C {
+		count++
+	}
+	assert.Equal(t, subscriberBuffer, count)
+	assert.Empty(t, bus.subs)
+}
@@ -0,0 +1,65 @@
+package msgevent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"app_server/model"
+	"app_server/pkg/db"
+)
+
+// 事件后端类型
+const (
+	BackendMemory = "memory"
+	BackendDB     = "db"
+)
+
+const defaultPollInterval = 500 * time.Millisecond
+
+type Config struct {
+	Backend      string        `mapstructure:"backend"` // memory 或 db，默认 memory
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
+
+var defaultBus = NewBus(NewMemoryBackend())
+
+// Init 按配置选择后端并开始分发事件，ctx 取消后停止
+func Init(ctx context.Context, cfg Config) {
+	var backend Backend
+	switch cfg.Backend {
+	case BackendDB:
+		interval := cfg.PollInterval
+		if interval <= 0 {
+			interval = defaultPollInterval
+		}
+		backend = NewDBBackend(db.GetDB(), interval)
+	default:
+		backend = NewMemoryBackend()
+	}
+	defaultBus = NewBus(backend)
+
+	bus := defaultBus
+	go func() {
+		if err := bus.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("message event bus stopped", "error", err)
+		}
+	}()
+}
+
+// Publish 发布消息变更事件，应在事务提交之后调用，发布失败只记录日志
+func Publish(ctx context.Context, eventType string, userID uint, msgs ...model.ChatMessage) {
+	if err := defaultBus.Publish(ctx, eventType, userID, msgs...); err != nil {
+		slog.Error("publish message event error", "error", err, "type", eventType, "userID", userID)
+	}
+}
+
+// Subscribe 订阅用户的消息变更，使用完后需要调用 Unsubscribe
+func Subscribe(userID uint, sessionIDs []uint) *Subscription {
+	return defaultBus.Subscribe(userID, sessionIDs)
+}
+
+func Unsubscribe(sub *Subscription) {
+	defaultBus.Unsubscribe(sub)
+}
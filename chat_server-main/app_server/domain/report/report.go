@@ -0,0 +1,356 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"app_server/domain/summary"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/oai"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ScheduleWeekly = "weekly" // 每周一生成上一周的报告
+	ScheduleOff    = "off"
+
+	DefaultPeriod        = 7 * 24 * time.Hour
+	MaxPeriod            = 31 * 24 * time.Hour
+	defaultCheckInterval = time.Hour
+	defaultMaxAttempts   = 5
+	maxRetryDelay        = 24 * time.Hour
+	maxLastErrorLength   = 1000
+)
+
+// 报告使用的消息类型
+var reportMessageTypes = []string{model.MessageTypeHistory, model.MessageTypeTranslate, model.MessageTypeConsult}
+
+var ErrNoMessages = errors.New("no messages in the period")
+
+var conf Config
+
+type Config struct {
+	Schedule      string        `mapstructure:"schedule"` // weekly（默认）, off
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	MaxAttempts   int           `mapstructure:"max_attempts"` // 定时生成连续失败多少次后不再重试
+}
+
+func Init(cfg Config) {
+	if cfg.Schedule == "" {
+		cfg.Schedule = ScheduleWeekly
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = defaultCheckInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	conf = cfg
+}
+
+// Generate 根据会话在 [start, end) 内的消息生成报告，同一时间段已有报告时覆盖
+func Generate(ctx context.Context, userID, sessionID uint, start, end time.Time, trigger string) (model.SessionReport, error) {
+	database := db.GetDB().WithContext(ctx)
+
+	var session model.ChatSession
+	if err := database.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		return model.SessionReport{}, err
+	}
+	friendName := session.Name
+	if session.ProfileID != 0 {
+		var profile model.Profile
+		if err := database.Where("id = ?", session.ProfileID).First(&profile).Error; err == nil && profile.Name != "" {
+			friendName = profile.Name
+		}
+	}
+
+	var msgs []model.ChatMessage
+	if err := database.Where("user_id = ? AND session_id = ? AND msg_type IN ? AND msg_at >= ? AND msg_at < ?",
+		userID, sessionID, reportMessageTypes, start, end).
+		Order("msg_at ASC, id ASC").
+		Find(&msgs).Error; err != nil {
+		return model.SessionReport{}, err
+	}
+	if len(msgs) == 0 {
+		return model.SessionReport{}, ErrNoMessages
+	}
+
+	content, err := digest(ctx, friendName, start, end, msgs)
+	if err != nil {
+		return model.SessionReport{}, err
+	}
+	d, err := parseDigest(content)
+	if err != nil {
+		slog.Error("parse session report error", "error", err, "content", content)
+		return model.SessionReport{}, err
+	}
+
+	report := model.SessionReport{
+		UserID:       userID,
+		SessionID:    sessionID,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		Trigger:      trigger,
+		MessageCount: len(msgs),
+		Summary:      d.Summary,
+		Topics:       d.Topics,
+		OpenItems:    d.OpenItems,
+		ToneChanges:  d.ToneChanges,
+		NextSteps:    d.NextSteps,
+	}
+	if err := database.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "session_id"}, {Name: "period_start"}, {Name: "period_end"}},
+		DoUpdates: clause.AssignmentColumns([]string{"trigger", "message_count", "summary", "topics",
+			"open_items", "tone_changes", "next_steps", "updated_at"}),
+	}).Create(&report).Error; err != nil {
+		return report, err
+	}
+
+	// 覆盖已有报告时 id 不会回填，重新查询
+	if err := database.Where("session_id = ? AND period_start = ? AND period_end = ?", sessionID, start, end).
+		First(&report).Error; err != nil {
+		return report, err
+	}
+
+	slog.Info("session report generated", "sessionID", sessionID, "start", start, "end", end, "trigger", trigger)
+
+	return report, nil
+}
+
+// digest 调用模型生成报告内容，消息超出预算时只保留时间段内最近的消息
+func digest(ctx context.Context, friendName string, start, end time.Time, msgs []model.ChatMessage) (string, error) {
+	older, recent := summary.SplitRecent(msgs, summary.MaxTokens(""))
+	omitted := ""
+	if len(older) > 0 {
+		omitted = fmt.Sprintf("（记录较多，省略了时间段内较早的 %d 条）", len(older))
+	}
+
+	var history strings.Builder
+	for _, msg := range recent {
+		history.WriteString(transcriptLine(msg))
+		history.WriteString("\n")
+	}
+
+	prompt := getDigestPrompt(ctx)
+	prompt = strings.ReplaceAll(prompt, "{{friend_name}}", lo.Ternary(friendName != "", friendName, "对方"))
+	prompt = strings.ReplaceAll(prompt, "{{period}}", fmt.Sprintf("%s 至 %s", start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04")))
+	prompt = strings.ReplaceAll(prompt, "{{omitted}}", omitted)
+	prompt = strings.ReplaceAll(prompt, "{{chat_context}}", history.String())
+
+	content, err := oai.Get().CreateChatCompletionSimple(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("generate session report: %w", err)
+	}
+	return content, nil
+}
+
+// transcriptLine 报告中的一行记录，带上消息时间，咨询记录单独标注
+func transcriptLine(msg model.ChatMessage) string {
+	line := msg.HistoryCnString()
+	if msg.MsgType == model.MessageTypeConsult {
+		line = "[咨询]" + line
+	}
+	return fmt.Sprintf("[%s] %s", msg.MsgAt.Format("01-02 15:04"), line)
+}
+
+// reportDigest 模型输出的报告内容
+type reportDigest struct {
+	Summary     string                 `json:"summary"`
+	Topics      []string               `json:"topics"`
+	OpenItems   []model.ReportOpenItem `json:"open_items"`
+	ToneChanges string                 `json:"tone_changes"`
+	NextSteps   []string               `json:"next_steps"`
+}
+
+// parseDigest 解析模型输出的报告，去掉空内容，规整请求和承诺的类型和归属
+func parseDigest(content string) (reportDigest, error) {
+	d, err := fn.JsonUnmarshalStr[reportDigest](oai.ExtractJSON(content))
+	if err != nil {
+		return d, fmt.Errorf("invalid session report: %w", err)
+	}
+
+	trim := func(items []string) []string {
+		return lo.Filter(fn.Map(items, strings.TrimSpace), func(item string, _ int) bool { return item != "" })
+	}
+	d.Summary = strings.TrimSpace(d.Summary)
+	d.ToneChanges = strings.TrimSpace(d.ToneChanges)
+	d.Topics = trim(d.Topics)
+	d.NextSteps = trim(d.NextSteps)
+
+	var openItems []model.ReportOpenItem
+	for _, item := range d.OpenItems {
+		item.Content = strings.TrimSpace(item.Content)
+		if item.Content == "" {
+			continue
+		}
+		item.Kind = strings.ToLower(strings.TrimSpace(item.Kind))
+		if item.Kind != model.ReportItemPromise {
+			item.Kind = model.ReportItemRequest
+		}
+		item.Owner = strings.ToUpper(strings.TrimSpace(item.Owner))
+		if item.Owner != model.MessageRoleSelf && item.Owner != model.MessageRoleFriend {
+			item.Owner = ""
+		}
+		openItems = append(openItems, item)
+	}
+	d.OpenItems = openItems
+
+	if d.Summary == "" && len(d.Topics) == 0 {
+		return d, errors.New("empty session report")
+	}
+	return d, nil
+}
+
+// WeeklyPeriod 返回 now 所在周的上一个完整自然周，周一 00:00 开始
+func WeeklyPeriod(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// time.Weekday 以周日为 0
+	offset := (int(today.Weekday()) + 6) % 7
+	end := today.AddDate(0, 0, -offset)
+	return end.AddDate(0, 0, -7), end
+}
+
+// GenerateScheduled 为时间段内有消息且还没有报告的会话生成报告，返回生成的数量
+// 失败的会话按退避时间重试，连续失败 MaxAttempts 次后不再重试
+func GenerateScheduled(ctx context.Context, start, end time.Time) (int, error) {
+	type activeSession struct {
+		UserID    uint
+		SessionID uint
+	}
+	var sessions []activeSession
+	if err := db.GetDB().WithContext(ctx).Model(&model.ChatMessage{}).
+		Select("DISTINCT chat_message.user_id, chat_message.session_id").
+		Joins("JOIN chat_session ON chat_session.id = chat_message.session_id AND chat_session.deleted_at IS NULL").
+		Where("chat_message.msg_type IN ? AND chat_message.msg_at >= ? AND chat_message.msg_at < ?", reportMessageTypes, start, end).
+		Where("NOT EXISTS (?)", db.GetDB().Model(&model.SessionReport{}).
+			Select("1").
+			Where("session_report.session_id = chat_message.session_id AND session_report.period_start = ? AND session_report.period_end = ?", start, end)).
+		Where("NOT EXISTS (?)", db.GetDB().Model(&model.SessionReportAttempt{}).
+			Select("1").
+			Where("session_report_attempt.session_id = chat_message.session_id AND session_report_attempt.period_start = ? AND session_report_attempt.period_end = ?", start, end).
+			Where("session_report_attempt.attempts >= ? OR session_report_attempt.next_attempt_at > ?", conf.MaxAttempts, time.Now())).
+		Scan(&sessions).Error; err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, s := range sessions {
+		if ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+		if _, err := Generate(ctx, s.UserID, s.SessionID, start, end, model.ReportTriggerScheduled); err != nil {
+			// 单个会话失败不影响其他会话，退避后重试
+			slog.Error("generate scheduled session report error", "error", err, "sessionID", s.SessionID)
+			recordFailure(ctx, s.UserID, s.SessionID, start, end, err)
+			continue
+		}
+		if err := db.GetDB().WithContext(ctx).Unscoped().
+			Where("session_id = ? AND period_start = ? AND period_end = ?", s.SessionID, start, end).
+			Delete(&model.SessionReportAttempt{}).Error; err != nil {
+			slog.Error("delete session report attempt error", "error", err, "sessionID", s.SessionID)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// recordFailure 记录会话生成报告失败的次数和原因，计算下次重试的时间
+func recordFailure(ctx context.Context, userID, sessionID uint, start, end time.Time, genErr error) {
+	database := db.GetDB().WithContext(ctx)
+	var attempt model.SessionReportAttempt
+	if err := database.Where("session_id = ? AND period_start = ? AND period_end = ?", sessionID, start, end).
+		Limit(1).Find(&attempt).Error; err != nil {
+		slog.Error("find session report attempt error", "error", err, "sessionID", sessionID)
+		return
+	}
+	attempt.UserID = userID
+	attempt.SessionID = sessionID
+	attempt.PeriodStart = start
+	attempt.PeriodEnd = end
+	attempt.Attempts++
+	attempt.LastError = truncateError(genErr.Error())
+	attempt.NextAttemptAt = time.Now().Add(retryDelay(attempt.Attempts))
+	if err := database.Save(&attempt).Error; err != nil {
+		slog.Error("save session report attempt error", "error", err, "sessionID", sessionID)
+		return
+	}
+	if attempt.Attempts >= conf.MaxAttempts {
+		slog.Warn("scheduled session report gave up", "sessionID", sessionID, "attempts", attempt.Attempts, "start", start)
+	}
+}
+
+// retryDelay 第 attempts 次失败后等待的时间，从检查间隔开始每次翻倍，最多一天
+func retryDelay(attempts int) time.Duration {
+	delay := conf.CheckInterval
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func truncateError(msg string) string {
+	if runes := []rune(msg); len(runes) > maxLastErrorLength {
+		return string(runes[:maxLastErrorLength])
+	}
+	return msg
+}
+
+// StartScheduleJob 在后台定期检查并生成上一周的报告，ctx 取消后退出
+// 多个实例同时运行时可能重复生成同一份报告，结果会互相覆盖，不会产生重复的报告
+func StartScheduleJob(ctx context.Context) {
+	if conf.Schedule == ScheduleOff {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(conf.CheckInterval)
+		defer ticker.Stop()
+		for {
+			start, end := WeeklyPeriod(time.Now())
+			count, err := GenerateScheduled(ctx, start, end)
+			if err != nil {
+				slog.Error("generate scheduled session reports error", "error", err)
+			} else if count > 0 {
+				slog.Info("scheduled session reports generated", "count", count, "start", start)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// getDigestPrompt 获取报告提示词，优先从 config 表获取
+func getDigestPrompt(ctx context.Context) string {
+	var config model.Config
+	if err := db.GetDB().WithContext(ctx).Model(&model.Config{}).
+		Where("k = ?", "prompt:report:digest").
+		First(&config).Error; err == nil && config.Value != "" {
+		return config.Value
+	}
+
+	return `你是一个人际关系助理。下面是用户和{{friend_name}}在 {{period}} 期间的聊天记录{{omitted}}，以及用户就这段关系向AI咨询的记录（标注为[咨询]）。
+请整理一份这段时间的报告，要求：
+1. summary：两三句话概括这段时间发生了什么；
+2. topics：主要话题，每条不超过 20 字；
+3. open_items：还没有完成的请求或承诺，kind 为 request（请求）或 promise（承诺），owner 为需要履行的一方，用户为 SELF，{{friend_name}}为 FRIEND；
+4. tone_changes：双方语气和态度有什么变化，没有明显变化时说明整体氛围；
+5. next_steps：给用户的下一步建议，具体可执行。
+
+只输出 JSON，不要输出其他内容，格式如下：
+{"summary": "", "topics": [""], "open_items": [{"kind": "request", "owner": "FRIEND", "content": ""}], "tone_changes": "", "next_steps": [""]}
+
+聊天记录：
+{{chat_context}}`
+}
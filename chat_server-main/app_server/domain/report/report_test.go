@@ -0,0 +1,71 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDigest(t *testing.T) {
+	content := "报告如下：\n```json\n" + `{
+		"summary": " 本周主要在推进项目上线 ",
+		"topics": ["上线时间", " ", "预算"],
+		"open_items": [
+			{"kind": "Promise", "owner": "self", "content": "周五前提交方案"},
+			{"kind": "ask", "owner": "boss", "content": "确认预算"},
+			{"kind": "request", "owner": "FRIEND", "content": "  "}
+		],
+		"tone_changes": "对方从催促转为认可",
+		"next_steps": ["周四同步进度", ""]
+	}` + "\n```"
+
+	d, err := parseDigest(content)
+	assert.NoError(t, err)
+	assert.Equal(t, "本周主要在推进项目上线", d.Summary)
+	assert.Equal(t, []string{"上线时间", "预算"}, d.Topics)
+	assert.Equal(t, []model.ReportOpenItem{
+		{Kind: model.ReportItemPromise, Owner: model.MessageRoleSelf, Content: "周五前提交方案"},
+		{Kind: model.ReportItemRequest, Owner: "", Content: "确认预算"},
+	}, d.OpenItems)
+	assert.Equal(t, []string{"周四同步进度"}, d.NextSteps)
+
+	_, err = parseDigest(`{"summary": "", "topics": []}`)
+	assert.Error(t, err)
+	_, err = parseDigest("无法生成报告")
+	assert.Error(t, err)
+}
+
+func TestWeeklyPeriod(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	monday := time.Date(2024, 5, 6, 0, 0, 0, 0, loc)
+
+	// 周三、周日和周一零点都返回上一个完整的周
+	for _, now := range []time.Time{
+		time.Date(2024, 5, 8, 15, 30, 0, 0, loc),
+		time.Date(2024, 5, 12, 23, 59, 0, 0, loc),
+		monday,
+	} {
+		start, end := WeeklyPeriod(now)
+		assert.Equal(t, monday.AddDate(0, 0, -7), start, now)
+		assert.Equal(t, monday, end, now)
+	}
+}
+
+func TestTranscriptLine(t *testing.T) {
+	at := time.Date(2024, 5, 6, 9, 5, 0, 0, time.Local)
+	line := transcriptLine(model.ChatMessage{Role: model.MessageRoleUser, MsgType: model.MessageTypeConsult, Content: "怎么回复", MsgAt: at})
+	assert.Equal(t, "[05-06 09:05] [咨询]用户:怎么回复", line)
+}
+
+func TestRetryDelay(t *testing.T) {
+	Init(Config{CheckInterval: time.Hour})
+	assert.Equal(t, time.Hour, retryDelay(1))
+	assert.Equal(t, 2*time.Hour, retryDelay(2))
+	assert.Equal(t, 8*time.Hour, retryDelay(4))
+	// 最多等待一天
+	assert.Equal(t, 24*time.Hour, retryDelay(6))
+	assert.Equal(t, 24*time.Hour, retryDelay(100))
+}
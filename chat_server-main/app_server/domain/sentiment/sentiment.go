@@ -0,0 +1,225 @@
+package sentiment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/oai"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	batchSize       = 40  // 每次请求模型分析的消息数
+	MaxClassifyOnce = 400 // 单次调用最多分析的消息数，其余留到下次调用
+	maxEmotions     = 3
+	queryChunkSize  = 500
+)
+
+// Emotions 情绪标签
+var Emotions = []string{
+	"joy",
+	"gratitude",
+	"affection",
+	"excitement",
+	"calm",
+	"surprise",
+	"confusion",
+	"anxiety",
+	"sadness",
+	"disappointment",
+	"frustration",
+	"anger",
+}
+
+// Classify 返回消息的情感分析结果，优先使用缓存，缓存缺失或过期的消息调用模型分析
+// 最多分析 MaxClassifyOnce 条消息，未分析的消息不在返回结果中
+func Classify(ctx context.Context, userID uint, messages []model.ChatMessage) (map[uint]model.MessageSentiment, error) {
+	results, err := loadCached(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := lo.Filter(messages, func(msg model.ChatMessage, _ int) bool {
+		_, ok := results[msg.ID]
+		return !ok && strings.TrimSpace(msg.Content) != ""
+	})
+	if len(pending) > MaxClassifyOnce {
+		pending = pending[:MaxClassifyOnce]
+	}
+
+	for _, chunk := range lo.Chunk(pending, batchSize) {
+		classified, err := classifyBatch(ctx, userID, chunk)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"session_id", "content_hash", "sentiment", "score", "emotions", "updated_at"}),
+		}).Create(&classified).Error; err != nil {
+			return nil, err
+		}
+		for _, item := range classified {
+			results[item.MessageID] = item
+		}
+	}
+
+	return results, nil
+}
+
+// InvalidateByMessages 消息内容修改后删除缓存的分析结果
+func InvalidateByMessages(tx *gorm.DB, messageIDs ...uint) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	return tx.Unscoped().Where("message_id IN ?", messageIDs).Delete(&model.MessageSentiment{}).Error
+}
+
+// ContentHash 消息内容的哈希
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadCached 查询消息已缓存且未过期的分析结果
+func loadCached(ctx context.Context, messages []model.ChatMessage) (map[uint]model.MessageSentiment, error) {
+	hashes := make(map[uint]string, len(messages))
+	for _, msg := range messages {
+		hashes[msg.ID] = ContentHash(msg.Content)
+	}
+
+	results := make(map[uint]model.MessageSentiment, len(messages))
+	for _, ids := range lo.Chunk(lo.Keys(hashes), queryChunkSize) {
+		var rows []model.MessageSentiment
+		if err := db.GetDB().WithContext(ctx).Where("message_id IN ?", ids).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row.ContentHash == hashes[row.MessageID] {
+				results[row.MessageID] = row
+			}
+		}
+	}
+	return results, nil
+}
+
+// classifyBatch 调用模型分析一批消息，模型没有返回结果的消息按中性处理
+func classifyBatch(ctx context.Context, userID uint, messages []model.ChatMessage) ([]model.MessageSentiment, error) {
+	var lines strings.Builder
+	for i, msg := range messages {
+		fmt.Fprintf(&lines, "[%d] %s\n", i+1, msg.HistoryCnString())
+	}
+
+	prompt := getClassifyPrompt(ctx)
+	prompt = strings.ReplaceAll(prompt, "{{emotions}}", strings.Join(Emotions, ", "))
+	prompt = strings.ReplaceAll(prompt, "{{messages}}", lines.String())
+
+	content, err := oai.Get().CreateChatCompletionSimple(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("classify sentiment: %w", err)
+	}
+	labels, err := parseLabels(content)
+	if err != nil {
+		slog.Error("parse sentiment labels error", "error", err, "content", content)
+		return nil, err
+	}
+
+	return fn.Map(lo.Range(len(messages)), func(i int) model.MessageSentiment {
+		msg := messages[i]
+		l, ok := labels[i+1]
+		if !ok {
+			l = label{Sentiment: model.SentimentNeutral}
+		}
+		return model.MessageSentiment{
+			UserID:      userID,
+			SessionID:   msg.SessionID,
+			MessageID:   msg.ID,
+			ContentHash: ContentHash(msg.Content),
+			Sentiment:   l.Sentiment,
+			Score:       l.Score,
+			Emotions:    l.Emotions,
+		}
+	}), nil
+}
+
+// label 模型输出的一条消息的分析结果，ID 为消息在本批中的序号
+type label struct {
+	ID        int      `json:"id"`
+	Sentiment string   `json:"sentiment"`
+	Score     float64  `json:"score"`
+	Emotions  []string `json:"emotions"`
+}
+
+// parseLabels 解析模型输出，规整情感和分值，丢弃未知的情绪标签
+func parseLabels(content string) (map[int]label, error) {
+	items, err := fn.JsonUnmarshalStr[[]label](oai.ExtractJSON(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentiment labels: %w", err)
+	}
+
+	labels := make(map[int]label, len(items))
+	for _, item := range items {
+		item.Score = max(-1, min(1, item.Score))
+		item.Sentiment = strings.ToLower(strings.TrimSpace(item.Sentiment))
+		switch item.Sentiment {
+		case model.SentimentPositive, model.SentimentNeutral, model.SentimentNegative:
+		default:
+			item.Sentiment = sentimentOfScore(item.Score)
+		}
+		item.Emotions = lo.Uniq(lo.Filter(fn.Map(item.Emotions, func(e string) string {
+			return strings.ToLower(strings.TrimSpace(e))
+		}), func(e string, _ int) bool {
+			return lo.Contains(Emotions, e)
+		}))
+		if len(item.Emotions) > maxEmotions {
+			item.Emotions = item.Emotions[:maxEmotions]
+		}
+		labels[item.ID] = item
+	}
+	return labels, nil
+}
+
+// sentimentOfScore 模型没有给出有效的情感时按分值判断
+func sentimentOfScore(score float64) string {
+	switch {
+	case score >= 0.2:
+		return model.SentimentPositive
+	case score <= -0.2:
+		return model.SentimentNegative
+	default:
+		return model.SentimentNeutral
+	}
+}
+
+// getClassifyPrompt 获取情感分析提示词，优先从 config 表获取
+func getClassifyPrompt(ctx context.Context) string {
+	var config model.Config
+	if err := db.GetDB().WithContext(ctx).Model(&model.Config{}).
+		Where("k = ?", "prompt:sentiment:classify").
+		First(&config).Error; err == nil && config.Value != "" {
+		return config.Value
+	}
+
+	return `你是一个对话情感分析助手。请逐条分析下面聊天记录中发送者表达的情感。
+要求：
+1. sentiment 只能是 positive、neutral、negative 之一；
+2. score 为 -1 到 1 之间的小数，-1 表示非常消极，1 表示非常积极；
+3. emotions 从以下标签中选择 0 到 3 个：{{emotions}}；
+4. 结合上下文理解反话、玩笑和客套话，不要只看字面意思；
+5. 每条记录都要输出，id 为记录前的序号。
+
+只输出 JSON 数组，不要输出其他内容，格式如下：
+[{"id": 1, "sentiment": "negative", "score": -0.6, "emotions": ["frustration"]}]
+
+聊天记录：
+{{messages}}`
+}
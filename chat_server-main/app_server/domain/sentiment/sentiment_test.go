@@ -0,0 +1,35 @@
+package sentiment
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabels(t *testing.T) {
+	content := "```json\n[" +
+		`{"id": 1, "sentiment": "Negative", "score": -1.5, "emotions": ["Anger", "anger", "rage", "frustration"]},` +
+		`{"id": 2, "sentiment": "unknown", "score": 0.5, "emotions": []},` +
+		`{"id": 3, "sentiment": "", "score": 0.1}` +
+		"]\n```"
+
+	labels, err := parseLabels(content)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 3)
+	assert.Equal(t, model.SentimentNegative, labels[1].Sentiment)
+	assert.Equal(t, -1.0, labels[1].Score)
+	assert.Equal(t, []string{"anger", "frustration"}, labels[1].Emotions)
+	assert.Equal(t, model.SentimentPositive, labels[2].Sentiment)
+	assert.Equal(t, model.SentimentNeutral, labels[3].Sentiment)
+
+	_, err = parseLabels("无法分析")
+	assert.Error(t, err)
+}
+
+func TestContentHash(t *testing.T) {
+	assert.Equal(t, ContentHash("你好"), ContentHash("你好"))
+	assert.NotEqual(t, ContentHash("你好"), ContentHash("你好！"))
+	assert.Len(t, ContentHash(""), 64)
+}
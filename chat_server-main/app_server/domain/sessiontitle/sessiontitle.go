@@ -0,0 +1,233 @@
+package sessiontitle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"app_server/domain/summary"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/oai"
+
+	"github.com/samber/lo"
+)
+
+// 默认会话有 10 条消息后生成标题和摘要，之后每新增 30 条刷新一次
+const (
+	defaultMinMessages  = 10
+	defaultRefreshEvery = 30
+	defaultInterval     = time.Minute
+	maxTitleLength      = 20
+	lookback            = time.Minute // 回看的时长，容忍事务提交延迟
+	sessionBatchSize    = 200
+)
+
+// 参与生成标题和摘要的消息类型
+var titleMessageTypes = []string{model.MessageTypeHistory, model.MessageTypeTranslate, model.MessageTypeConsult}
+
+var conf Config
+
+type Config struct {
+	MinMessages  int           `mapstructure:"min_messages"`
+	RefreshEvery int           `mapstructure:"refresh_every"`
+	Interval     time.Duration `mapstructure:"interval"`
+}
+
+func Init(cfg Config) {
+	if cfg.MinMessages <= 0 {
+		cfg.MinMessages = defaultMinMessages
+	}
+	if cfg.RefreshEvery <= 0 {
+		cfg.RefreshEvery = defaultRefreshEvery
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	conf = cfg
+}
+
+// needsRefresh 会话消息数达到要求且距上次生成新增了足够多的消息时需要重新生成
+func needsRefresh(count, generatedCount, minMessages, refreshEvery int) bool {
+	if count < minMessages {
+		return false
+	}
+	return generatedCount == 0 || count-generatedCount >= refreshEvery
+}
+
+// Refresh 重新生成会话的自动标题和摘要，手动设置的标题不受影响
+func Refresh(ctx context.Context, session model.ChatSession, msgCount int) error {
+	database := db.GetDB().WithContext(ctx)
+
+	var msgs []model.ChatMessage
+	if err := database.Where("user_id = ? AND session_id = ? AND msg_type IN ?", session.UserID, session.ID, titleMessageTypes).
+		Order("id ASC").
+		Find(&msgs).Error; err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	// 较早的消息使用会话的滚动摘要代替
+	historySummary, recent := summary.Compact(ctx, session.UserID, session.ID, msgs, "")
+	var history strings.Builder
+	for _, msg := range recent {
+		history.WriteString(msg.HistoryCnString())
+		history.WriteString("\n")
+	}
+
+	prompt := getTitlePrompt(ctx)
+	prompt = strings.ReplaceAll(prompt, "{{friend_name}}", lo.Ternary(session.Name != "", session.Name, "对方"))
+	prompt = strings.ReplaceAll(prompt, "{{previous_summary}}", historySummary)
+	prompt = strings.ReplaceAll(prompt, "{{chat_context}}", history.String())
+
+	content, err := oai.Get().CreateChatCompletionSimple(ctx, prompt)
+	if err != nil {
+		return fmt.Errorf("generate session title: %w", err)
+	}
+	result, err := parseTitle(content)
+	if err != nil {
+		slog.Error("parse session title error", "error", err, "content", content)
+		return err
+	}
+
+	now := time.Now()
+	return database.Model(&model.ChatSession{}).
+		Where("id = ?", session.ID).
+		Updates(map[string]any{
+			"auto_title":        result.Title,
+			"auto_summary":      result.Summary,
+			"auto_msg_count":    msgCount,
+			"auto_generated_at": now,
+		}).Error
+}
+
+// titleResult 模型输出的标题和摘要
+type titleResult struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+// parseTitle 解析模型输出，去掉标题两侧的引号和书名号，过长时截断
+func parseTitle(content string) (titleResult, error) {
+	result, err := fn.JsonUnmarshalStr[titleResult](oai.ExtractJSON(content))
+	if err != nil {
+		return result, fmt.Errorf("invalid session title: %w", err)
+	}
+
+	result.Title = strings.Trim(strings.TrimSpace(result.Title), "\"'“”‘’《》「」")
+	if title := []rune(result.Title); len(title) > maxTitleLength {
+		result.Title = string(title[:maxTitleLength])
+	}
+	result.Summary = strings.TrimSpace(result.Summary)
+	if result.Title == "" {
+		return result, fmt.Errorf("empty session title")
+	}
+	return result, nil
+}
+
+// refreshSince 刷新 since 之后有新消息、且需要重新生成的会话，返回刷新的数量
+func refreshSince(ctx context.Context, since time.Time) (int, error) {
+	database := db.GetDB().WithContext(ctx)
+
+	var sessionIDs []uint
+	if err := database.Model(&model.ChatMessage{}).
+		Where("msg_type IN ? AND created_at >= ?", titleMessageTypes, since).
+		Distinct().
+		Pluck("session_id", &sessionIDs).Error; err != nil {
+		return 0, err
+	}
+
+	var refreshed int
+	for _, ids := range lo.Chunk(sessionIDs, sessionBatchSize) {
+		var sessions []model.ChatSession
+		if err := database.Where("id IN ?", ids).Find(&sessions).Error; err != nil {
+			return refreshed, err
+		}
+		type sessionCount struct {
+			SessionID uint
+			Count     int
+		}
+		var counts []sessionCount
+		if err := database.Model(&model.ChatMessage{}).
+			Select("session_id, COUNT(*) AS count").
+			Where("session_id IN ? AND msg_type IN ?", ids, titleMessageTypes).
+			Group("session_id").
+			Scan(&counts).Error; err != nil {
+			return refreshed, err
+		}
+		countMap := lo.SliceToMap(counts, func(c sessionCount) (uint, int) { return c.SessionID, c.Count })
+
+		for _, session := range sessions {
+			if ctx.Err() != nil {
+				return refreshed, ctx.Err()
+			}
+			count := countMap[session.ID]
+			if !needsRefresh(count, session.AutoMsgCount, conf.MinMessages, conf.RefreshEvery) {
+				continue
+			}
+			if err := Refresh(ctx, session, count); err != nil {
+				slog.Error("refresh session title error", "error", err, "sessionID", session.ID)
+				continue
+			}
+			refreshed++
+		}
+	}
+	return refreshed, nil
+}
+
+// StartRefreshJob 在后台定期为有新消息的会话生成标题和摘要，ctx 取消后退出
+// 启动后第一次检查全部会话，之后只检查上次检查以来有新消息的会话
+func StartRefreshJob(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(conf.Interval)
+		defer ticker.Stop()
+		var since time.Time
+		for {
+			checkAt := time.Now()
+			count, err := refreshSince(ctx, since)
+			if err != nil {
+				slog.Error("refresh session titles error", "error", err)
+			} else {
+				since = checkAt.Add(-lookback)
+				if count > 0 {
+					slog.Info("session titles refreshed", "count", count)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// getTitlePrompt 获取会话标题提示词，优先从 config 表获取
+func getTitlePrompt(ctx context.Context) string {
+	var config model.Config
+	if err := db.GetDB().WithContext(ctx).Model(&model.Config{}).
+		Where("k = ?", "prompt:session:title").
+		First(&config).Error; err == nil && config.Value != "" {
+		return config.Value
+	}
+
+	return `下面是用户和{{friend_name}}的聊天记录，以及用户向AI咨询的记录。请为这个会话起一个标题并写一段摘要。
+要求：
+1. title：概括会话的主题，不超过 15 个字，不要包含对方的名字，不要使用标点；
+2. summary：一段话概括双方在聊什么、进展到哪一步，不超过 150 字。
+
+只输出 JSON，不要输出其他内容，格式如下：
+{"title": "", "summary": ""}
+
+较早记录的摘要：
+{{previous_summary}}
+
+聊天记录：
+{{chat_context}}`
+}
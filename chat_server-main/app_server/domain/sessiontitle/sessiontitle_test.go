@@ -0,0 +1,32 @@
+package sessiontitle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeedsRefresh(t *testing.T) {
+	assert.False(t, needsRefresh(9, 0, 10, 30))
+	assert.True(t, needsRefresh(10, 0, 10, 30))
+	assert.False(t, needsRefresh(39, 10, 10, 30))
+	assert.True(t, needsRefresh(40, 10, 10, 30))
+	// 消息被删除后数量减少，不重新生成
+	assert.False(t, needsRefresh(12, 40, 10, 30))
+}
+
+func TestParseTitle(t *testing.T) {
+	result, err := parseTitle("```json\n" + `{"title": "《项目延期的沟通与补救方案讨论以及后续安排》", "summary": " 双方在讨论项目延期 "}` + "\n```")
+	assert.NoError(t, err)
+	assert.Equal(t, "项目延期的沟通与补救方案讨论以及后续安排", result.Title)
+	assert.Equal(t, "双方在讨论项目延期", result.Summary)
+
+	result, err = parseTitle(`{"title": "一二三四五六七八九十一二三四五六七八九十一二", "summary": ""}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "一二三四五六七八九十一二三四五六七八九十", result.Title)
+
+	_, err = parseTitle(`{"title": " “” ", "summary": "摘要"}`)
+	assert.Error(t, err)
+	_, err = parseTitle("无法生成")
+	assert.Error(t, err)
+}
@@ -0,0 +1,292 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/oai"
+	"app_server/pkg/openaic"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 默认的历史消息 token 预算，未按模型配置时使用
+const defaultMaxTokens = 6000
+
+var conf Config
+
+type Config struct {
+	DefaultMaxTokens int      `mapstructure:"default_max_tokens"`
+	Budgets          []Budget `mapstructure:"budgets"`
+}
+
+// Budget 单个模型的历史消息 token 预算
+type Budget struct {
+	Model     string `mapstructure:"model"`
+	MaxTokens int    `mapstructure:"max_tokens"`
+}
+
+func Init(cfg Config) {
+	conf = cfg
+}
+
+// MaxTokens 获取模型的历史消息 token 预算
+func MaxTokens(modelName string) int {
+	if modelName == "" {
+		modelName = openaic.Model.Chat
+	}
+	for _, budget := range conf.Budgets {
+		if budget.Model == modelName && budget.MaxTokens > 0 {
+			return budget.MaxTokens
+		}
+	}
+	if conf.DefaultMaxTokens > 0 {
+		return conf.DefaultMaxTokens
+	}
+	return defaultMaxTokens
+}
+
+// EstimateTokens 粗略估算文本的 token 数：中日韩字符按 1 个 token，其余字符按 4 个字符 1 个 token
+func EstimateTokens(s string) int {
+	var cjk, other int
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// EstimateMessagesTokens 估算消息列表的 token 数
+func EstimateMessagesTokens(messages []model.ChatMessage) int {
+	var total int
+	for _, msg := range messages {
+		total += EstimateTokens(msg.HistoryCnString())
+	}
+	return total
+}
+
+// SplitRecent 从末尾开始保留不超过 maxTokens 的消息，返回更早的消息和保留的最近消息
+// 最后一条消息总是会被保留
+func SplitRecent(messages []model.ChatMessage, maxTokens int) (older, recent []model.ChatMessage) {
+	var total int
+	i := len(messages)
+	for i > 0 {
+		tokens := EstimateTokens(messages[i-1].HistoryCnString())
+		if i < len(messages) && total+tokens > maxTokens {
+			break
+		}
+		total += tokens
+		i--
+	}
+	return messages[:i], messages[i:]
+}
+
+// SplitAround 从 center 位置的消息开始向前后交替扩展，保留不超过 maxTokens 的连续消息，返回保留范围之前的消息和保留的消息
+// center 位置的消息总是会被保留，一侧没有更多消息或放不下时继续扩展另一侧
+func SplitAround(messages []model.ChatMessage, center, maxTokens int) (older, window []model.ChatMessage) {
+	start, end := center, center+1
+	total := EstimateTokens(messages[center].HistoryCnString())
+	for beforeOK, afterOK := true, true; beforeOK || afterOK; {
+		if beforeOK = start > 0; beforeOK {
+			tokens := EstimateTokens(messages[start-1].HistoryCnString())
+			if beforeOK = total+tokens <= maxTokens; beforeOK {
+				total += tokens
+				start--
+			}
+		}
+		if afterOK = end < len(messages); afterOK {
+			tokens := EstimateTokens(messages[end].HistoryCnString())
+			if afterOK = total+tokens <= maxTokens; afterOK {
+				total += tokens
+				end++
+			}
+		}
+	}
+	return messages[:start], messages[start:end]
+}
+
+// Compact 在会话消息超出模型 token 预算时，用滚动摘要替换较早的消息
+// messages 需按 id 升序排列；返回摘要内容（未超出预算时为空）和需要原样放入 prompt 的最近消息
+// 摘要生成失败时退化为返回全部消息，不影响调用方
+func Compact(ctx context.Context, userID, sessionID uint, messages []model.ChatMessage, modelName string) (string, []model.ChatMessage) {
+	maxTokens := MaxTokens(modelName)
+	if EstimateMessagesTokens(messages) <= maxTokens {
+		return "", messages
+	}
+
+	// 一半预算留给最近的原始消息，另一半留给摘要
+	older, recent := SplitRecent(messages, maxTokens/2)
+	if len(older) == 0 {
+		return "", messages
+	}
+
+	chatSummary, err := ensureSummary(ctx, userID, sessionID, older, maxTokens)
+	if err != nil {
+		slog.Error("build chat summary error", "error", err, "sessionID", sessionID)
+		return "", messages
+	}
+
+	// 摘要可能覆盖到比 older 更新的消息（例如之后有消息被删除），这些消息不再重复放入 prompt
+	for len(recent) > 1 && recent[0].ID <= chatSummary.UntilMsgID {
+		recent = recent[1:]
+	}
+
+	return chatSummary.Content, recent
+}
+
+// Get 获取会话当前可用的摘要，不存在或已过期时返回 nil
+func Get(ctx context.Context, userID, sessionID uint) *model.ChatSummary {
+	var chatSummary model.ChatSummary
+	if err := db.GetDB().WithContext(ctx).
+		Where("user_id = ? AND session_id = ? AND stale = ?", userID, sessionID, false).
+		First(&chatSummary).Error; err != nil {
+		return nil
+	}
+	return &chatSummary
+}
+
+// InvalidateByMessages 消息被修改或删除后，将覆盖这些消息的摘要标记为过期，下次使用时重建
+func InvalidateByMessages(tx *gorm.DB, messages []model.ChatMessage) error {
+	// 每个会话只需要按最早的消息判断
+	minIDs := make(map[uint]uint)
+	for _, msg := range messages {
+		if id, ok := minIDs[msg.SessionID]; !ok || msg.ID < id {
+			minIDs[msg.SessionID] = msg.ID
+		}
+	}
+
+	for sessionID, minID := range minIDs {
+		if err := tx.Model(&model.ChatSummary{}).
+			Where("session_id = ? AND until_msg_id >= ?", sessionID, minID).
+			Update("stale", true).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidateSessions 会话中的消息整体变化（移入、移出、合并）后，将会话的摘要标记为过期
+func InvalidateSessions(tx *gorm.DB, sessionIDs ...uint) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&model.ChatSummary{}).
+		Where("session_id IN ?", sessionIDs).
+		Update("stale", true).Error
+}
+
+// ensureSummary 确保摘要覆盖 older 中的所有消息，必要时增量更新或从头重建
+func ensureSummary(ctx context.Context, userID, sessionID uint, older []model.ChatMessage, maxTokens int) (*model.ChatSummary, error) {
+	var chatSummary model.ChatSummary
+	err := db.GetDB().WithContext(ctx).
+		Where("user_id = ? AND session_id = ?", userID, sessionID).
+		First(&chatSummary).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	lastID := older[len(older)-1].ID
+	if err == nil && !chatSummary.Stale && chatSummary.UntilMsgID >= lastID {
+		return &chatSummary, nil
+	}
+
+	// 已过期或不存在时从头重建，否则只追加摘要之后的消息
+	content := chatSummary.Content
+	pending := older
+	if err != nil || chatSummary.Stale {
+		content = ""
+		chatSummary.Tokens = 0
+	} else {
+		for len(pending) > 0 && pending[0].ID <= chatSummary.UntilMsgID {
+			pending = pending[1:]
+		}
+	}
+
+	// 按预算分块滚动生成，避免摘要请求本身超出上下文
+	for len(pending) > 0 {
+		chunk := pending
+		var tokens int
+		for i, msg := range pending {
+			tokens += EstimateTokens(msg.HistoryCnString())
+			if i > 0 && tokens > maxTokens {
+				chunk = pending[:i]
+				break
+			}
+		}
+
+		content, err = summarize(ctx, content, chunk)
+		if err != nil {
+			return nil, err
+		}
+		chatSummary.Tokens += EstimateMessagesTokens(chunk)
+		pending = pending[len(chunk):]
+	}
+
+	chatSummary.UserID = userID
+	chatSummary.SessionID = sessionID
+	chatSummary.Content = content
+	chatSummary.UntilMsgID = lastID
+	chatSummary.Stale = false
+
+	if err := db.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "until_msg_id", "tokens", "stale", "updated_at"}),
+	}).Create(&chatSummary).Error; err != nil {
+		return nil, err
+	}
+
+	slog.Info("chat summary updated", "sessionID", sessionID, "untilMsgID", lastID, "tokens", chatSummary.Tokens)
+
+	return &chatSummary, nil
+}
+
+// summarize 将已有摘要和新的消息合并成新的摘要
+func summarize(ctx context.Context, previous string, messages []model.ChatMessage) (string, error) {
+	var history strings.Builder
+	for _, msg := range messages {
+		history.WriteString(msg.HistoryCnString())
+		history.WriteString("\n")
+	}
+
+	prompt := getSummaryPrompt(ctx)
+	prompt = strings.ReplaceAll(prompt, "{{previous_summary}}", previous)
+	prompt = strings.ReplaceAll(prompt, "{{chat_context}}", history.String())
+
+	content, err := oai.Get().CreateChatCompletionSimple(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("summarize chat history: %w", err)
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// getSummaryPrompt 获取摘要提示词，优先从 config 表获取
+func getSummaryPrompt(ctx context.Context) string {
+	var config model.Config
+	if err := db.GetDB().WithContext(ctx).Model(&model.Config{}).
+		Where("k = ?", "prompt:summary:rolling").
+		First(&config).Error; err == nil && config.Value != "" {
+		return config.Value
+	}
+
+	return `你是一个对话记录整理助手。请把「已有摘要」和「新增记录」合并成一份新的摘要。
+要求：
+1. 保留双方的关键事实、约定、请求、承诺、时间点和情绪变化；
+2. 区分清楚是用户说的还是对方说的，以及用户向AI咨询过什么、AI给过什么建议；
+3. 只输出摘要正文，不超过500字。
+
+已有摘要：
+{{previous_summary}}
+
+新增记录：
+{{chat_context}}`
+}
@@ -0,0 +1,81 @@
+package summary
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 4, EstimateTokens("明天开会"))
+	assert.Equal(t, 2, EstimateTokens("hello ok"))
+	assert.Equal(t, 3, EstimateTokens("好的ok"))
+}
+
+func TestSplitRecent(t *testing.T) {
+	newMsg := func(id uint, content string) model.ChatMessage {
+		msg := model.ChatMessage{Role: model.MessageRoleFriend, MsgType: model.MessageTypeHistory, Content: content}
+		msg.ID = id
+		return msg
+	}
+	// 每条消息 "朋友:xxxx" 估算为 2 + 1 + 4 = 7 个 token
+	messages := []model.ChatMessage{
+		newMsg(1, "第一条呀"),
+		newMsg(2, "第二条呀"),
+		newMsg(3, "第三条呀"),
+	}
+
+	older, recent := SplitRecent(messages, 14)
+	assert.Equal(t, []uint{1}, ids(older))
+	assert.Equal(t, []uint{2, 3}, ids(recent))
+
+	// 预算不足一条时仍保留最后一条
+	older, recent = SplitRecent(messages, 1)
+	assert.Equal(t, []uint{1, 2}, ids(older))
+	assert.Equal(t, []uint{3}, ids(recent))
+
+	older, recent = SplitRecent(messages, 100)
+	assert.Empty(t, older)
+	assert.Equal(t, []uint{1, 2, 3}, ids(recent))
+}
+
+func ids(messages []model.ChatMessage) []uint {
+	result := make([]uint, 0, len(messages))
+	for _, msg := range messages {
+		result = append(result, msg.ID)
+	}
+	return result
+}
+
+func TestSplitAround(t *testing.T) {
+	newMsg := func(id uint) model.ChatMessage {
+		msg := model.ChatMessage{Role: model.MessageRoleFriend, MsgType: model.MessageTypeHistory, Content: "消息内容"}
+		msg.ID = id
+		return msg
+	}
+	// 每条消息估算为 7 个 token
+	messages := []model.ChatMessage{newMsg(1), newMsg(2), newMsg(3), newMsg(4), newMsg(5)}
+
+	// 前后交替扩展，目标消息之后的上下文同样保留
+	older, window := SplitAround(messages, 2, 21)
+	assert.Equal(t, []uint{1}, ids(older))
+	assert.Equal(t, []uint{2, 3, 4}, ids(window))
+
+	// 后面没有更多消息时继续向前扩展
+	older, window = SplitAround(messages, 4, 21)
+	assert.Equal(t, []uint{1, 2}, ids(older))
+	assert.Equal(t, []uint{3, 4, 5}, ids(window))
+
+	// 前面没有更多消息时继续向后扩展
+	older, window = SplitAround(messages, 0, 21)
+	assert.Empty(t, older)
+	assert.Equal(t, []uint{1, 2, 3}, ids(window))
+
+	// 预算不足一条时仍保留目标消息
+	older, window = SplitAround(messages, 3, 1)
+	assert.Equal(t, []uint{1, 2, 3}, ids(older))
+	assert.Equal(t, []uint{4}, ids(window))
+}
@@ -0,0 +1,193 @@
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"app_server/domain/attachment"
+	"app_server/model"
+	"app_server/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+// 默认保留 30 天，每小时清理一次
+const (
+	defaultRetentionDays = 30
+	defaultPurgeInterval = time.Hour
+	purgeBatchSize       = 500
+)
+
+// 回收站中的条目类型
+const (
+	ItemTypeMessage = "MESSAGE"
+	ItemTypeSession = "SESSION"
+	ItemTypeProfile = "PROFILE"
+)
+
+var conf Config
+
+type Config struct {
+	RetentionDays int           `mapstructure:"retention_days"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+func Init(cfg Config) {
+	conf = cfg
+}
+
+// Retention 回收站中的条目保留时长，超过后会被物理删除
+func Retention() time.Duration {
+	days := conf.RetentionDays
+	if days <= 0 {
+		days = defaultRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// DeleteSession 软删除会话，会话中的消息使用相同的删除时间一起软删除，恢复会话时一起恢复
+func DeleteSession(tx *gorm.DB, userID, sessionID uint) error {
+	now := time.Now()
+	result := tx.Model(&model.ChatSession{}).
+		Where("id = ? AND user_id = ?", sessionID, userID).
+		Update("deleted_at", now)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return tx.Model(&model.ChatMessage{}).
+		Where("session_id = ? AND user_id = ?", sessionID, userID).
+		Update("deleted_at", now).Error
+}
+
+// PurgeMessages 物理删除消息及其修改记录、反馈、情感分析结果和附件
+func PurgeMessages(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := attachment.Release(tx, ids); err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id IN ?", ids).Delete(&model.ChatMessageRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id IN ?", ids).Delete(&model.MessageFeedback{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id IN ?", ids).Delete(&model.MessageSentiment{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&model.ChatMessage{}).Error
+}
+
+// PurgeSessions 物理删除会话及会话中的全部消息、分支选择、摘要和报告
+func PurgeSessions(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	for {
+		var messageIDs []uint
+		if err := tx.Unscoped().Model(&model.ChatMessage{}).
+			Where("session_id IN ?", ids).
+			Limit(purgeBatchSize).
+			Pluck("id", &messageIDs).Error; err != nil {
+			return err
+		}
+		if len(messageIDs) == 0 {
+			break
+		}
+		if err := PurgeMessages(tx, messageIDs); err != nil {
+			return err
+		}
+	}
+	if err := tx.Unscoped().Where("session_id IN ?", ids).Delete(&model.ChatBranchSelection{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("session_id IN ?", ids).Delete(&model.ChatSummary{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("session_id IN ?", ids).Delete(&model.SessionReport{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&model.ChatSession{}).Error
+}
+
+// PurgeProfiles 物理删除 Profile，引用它的会话不再关联 Profile
+func PurgeProfiles(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Model(&model.ChatSession{}).
+		Where("profile_id IN ?", ids).
+		Update("profile_id", 0).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&model.Profile{}).Error
+}
+
+// PurgeExpired 物理删除超过保留时长的回收站条目，返回删除的条目数量
+func PurgeExpired(ctx context.Context) (int, error) {
+	database := db.GetDB().WithContext(ctx)
+	before := time.Now().Add(-Retention())
+
+	var total int
+	purge := func(table any, purgeFn func(tx *gorm.DB, ids []uint) error) error {
+		for {
+			var ids []uint
+			if err := database.Unscoped().Model(table).
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+				Limit(purgeBatchSize).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			if err := database.Transaction(func(tx *gorm.DB) error {
+				return purgeFn(tx, ids)
+			}); err != nil {
+				return err
+			}
+			total += len(ids)
+		}
+	}
+
+	// 先删除会话，会话中的消息随会话一起删除
+	if err := purge(&model.ChatSession{}, PurgeSessions); err != nil {
+		return total, err
+	}
+	if err := purge(&model.ChatMessage{}, PurgeMessages); err != nil {
+		return total, err
+	}
+	if err := purge(&model.Profile{}, PurgeProfiles); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// StartPurgeJob 在后台定期清理过期的回收站条目，ctx 取消后退出
+func StartPurgeJob(ctx context.Context) {
+	interval := conf.PurgeInterval
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			count, err := PurgeExpired(ctx)
+			if err != nil {
+				slog.Error("purge expired trash error", "error", err)
+			} else if count > 0 {
+				slog.Info("purged expired trash", "count", count)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
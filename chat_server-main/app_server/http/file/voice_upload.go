@@ -0,0 +1,144 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"app_server/service/auth"
+	"app_server/service/message"
+)
+
+// 转写接口支持的音频格式和大小上限
+const maxVoiceFileSize = 25 << 20
+
+var voiceExts = []string{".mp3", ".m4a", ".wav", ".aac", ".amr", ".ogg", ".opus", ".webm", ".flac", ".mp4", ".mpeg", ".mpga"}
+
+// VoiceMessageUpload 上传语音，转写为文字后保存为聊天记录，转写成功后原音频作为消息附件保存
+// 表单字段：file 音频文件，session_id 会话ID，role 发送方 SELF 或 FRIEND，msg_at 发送时间（可选，RFC3339）
+func VoiceMessageUpload(c *gin.Context) {
+	userID, err := auth.ParseUserID(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code": -1,
+			"msg":  "认证失败: " + err.Error(),
+		})
+		return
+	}
+
+	uploadFile, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": -1,
+			"msg":  "获取上传文件失败: " + err.Error(),
+		})
+		return
+	}
+	defer uploadFile.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !slices.Contains(voiceExts, ext) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": -1,
+			"msg":  "不支持的音频格式: " + ext,
+		})
+		return
+	}
+	if header.Size > maxVoiceFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": -1,
+			"msg":  fmt.Sprintf("音频文件不能超过 %dMB", maxVoiceFileSize>>20),
+		})
+		return
+	}
+
+	req := message.VoiceMessageRequest{
+		Role:     c.PostForm("role"),
+		Filename: header.Filename,
+	}
+	if req.SessionID, err = parseUintForm(c.PostForm("session_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": -1,
+			"msg":  "session_id 无效",
+		})
+		return
+	}
+	if msgAt := c.PostForm("msg_at"); msgAt != "" {
+		if req.MsgAt, err = time.Parse(time.RFC3339, msgAt); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code": -1,
+				"msg":  "msg_at 无效: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.Audio, err = io.ReadAll(uploadFile); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code": -1,
+			"msg":  "读取文件内容失败: " + err.Error(),
+		})
+		return
+	}
+	if req.ContentType = header.Header.Get("Content-Type"); req.ContentType == "" {
+		req.ContentType = "application/octet-stream"
+	}
+
+	msg, err := message.CreateVoiceMessage(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(httpStatus(connect.CodeOf(err)), gin.H{
+			"code": -1,
+			"msg":  "语音转写失败: " + err.Error(),
+		})
+		return
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code": -1,
+			"msg":  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "转写成功",
+		"data": json.RawMessage(data),
+	})
+}
+
+func parseUintForm(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid id %q", value)
+	}
+	return uint(id), nil
+}
+
+// httpStatus connect 错误码对应的 HTTP 状态码
+func httpStatus(code connect.Code) int {
+	switch code {
+	case connect.CodeInvalidArgument:
+		return http.StatusBadRequest
+	case connect.CodeNotFound:
+		return http.StatusNotFound
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
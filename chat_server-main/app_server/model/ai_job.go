@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// AIJob 后台执行的 AI 任务，请求和结果使用 proto 编码保存
+type AIJob struct {
+	ID          uint       `gorm:"primarykey"`
+	UserID      uint       `gorm:"index:idx_ai_job_user_status"`
+	Type        string     `gorm:"size:32"`
+	Status      string     `gorm:"size:16;index:idx_ai_job_user_status;index:idx_ai_job_status_run"`
+	Payload     []byte     `gorm:"type:mediumblob"` // proto 编码的请求
+	Result      []byte     `gorm:"type:mediumblob"` // proto 编码的响应
+	Error       string     `gorm:"type:text"`       // 最近一次失败的原因
+	Attempts    int        // 已执行的次数
+	MaxAttempts int        // 最多执行的次数
+	NextRunAt   time.Time  `gorm:"index:idx_ai_job_status_run"` // 等待中的任务最早在该时间执行
+	LockedUntil *time.Time // 执行中的任务超过该时间仍未结束，视为执行者已退出，可以重新执行
+	StartedAt   *time.Time
+	FinishedAt  *time.Time `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (AIJob) TableName() string {
+	return "ai_job"
+}
+
+const (
+	AIJobStatusPending   = "PENDING"
+	AIJobStatusRunning   = "RUNNING"
+	AIJobStatusSucceeded = "SUCCEEDED"
+	AIJobStatusFailed    = "FAILED"
+	AIJobStatusCanceled  = "CANCELED"
+)
+
+const (
+	AIJobTypeConsult    = "CONSULT"
+	AIJobTypeTranslate  = "TRANSLATE"
+	AIJobTypeParseImage = "PARSE_IMAGE"
+)
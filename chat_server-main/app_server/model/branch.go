@@ -0,0 +1,17 @@
+package model
+
+import "gorm.io/gorm"
+
+// ChatBranchSelection 咨询对话树中用户选择的分支，没有选择时默认使用最新的分支
+type ChatBranchSelection struct {
+	gorm.Model
+	UserID    uint   `json:"user_id"`
+	SessionID uint   `json:"session_id" gorm:"uniqueIndex:idx_branch_group"`
+	GroupID   uint   `json:"group_id" gorm:"uniqueIndex:idx_branch_group"` // USER 分支为原始咨询消息ID，AI 分支为所回复的咨询消息ID
+	Role      string `json:"role" gorm:"uniqueIndex:idx_branch_group"`     // 分支中消息的角色：USER、AI
+	ActiveID  uint   `json:"active_id"`
+}
+
+func (ChatBranchSelection) TableName() string {
+	return "chat_branch_selection"
+}
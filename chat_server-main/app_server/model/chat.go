@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"app_server/pkg/fn"
 	"app_server/proto/chat"
 
@@ -15,17 +17,33 @@ type ChatSession struct {
 	UserID    uint   `json:"user_id"`
 	ProfileID uint   `json:"profile_id"`
 	Avatar    string `json:"avatar"`
+
+	// 根据会话内容自动生成的标题和摘要，消息增加后定期刷新
+	AutoTitle       string     `json:"auto_title"`
+	AutoSummary     string     `json:"auto_summary" gorm:"type:text"`
+	AutoMsgCount    int        `json:"auto_msg_count"` // 上次生成时会话中的消息数
+	AutoGeneratedAt *time.Time `json:"auto_generated_at"`
+	PinnedTitle     string     `json:"pinned_title"` // 用户手动设置的标题，不为空时不使用自动标题
+}
+
+// Title 会话的标题，优先使用手动设置的标题
+func (c ChatSession) Title() string {
+	return lo.Ternary(c.PinnedTitle != "", c.PinnedTitle, c.AutoTitle)
 }
 
 func (c ChatSession) ToProto() *chat.ChatSession {
 	return &chat.ChatSession{
-		Id:        fn.Itoa(c.ID),
-		Name:      c.Name,
-		ProfileId: lo.Ternary(c.ProfileID == 0, "", fn.Itoa(c.ProfileID)),
-		UserId:    fn.Itoa(c.UserID),
-		Avatar:    c.Avatar,
-		CreatedAt: timestamppb.New(c.CreatedAt),
-		UpdatedAt: timestamppb.New(c.UpdatedAt),
+		Id:          fn.Itoa(c.ID),
+		Name:        c.Name,
+		ProfileId:   lo.Ternary(c.ProfileID == 0, "", fn.Itoa(c.ProfileID)),
+		UserId:      fn.Itoa(c.UserID),
+		Avatar:      c.Avatar,
+		Title:       c.Title(),
+		AutoTitle:   c.AutoTitle,
+		Summary:     c.AutoSummary,
+		TitlePinned: c.PinnedTitle != "",
+		CreatedAt:   timestamppb.New(c.CreatedAt),
+		UpdatedAt:   timestamppb.New(c.UpdatedAt),
 	}
 }
 
@@ -36,9 +54,12 @@ func ChatFromProto(protoChat *chat.ChatSession) *ChatSession {
 			CreatedAt: protoChat.CreatedAt.AsTime(),
 			UpdatedAt: protoChat.UpdatedAt.AsTime(),
 		},
-		Name:      protoChat.Name,
-		UserID:    fn.Atoi[uint](protoChat.UserId),
-		ProfileID: fn.Atoi[uint](protoChat.ProfileId),
-		Avatar:    protoChat.Avatar,
+		Name:        protoChat.Name,
+		UserID:      fn.Atoi[uint](protoChat.UserId),
+		ProfileID:   fn.Atoi[uint](protoChat.ProfileId),
+		Avatar:      protoChat.Avatar,
+		AutoTitle:   protoChat.AutoTitle,
+		AutoSummary: protoChat.Summary,
+		PinnedTitle: lo.Ternary(protoChat.TitlePinned, protoChat.Title, ""),
 	}
 }
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// MessageFeedback 用户对 AI 生成消息的反馈，每个用户对每条消息只保留一条
+type MessageFeedback struct {
+	gorm.Model
+	UserID        uint     `json:"user_id" gorm:"uniqueIndex:idx_feedback_user_message"`
+	MessageID     uint     `json:"message_id" gorm:"uniqueIndex:idx_feedback_user_message"`
+	SessionID     uint     `json:"session_id"`
+	Attitude      string   `json:"attitude"`                       // up, down
+	Reasons       []string `json:"reasons" gorm:"serializer:json"` // 原因分类，见 FeedbackReason*
+	Comment       string   `json:"comment"`                        // 文字反馈
+	MsgType       string   `json:"msg_type"`                       // 被反馈消息的类型
+	PromptKey     string   `json:"prompt_key" gorm:"index"`        // 生成消息使用的提示词
+	PromptVersion string   `json:"prompt_version"`
+	AIModel       string   `json:"ai_model"`
+}
+
+func (MessageFeedback) TableName() string {
+	return "message_feedback"
+}
+
+const (
+	FeedbackAttitudeUp   = "up"
+	FeedbackAttitudeDown = "down"
+)
+
+// 反馈原因分类
+const (
+	FeedbackReasonInaccurate = "inaccurate" // 理解错误、内容不准确
+	FeedbackReasonIrrelevant = "irrelevant" // 答非所问
+	FeedbackReasonUnhelpful  = "unhelpful"  // 没有帮助
+	FeedbackReasonTone       = "tone"       // 语气、情商不合适
+	FeedbackReasonTooLong    = "too_long"   // 太长
+	FeedbackReasonTooShort   = "too_short"  // 太简略
+	FeedbackReasonUnsafe     = "unsafe"     // 不安全、冒犯
+	FeedbackReasonHelpful    = "helpful"    // 有帮助
+	FeedbackReasonOther      = "other"
+)
+
+var FeedbackReasons = []string{
+	FeedbackReasonInaccurate,
+	FeedbackReasonIrrelevant,
+	FeedbackReasonUnhelpful,
+	FeedbackReasonTone,
+	FeedbackReasonTooLong,
+	FeedbackReasonTooShort,
+	FeedbackReasonUnsafe,
+	FeedbackReasonHelpful,
+	FeedbackReasonOther,
+}
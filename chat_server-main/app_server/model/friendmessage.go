@@ -19,7 +19,7 @@ func FriendMessageToConsultMessage(profileID uint, role string, content string)
 		MsgAt:     now,
 		MsgType:   "HISTORY", // 历史消息类型固定为HISTORY
 		Content:   content,
-		Tags:      []string{"friend_message", "profile_" + strconv.Itoa(int(profileID))},
+		Tags:      []string{"friend_message", MessageTagProfilePrefix + strconv.Itoa(int(profileID))},
 	}
 }
 
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// IdempotencyRecord 带 Idempotency-Key 的请求记录，同一用户、接口和 key 只执行一次
+type IdempotencyRecord struct {
+	ID          uint      `gorm:"primarykey"`
+	UserID      uint      `gorm:"uniqueIndex:idx_idempotency_key"`
+	Procedure   string    `gorm:"uniqueIndex:idx_idempotency_key;size:191"`
+	Key         string    `gorm:"uniqueIndex:idx_idempotency_key;size:128"`
+	Fingerprint string    `gorm:"size:64"` // 请求内容的哈希，同一个 key 不能用于不同的请求
+	Status      string    // pending, done
+	Response    []byte    `gorm:"type:mediumblob"` // proto 编码的响应
+	ExpiresAt   time.Time `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_record"
+}
+
+const (
+	IdempotencyStatusPending = "pending"
+	IdempotencyStatusDone    = "done"
+)
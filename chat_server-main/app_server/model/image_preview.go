@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ImageParsePreview 截图解析的预览结果，用户确认后保存为消息，过期未确认的会被删除
+type ImageParsePreview struct {
+	ID        uint             `gorm:"primarykey"`
+	ParseID   string           `gorm:"uniqueIndex;size:32"`
+	UserID    uint             `gorm:"index"`
+	SessionID uint             `gorm:"index"`
+	ImageURLs []string         `gorm:"serializer:json"` // 解析的截图，保存时作为附件
+	Lines     []ImageParseLine `gorm:"serializer:json"`
+	ExpiresAt time.Time        `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (ImageParsePreview) TableName() string {
+	return "image_parse_preview"
+}
+
+// ImageParseLine 截图中识别出的一条聊天记录
+type ImageParseLine struct {
+	Role           string    `json:"role"`
+	SenderName     string    `json:"sender_name"`
+	Content        string    `json:"content"`
+	MsgAt          time.Time `json:"msg_at"`
+	TimeText       string    `json:"time_text"`
+	DuplicateOf    uint      `json:"duplicate_of"`     // 重复的已有消息，不重复时为 0
+	AfterMessageID uint      `json:"after_message_id"` // 按发送时间排在该消息之后
+	Image          int       `json:"image"`            // 来源截图在 ImageURLs 中的序号，从 1 开始，0 表示没有来源截图
+}
+
+func (l ImageParseLine) ToProto() *message.ParsedImageLine {
+	return &message.ParsedImageLine{
+		Role:           l.Role,
+		SenderName:     l.SenderName,
+		Content:        l.Content,
+		MsgAt:          timestamppb.New(l.MsgAt),
+		TimeText:       l.TimeText,
+		Duplicate:      l.DuplicateOf > 0,
+		DuplicateOf:    lo.Ternary(l.DuplicateOf > 0, fn.Itoa(l.DuplicateOf), ""),
+		AfterMessageId: lo.Ternary(l.AfterMessageID > 0, fn.Itoa(l.AfterMessageID), ""),
+		Image:          int32(l.Image),
+	}
+}
@@ -2,7 +2,9 @@ package model
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"app_server/pkg/fn"
@@ -17,12 +19,21 @@ type ChatMessage struct {
 	UserID    uint      `json:"user_id"`
 	SessionID uint      `json:"session_id"`
 	ParentID  uint      `json:"parent_id"`
+	PrevID    uint      `json:"prev_id"` // CONSULT 对话树中的上一条消息，旧数据为 0
 	ProfileID uint      `json:"profile_id"`
 	Role      string    `json:"role"`
 	MsgType   string    `json:"msg_type"`
-	Content   string    `json:"content"`
-	Tags      []string  `json:"tags" gorm:"serializer:json"`
+	Content   string    `json:"content" gorm:"index:idx_chat_message_content,class:FULLTEXT,option:WITH PARSER ngram"` // 全文索引使用 ngram 分词以支持中文搜索
+	Tags      []string  `json:"tags" gorm:"serializer:json"`                                                           // 系统标签，只能由服务端设置
 	MsgAt     time.Time `json:"msg_at"`
+
+	SenderName string   `json:"sender_name"`                      // 发送人显示名称，从截图中识别
+	UserTags   []string `json:"user_tags" gorm:"serializer:json"` // 用户标签
+
+	// AI 生成的消息记录使用的提示词和模型，用于反馈分析
+	PromptKey     string `json:"prompt_key"`
+	PromptVersion string `json:"prompt_version"`
+	AIModel       string `json:"ai_model"`
 }
 
 func (ChatMessage) TableName() string {
@@ -31,17 +42,19 @@ func (ChatMessage) TableName() string {
 
 func (m ChatMessage) ToProto() *message.ChatMessage {
 	return &message.ChatMessage{
-		Id:        strconv.Itoa(int(m.ID)),
-		Content:   m.Content,
-		UserId:    fn.Itoa(m.UserID),
-		Role:      m.Role,
-		MsgAt:     timestamppb.New(m.MsgAt),
-		MsgType:   m.MsgType,
-		SessionId: fn.Itoa(m.SessionID),
-		ParentId:  fn.Itoa(m.ParentID),
-		Tags:      m.Tags,
-		CreatedAt: timestamppb.New(m.CreatedAt),
-		UpdatedAt: timestamppb.New(m.UpdatedAt),
+		Id:         strconv.Itoa(int(m.ID)),
+		Content:    m.Content,
+		UserId:     fn.Itoa(m.UserID),
+		Role:       m.Role,
+		MsgAt:      timestamppb.New(m.MsgAt),
+		MsgType:    m.MsgType,
+		SessionId:  fn.Itoa(m.SessionID),
+		ParentId:   fn.Itoa(m.ParentID),
+		Tags:       m.Tags,
+		CreatedAt:  timestamppb.New(m.CreatedAt),
+		UpdatedAt:  timestamppb.New(m.UpdatedAt),
+		SenderName: m.SenderName,
+		UserTags:   m.UserTags,
 	}
 }
 
@@ -66,13 +79,15 @@ func (m ChatMessage) ToConsultProto() *message.ConsultMessage {
 // ChatMessageFromProto 从新的 ChatMessage proto 转换
 func ChatMessageFromProto(protoMessage *message.ChatMessage) *ChatMessage {
 	m := &ChatMessage{
-		Content:   protoMessage.Content,
-		UserID:    fn.Atoi[uint](protoMessage.UserId),
-		Role:      protoMessage.Role,
-		MsgType:   protoMessage.MsgType,
-		ParentID:  fn.Atoi[uint](protoMessage.ParentId),
-		SessionID: fn.Atoi[uint](protoMessage.SessionId),
-		Tags:      protoMessage.Tags,
+		Content:    protoMessage.Content,
+		UserID:     fn.Atoi[uint](protoMessage.UserId),
+		Role:       protoMessage.Role,
+		MsgType:    protoMessage.MsgType,
+		ParentID:   fn.Atoi[uint](protoMessage.ParentId),
+		SessionID:  fn.Atoi[uint](protoMessage.SessionId),
+		Tags:       protoMessage.Tags,
+		SenderName: protoMessage.SenderName,
+		UserTags:   protoMessage.UserTags,
 	}
 
 	if protoMessage.Id != "" {
@@ -163,3 +178,32 @@ const (
 	MessageTypeTranslate = "TRANSLATE"
 	MessageTypeConsult   = "CONSULT"
 )
+
+const (
+	MessageTagInterrupted   = "interrupted" // 流式生成被中断，内容不完整
+	MessageTagVoice         = "voice"       // 由语音转写
+	MessageTagImported      = "imported"    // 从导出的聊天记录导入
+	MessageTagSuggested     = "suggested"   // 保存的回复建议
+	MessageTagProfilePrefix = "profile_"    // 好友消息所属的 Profile，后接 Profile ID
+)
+
+// SystemTags 服务端设置的固定标签，用户标签不能使用这些名称
+// 语气、翻译目标语言等取值不固定的系统标签不在其中，统计时按所在的字段区分系统标签和用户标签
+var SystemTags = []string{
+	"demo",
+	"ai_reply",
+	"parsed_from_image",
+	"disable_interact",
+	"friend_message",
+	MessageTagSuggested,
+	FeedbackAttitudeUp,
+	FeedbackAttitudeDown,
+	MessageTagInterrupted,
+	MessageTagVoice,
+	MessageTagImported,
+}
+
+// IsSystemTag 是否为保留的系统标签名称
+func IsSystemTag(tag string) bool {
+	return slices.Contains(SystemTags, tag) || strings.HasPrefix(tag, MessageTagProfilePrefix)
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// ChatMessageAttachment 消息附件，引用用户上传的文件，同一文件可以被多条消息引用
+type ChatMessageAttachment struct {
+	ID        uint `gorm:"primarykey"`
+	UserID    uint `gorm:"index"`
+	MessageID uint `gorm:"index"`
+	FileID    uint `gorm:"index"`
+	Sort      int  // 附件在消息中的顺序
+	CreatedAt time.Time
+}
+
+func (ChatMessageAttachment) TableName() string {
+	return "chat_message_attachment"
+}
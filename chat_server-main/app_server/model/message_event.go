@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ChatMessageEvent 消息变更事件，多实例部署时用于在实例之间分发事件，只保留很短的时间
+type ChatMessageEvent struct {
+	ID        uint `gorm:"primarykey"`
+	UserID    uint
+	Payload   string    `gorm:"type:mediumtext"` // JSON 格式的事件
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (ChatMessageEvent) TableName() string {
+	return "chat_message_event"
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// ChatMessageRevision 消息修改记录，保存每次修改前的内容
+type ChatMessageRevision struct {
+	gorm.Model
+	MessageID      uint     `json:"message_id" gorm:"index"`
+	UserID         uint     `json:"user_id"`
+	SessionID      uint     `json:"session_id"`
+	EditorID       uint     `json:"editor_id"` // 修改人
+	Role           string   `json:"role"`
+	MsgType        string   `json:"msg_type"`
+	Content        string   `json:"content"`
+	Tags           []string `json:"tags" gorm:"serializer:json"`
+	ContentChanged bool     `json:"content_changed"` // 本次修改是否改变了 content
+}
+
+func (ChatMessageRevision) TableName() string {
+	return "chat_message_revision"
+}
+
+func (r ChatMessageRevision) ToProto() *message.ChatMessageRevision {
+	return &message.ChatMessageRevision{
+		Id:        fn.Itoa(r.ID),
+		MessageId: fn.Itoa(r.MessageID),
+		EditorId:  fn.Itoa(r.EditorID),
+		Role:      r.Role,
+		MsgType:   r.MsgType,
+		Content:   r.Content,
+		Tags:      r.Tags,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+}
+
+// NewRevisionFromMessage 用消息当前的内容生成一条修改记录
+func NewRevisionFromMessage(m ChatMessage, editorID uint) ChatMessageRevision {
+	return ChatMessageRevision{
+		MessageID: m.ID,
+		UserID:    m.UserID,
+		SessionID: m.SessionID,
+		EditorID:  editorID,
+		Role:      m.Role,
+		MsgType:   m.MsgType,
+		Content:   m.Content,
+		Tags:      m.Tags,
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+import "gorm.io/gorm"
+
+// MessageSentiment 聊天记录的情感分析结果，每条消息缓存一条，内容修改后失效
+type MessageSentiment struct {
+	gorm.Model
+	UserID      uint     `json:"user_id" gorm:"index"`
+	SessionID   uint     `json:"session_id" gorm:"index"`
+	MessageID   uint     `json:"message_id" gorm:"uniqueIndex"`
+	ContentHash string   `json:"content_hash" gorm:"size:64"` // 分析时消息内容的哈希，与当前内容不一致时视为过期
+	Sentiment   string   `json:"sentiment"`                   // positive, neutral, negative
+	Score       float64  `json:"score"`                       // 情感倾向，-1 最消极，1 最积极
+	Emotions    []string `json:"emotions" gorm:"serializer:json"`
+}
+
+func (MessageSentiment) TableName() string {
+	return "message_sentiment"
+}
+
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
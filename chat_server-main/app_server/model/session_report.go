@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"app_server/pkg/fn"
+	"app_server/proto/chat"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// SessionReport 会话在一段时间内的摘要报告，同一会话同一时间段只保留一份
+type SessionReport struct {
+	gorm.Model
+	UserID       uint             `json:"user_id" gorm:"index"`
+	SessionID    uint             `json:"session_id" gorm:"uniqueIndex:idx_session_report_period"`
+	PeriodStart  time.Time        `json:"period_start" gorm:"uniqueIndex:idx_session_report_period"`
+	PeriodEnd    time.Time        `json:"period_end" gorm:"uniqueIndex:idx_session_report_period"`
+	Trigger      string           `json:"trigger"` // manual, scheduled
+	MessageCount int              `json:"message_count"`
+	Summary      string           `json:"summary" gorm:"type:text"`
+	Topics       []string         `json:"topics" gorm:"serializer:json"`
+	OpenItems    []ReportOpenItem `json:"open_items" gorm:"serializer:json"` // 尚未完成的请求和承诺
+	ToneChanges  string           `json:"tone_changes" gorm:"type:text"`
+	NextSteps    []string         `json:"next_steps" gorm:"serializer:json"`
+}
+
+func (SessionReport) TableName() string {
+	return "session_report"
+}
+
+// SessionReportAttempt 定时生成报告失败的记录，用于退避重试，生成成功后删除
+type SessionReportAttempt struct {
+	gorm.Model
+	UserID        uint      `json:"user_id" gorm:"index"`
+	SessionID     uint      `json:"session_id" gorm:"uniqueIndex:idx_session_report_attempt_period"`
+	PeriodStart   time.Time `json:"period_start" gorm:"uniqueIndex:idx_session_report_attempt_period"`
+	PeriodEnd     time.Time `json:"period_end" gorm:"uniqueIndex:idx_session_report_attempt_period"`
+	Attempts      int       `json:"attempts"` // 连续失败的次数
+	LastError     string    `json:"last_error" gorm:"type:text"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index"` // 在此之前不再重试
+}
+
+func (SessionReportAttempt) TableName() string {
+	return "session_report_attempt"
+}
+
+// ReportOpenItem 报告中尚未完成的请求或承诺
+type ReportOpenItem struct {
+	Kind    string `json:"kind"`  // request, promise
+	Owner   string `json:"owner"` // 需要履行的一方：SELF, FRIEND
+	Content string `json:"content"`
+}
+
+const (
+	ReportTriggerManual    = "manual"
+	ReportTriggerScheduled = "scheduled"
+)
+
+const (
+	ReportItemRequest = "request"
+	ReportItemPromise = "promise"
+)
+
+func (r SessionReport) ToProto() *chat.SessionReport {
+	return &chat.SessionReport{
+		Id:           fn.Itoa(r.ID),
+		SessionId:    fn.Itoa(r.SessionID),
+		PeriodStart:  timestamppb.New(r.PeriodStart),
+		PeriodEnd:    timestamppb.New(r.PeriodEnd),
+		Trigger:      r.Trigger,
+		MessageCount: int32(r.MessageCount),
+		Summary:      r.Summary,
+		Topics:       r.Topics,
+		OpenItems: fn.Map(r.OpenItems, func(item ReportOpenItem) *chat.ReportOpenItem {
+			return &chat.ReportOpenItem{
+				Kind:    item.Kind,
+				Owner:   item.Owner,
+				Content: item.Content,
+			}
+		}),
+		ToneChanges: r.ToneChanges,
+		NextSteps:   r.NextSteps,
+		CreatedAt:   timestamppb.New(r.CreatedAt),
+		UpdatedAt:   timestamppb.New(r.UpdatedAt),
+	}
+}
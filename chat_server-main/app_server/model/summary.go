@@ -0,0 +1,18 @@
+package model
+
+import "gorm.io/gorm"
+
+// ChatSummary 会话滚动摘要，覆盖 id <= UntilMsgID 的所有消息
+type ChatSummary struct {
+	gorm.Model
+	UserID     uint   `json:"user_id" gorm:"index"`
+	SessionID  uint   `json:"session_id" gorm:"uniqueIndex"`
+	Content    string `json:"content" gorm:"type:text"`
+	UntilMsgID uint   `json:"until_msg_id"`               // 摘要覆盖到的最后一条消息ID
+	Tokens     int    `json:"tokens"`                     // 摘要覆盖的原始消息估算 token 数
+	Stale      bool   `json:"stale" gorm:"default:false"` // 被覆盖的消息有修改或删除，需要重建
+}
+
+func (ChatSummary) TableName() string {
+	return "chat_summary"
+}
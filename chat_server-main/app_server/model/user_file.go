@@ -39,6 +39,8 @@ const (
 	UsageTypeAvatar     = "avatar"      // 头像
 	UsageTypeChatImage  = "chat_image"  // 聊天图片
 	UsageTypeTempUpload = "temp_upload" // 临时上传
+	UsageTypeChatExport = "chat_export" // 会话导出
+	UsageTypeChatAudio  = "chat_audio"  // 聊天语音
 )
 
 
@@ -50,8 +52,8 @@ func GetExpirationTime(usageType string) *time.Time {
 		// 临时文件：24小时后过期
 		expTime := now.Add(24 * time.Hour)
 		return &expTime
-	case UsageTypeChatImage:
-		// 聊天图片：30天后过期
+	case UsageTypeChatImage, UsageTypeChatAudio:
+		// 聊天图片、语音：30天后过期
 		expTime := now.Add(30 * 24 * time.Hour)
 		return &expTime
 	case UsageTypeAvatar:
@@ -1,6 +1,8 @@
 package aiapi
 
 import (
+	"app_server/pkg/fn"
+	"app_server/pkg/oai"
 	"app_server/pkg/openaic"
 	"context"
 	"errors"
@@ -9,8 +11,23 @@ import (
 	"github.com/sashabaranov/go-openai"
 )
 
+// ChatLine 从截图中识别出的一条聊天记录
+type ChatLine struct {
+	Role    string `json:"role"`   // FRIEND 或 SELF
+	Sender  string `json:"sender"` // 截图中显示的发送人昵称，没有显示时为空
+	Time    string `json:"time"`   // 这条消息之前最近的时间分隔文字，如「昨天 14:32」，没有时为空
+	Content string `json:"content"`
+}
+
+const parseImagePrompt = `提取图中的聊天记录，忽略引用的内容。
+右侧气泡是自己发的，role 为 SELF；左侧气泡是对方发的，role 为 FRIEND。
+sender 为气泡旁显示的发送人昵称，没有显示时留空。
+time 为这条消息上方最近的时间分隔文字，原样输出，如「14:32」「昨天 下午2:32」「星期一 09:15」「3月5日 14:32」，没有时留空。
+只输出 JSON 数组，按从上到下的顺序，格式如下：
+[{"role":"FRIEND","sender":"","time":"","content":""}]`
+
 // ParseImageChat 解析图片中的聊天内容
-func ParseImageChat(imageUrl string) ([]string, error) {
+func ParseImageChat(imageUrl string) ([]ChatLine, error) {
 	// 构建请求
 	resp, err := openaic.Get().CreateChatCompletion(
 		context.Background(),
@@ -22,7 +39,7 @@ func ParseImageChat(imageUrl string) ([]string, error) {
 					MultiContent: []openai.ChatMessagePart{
 						{
 							Type: openai.ChatMessagePartTypeText,
-							Text: "提取图中的聊天记录。忽略引用的内容。每行一句，每行下列格式开始\n【朋友】xxx\n【自己】yyy",
+							Text: parseImagePrompt,
 						},
 						{
 							Type: openai.ChatMessagePartTypeImageURL,
@@ -45,19 +62,31 @@ func ParseImageChat(imageUrl string) ([]string, error) {
 		return nil, errors.New("未获取到解析结果")
 	}
 
-	// 解析聊天记录，按行分割
-	content := resp.Choices[0].Message.Content
-	lines := strings.Split(content, "\n")
+	return parseChatContent(resp.Choices[0].Message.Content), nil
+}
 
-	var chatLines []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && (strings.HasPrefix(line, "【朋友】") || strings.HasPrefix(line, "【自己】")) {
-			chatLines = append(chatLines, line)
+// parseChatContent 解析模型输出，不是 JSON 时按旧的【朋友】【自己】逐行格式解析
+func parseChatContent(content string) []ChatLine {
+	var chatLines []ChatLine
+	if lines, err := fn.JsonUnmarshalStr[[]ChatLine](oai.ExtractJSON(content)); err == nil {
+		for _, line := range lines {
+			line.Role = strings.ToUpper(strings.TrimSpace(line.Role))
+			line.Sender = strings.TrimSpace(line.Sender)
+			line.Time = strings.TrimSpace(line.Time)
+			line.Content = strings.TrimSpace(line.Content)
+			if (line.Role == "FRIEND" || line.Role == "SELF") && line.Content != "" {
+				chatLines = append(chatLines, line)
+			}
 		}
+		return chatLines
 	}
 
-	return chatLines, nil
+	for _, line := range strings.Split(content, "\n") {
+		if role, content, ok := ParseChatLine(line); ok {
+			chatLines = append(chatLines, ChatLine{Role: role, Content: content})
+		}
+	}
+	return chatLines
 }
 
 // 解析单行聊天记录，返回角色和内容
@@ -68,9 +97,9 @@ func ParseChatLine(line string) (role string, content string, ok bool) {
 	}
 
 	// 检查【朋友】或【自己】格式
-	if after, ok0 :=strings.CutPrefix(line, "【朋友】"); ok0  {
+	if after, ok0 := strings.CutPrefix(line, "【朋友】"); ok0 {
 		return "FRIEND", after, true
-	} else if after, ok0 :=strings.CutPrefix(line, "【自己】"); ok0  {
+	} else if after, ok0 := strings.CutPrefix(line, "【自己】"); ok0 {
 		return "SELF", after, true
 	}
 
@@ -0,0 +1,32 @@
+package aiapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChatContentJSON(t *testing.T) {
+	content := "```json\n" + `[
+  {"role":"FRIEND","sender":"小王","time":"昨天 14:32","content":"在吗"},
+  {"role":"self","sender":"","time":"","content":" 在的 "},
+  {"role":"SYSTEM","sender":"","time":"","content":"以下是新消息"},
+  {"role":"FRIEND","sender":"小王","time":"","content":""}
+]` + "\n```"
+
+	lines := parseChatContent(content)
+	assert.Equal(t, []ChatLine{
+		{Role: "FRIEND", Sender: "小王", Time: "昨天 14:32", Content: "在吗"},
+		{Role: "SELF", Content: "在的"},
+	}, lines)
+}
+
+func TestParseChatContentLegacy(t *testing.T) {
+	content := "以下是聊天记录\n【朋友】晚上吃什么 [微笑]\n【自己】火锅\n其他内容"
+
+	lines := parseChatContent(content)
+	assert.Equal(t, []ChatLine{
+		{Role: "FRIEND", Content: "晚上吃什么 [微笑]"},
+		{Role: "SELF", Content: "火锅"},
+	}, lines)
+}
@@ -0,0 +1,147 @@
+package chattime
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	clockRe     = regexp.MustCompile(`(?i)(上午|下午|晚上|凌晨|中午|早上|傍晚|am|pm)?\s*(\d{1,2}):(\d{2})(?::\d{2})?\s*(am|pm)?`)
+	fullDateRe  = regexp.MustCompile(`^(\d{4})\s*[年/.\-]\s*(\d{1,2})\s*[月/.\-]\s*(\d{1,2})\s*日?$`)
+	shortDateRe = regexp.MustCompile(`^(\d{1,2})\s*[月/.\-]\s*(\d{1,2})\s*日?$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"日": time.Sunday, "天": time.Sunday, "一": time.Monday, "二": time.Tuesday, "三": time.Wednesday,
+	"四": time.Thursday, "五": time.Friday, "六": time.Saturday,
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Resolve 解析聊天截图中显示的时间，例如「14:32」「昨天 下午2:32」「星期一 09:15」「3月5日 14:32」「2024/3/5 14:32」
+// 相对日期按 ref 所在的日期和时区计算，ref 一般为截图上传的时间
+func Resolve(raw string, ref time.Time) (time.Time, bool) {
+	raw = strings.TrimSpace(strings.ReplaceAll(raw, "：", ":"))
+	if raw == "" {
+		return time.Time{}, false
+	}
+
+	hour, minute := 0, 0
+	datePart := raw
+	hasClock := false
+	if m := clockRe.FindStringSubmatchIndex(raw); m != nil {
+		hasClock = true
+		hour, _ = strconv.Atoi(raw[m[4]:m[5]])
+		minute, _ = strconv.Atoi(raw[m[6]:m[7]])
+		var period string
+		if m[2] >= 0 {
+			period = raw[m[2]:m[3]]
+		} else if m[8] >= 0 {
+			period = raw[m[8]:m[9]]
+		}
+		hour = adjustHour(hour, strings.ToLower(period))
+		if hour > 23 || minute > 59 {
+			return time.Time{}, false
+		}
+		datePart = strings.TrimSpace(raw[:m[0]] + " " + raw[m[1]:])
+	}
+
+	year, month, day, ok := resolveDate(datePart, ref)
+	if !ok {
+		return time.Time{}, false
+	}
+	t := time.Date(year, month, day, hour, minute, 0, 0, ref.Location())
+
+	// 只显示时间时为当天的消息，比 ref 还晚时说明截图是前一天截的
+	if datePart == "" && hasClock && t.After(ref) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t, true
+}
+
+// adjustHour 按上午、下午等时段把 12 小时制转换为 24 小时制
+func adjustHour(hour int, period string) int {
+	switch period {
+	case "下午", "晚上", "傍晚", "pm":
+		if hour < 12 {
+			return hour + 12
+		}
+	case "中午":
+		if hour < 11 {
+			return hour + 12
+		}
+	case "凌晨", "上午", "早上", "am":
+		if hour == 12 {
+			return 0
+		}
+	}
+	return hour
+}
+
+// resolveDate 解析日期部分，为空时为 ref 当天
+func resolveDate(datePart string, ref time.Time) (int, time.Month, int, bool) {
+	y, m, d := ref.Date()
+	lower := strings.ToLower(datePart)
+
+	switch lower {
+	case "", "今天", "today":
+		return y, m, d, true
+	case "昨天", "yesterday":
+		y, m, d = ref.AddDate(0, 0, -1).Date()
+		return y, m, d, true
+	case "前天":
+		y, m, d = ref.AddDate(0, 0, -2).Date()
+		return y, m, d, true
+	}
+
+	if weekday, ok := parseWeekday(lower); ok {
+		// 显示星期几的是最近一周内、今天之前的日期
+		days := (int(ref.Weekday()) - int(weekday) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		y, m, d = ref.AddDate(0, 0, -days).Date()
+		return y, m, d, true
+	}
+
+	if match := fullDateRe.FindStringSubmatch(datePart); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		day, _ := strconv.Atoi(match[3])
+		return year, time.Month(month), day, validDate(month, day)
+	}
+
+	if match := shortDateRe.FindStringSubmatch(datePart); match != nil {
+		month, _ := strconv.Atoi(match[1])
+		day, _ := strconv.Atoi(match[2])
+		// 不显示年份的是今年的日期，晚于 ref 时为去年
+		year := y
+		if time.Date(year, time.Month(month), day, 0, 0, 0, 0, ref.Location()).After(ref) {
+			year--
+		}
+		return year, time.Month(month), day, validDate(month, day)
+	}
+
+	return 0, 0, 0, false
+}
+
+// parseWeekday 解析「星期一」「周一」「礼拜一」「Monday」「Mon」
+func parseWeekday(s string) (time.Weekday, bool) {
+	for _, prefix := range []string{"星期", "礼拜", "周"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			weekday, ok := weekdays[rest]
+			return weekday, ok
+		}
+	}
+	if len(s) >= 3 {
+		weekday, ok := weekdays[s[:3]]
+		return weekday, ok
+	}
+	return 0, false
+}
+
+func validDate(month, day int) bool {
+	return month >= 1 && month <= 12 && day >= 1 && day <= 31
+}
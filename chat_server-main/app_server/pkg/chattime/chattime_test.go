@@ -0,0 +1,62 @@
+package chattime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-03-06 是星期三
+	ref := time.Date(2024, 3, 6, 15, 0, 0, 0, loc)
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+
+	cases := []struct {
+		raw  string
+		want time.Time
+	}{
+		{"14:32", at(2024, 3, 6, 14, 32)},
+		{"14：32", at(2024, 3, 6, 14, 32)},
+		{"16:10", at(2024, 3, 5, 16, 10)}, // 晚于上传时间，为前一天
+		{"下午2:32", at(2024, 3, 6, 14, 32)},
+		{"上午12:05", at(2024, 3, 6, 0, 5)},
+		{"晚上 9:00", at(2024, 3, 5, 21, 0)},
+		{"中午12:10", at(2024, 3, 6, 12, 10)},
+		{"2:32 PM", at(2024, 3, 6, 14, 32)},
+		{"今天 08:00", at(2024, 3, 6, 8, 0)},
+		{"昨天 23:15", at(2024, 3, 5, 23, 15)},
+		{"Yesterday 9:41 AM", at(2024, 3, 5, 9, 41)},
+		{"前天 10:00", at(2024, 3, 4, 10, 0)},
+		{"星期一 09:15", at(2024, 3, 4, 9, 15)},
+		{"周三 09:15", at(2024, 2, 28, 9, 15)}, // 与今天同为星期三，为上周
+		{"礼拜日 20:00", at(2024, 3, 3, 20, 0)},
+		{"Friday 18:30", at(2024, 3, 1, 18, 30)},
+		{"3月5日 14:32", at(2024, 3, 5, 14, 32)},
+		{"12月31日 下午3:00", at(2023, 12, 31, 15, 0)}, // 晚于上传时间，为去年
+		{"03-01 08:00", at(2024, 3, 1, 8, 0)},
+		{"2/29 08:00", at(2024, 2, 29, 8, 0)},
+		{"2023年11月2日 10:20", at(2023, 11, 2, 10, 20)},
+		{"2023-11-02 10:20:59", at(2023, 11, 2, 10, 20)},
+		{"2023/11/2", at(2023, 11, 2, 0, 0)},
+		{"昨天", at(2024, 3, 5, 0, 0)},
+	}
+	for _, c := range cases {
+		t.Run(c.raw, func(t *testing.T) {
+			got, ok := Resolve(c.raw, ref)
+			assert.True(t, ok)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	ref := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+	for _, raw := range []string{"", "  ", "以下是新消息", "25:00", "13月1日 10:00", "星期八 10:00", "刚刚"} {
+		_, ok := Resolve(raw, ref)
+		assert.False(t, ok, raw)
+	}
+}
@@ -0,0 +1,21 @@
+package oai
+
+import "strings"
+
+// ExtractJSON 从模型回复中取出 JSON 内容，去掉 markdown 代码块和前后的说明文字
+func ExtractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	start := strings.IndexAny(content, "{[")
+	if start < 0 {
+		return content
+	}
+	closing := "}"
+	if content[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(content, closing)
+	if end < start {
+		return content[start:]
+	}
+	return content[start : end+1]
+}
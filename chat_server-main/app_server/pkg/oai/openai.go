@@ -2,7 +2,9 @@ package oai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -78,6 +80,64 @@ func (c *Client) CreateChatCompletion(ctx context.Context, req ChatCompletionReq
 	return content, nil
 }
 
+// CreateChatCompletionStream 以流式方式调用 OpenAI API，每收到一段增量内容回调一次 onDelta
+// 返回已累积的内容；出错（包括 onDelta 返回错误、ctx 取消）时也会返回已收到的部分内容
+func (c *Client) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(delta string) error) (content string, err error) {
+	// 如果没有指定模型，使用默认模型
+	if req.Model == "" {
+		req.Model = openaic.Model.Chat
+	}
+	req.Stream = true
+
+	// 在 debug 模式下打印请求
+	if c.debug {
+		defer func() {
+			c.logRequest(req, content)
+		}()
+	}
+
+	stream, err := c.client.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   true,
+	})
+	if err != nil {
+		slog.Error("OpenAI API 流式调用失败",
+			"error", err,
+			"model", req.Model,
+		)
+		return "", fmt.Errorf("OpenAI API error: %w", err)
+	}
+	defer stream.Close()
+
+	var sb strings.Builder
+	for {
+		resp, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		if recvErr != nil {
+			return sb.String(), fmt.Errorf("OpenAI API stream error: %w", recvErr)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := resp.Choices[0].Delta.Content
+		sb.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return sb.String(), err
+		}
+	}
+
+	content = sb.String()
+	if content == "" {
+		return "", fmt.Errorf("OpenAI API 返回空响应")
+	}
+
+	return content, nil
+}
+
 // CreateChatCompletionSimple 简化版本的聊天完成请求，接受单个用户消息
 func (c *Client) CreateChatCompletionSimple(ctx context.Context, userPrompt string) (string, error) {
 	messages := []openai.ChatCompletionMessage{
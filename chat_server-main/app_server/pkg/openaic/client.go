@@ -1,6 +1,10 @@
 package openaic
 
-import "github.com/sashabaranov/go-openai"
+import (
+	"cmp"
+
+	"github.com/sashabaranov/go-openai"
+)
 
 var (
 	client *openai.Client
@@ -11,11 +15,16 @@ type Config struct {
 	ApiKey  string `mapstructure:"api_key"`
 	BaseURL string `mapstructure:"base_url"`
 	Models  Models `mapstructure:"models"`
+
+	// 语音转文字接口，不填的项使用上面的 ApiKey 和 BaseURL
+	TranscribeApiKey  string `mapstructure:"transcribe_api_key"`
+	TranscribeBaseURL string `mapstructure:"transcribe_base_url"`
 }
 
 type Models struct {
-	Chat string `mapstructure:"chat"`
-	Ocr  string `mapstructure:"ocr"`
+	Chat       string `mapstructure:"chat"`
+	Ocr        string `mapstructure:"ocr"`
+	Transcribe string `mapstructure:"transcribe"` // 语音转文字
 }
 
 func Init(cfg Config) {
@@ -24,6 +33,15 @@ func Init(cfg Config) {
 	config.BaseURL = cfg.BaseURL
 	Model = cfg.Models
 	client = openai.NewClientWithConfig(config)
+
+	transcribeClient := client
+	if cfg.TranscribeApiKey != "" || cfg.TranscribeBaseURL != "" {
+		// 逐项回退，只配置了其中一项时另一项沿用聊天接口的配置
+		transcribeConfig := openai.DefaultConfig(cmp.Or(cfg.TranscribeApiKey, cfg.ApiKey))
+		transcribeConfig.BaseURL = cmp.Or(cfg.TranscribeBaseURL, cfg.BaseURL)
+		transcribeClient = openai.NewClientWithConfig(transcribeConfig)
+	}
+	transcriber = ClientTranscriber{Client: transcribeClient, Model: cfg.Models.Transcribe}
 }
 
 func Get() *openai.Client {
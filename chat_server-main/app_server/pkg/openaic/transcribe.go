@@ -0,0 +1,45 @@
+package openaic
+
+import (
+	"context"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+var transcriber Transcriber
+
+// Transcriber 语音转文字
+type Transcriber interface {
+	// Transcribe 转写音频，filename 用于接口识别音频格式
+	Transcribe(ctx context.Context, filename string, audio io.Reader) (string, error)
+}
+
+// ClientTranscriber 调用 OpenAI 兼容的 /audio/transcriptions 接口
+type ClientTranscriber struct {
+	Client *openai.Client
+	Model  string
+}
+
+func (t ClientTranscriber) Transcribe(ctx context.Context, filename string, audio io.Reader) (string, error) {
+	resp, err := t.Client.CreateTranscription(ctx, openai.AudioRequest{
+		Model:    t.Model,
+		FilePath: filename,
+		Reader:   audio,
+		Format:   openai.AudioResponseFormatJSON,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// GetTranscriber 返回当前使用的语音转文字实现
+func GetTranscriber() Transcriber {
+	return transcriber
+}
+
+// SetTranscriber 替换语音转文字实现，测试时可以使用本地实现
+func SetTranscriber(t Transcriber) {
+	transcriber = t
+}
@@ -38,7 +38,15 @@ type ChatSession struct {
 	// 创建时间
 	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	// 更新时间
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// 标题，手动设置的标题优先，否则为自动生成的标题，消息较少时为空
+	Title string `protobuf:"bytes,8,opt,name=title,proto3" json:"title,omitempty"`
+	// 自动生成的标题
+	AutoTitle string `protobuf:"bytes,9,opt,name=auto_title,json=autoTitle,proto3" json:"auto_title,omitempty"`
+	// 自动生成的会话摘要
+	Summary string `protobuf:"bytes,10,opt,name=summary,proto3" json:"summary,omitempty"`
+	// 标题是否为手动设置
+	TitlePinned   bool `protobuf:"varint,11,opt,name=title_pinned,json=titlePinned,proto3" json:"title_pinned,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -122,6 +130,34 @@ func (x *ChatSession) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ChatSession) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChatSession) GetAutoTitle() string {
+	if x != nil {
+		return x.AutoTitle
+	}
+	return ""
+}
+
+func (x *ChatSession) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *ChatSession) GetTitlePinned() bool {
+	if x != nil {
+		return x.TitlePinned
+	}
+	return false
+}
+
 type ListChatSessionsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// 分页
@@ -506,7 +542,9 @@ type UpdateChatSessionRequest struct {
 	// 名称
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	// 头像
-	Avatar        string `protobuf:"bytes,3,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Avatar string `protobuf:"bytes,3,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	// 手动设置标题，设置后不会被自动生成的标题覆盖；传空字符串取消，恢复使用自动标题
+	Title         *string `protobuf:"bytes,4,opt,name=title,proto3,oneof" json:"title,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -562,6 +600,13 @@ func (x *UpdateChatSessionRequest) GetAvatar() string {
 	return ""
 }
 
+func (x *UpdateChatSessionRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
 type UpdateChatSessionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChatSession   *ChatSession           `protobuf:"bytes,1,opt,name=chat_session,json=chatSession,proto3" json:"chat_session,omitempty"`
@@ -606,11 +651,449 @@ func (x *UpdateChatSessionResponse) GetChatSession() *ChatSession {
 	return nil
 }
 
+type SessionReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 报告ID
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 聊天会话ID
+	SessionId string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// 报告覆盖的时间段 [period_start, period_end)
+	PeriodStart *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`
+	PeriodEnd   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`
+	// 生成方式 manual, scheduled
+	Trigger string `protobuf:"bytes,5,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	// 时间段内的消息数
+	MessageCount int32 `protobuf:"varint,6,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`
+	// 总体概述
+	Summary string `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
+	// 主要话题
+	Topics []string `protobuf:"bytes,8,rep,name=topics,proto3" json:"topics,omitempty"`
+	// 尚未完成的请求和承诺
+	OpenItems []*ReportOpenItem `protobuf:"bytes,9,rep,name=open_items,json=openItems,proto3" json:"open_items,omitempty"`
+	// 语气和态度的变化
+	ToneChanges string `protobuf:"bytes,10,opt,name=tone_changes,json=toneChanges,proto3" json:"tone_changes,omitempty"`
+	// 建议的下一步
+	NextSteps []string `protobuf:"bytes,11,rep,name=next_steps,json=nextSteps,proto3" json:"next_steps,omitempty"`
+	// 创建时间
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// 更新时间
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionReport) Reset() {
+	*x = SessionReport{}
+	mi := &file_proto_chat_chat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionReport) ProtoMessage() {}
+
+func (x *SessionReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_chat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionReport.ProtoReflect.Descriptor instead.
+func (*SessionReport) Descriptor() ([]byte, []int) {
+	return file_proto_chat_chat_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SessionReport) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionReport) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionReport) GetPeriodStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return nil
+}
+
+func (x *SessionReport) GetPeriodEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return nil
+}
+
+func (x *SessionReport) GetTrigger() string {
+	if x != nil {
+		return x.Trigger
+	}
+	return ""
+}
+
+func (x *SessionReport) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *SessionReport) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *SessionReport) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *SessionReport) GetOpenItems() []*ReportOpenItem {
+	if x != nil {
+		return x.OpenItems
+	}
+	return nil
+}
+
+func (x *SessionReport) GetToneChanges() string {
+	if x != nil {
+		return x.ToneChanges
+	}
+	return ""
+}
+
+func (x *SessionReport) GetNextSteps() []string {
+	if x != nil {
+		return x.NextSteps
+	}
+	return nil
+}
+
+func (x *SessionReport) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SessionReport) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ReportOpenItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 类型 request（请求）, promise（承诺）
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// 需要履行的一方 SELF, FRIEND
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// 内容
+	Content       string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportOpenItem) Reset() {
+	*x = ReportOpenItem{}
+	mi := &file_proto_chat_chat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportOpenItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportOpenItem) ProtoMessage() {}
+
+func (x *ReportOpenItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_chat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportOpenItem.ProtoReflect.Descriptor instead.
+func (*ReportOpenItem) Descriptor() ([]byte, []int) {
+	return file_proto_chat_chat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReportOpenItem) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ReportOpenItem) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ReportOpenItem) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type GenerateSessionReportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 聊天会话ID
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// 时间段，默认最近 7 天，最长 31 天
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3,oneof" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3,oneof" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateSessionReportRequest) Reset() {
+	*x = GenerateSessionReportRequest{}
+	mi := &file_proto_chat_chat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateSessionReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateSessionReportRequest) ProtoMessage() {}
+
+func (x *GenerateSessionReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_chat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateSessionReportRequest.ProtoReflect.Descriptor instead.
+func (*GenerateSessionReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_chat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GenerateSessionReportRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *GenerateSessionReportRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GenerateSessionReportRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type GenerateSessionReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *SessionReport         `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateSessionReportResponse) Reset() {
+	*x = GenerateSessionReportResponse{}
+	mi := &file_proto_chat_chat_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateSessionReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateSessionReportResponse) ProtoMessage() {}
+
+func (x *GenerateSessionReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_chat_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateSessionReportResponse.ProtoReflect.Descriptor instead.
+func (*GenerateSessionReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_chat_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GenerateSessionReportResponse) GetReport() *SessionReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type ListSessionReportsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 聊天会话ID
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// 分页
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// 每页大小
+	PageSize      int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionReportsRequest) Reset() {
+	*x = ListSessionReportsRequest{}
+	mi := &file_proto_chat_chat_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionReportsRequest) ProtoMessage() {}
+
+func (x *ListSessionReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_chat_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionReportsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_chat_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListSessionReportsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ListSessionReportsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListSessionReportsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListSessionReportsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 按时间段从新到旧排序
+	Data []*SessionReport `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	// 下一页
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionReportsResponse) Reset() {
+	*x = ListSessionReportsResponse{}
+	mi := &file_proto_chat_chat_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionReportsResponse) ProtoMessage() {}
+
+func (x *ListSessionReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_chat_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionReportsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_chat_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListSessionReportsResponse) GetData() []*SessionReport {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ListSessionReportsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 var File_proto_chat_chat_proto protoreflect.FileDescriptor
 
 const file_proto_chat_chat_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/chat/chat.proto\x12\x04chat\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\"\xf7\x01\n" +
+	"\x15proto/chat/chat.proto\x12\x04chat\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\"\xe9\x02\n" +
 	"\vChatSession\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
@@ -621,7 +1104,13 @@ const file_proto_chat_chat_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"U\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05title\x18\b \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"auto_title\x18\t \x01(\tR\tautoTitle\x12\x18\n" +
+	"\asummary\x18\n" +
+	" \x01(\tR\asummary\x12!\n" +
+	"\ftitle_pinned\x18\v \x01(\bR\vtitlePinned\"U\n" +
 	"\x17ListChatSessionsRequest\x12\x1d\n" +
 	"\n" +
 	"page_token\x18\x01 \x01(\tR\tpageToken\x12\x1b\n" +
@@ -650,18 +1139,66 @@ const file_proto_chat_chat_proto_rawDesc = "" +
 	"\fchat_session\x18\x01 \x01(\v2\x11.chat.ChatSessionR\vchatSession\"*\n" +
 	"\x18DeleteChatSessionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x1b\n" +
-	"\x19DeleteChatSessionResponse\"V\n" +
+	"\x19DeleteChatSessionResponse\"{\n" +
 	"\x18UpdateChatSessionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
-	"\x06avatar\x18\x03 \x01(\tR\x06avatar\"Q\n" +
+	"\x06avatar\x18\x03 \x01(\tR\x06avatar\x12\x19\n" +
+	"\x05title\x18\x04 \x01(\tH\x00R\x05title\x88\x01\x01B\b\n" +
+	"\x06_title\"Q\n" +
 	"\x19UpdateChatSessionResponse\x124\n" +
-	"\fchat_session\x18\x01 \x01(\v2\x11.chat.ChatSessionR\vchatSession2\xa5\x04\n" +
+	"\fchat_session\x18\x01 \x01(\v2\x11.chat.ChatSessionR\vchatSession\"\x96\x04\n" +
+	"\rSessionReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12=\n" +
+	"\fperiod_start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vperiodStart\x129\n" +
+	"\n" +
+	"period_end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tperiodEnd\x12\x18\n" +
+	"\atrigger\x18\x05 \x01(\tR\atrigger\x12#\n" +
+	"\rmessage_count\x18\x06 \x01(\x05R\fmessageCount\x12\x18\n" +
+	"\asummary\x18\a \x01(\tR\asummary\x12\x16\n" +
+	"\x06topics\x18\b \x03(\tR\x06topics\x123\n" +
+	"\n" +
+	"open_items\x18\t \x03(\v2\x14.chat.ReportOpenItemR\topenItems\x12!\n" +
+	"\ftone_changes\x18\n" +
+	" \x01(\tR\vtoneChanges\x12\x1d\n" +
+	"\n" +
+	"next_steps\x18\v \x03(\tR\tnextSteps\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"T\n" +
+	"\x0eReportOpenItem\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"\xd5\x01\n" +
+	"\x1cGenerateSessionReportRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12>\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\tstartTime\x88\x01\x01\x12:\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampH\x01R\aendTime\x88\x01\x01B\r\n" +
+	"\v_start_timeB\v\n" +
+	"\t_end_time\"L\n" +
+	"\x1dGenerateSessionReportResponse\x12+\n" +
+	"\x06report\x18\x01 \x01(\v2\x13.chat.SessionReportR\x06report\"v\n" +
+	"\x19ListSessionReportsRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"m\n" +
+	"\x1aListSessionReportsResponse\x12'\n" +
+	"\x04data\x18\x01 \x03(\v2\x13.chat.SessionReportR\x04data\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken2\xc7\x06\n" +
 	"\vChatService\x12\x80\x01\n" +
 	"\x10ListChatSessions\x12\x1d.chat.ListChatSessionsRequest\x1a\x1e.chat.ListChatSessionsResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/chat.ChatService/ListChatSessions\x12\x84\x01\n" +
 	"\x11CreateChatSession\x12\x1e.chat.CreateChatSessionRequest\x1a\x1f.chat.CreateChatSessionResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/chat.ChatService/CreateChatSession\x12\x84\x01\n" +
 	"\x11DeleteChatSession\x12\x1e.chat.DeleteChatSessionRequest\x1a\x1f.chat.DeleteChatSessionResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/chat.ChatService/DeleteChatSession\x12\x84\x01\n" +
-	"\x11UpdateChatSession\x12\x1e.chat.UpdateChatSessionRequest\x1a\x1f.chat.UpdateChatSessionResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/chat.ChatService/UpdateChatSessionB\x17Z\x15app_server/proto/chatb\x06proto3"
+	"\x11UpdateChatSession\x12\x1e.chat.UpdateChatSessionRequest\x1a\x1f.chat.UpdateChatSessionResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/chat.ChatService/UpdateChatSession\x12\x94\x01\n" +
+	"\x15GenerateSessionReport\x12\".chat.GenerateSessionReportRequest\x1a#.chat.GenerateSessionReportResponse\"2\x82\xd3\xe4\x93\x02,:\x01*\"'/chat.ChatService/GenerateSessionReport\x12\x88\x01\n" +
+	"\x12ListSessionReports\x12\x1f.chat.ListSessionReportsRequest\x1a .chat.ListSessionReportsResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/chat.ChatService/ListSessionReportsB\x17Z\x15app_server/proto/chatb\x06proto3"
 
 var (
 	file_proto_chat_chat_proto_rawDescOnce sync.Once
@@ -675,41 +1212,60 @@ func file_proto_chat_chat_proto_rawDescGZIP() []byte {
 	return file_proto_chat_chat_proto_rawDescData
 }
 
-var file_proto_chat_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_chat_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_proto_chat_chat_proto_goTypes = []any{
-	(*ChatSession)(nil),               // 0: chat.ChatSession
-	(*ListChatSessionsRequest)(nil),   // 1: chat.ListChatSessionsRequest
-	(*ListChatSessionsResponse)(nil),  // 2: chat.ListChatSessionsResponse
-	(*CreateChatSessionRequest)(nil),  // 3: chat.CreateChatSessionRequest
-	(*ProfileShort)(nil),              // 4: chat.ProfileShort
-	(*CreateChatSessionResponse)(nil), // 5: chat.CreateChatSessionResponse
-	(*DeleteChatSessionRequest)(nil),  // 6: chat.DeleteChatSessionRequest
-	(*DeleteChatSessionResponse)(nil), // 7: chat.DeleteChatSessionResponse
-	(*UpdateChatSessionRequest)(nil),  // 8: chat.UpdateChatSessionRequest
-	(*UpdateChatSessionResponse)(nil), // 9: chat.UpdateChatSessionResponse
-	(*timestamppb.Timestamp)(nil),     // 10: google.protobuf.Timestamp
+	(*ChatSession)(nil),                   // 0: chat.ChatSession
+	(*ListChatSessionsRequest)(nil),       // 1: chat.ListChatSessionsRequest
+	(*ListChatSessionsResponse)(nil),      // 2: chat.ListChatSessionsResponse
+	(*CreateChatSessionRequest)(nil),      // 3: chat.CreateChatSessionRequest
+	(*ProfileShort)(nil),                  // 4: chat.ProfileShort
+	(*CreateChatSessionResponse)(nil),     // 5: chat.CreateChatSessionResponse
+	(*DeleteChatSessionRequest)(nil),      // 6: chat.DeleteChatSessionRequest
+	(*DeleteChatSessionResponse)(nil),     // 7: chat.DeleteChatSessionResponse
+	(*UpdateChatSessionRequest)(nil),      // 8: chat.UpdateChatSessionRequest
+	(*UpdateChatSessionResponse)(nil),     // 9: chat.UpdateChatSessionResponse
+	(*SessionReport)(nil),                 // 10: chat.SessionReport
+	(*ReportOpenItem)(nil),                // 11: chat.ReportOpenItem
+	(*GenerateSessionReportRequest)(nil),  // 12: chat.GenerateSessionReportRequest
+	(*GenerateSessionReportResponse)(nil), // 13: chat.GenerateSessionReportResponse
+	(*ListSessionReportsRequest)(nil),     // 14: chat.ListSessionReportsRequest
+	(*ListSessionReportsResponse)(nil),    // 15: chat.ListSessionReportsResponse
+	(*timestamppb.Timestamp)(nil),         // 16: google.protobuf.Timestamp
 }
 var file_proto_chat_chat_proto_depIdxs = []int32{
-	10, // 0: chat.ChatSession.created_at:type_name -> google.protobuf.Timestamp
-	10, // 1: chat.ChatSession.updated_at:type_name -> google.protobuf.Timestamp
+	16, // 0: chat.ChatSession.created_at:type_name -> google.protobuf.Timestamp
+	16, // 1: chat.ChatSession.updated_at:type_name -> google.protobuf.Timestamp
 	0,  // 2: chat.ListChatSessionsResponse.data:type_name -> chat.ChatSession
 	4,  // 3: chat.CreateChatSessionRequest.profile:type_name -> chat.ProfileShort
-	10, // 4: chat.ProfileShort.birthday:type_name -> google.protobuf.Timestamp
+	16, // 4: chat.ProfileShort.birthday:type_name -> google.protobuf.Timestamp
 	0,  // 5: chat.CreateChatSessionResponse.chat_session:type_name -> chat.ChatSession
 	0,  // 6: chat.UpdateChatSessionResponse.chat_session:type_name -> chat.ChatSession
-	1,  // 7: chat.ChatService.ListChatSessions:input_type -> chat.ListChatSessionsRequest
-	3,  // 8: chat.ChatService.CreateChatSession:input_type -> chat.CreateChatSessionRequest
-	6,  // 9: chat.ChatService.DeleteChatSession:input_type -> chat.DeleteChatSessionRequest
-	8,  // 10: chat.ChatService.UpdateChatSession:input_type -> chat.UpdateChatSessionRequest
-	2,  // 11: chat.ChatService.ListChatSessions:output_type -> chat.ListChatSessionsResponse
-	5,  // 12: chat.ChatService.CreateChatSession:output_type -> chat.CreateChatSessionResponse
-	7,  // 13: chat.ChatService.DeleteChatSession:output_type -> chat.DeleteChatSessionResponse
-	9,  // 14: chat.ChatService.UpdateChatSession:output_type -> chat.UpdateChatSessionResponse
-	11, // [11:15] is the sub-list for method output_type
-	7,  // [7:11] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	16, // 7: chat.SessionReport.period_start:type_name -> google.protobuf.Timestamp
+	16, // 8: chat.SessionReport.period_end:type_name -> google.protobuf.Timestamp
+	11, // 9: chat.SessionReport.open_items:type_name -> chat.ReportOpenItem
+	16, // 10: chat.SessionReport.created_at:type_name -> google.protobuf.Timestamp
+	16, // 11: chat.SessionReport.updated_at:type_name -> google.protobuf.Timestamp
+	16, // 12: chat.GenerateSessionReportRequest.start_time:type_name -> google.protobuf.Timestamp
+	16, // 13: chat.GenerateSessionReportRequest.end_time:type_name -> google.protobuf.Timestamp
+	10, // 14: chat.GenerateSessionReportResponse.report:type_name -> chat.SessionReport
+	10, // 15: chat.ListSessionReportsResponse.data:type_name -> chat.SessionReport
+	1,  // 16: chat.ChatService.ListChatSessions:input_type -> chat.ListChatSessionsRequest
+	3,  // 17: chat.ChatService.CreateChatSession:input_type -> chat.CreateChatSessionRequest
+	6,  // 18: chat.ChatService.DeleteChatSession:input_type -> chat.DeleteChatSessionRequest
+	8,  // 19: chat.ChatService.UpdateChatSession:input_type -> chat.UpdateChatSessionRequest
+	12, // 20: chat.ChatService.GenerateSessionReport:input_type -> chat.GenerateSessionReportRequest
+	14, // 21: chat.ChatService.ListSessionReports:input_type -> chat.ListSessionReportsRequest
+	2,  // 22: chat.ChatService.ListChatSessions:output_type -> chat.ListChatSessionsResponse
+	5,  // 23: chat.ChatService.CreateChatSession:output_type -> chat.CreateChatSessionResponse
+	7,  // 24: chat.ChatService.DeleteChatSession:output_type -> chat.DeleteChatSessionResponse
+	9,  // 25: chat.ChatService.UpdateChatSession:output_type -> chat.UpdateChatSessionResponse
+	13, // 26: chat.ChatService.GenerateSessionReport:output_type -> chat.GenerateSessionReportResponse
+	15, // 27: chat.ChatService.ListSessionReports:output_type -> chat.ListSessionReportsResponse
+	22, // [22:28] is the sub-list for method output_type
+	16, // [16:22] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_proto_chat_chat_proto_init() }
@@ -719,13 +1275,15 @@ func file_proto_chat_chat_proto_init() {
 	}
 	file_proto_chat_chat_proto_msgTypes[3].OneofWrappers = []any{}
 	file_proto_chat_chat_proto_msgTypes[4].OneofWrappers = []any{}
+	file_proto_chat_chat_proto_msgTypes[8].OneofWrappers = []any{}
+	file_proto_chat_chat_proto_msgTypes[12].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_chat_chat_proto_rawDesc), len(file_proto_chat_chat_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -45,6 +45,12 @@ const (
 	// ChatServiceUpdateChatSessionProcedure is the fully-qualified name of the ChatService's
 	// UpdateChatSession RPC.
 	ChatServiceUpdateChatSessionProcedure = "/chat.ChatService/UpdateChatSession"
+	// ChatServiceGenerateSessionReportProcedure is the fully-qualified name of the ChatService's
+	// GenerateSessionReport RPC.
+	ChatServiceGenerateSessionReportProcedure = "/chat.ChatService/GenerateSessionReport"
+	// ChatServiceListSessionReportsProcedure is the fully-qualified name of the ChatService's
+	// ListSessionReports RPC.
+	ChatServiceListSessionReportsProcedure = "/chat.ChatService/ListSessionReports"
 )
 
 // ChatServiceClient is a client for the chat.ChatService service.
@@ -57,6 +63,12 @@ type ChatServiceClient interface {
 	DeleteChatSession(context.Context, *connect.Request[chat.DeleteChatSessionRequest]) (*connect.Response[chat.DeleteChatSessionResponse], error)
 	// POST /chat.ChatService/UpdateChatSession
 	UpdateChatSession(context.Context, *connect.Request[chat.UpdateChatSessionRequest]) (*connect.Response[chat.UpdateChatSessionResponse], error)
+	// 生成会话在一段时间内的摘要报告，同一时间段已有报告时重新生成
+	// POST /chat.ChatService/GenerateSessionReport
+	GenerateSessionReport(context.Context, *connect.Request[chat.GenerateSessionReportRequest]) (*connect.Response[chat.GenerateSessionReportResponse], error)
+	// 查询会话的摘要报告，包括定时生成的周报
+	// POST /chat.ChatService/ListSessionReports
+	ListSessionReports(context.Context, *connect.Request[chat.ListSessionReportsRequest]) (*connect.Response[chat.ListSessionReportsResponse], error)
 }
 
 // NewChatServiceClient constructs a client for the chat.ChatService service. By default, it uses
@@ -94,15 +106,29 @@ func NewChatServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(chatServiceMethods.ByName("UpdateChatSession")),
 			connect.WithClientOptions(opts...),
 		),
+		generateSessionReport: connect.NewClient[chat.GenerateSessionReportRequest, chat.GenerateSessionReportResponse](
+			httpClient,
+			baseURL+ChatServiceGenerateSessionReportProcedure,
+			connect.WithSchema(chatServiceMethods.ByName("GenerateSessionReport")),
+			connect.WithClientOptions(opts...),
+		),
+		listSessionReports: connect.NewClient[chat.ListSessionReportsRequest, chat.ListSessionReportsResponse](
+			httpClient,
+			baseURL+ChatServiceListSessionReportsProcedure,
+			connect.WithSchema(chatServiceMethods.ByName("ListSessionReports")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // chatServiceClient implements ChatServiceClient.
 type chatServiceClient struct {
-	listChatSessions  *connect.Client[chat.ListChatSessionsRequest, chat.ListChatSessionsResponse]
-	createChatSession *connect.Client[chat.CreateChatSessionRequest, chat.CreateChatSessionResponse]
-	deleteChatSession *connect.Client[chat.DeleteChatSessionRequest, chat.DeleteChatSessionResponse]
-	updateChatSession *connect.Client[chat.UpdateChatSessionRequest, chat.UpdateChatSessionResponse]
+	listChatSessions      *connect.Client[chat.ListChatSessionsRequest, chat.ListChatSessionsResponse]
+	createChatSession     *connect.Client[chat.CreateChatSessionRequest, chat.CreateChatSessionResponse]
+	deleteChatSession     *connect.Client[chat.DeleteChatSessionRequest, chat.DeleteChatSessionResponse]
+	updateChatSession     *connect.Client[chat.UpdateChatSessionRequest, chat.UpdateChatSessionResponse]
+	generateSessionReport *connect.Client[chat.GenerateSessionReportRequest, chat.GenerateSessionReportResponse]
+	listSessionReports    *connect.Client[chat.ListSessionReportsRequest, chat.ListSessionReportsResponse]
 }
 
 // ListChatSessions calls chat.ChatService.ListChatSessions.
@@ -125,6 +151,16 @@ func (c *chatServiceClient) UpdateChatSession(ctx context.Context, req *connect.
 	return c.updateChatSession.CallUnary(ctx, req)
 }
 
+// GenerateSessionReport calls chat.ChatService.GenerateSessionReport.
+func (c *chatServiceClient) GenerateSessionReport(ctx context.Context, req *connect.Request[chat.GenerateSessionReportRequest]) (*connect.Response[chat.GenerateSessionReportResponse], error) {
+	return c.generateSessionReport.CallUnary(ctx, req)
+}
+
+// ListSessionReports calls chat.ChatService.ListSessionReports.
+func (c *chatServiceClient) ListSessionReports(ctx context.Context, req *connect.Request[chat.ListSessionReportsRequest]) (*connect.Response[chat.ListSessionReportsResponse], error) {
+	return c.listSessionReports.CallUnary(ctx, req)
+}
+
 // ChatServiceHandler is an implementation of the chat.ChatService service.
 type ChatServiceHandler interface {
 	// POST /chat.ChatService/ListChatSessions
@@ -135,6 +171,12 @@ type ChatServiceHandler interface {
 	DeleteChatSession(context.Context, *connect.Request[chat.DeleteChatSessionRequest]) (*connect.Response[chat.DeleteChatSessionResponse], error)
 	// POST /chat.ChatService/UpdateChatSession
 	UpdateChatSession(context.Context, *connect.Request[chat.UpdateChatSessionRequest]) (*connect.Response[chat.UpdateChatSessionResponse], error)
+	// 生成会话在一段时间内的摘要报告，同一时间段已有报告时重新生成
+	// POST /chat.ChatService/GenerateSessionReport
+	GenerateSessionReport(context.Context, *connect.Request[chat.GenerateSessionReportRequest]) (*connect.Response[chat.GenerateSessionReportResponse], error)
+	// 查询会话的摘要报告，包括定时生成的周报
+	// POST /chat.ChatService/ListSessionReports
+	ListSessionReports(context.Context, *connect.Request[chat.ListSessionReportsRequest]) (*connect.Response[chat.ListSessionReportsResponse], error)
 }
 
 // NewChatServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -168,6 +210,18 @@ func NewChatServiceHandler(svc ChatServiceHandler, opts ...connect.HandlerOption
 		connect.WithSchema(chatServiceMethods.ByName("UpdateChatSession")),
 		connect.WithHandlerOptions(opts...),
 	)
+	chatServiceGenerateSessionReportHandler := connect.NewUnaryHandler(
+		ChatServiceGenerateSessionReportProcedure,
+		svc.GenerateSessionReport,
+		connect.WithSchema(chatServiceMethods.ByName("GenerateSessionReport")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatServiceListSessionReportsHandler := connect.NewUnaryHandler(
+		ChatServiceListSessionReportsProcedure,
+		svc.ListSessionReports,
+		connect.WithSchema(chatServiceMethods.ByName("ListSessionReports")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/chat.ChatService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ChatServiceListChatSessionsProcedure:
@@ -178,6 +232,10 @@ func NewChatServiceHandler(svc ChatServiceHandler, opts ...connect.HandlerOption
 			chatServiceDeleteChatSessionHandler.ServeHTTP(w, r)
 		case ChatServiceUpdateChatSessionProcedure:
 			chatServiceUpdateChatSessionHandler.ServeHTTP(w, r)
+		case ChatServiceGenerateSessionReportProcedure:
+			chatServiceGenerateSessionReportHandler.ServeHTTP(w, r)
+		case ChatServiceListSessionReportsProcedure:
+			chatServiceListSessionReportsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -202,3 +260,11 @@ func (UnimplementedChatServiceHandler) DeleteChatSession(context.Context, *conne
 func (UnimplementedChatServiceHandler) UpdateChatSession(context.Context, *connect.Request[chat.UpdateChatSessionRequest]) (*connect.Response[chat.UpdateChatSessionResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("chat.ChatService.UpdateChatSession is not implemented"))
 }
+
+func (UnimplementedChatServiceHandler) GenerateSessionReport(context.Context, *connect.Request[chat.GenerateSessionReportRequest]) (*connect.Response[chat.GenerateSessionReportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("chat.ChatService.GenerateSessionReport is not implemented"))
+}
+
+func (UnimplementedChatServiceHandler) ListSessionReports(context.Context, *connect.Request[chat.ListSessionReportsRequest]) (*connect.Response[chat.ListSessionReportsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("chat.ChatService.ListSessionReports is not implemented"))
+}
@@ -0,0 +1,653 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/job/job.proto
+
+package job
+
+import (
+	message "app_server/proto/message"
+	translate "app_server/proto/translate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Job 后台执行的 AI 任务，提交后轮询 GetJob 获取结果
+type Job struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type       string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`          // CONSULT, TRANSLATE, PARSE_IMAGE
+	Status     string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`      // PENDING, RUNNING, SUCCEEDED, FAILED, CANCELED
+	Attempts   int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"` // 已执行的次数，失败后会自动重试
+	Error      string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`        // 最近一次失败的原因
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	NextRunAt  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"` // 等待重试时下次执行的时间
+	// 任务结果，仅 SUCCEEDED 时返回，与对应接口同步调用的响应相同
+	//
+	// Types that are valid to be assigned to Result:
+	//
+	//	*Job_Consult
+	//	*Job_Translate
+	//	*Job_ParseImage
+	Result        isJob_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_job_job_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Job) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Job) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Job) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Job) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+func (x *Job) GetResult() isJob_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Job) GetConsult() *message.SendConsultMessageResponse {
+	if x != nil {
+		if x, ok := x.Result.(*Job_Consult); ok {
+			return x.Consult
+		}
+	}
+	return nil
+}
+
+func (x *Job) GetTranslate() *translate.TranslateV2Response {
+	if x != nil {
+		if x, ok := x.Result.(*Job_Translate); ok {
+			return x.Translate
+		}
+	}
+	return nil
+}
+
+func (x *Job) GetParseImage() *message.ParseImageMessagesResponse {
+	if x != nil {
+		if x, ok := x.Result.(*Job_ParseImage); ok {
+			return x.ParseImage
+		}
+	}
+	return nil
+}
+
+type isJob_Result interface {
+	isJob_Result()
+}
+
+type Job_Consult struct {
+	Consult *message.SendConsultMessageResponse `protobuf:"bytes,10,opt,name=consult,proto3,oneof"`
+}
+
+type Job_Translate struct {
+	Translate *translate.TranslateV2Response `protobuf:"bytes,11,opt,name=translate,proto3,oneof"`
+}
+
+type Job_ParseImage struct {
+	ParseImage *message.ParseImageMessagesResponse `protobuf:"bytes,12,opt,name=parse_image,json=parseImage,proto3,oneof"`
+}
+
+func (*Job_Consult) isJob_Result() {}
+
+func (*Job_Translate) isJob_Result() {}
+
+func (*Job_ParseImage) isJob_Result() {}
+
+type SubmitJobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*SubmitJobRequest_Consult
+	//	*SubmitJobRequest_Translate
+	//	*SubmitJobRequest_ParseImage
+	Request       isSubmitJobRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobRequest) Reset() {
+	*x = SubmitJobRequest{}
+	mi := &file_proto_job_job_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobRequest) ProtoMessage() {}
+
+func (x *SubmitJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobRequest.ProtoReflect.Descriptor instead.
+func (*SubmitJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitJobRequest) GetRequest() isSubmitJobRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetConsult() *message.SendConsultMessageRequest {
+	if x != nil {
+		if x, ok := x.Request.(*SubmitJobRequest_Consult); ok {
+			return x.Consult
+		}
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetTranslate() *translate.TranslateV2Request {
+	if x != nil {
+		if x, ok := x.Request.(*SubmitJobRequest_Translate); ok {
+			return x.Translate
+		}
+	}
+	return nil
+}
+
+func (x *SubmitJobRequest) GetParseImage() *message.ParseImageMessagesRequest {
+	if x != nil {
+		if x, ok := x.Request.(*SubmitJobRequest_ParseImage); ok {
+			return x.ParseImage
+		}
+	}
+	return nil
+}
+
+type isSubmitJobRequest_Request interface {
+	isSubmitJobRequest_Request()
+}
+
+type SubmitJobRequest_Consult struct {
+	Consult *message.SendConsultMessageRequest `protobuf:"bytes,1,opt,name=consult,proto3,oneof"`
+}
+
+type SubmitJobRequest_Translate struct {
+	Translate *translate.TranslateV2Request `protobuf:"bytes,2,opt,name=translate,proto3,oneof"`
+}
+
+type SubmitJobRequest_ParseImage struct {
+	ParseImage *message.ParseImageMessagesRequest `protobuf:"bytes,3,opt,name=parse_image,json=parseImage,proto3,oneof"`
+}
+
+func (*SubmitJobRequest_Consult) isSubmitJobRequest_Request() {}
+
+func (*SubmitJobRequest_Translate) isSubmitJobRequest_Request() {}
+
+func (*SubmitJobRequest_ParseImage) isSubmitJobRequest_Request() {}
+
+type SubmitJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitJobResponse) Reset() {
+	*x = SubmitJobResponse{}
+	mi := &file_proto_job_job_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobResponse) ProtoMessage() {}
+
+func (x *SubmitJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobResponse.ProtoReflect.Descriptor instead.
+func (*SubmitJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitJobResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_proto_job_job_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobResponse) Reset() {
+	*x = GetJobResponse{}
+	mi := &file_proto_job_job_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobResponse) ProtoMessage() {}
+
+func (x *GetJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobResponse.ProtoReflect.Descriptor instead.
+func (*GetJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetJobResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_proto_job_job_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobResponse) Reset() {
+	*x = CancelJobResponse{}
+	mi := &file_proto_job_job_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobResponse) ProtoMessage() {}
+
+func (x *CancelJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_job_job_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobResponse.ProtoReflect.Descriptor instead.
+func (*CancelJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_job_job_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CancelJobResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+var File_proto_job_job_proto protoreflect.FileDescriptor
+
+const file_proto_job_job_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/job/job.proto\x12\x03job\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bproto/message/message.proto\x1a\x1fproto/translate/translate.proto\"\xb5\x04\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12:\n" +
+	"\vnext_run_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\x12?\n" +
+	"\aconsult\x18\n" +
+	" \x01(\v2#.message.SendConsultMessageResponseH\x00R\aconsult\x12>\n" +
+	"\ttranslate\x18\v \x01(\v2\x1e.translate.TranslateV2ResponseH\x00R\ttranslate\x12F\n" +
+	"\vparse_image\x18\f \x01(\v2#.message.ParseImageMessagesResponseH\x00R\n" +
+	"parseImageB\b\n" +
+	"\x06result\"\xe3\x01\n" +
+	"\x10SubmitJobRequest\x12>\n" +
+	"\aconsult\x18\x01 \x01(\v2\".message.SendConsultMessageRequestH\x00R\aconsult\x12=\n" +
+	"\ttranslate\x18\x02 \x01(\v2\x1d.translate.TranslateV2RequestH\x00R\ttranslate\x12E\n" +
+	"\vparse_image\x18\x03 \x01(\v2\".message.ParseImageMessagesRequestH\x00R\n" +
+	"parseImageB\t\n" +
+	"\arequest\"/\n" +
+	"\x11SubmitJobResponse\x12\x1a\n" +
+	"\x03job\x18\x01 \x01(\v2\b.job.JobR\x03job\"\x1f\n" +
+	"\rGetJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\x0eGetJobResponse\x12\x1a\n" +
+	"\x03job\x18\x01 \x01(\v2\b.job.JobR\x03job\"\"\n" +
+	"\x10CancelJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
+	"\x11CancelJobResponse\x12\x1a\n" +
+	"\x03job\x18\x01 \x01(\v2\b.job.JobR\x03job2\xa6\x02\n" +
+	"\n" +
+	"JobService\x12`\n" +
+	"\tSubmitJob\x12\x15.job.SubmitJobRequest\x1a\x16.job.SubmitJobResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/job.JobService/SubmitJob\x12T\n" +
+	"\x06GetJob\x12\x12.job.GetJobRequest\x1a\x13.job.GetJobResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/job.JobService/GetJob\x12`\n" +
+	"\tCancelJob\x12\x15.job.CancelJobRequest\x1a\x16.job.CancelJobResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/job.JobService/CancelJobB\x16Z\x14app_server/proto/jobb\x06proto3"
+
+var (
+	file_proto_job_job_proto_rawDescOnce sync.Once
+	file_proto_job_job_proto_rawDescData []byte
+)
+
+func file_proto_job_job_proto_rawDescGZIP() []byte {
+	file_proto_job_job_proto_rawDescOnce.Do(func() {
+		file_proto_job_job_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_job_job_proto_rawDesc), len(file_proto_job_job_proto_rawDesc)))
+	})
+	return file_proto_job_job_proto_rawDescData
+}
+
+var file_proto_job_job_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_job_job_proto_goTypes = []any{
+	(*Job)(nil),                                // 0: job.Job
+	(*SubmitJobRequest)(nil),                   // 1: job.SubmitJobRequest
+	(*SubmitJobResponse)(nil),                  // 2: job.SubmitJobResponse
+	(*GetJobRequest)(nil),                      // 3: job.GetJobRequest
+	(*GetJobResponse)(nil),                     // 4: job.GetJobResponse
+	(*CancelJobRequest)(nil),                   // 5: job.CancelJobRequest
+	(*CancelJobResponse)(nil),                  // 6: job.CancelJobResponse
+	(*timestamppb.Timestamp)(nil),              // 7: google.protobuf.Timestamp
+	(*message.SendConsultMessageResponse)(nil), // 8: message.SendConsultMessageResponse
+	(*translate.TranslateV2Response)(nil),      // 9: translate.TranslateV2Response
+	(*message.ParseImageMessagesResponse)(nil), // 10: message.ParseImageMessagesResponse
+	(*message.SendConsultMessageRequest)(nil),  // 11: message.SendConsultMessageRequest
+	(*translate.TranslateV2Request)(nil),       // 12: translate.TranslateV2Request
+	(*message.ParseImageMessagesRequest)(nil),  // 13: message.ParseImageMessagesRequest
+}
+var file_proto_job_job_proto_depIdxs = []int32{
+	7,  // 0: job.Job.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 1: job.Job.started_at:type_name -> google.protobuf.Timestamp
+	7,  // 2: job.Job.finished_at:type_name -> google.protobuf.Timestamp
+	7,  // 3: job.Job.next_run_at:type_name -> google.protobuf.Timestamp
+	8,  // 4: job.Job.consult:type_name -> message.SendConsultMessageResponse
+	9,  // 5: job.Job.translate:type_name -> translate.TranslateV2Response
+	10, // 6: job.Job.parse_image:type_name -> message.ParseImageMessagesResponse
+	11, // 7: job.SubmitJobRequest.consult:type_name -> message.SendConsultMessageRequest
+	12, // 8: job.SubmitJobRequest.translate:type_name -> translate.TranslateV2Request
+	13, // 9: job.SubmitJobRequest.parse_image:type_name -> message.ParseImageMessagesRequest
+	0,  // 10: job.SubmitJobResponse.job:type_name -> job.Job
+	0,  // 11: job.GetJobResponse.job:type_name -> job.Job
+	0,  // 12: job.CancelJobResponse.job:type_name -> job.Job
+	1,  // 13: job.JobService.SubmitJob:input_type -> job.SubmitJobRequest
+	3,  // 14: job.JobService.GetJob:input_type -> job.GetJobRequest
+	5,  // 15: job.JobService.CancelJob:input_type -> job.CancelJobRequest
+	2,  // 16: job.JobService.SubmitJob:output_type -> job.SubmitJobResponse
+	4,  // 17: job.JobService.GetJob:output_type -> job.GetJobResponse
+	6,  // 18: job.JobService.CancelJob:output_type -> job.CancelJobResponse
+	16, // [16:19] is the sub-list for method output_type
+	13, // [13:16] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_proto_job_job_proto_init() }
+func file_proto_job_job_proto_init() {
+	if File_proto_job_job_proto != nil {
+		return
+	}
+	file_proto_job_job_proto_msgTypes[0].OneofWrappers = []any{
+		(*Job_Consult)(nil),
+		(*Job_Translate)(nil),
+		(*Job_ParseImage)(nil),
+	}
+	file_proto_job_job_proto_msgTypes[1].OneofWrappers = []any{
+		(*SubmitJobRequest_Consult)(nil),
+		(*SubmitJobRequest_Translate)(nil),
+		(*SubmitJobRequest_ParseImage)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_job_job_proto_rawDesc), len(file_proto_job_job_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_job_job_proto_goTypes,
+		DependencyIndexes: file_proto_job_job_proto_depIdxs,
+		MessageInfos:      file_proto_job_job_proto_msgTypes,
+	}.Build()
+	File_proto_job_job_proto = out.File
+	file_proto_job_job_proto_goTypes = nil
+	file_proto_job_job_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: proto/job/job.proto
+
+package jobconnect
+
+import (
+	job "app_server/proto/job"
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// JobServiceName is the fully-qualified name of the JobService service.
+	JobServiceName = "job.JobService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// JobServiceSubmitJobProcedure is the fully-qualified name of the JobService's SubmitJob RPC.
+	JobServiceSubmitJobProcedure = "/job.JobService/SubmitJob"
+	// JobServiceGetJobProcedure is the fully-qualified name of the JobService's GetJob RPC.
+	JobServiceGetJobProcedure = "/job.JobService/GetJob"
+	// JobServiceCancelJobProcedure is the fully-qualified name of the JobService's CancelJob RPC.
+	JobServiceCancelJobProcedure = "/job.JobService/CancelJob"
+)
+
+// JobServiceClient is a client for the job.JobService service.
+type JobServiceClient interface {
+	// 提交任务，参数与对应接口的请求相同，其中的 async 字段会被忽略
+	// POST /job.JobService/SubmitJob
+	SubmitJob(context.Context, *connect.Request[job.SubmitJobRequest]) (*connect.Response[job.SubmitJobResponse], error)
+	// POST /job.JobService/GetJob
+	GetJob(context.Context, *connect.Request[job.GetJobRequest]) (*connect.Response[job.GetJobResponse], error)
+	// 取消等待中或执行中的任务，已结束的任务不受影响
+	// POST /job.JobService/CancelJob
+	CancelJob(context.Context, *connect.Request[job.CancelJobRequest]) (*connect.Response[job.CancelJobResponse], error)
+}
+
+// NewJobServiceClient constructs a client for the job.JobService service. By default, it uses the
+// Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewJobServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) JobServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	jobServiceMethods := job.File_proto_job_job_proto.Services().ByName("JobService").Methods()
+	return &jobServiceClient{
+		submitJob: connect.NewClient[job.SubmitJobRequest, job.SubmitJobResponse](
+			httpClient,
+			baseURL+JobServiceSubmitJobProcedure,
+			connect.WithSchema(jobServiceMethods.ByName("SubmitJob")),
+			connect.WithClientOptions(opts...),
+		),
+		getJob: connect.NewClient[job.GetJobRequest, job.GetJobResponse](
+			httpClient,
+			baseURL+JobServiceGetJobProcedure,
+			connect.WithSchema(jobServiceMethods.ByName("GetJob")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelJob: connect.NewClient[job.CancelJobRequest, job.CancelJobResponse](
+			httpClient,
+			baseURL+JobServiceCancelJobProcedure,
+			connect.WithSchema(jobServiceMethods.ByName("CancelJob")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// jobServiceClient implements JobServiceClient.
+type jobServiceClient struct {
+	submitJob *connect.Client[job.SubmitJobRequest, job.SubmitJobResponse]
+	getJob    *connect.Client[job.GetJobRequest, job.GetJobResponse]
+	cancelJob *connect.Client[job.CancelJobRequest, job.CancelJobResponse]
+}
+
+// SubmitJob calls job.JobService.SubmitJob.
+func (c *jobServiceClient) SubmitJob(ctx context.Context, req *connect.Request[job.SubmitJobRequest]) (*connect.Response[job.SubmitJobResponse], error) {
+	return c.submitJob.CallUnary(ctx, req)
+}
+
+// GetJob calls job.JobService.GetJob.
+func (c *jobServiceClient) GetJob(ctx context.Context, req *connect.Request[job.GetJobRequest]) (*connect.Response[job.GetJobResponse], error) {
+	return c.getJob.CallUnary(ctx, req)
+}
+
+// CancelJob calls job.JobService.CancelJob.
+func (c *jobServiceClient) CancelJob(ctx context.Context, req *connect.Request[job.CancelJobRequest]) (*connect.Response[job.CancelJobResponse], error) {
+	return c.cancelJob.CallUnary(ctx, req)
+}
+
+// JobServiceHandler is an implementation of the job.JobService service.
+type JobServiceHandler interface {
+	// 提交任务，参数与对应接口的请求相同，其中的 async 字段会被忽略
+	// POST /job.JobService/SubmitJob
+	SubmitJob(context.Context, *connect.Request[job.SubmitJobRequest]) (*connect.Response[job.SubmitJobResponse], error)
+	// POST /job.JobService/GetJob
+	GetJob(context.Context, *connect.Request[job.GetJobRequest]) (*connect.Response[job.GetJobResponse], error)
+	// 取消等待中或执行中的任务，已结束的任务不受影响
+	// POST /job.JobService/CancelJob
+	CancelJob(context.Context, *connect.Request[job.CancelJobRequest]) (*connect.Response[job.CancelJobResponse], error)
+}
+
+// NewJobServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewJobServiceHandler(svc JobServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	jobServiceMethods := job.File_proto_job_job_proto.Services().ByName("JobService").Methods()
+	jobServiceSubmitJobHandler := connect.NewUnaryHandler(
+		JobServiceSubmitJobProcedure,
+		svc.SubmitJob,
+		connect.WithSchema(jobServiceMethods.ByName("SubmitJob")),
+		connect.WithHandlerOptions(opts...),
+	)
+	jobServiceGetJobHandler := connect.NewUnaryHandler(
+		JobServiceGetJobProcedure,
+		svc.GetJob,
+		connect.WithSchema(jobServiceMethods.ByName("GetJob")),
+		connect.WithHandlerOptions(opts...),
+	)
+	jobServiceCancelJobHandler := connect.NewUnaryHandler(
+		JobServiceCancelJobProcedure,
+		svc.CancelJob,
+		connect.WithSchema(jobServiceMethods.ByName("CancelJob")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/job.JobService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case JobServiceSubmitJobProcedure:
+			jobServiceSubmitJobHandler.ServeHTTP(w, r)
+		case JobServiceGetJobProcedure:
+			jobServiceGetJobHandler.ServeHTTP(w, r)
+		case JobServiceCancelJobProcedure:
+			jobServiceCancelJobHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedJobServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedJobServiceHandler struct{}
+
+func (UnimplementedJobServiceHandler) SubmitJob(context.Context, *connect.Request[job.SubmitJobRequest]) (*connect.Response[job.SubmitJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("job.JobService.SubmitJob is not implemented"))
+}
+
+func (UnimplementedJobServiceHandler) GetJob(context.Context, *connect.Request[job.GetJobRequest]) (*connect.Response[job.GetJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("job.JobService.GetJob is not implemented"))
+}
+
+func (UnimplementedJobServiceHandler) CancelJob(context.Context, *connect.Request[job.CancelJobRequest]) (*connect.Response[job.CancelJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("job.JobService.CancelJob is not implemented"))
+}
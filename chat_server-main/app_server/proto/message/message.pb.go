@@ -33,11 +33,17 @@ type ChatMessage struct {
 	Role             string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`                      // SELF, FRIEND, USER, AI.
 	MsgType          string                 `protobuf:"bytes,6,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"` // HISTORY, CONSULT, TRANSLATE.
 	Content          string                 `protobuf:"bytes,7,opt,name=content,proto3" json:"content,omitempty"`
-	Tags             []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	Tags             []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"` // 系统标签，只能由服务端设置，创建和更新消息时忽略
 	MsgAt            *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=msg_at,json=msgAt,proto3" json:"msg_at,omitempty"`
 	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	TranslateContent *string                `protobuf:"bytes,12,opt,name=translate_content,json=translateContent,proto3,oneof" json:"translate_content,omitempty"`
+	TranslateStale   bool                   `protobuf:"varint,13,opt,name=translate_stale,json=translateStale,proto3" json:"translate_stale,omitempty"` // 翻译生成后原消息内容又被修改过，翻译可能已过期
+	BranchIndex      int32                  `protobuf:"varint,14,opt,name=branch_index,json=branchIndex,proto3" json:"branch_index,omitempty"`          // CONSULT 消息在兄弟分支中的位置，从 1 开始
+	BranchCount      int32                  `protobuf:"varint,15,opt,name=branch_count,json=branchCount,proto3" json:"branch_count,omitempty"`          // CONSULT 消息的兄弟分支数量
+	SenderName       string                 `protobuf:"bytes,16,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`              // 发送人显示名称，从截图中识别，没有时为空
+	UserTags         []string               `protobuf:"bytes,17,rep,name=user_tags,json=userTags,proto3" json:"user_tags,omitempty"`                    // 用户标签，创建时可以设置，之后通过 AddMessageTags 和 RemoveMessageTags 修改
+	Attachments      []*MessageAttachment   `protobuf:"bytes,18,rep,name=attachments,proto3" json:"attachments,omitempty"`                              // 附件，创建消息时只需要填写 file_id
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -156,25 +162,261 @@ func (x *ChatMessage) GetTranslateContent() string {
 	return ""
 }
 
+func (x *ChatMessage) GetTranslateStale() bool {
+	if x != nil {
+		return x.TranslateStale
+	}
+	return false
+}
+
+func (x *ChatMessage) GetBranchIndex() int32 {
+	if x != nil {
+		return x.BranchIndex
+	}
+	return 0
+}
+
+func (x *ChatMessage) GetBranchCount() int32 {
+	if x != nil {
+		return x.BranchCount
+	}
+	return 0
+}
+
+func (x *ChatMessage) GetSenderName() string {
+	if x != nil {
+		return x.SenderName
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetUserTags() []string {
+	if x != nil {
+		return x.UserTags
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetAttachments() []*MessageAttachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// MessageAttachment 消息附件，引用用户上传的文件
+type MessageAttachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`                                         // 签名后的访问地址，每次查询时重新生成
+	UrlExpiresAt  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=url_expires_at,json=urlExpiresAt,proto3" json:"url_expires_at,omitempty"` // 访问地址的过期时间
+	FileType      string                 `protobuf:"bytes,4,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`               // 文件 MIME 类型
+	OriginalName  string                 `protobuf:"bytes,5,opt,name=original_name,json=originalName,proto3" json:"original_name,omitempty"`
+	FileSize      int64                  `protobuf:"varint,6,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageAttachment) Reset() {
+	*x = MessageAttachment{}
+	mi := &file_proto_message_message_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageAttachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageAttachment) ProtoMessage() {}
+
+func (x *MessageAttachment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageAttachment.ProtoReflect.Descriptor instead.
+func (*MessageAttachment) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MessageAttachment) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *MessageAttachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *MessageAttachment) GetUrlExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UrlExpiresAt
+	}
+	return nil
+}
+
+func (x *MessageAttachment) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *MessageAttachment) GetOriginalName() string {
+	if x != nil {
+		return x.OriginalName
+	}
+	return ""
+}
+
+func (x *MessageAttachment) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+// ChatMessageRevision 消息修改记录，保存修改前的内容
+type ChatMessageRevision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	EditorId      string                 `protobuf:"bytes,3,opt,name=editor_id,json=editorId,proto3" json:"editor_id,omitempty"` // 修改人
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	MsgType       string                 `protobuf:"bytes,5,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // 修改时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessageRevision) Reset() {
+	*x = ChatMessageRevision{}
+	mi := &file_proto_message_message_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessageRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessageRevision) ProtoMessage() {}
+
+func (x *ChatMessageRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessageRevision.ProtoReflect.Descriptor instead.
+func (*ChatMessageRevision) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChatMessageRevision) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChatMessageRevision) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ChatMessageRevision) GetEditorId() string {
+	if x != nil {
+		return x.EditorId
+	}
+	return ""
+}
+
+func (x *ChatMessageRevision) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ChatMessageRevision) GetMsgType() string {
+	if x != nil {
+		return x.MsgType
+	}
+	return ""
+}
+
+func (x *ChatMessageRevision) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ChatMessageRevision) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ChatMessageRevision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 // 查询消息请求 - 支持多种过滤条件
 type ListChatMessagesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// 过滤条件
-	SessionId string   `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 按会话ID过滤（必填）
-	MsgType   string   `protobuf:"bytes,2,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`       // 按消息类型过滤（可选）
-	Roles     []string `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`                          // 按角色过滤（可选）
-	Ids       []string `protobuf:"bytes,4,rep,name=ids,proto3" json:"ids,omitempty"`                              // 按ID列表过滤（可选）
-	ParentIds []string `protobuf:"bytes,5,rep,name=parent_ids,json=parentIds,proto3" json:"parent_ids,omitempty"` // 按父消息ID过滤（可选）
+	SessionId   string   `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`       // 按会话ID过滤（必填）
+	MsgType     string   `protobuf:"bytes,2,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`             // 按消息类型过滤（可选）
+	Roles       []string `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`                                // 按角色过滤（可选）
+	Ids         []string `protobuf:"bytes,4,rep,name=ids,proto3" json:"ids,omitempty"`                                    // 按ID列表过滤（可选）
+	ParentIds   []string `protobuf:"bytes,5,rep,name=parent_ids,json=parentIds,proto3" json:"parent_ids,omitempty"`       // 按父消息ID过滤（可选）
+	IncludeTags []string `protobuf:"bytes,6,rep,name=include_tags,json=includeTags,proto3" json:"include_tags,omitempty"` // 包含任一标签（可选），系统标签和用户标签都会匹配
+	ExcludeTags []string `protobuf:"bytes,7,rep,name=exclude_tags,json=excludeTags,proto3" json:"exclude_tags,omitempty"` // 不包含其中任何标签（可选）
 	// 分页参数
-	PageSize      int32  `protobuf:"varint,21,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string `protobuf:"bytes,22,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize  int32  `protobuf:"varint,21,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,22,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token 或 newer_page_token，兼容旧的消息ID
+	// 定位参数 - 以某条消息为中心，同时查询之前和之后的消息，用于跳转到搜索结果或分享链接
+	// 设置 anchor_id 或 anchor_time 时忽略 page_size 和 page_token
+	AnchorId      string                 `protobuf:"bytes,23,opt,name=anchor_id,json=anchorId,proto3" json:"anchor_id,omitempty"`           // 定位到这条消息
+	AnchorTime    *timestamppb.Timestamp `protobuf:"bytes,24,opt,name=anchor_time,json=anchorTime,proto3" json:"anchor_time,omitempty"`     // 定位到这个时间之后的第一条消息
+	BeforeCount   int32                  `protobuf:"varint,25,opt,name=before_count,json=beforeCount,proto3" json:"before_count,omitempty"` // 定位消息之前的消息数，默认 20，最多 200
+	AfterCount    int32                  `protobuf:"varint,26,opt,name=after_count,json=afterCount,proto3" json:"after_count,omitempty"`    // 定位消息之后的消息数（不含定位消息），默认 20，最多 200
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListChatMessagesRequest) Reset() {
 	*x = ListChatMessagesRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[1]
+	mi := &file_proto_message_message_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -186,7 +428,7 @@ func (x *ListChatMessagesRequest) String() string {
 func (*ListChatMessagesRequest) ProtoMessage() {}
 
 func (x *ListChatMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[1]
+	mi := &file_proto_message_message_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -199,7 +441,7 @@ func (x *ListChatMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListChatMessagesRequest.ProtoReflect.Descriptor instead.
 func (*ListChatMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{1}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ListChatMessagesRequest) GetSessionId() string {
@@ -237,6 +479,20 @@ func (x *ListChatMessagesRequest) GetParentIds() []string {
 	return nil
 }
 
+func (x *ListChatMessagesRequest) GetIncludeTags() []string {
+	if x != nil {
+		return x.IncludeTags
+	}
+	return nil
+}
+
+func (x *ListChatMessagesRequest) GetExcludeTags() []string {
+	if x != nil {
+		return x.ExcludeTags
+	}
+	return nil
+}
+
 func (x *ListChatMessagesRequest) GetPageSize() int32 {
 	if x != nil {
 		return x.PageSize
@@ -251,17 +507,47 @@ func (x *ListChatMessagesRequest) GetPageToken() string {
 	return ""
 }
 
+func (x *ListChatMessagesRequest) GetAnchorId() string {
+	if x != nil {
+		return x.AnchorId
+	}
+	return ""
+}
+
+func (x *ListChatMessagesRequest) GetAnchorTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnchorTime
+	}
+	return nil
+}
+
+func (x *ListChatMessagesRequest) GetBeforeCount() int32 {
+	if x != nil {
+		return x.BeforeCount
+	}
+	return 0
+}
+
+func (x *ListChatMessagesRequest) GetAfterCount() int32 {
+	if x != nil {
+		return x.AfterCount
+	}
+	return 0
+}
+
 type ListChatMessagesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Messages      []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Messages       []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`                                     // 按消息顺序从旧到新
+	NextPageToken  string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`    // 更早的消息，没有时为空
+	NewerPageToken string                 `protobuf:"bytes,3,opt,name=newer_page_token,json=newerPageToken,proto3" json:"newer_page_token,omitempty"` // 更新的消息，只在定位查询和向后翻页时返回，没有时为空
+	AnchorId       string                 `protobuf:"bytes,4,opt,name=anchor_id,json=anchorId,proto3" json:"anchor_id,omitempty"`                     // 定位查询实际定位到的消息
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ListChatMessagesResponse) Reset() {
 	*x = ListChatMessagesResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[2]
+	mi := &file_proto_message_message_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -273,7 +559,7 @@ func (x *ListChatMessagesResponse) String() string {
 func (*ListChatMessagesResponse) ProtoMessage() {}
 
 func (x *ListChatMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[2]
+	mi := &file_proto_message_message_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +572,7 @@ func (x *ListChatMessagesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListChatMessagesResponse.ProtoReflect.Descriptor instead.
 func (*ListChatMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{2}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListChatMessagesResponse) GetMessages() []*ChatMessage {
@@ -303,6 +589,20 @@ func (x *ListChatMessagesResponse) GetNextPageToken() string {
 	return ""
 }
 
+func (x *ListChatMessagesResponse) GetNewerPageToken() string {
+	if x != nil {
+		return x.NewerPageToken
+	}
+	return ""
+}
+
+func (x *ListChatMessagesResponse) GetAnchorId() string {
+	if x != nil {
+		return x.AnchorId
+	}
+	return ""
+}
+
 // 创建消息请求
 type CreateChatMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -313,7 +613,7 @@ type CreateChatMessageRequest struct {
 
 func (x *CreateChatMessageRequest) Reset() {
 	*x = CreateChatMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[3]
+	mi := &file_proto_message_message_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -325,7 +625,7 @@ func (x *CreateChatMessageRequest) String() string {
 func (*CreateChatMessageRequest) ProtoMessage() {}
 
 func (x *CreateChatMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[3]
+	mi := &file_proto_message_message_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,7 +638,7 @@ func (x *CreateChatMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateChatMessageRequest.ProtoReflect.Descriptor instead.
 func (*CreateChatMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{3}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateChatMessageRequest) GetMessages() []*ChatMessage {
@@ -357,7 +657,7 @@ type CreateChatMessageResponse struct {
 
 func (x *CreateChatMessageResponse) Reset() {
 	*x = CreateChatMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[4]
+	mi := &file_proto_message_message_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -369,7 +669,7 @@ func (x *CreateChatMessageResponse) String() string {
 func (*CreateChatMessageResponse) ProtoMessage() {}
 
 func (x *CreateChatMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[4]
+	mi := &file_proto_message_message_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -382,7 +682,7 @@ func (x *CreateChatMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateChatMessageResponse.ProtoReflect.Descriptor instead.
 func (*CreateChatMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{4}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateChatMessageResponse) GetMessages() []*ChatMessage {
@@ -402,7 +702,7 @@ type UpdateChatMessageRequest struct {
 
 func (x *UpdateChatMessageRequest) Reset() {
 	*x = UpdateChatMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[5]
+	mi := &file_proto_message_message_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -414,7 +714,7 @@ func (x *UpdateChatMessageRequest) String() string {
 func (*UpdateChatMessageRequest) ProtoMessage() {}
 
 func (x *UpdateChatMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[5]
+	mi := &file_proto_message_message_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -427,7 +727,7 @@ func (x *UpdateChatMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateChatMessageRequest.ProtoReflect.Descriptor instead.
 func (*UpdateChatMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{5}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateChatMessageRequest) GetMessages() []*ChatMessage {
@@ -446,7 +746,7 @@ type UpdateChatMessageResponse struct {
 
 func (x *UpdateChatMessageResponse) Reset() {
 	*x = UpdateChatMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[6]
+	mi := &file_proto_message_message_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -458,7 +758,7 @@ func (x *UpdateChatMessageResponse) String() string {
 func (*UpdateChatMessageResponse) ProtoMessage() {}
 
 func (x *UpdateChatMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[6]
+	mi := &file_proto_message_message_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -471,7 +771,7 @@ func (x *UpdateChatMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateChatMessageResponse.ProtoReflect.Descriptor instead.
 func (*UpdateChatMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{6}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateChatMessageResponse) GetMessages() []*ChatMessage {
@@ -481,29 +781,215 @@ func (x *UpdateChatMessageResponse) GetMessages() []*ChatMessage {
 	return nil
 }
 
-// 删除消息请求
-type DeleteChatMessageRequest struct {
+// 查询消息修改记录请求
+type ListMessageRevisionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteChatMessageRequest) Reset() {
-	*x = DeleteChatMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[7]
+func (x *ListMessageRevisionsRequest) Reset() {
+	*x = ListMessageRevisionsRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteChatMessageRequest) String() string {
+func (x *ListMessageRevisionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteChatMessageRequest) ProtoMessage() {}
+func (*ListMessageRevisionsRequest) ProtoMessage() {}
+
+func (x *ListMessageRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMessageRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListMessageRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListMessageRevisionsRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type ListMessageRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*ChatMessageRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"` // 按修改时间倒序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMessageRevisionsResponse) Reset() {
+	*x = ListMessageRevisionsResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMessageRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMessageRevisionsResponse) ProtoMessage() {}
+
+func (x *ListMessageRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMessageRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListMessageRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListMessageRevisionsResponse) GetRevisions() []*ChatMessageRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+// 回滚消息请求
+type RollbackChatMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	RevisionId    string                 `protobuf:"bytes,2,opt,name=revision_id,json=revisionId,proto3" json:"revision_id,omitempty"` // 回滚到该修改记录保存的内容
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackChatMessageRequest) Reset() {
+	*x = RollbackChatMessageRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackChatMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackChatMessageRequest) ProtoMessage() {}
+
+func (x *RollbackChatMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackChatMessageRequest.ProtoReflect.Descriptor instead.
+func (*RollbackChatMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RollbackChatMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *RollbackChatMessageRequest) GetRevisionId() string {
+	if x != nil {
+		return x.RevisionId
+	}
+	return ""
+}
+
+type RollbackChatMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *ChatMessage           `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackChatMessageResponse) Reset() {
+	*x = RollbackChatMessageResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackChatMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackChatMessageResponse) ProtoMessage() {}
+
+func (x *RollbackChatMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackChatMessageResponse.ProtoReflect.Descriptor instead.
+func (*RollbackChatMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RollbackChatMessageResponse) GetMessage() *ChatMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 删除消息请求
+type DeleteChatMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteChatMessageRequest) Reset() {
+	*x = DeleteChatMessageRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteChatMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteChatMessageRequest) ProtoMessage() {}
 
 func (x *DeleteChatMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[7]
+	mi := &file_proto_message_message_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,7 +1002,7 @@ func (x *DeleteChatMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteChatMessageRequest.ProtoReflect.Descriptor instead.
 func (*DeleteChatMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{7}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *DeleteChatMessageRequest) GetIds() []string {
@@ -535,7 +1021,7 @@ type DeleteChatMessageResponse struct {
 
 func (x *DeleteChatMessageResponse) Reset() {
 	*x = DeleteChatMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[8]
+	mi := &file_proto_message_message_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -547,7 +1033,7 @@ func (x *DeleteChatMessageResponse) String() string {
 func (*DeleteChatMessageResponse) ProtoMessage() {}
 
 func (x *DeleteChatMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[8]
+	mi := &file_proto_message_message_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -560,7 +1046,7 @@ func (x *DeleteChatMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteChatMessageResponse.ProtoReflect.Descriptor instead.
 func (*DeleteChatMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{8}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeleteChatMessageResponse) GetDeletedCount() int32 {
@@ -576,14 +1062,16 @@ type SendConsultMessageRequest struct {
 	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 会话ID
 	Content   string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`                      // 咨询内容
 	// optional string mention_id = 3;      // 提及消息ID
-	TargetId      *string `protobuf:"bytes,4,opt,name=target_id,json=targetId,proto3,oneof" json:"target_id,omitempty"` // 目标消息ID regenerate时使用
+	TargetId      *string `protobuf:"bytes,4,opt,name=target_id,json=targetId,proto3,oneof" json:"target_id,omitempty"` // 目标消息ID regenerate时使用，可以是当前分支上的任意一条 AI 回复
+	EditId        *string `protobuf:"bytes,5,opt,name=edit_id,json=editId,proto3,oneof" json:"edit_id,omitempty"`       // 编辑当前分支上的某条用户咨询，使用 content 创建新的分支并重新生成回复
+	Async         bool    `protobuf:"varint,6,opt,name=async,proto3" json:"async,omitempty"`                            // 在后台执行，立即返回 job_id，通过 JobService.GetJob 获取结果；流式接口不支持
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendConsultMessageRequest) Reset() {
 	*x = SendConsultMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[9]
+	mi := &file_proto_message_message_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -595,7 +1083,7 @@ func (x *SendConsultMessageRequest) String() string {
 func (*SendConsultMessageRequest) ProtoMessage() {}
 
 func (x *SendConsultMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[9]
+	mi := &file_proto_message_message_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -608,7 +1096,7 @@ func (x *SendConsultMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendConsultMessageRequest.ProtoReflect.Descriptor instead.
 func (*SendConsultMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{9}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *SendConsultMessageRequest) GetSessionId() string {
@@ -618,96 +1106,2963 @@ func (x *SendConsultMessageRequest) GetSessionId() string {
 	return ""
 }
 
-func (x *SendConsultMessageRequest) GetContent() string {
+func (x *SendConsultMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SendConsultMessageRequest) GetTargetId() string {
+	if x != nil && x.TargetId != nil {
+		return *x.TargetId
+	}
+	return ""
+}
+
+func (x *SendConsultMessageRequest) GetEditId() string {
+	if x != nil && x.EditId != nil {
+		return *x.EditId
+	}
+	return ""
+}
+
+func (x *SendConsultMessageRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+type SendConsultMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consult       *ChatMessage           `protobuf:"bytes,1,opt,name=consult,proto3" json:"consult,omitempty"`          // 创建的咨询消息
+	Reply         *ChatMessage           `protobuf:"bytes,2,opt,name=reply,proto3" json:"reply,omitempty"`              // 回复的消息
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // async 为 true 时返回，consult 和 reply 为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendConsultMessageResponse) Reset() {
+	*x = SendConsultMessageResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendConsultMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendConsultMessageResponse) ProtoMessage() {}
+
+func (x *SendConsultMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendConsultMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendConsultMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SendConsultMessageResponse) GetConsult() *ChatMessage {
+	if x != nil {
+		return x.Consult
+	}
+	return nil
+}
+
+func (x *SendConsultMessageResponse) GetReply() *ChatMessage {
+	if x != nil {
+		return x.Reply
+	}
+	return nil
+}
+
+func (x *SendConsultMessageResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type StreamConsultMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consult       *ChatMessage           `protobuf:"bytes,1,opt,name=consult,proto3" json:"consult,omitempty"` // 咨询消息，仅第一帧返回
+	Delta         string                 `protobuf:"bytes,2,opt,name=delta,proto3" json:"delta,omitempty"`     // AI 回复的增量内容
+	Reply         *ChatMessage           `protobuf:"bytes,3,opt,name=reply,proto3" json:"reply,omitempty"`     // 保存后的完整回复，仅最后一帧返回
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamConsultMessageResponse) Reset() {
+	*x = StreamConsultMessageResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamConsultMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamConsultMessageResponse) ProtoMessage() {}
+
+func (x *StreamConsultMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamConsultMessageResponse.ProtoReflect.Descriptor instead.
+func (*StreamConsultMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StreamConsultMessageResponse) GetConsult() *ChatMessage {
+	if x != nil {
+		return x.Consult
+	}
+	return nil
+}
+
+func (x *StreamConsultMessageResponse) GetDelta() string {
+	if x != nil {
+		return x.Delta
+	}
+	return ""
+}
+
+func (x *StreamConsultMessageResponse) GetReply() *ChatMessage {
+	if x != nil {
+		return x.Reply
+	}
+	return nil
+}
+
+type ListTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 限定会话ID（可选，不填则统计全部会话）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListTagsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type MessageTag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	System        bool                   `protobuf:"varint,2,opt,name=system,proto3" json:"system,omitempty"`                                 // 是否为系统标签
+	MessageCount  int32                  `protobuf:"varint,3,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"` // 带有该标签的消息数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageTag) Reset() {
+	*x = MessageTag{}
+	mi := &file_proto_message_message_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageTag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageTag) ProtoMessage() {}
+
+func (x *MessageTag) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageTag.ProtoReflect.Descriptor instead.
+func (*MessageTag) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MessageTag) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *MessageTag) GetSystem() bool {
+	if x != nil {
+		return x.System
+	}
+	return false
+}
+
+func (x *MessageTag) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+type ListTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []*MessageTag          `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"` // 按消息数从多到少排列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsResponse) Reset() {
+	*x = ListTagsResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsResponse) ProtoMessage() {}
+
+func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListTagsResponse) GetTags() []*MessageTag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// 批量修改用户标签请求
+type UpdateMessageTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageIds    []string               `protobuf:"bytes,1,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"` // 最多 500 条
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`                               // 每个标签最多 32 个字符，不能使用系统标签的名称
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMessageTagsRequest) Reset() {
+	*x = UpdateMessageTagsRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMessageTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMessageTagsRequest) ProtoMessage() {}
+
+func (x *UpdateMessageTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMessageTagsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMessageTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateMessageTagsRequest) GetMessageIds() []string {
+	if x != nil {
+		return x.MessageIds
+	}
+	return nil
+}
+
+func (x *UpdateMessageTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type UpdateMessageTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"` // 修改后的消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMessageTagsResponse) Reset() {
+	*x = UpdateMessageTagsResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMessageTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMessageTagsResponse) ProtoMessage() {}
+
+func (x *UpdateMessageTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMessageTagsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMessageTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateMessageTagsResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// 搜索消息请求
+type SearchChatMessagesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Query     string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`                          // 搜索关键词，多个关键词用空格分隔（必填）
+	SessionId string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 限定会话ID（可选，不填则搜索全部会话）
+	Roles     []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`                          // 按角色过滤（可选）
+	MsgType   string                 `protobuf:"bytes,4,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`       // 按消息类型过滤（可选）
+	Tags      []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                            // 包含任一标签（可选），系统标签和用户标签都会匹配
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // msg_at 起始时间（可选）
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // msg_at 结束时间（可选）
+	// 分页参数
+	PageSize      int32  `protobuf:"varint,21,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,22,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchChatMessagesRequest) Reset() {
+	*x = SearchChatMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchChatMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchChatMessagesRequest) ProtoMessage() {}
+
+func (x *SearchChatMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchChatMessagesRequest.ProtoReflect.Descriptor instead.
+func (*SearchChatMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchChatMessagesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchChatMessagesRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SearchChatMessagesRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *SearchChatMessagesRequest) GetMsgType() string {
+	if x != nil {
+		return x.MsgType
+	}
+	return ""
+}
+
+func (x *SearchChatMessagesRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SearchChatMessagesRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *SearchChatMessagesRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *SearchChatMessagesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchChatMessagesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SearchChatMessageHit struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Message         *ChatMessage           `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Snippet         string                 `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`                                          // 命中片段，关键词用 <em></em> 包裹
+	SessionName     string                 `protobuf:"bytes,3,opt,name=session_name,json=sessionName,proto3" json:"session_name,omitempty"`               // 所属会话名称
+	SessionAvatar   string                 `protobuf:"bytes,4,opt,name=session_avatar,json=sessionAvatar,proto3" json:"session_avatar,omitempty"`         // 所属会话头像
+	AnchorPageToken string                 `protobuf:"bytes,5,opt,name=anchor_page_token,json=anchorPageToken,proto3" json:"anchor_page_token,omitempty"` // 传给 ListChatMessages 的 page_token，可加载以该消息结尾的一页
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchChatMessageHit) Reset() {
+	*x = SearchChatMessageHit{}
+	mi := &file_proto_message_message_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchChatMessageHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchChatMessageHit) ProtoMessage() {}
+
+func (x *SearchChatMessageHit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchChatMessageHit.ProtoReflect.Descriptor instead.
+func (*SearchChatMessageHit) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SearchChatMessageHit) GetMessage() *ChatMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *SearchChatMessageHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *SearchChatMessageHit) GetSessionName() string {
+	if x != nil {
+		return x.SessionName
+	}
+	return ""
+}
+
+func (x *SearchChatMessageHit) GetSessionAvatar() string {
+	if x != nil {
+		return x.SessionAvatar
+	}
+	return ""
+}
+
+func (x *SearchChatMessageHit) GetAnchorPageToken() string {
+	if x != nil {
+		return x.AnchorPageToken
+	}
+	return ""
+}
+
+type SearchChatMessagesResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Hits          []*SearchChatMessageHit `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	NextPageToken string                  `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchChatMessagesResponse) Reset() {
+	*x = SearchChatMessagesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchChatMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchChatMessagesResponse) ProtoMessage() {}
+
+func (x *SearchChatMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchChatMessagesResponse.ProtoReflect.Descriptor instead.
+func (*SearchChatMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SearchChatMessagesResponse) GetHits() []*SearchChatMessageHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchChatMessagesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// 查询分支请求
+type ListConsultBranchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 分支组中任意一条咨询消息ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConsultBranchesRequest) Reset() {
+	*x = ListConsultBranchesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConsultBranchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConsultBranchesRequest) ProtoMessage() {}
+
+func (x *ListConsultBranchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConsultBranchesRequest.ProtoReflect.Descriptor instead.
+func (*ListConsultBranchesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListConsultBranchesRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ListConsultBranchesRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type ListConsultBranchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`                 // 分支组中的全部消息，按创建时间升序
+	ActiveId      string                 `protobuf:"bytes,2,opt,name=active_id,json=activeId,proto3" json:"active_id,omitempty"` // 当前生效的消息ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConsultBranchesResponse) Reset() {
+	*x = ListConsultBranchesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConsultBranchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConsultBranchesResponse) ProtoMessage() {}
+
+func (x *ListConsultBranchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConsultBranchesResponse.ProtoReflect.Descriptor instead.
+func (*ListConsultBranchesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListConsultBranchesResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ListConsultBranchesResponse) GetActiveId() string {
+	if x != nil {
+		return x.ActiveId
+	}
+	return ""
+}
+
+// 选择分支请求
+type SelectConsultBranchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 要切换到的咨询消息ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectConsultBranchRequest) Reset() {
+	*x = SelectConsultBranchRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectConsultBranchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectConsultBranchRequest) ProtoMessage() {}
+
+func (x *SelectConsultBranchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectConsultBranchRequest.ProtoReflect.Descriptor instead.
+func (*SelectConsultBranchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SelectConsultBranchRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SelectConsultBranchRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type SelectConsultBranchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectConsultBranchResponse) Reset() {
+	*x = SelectConsultBranchResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectConsultBranchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectConsultBranchResponse) ProtoMessage() {}
+
+func (x *SelectConsultBranchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectConsultBranchResponse.ProtoReflect.Descriptor instead.
+func (*SelectConsultBranchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{29}
+}
+
+// 解析图片消息请求（保持不变）
+type ParseImageMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 改为 session_id，不再需要 profile_id
+	ImageUrl      string                 `protobuf:"bytes,2,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // 用户所在时区，IANA 名称如 Asia/Shanghai，用于推断「昨天」等相对时间，为空时使用服务器时区
+	Async         bool                   `protobuf:"varint,4,opt,name=async,proto3" json:"async,omitempty"`                      // 在后台执行，立即返回 job_id，通过 JobService.GetJob 获取结果；仅 ChatMessageService 支持
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseImageMessagesRequest) Reset() {
+	*x = ParseImageMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseImageMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseImageMessagesRequest) ProtoMessage() {}
+
+func (x *ParseImageMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseImageMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ParseImageMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ParseImageMessagesRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ParseImageMessagesRequest) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *ParseImageMessagesRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ParseImageMessagesRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+// 批量解析截图请求，截图按聊天记录从早到晚的顺序排列，最多 10 张
+type ParseImageMessagesBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ImageUrls     []string               `protobuf:"bytes,2,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // 同 ParseImageMessagesRequest.time_zone
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseImageMessagesBatchRequest) Reset() {
+	*x = ParseImageMessagesBatchRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseImageMessagesBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseImageMessagesBatchRequest) ProtoMessage() {}
+
+func (x *ParseImageMessagesBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseImageMessagesBatchRequest.ProtoReflect.Descriptor instead.
+func (*ParseImageMessagesBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ParseImageMessagesBatchRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ParseImageMessagesBatchRequest) GetImageUrls() []string {
+	if x != nil {
+		return x.ImageUrls
+	}
+	return nil
+}
+
+func (x *ParseImageMessagesBatchRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type ParseImageMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Messages      []*ChatMessage         `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	JobId         string                 `protobuf:"bytes,4,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // async 为 true 时返回
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseImageMessagesResponse) Reset() {
+	*x = ParseImageMessagesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseImageMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseImageMessagesResponse) ProtoMessage() {}
+
+func (x *ParseImageMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseImageMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ParseImageMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ParseImageMessagesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ParseImageMessagesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ParseImageMessagesResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ParseImageMessagesResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ParsedImageLine 截图中识别出的一条聊天记录
+type ParsedImageLine struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Role        string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"` // FRIEND, SELF
+	SenderName  string                 `protobuf:"bytes,2,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`
+	Content     string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	MsgAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=msg_at,json=msgAt,proto3" json:"msg_at,omitempty"`                   // 推断的发送时间
+	TimeText    string                 `protobuf:"bytes,5,opt,name=time_text,json=timeText,proto3" json:"time_text,omitempty"`          // 截图中显示的时间文字，只读
+	Duplicate   bool                   `protobuf:"varint,6,opt,name=duplicate,proto3" json:"duplicate,omitempty"`                       // 与会话中已有的消息重复，只读
+	DuplicateOf string                 `protobuf:"bytes,7,opt,name=duplicate_of,json=duplicateOf,proto3" json:"duplicate_of,omitempty"` // 重复的消息 id，只读
+	// 保存的位置：排在该消息之后。预览时为按发送时间建议的位置，为空表示排在会话最前面
+	// 确认时为空则沿用上一行的位置，第一行为空时排在会话最前面
+	AfterMessageId string `protobuf:"bytes,8,opt,name=after_message_id,json=afterMessageId,proto3" json:"after_message_id,omitempty"`
+	Image          int32  `protobuf:"varint,9,opt,name=image,proto3" json:"image,omitempty"` // 来源截图在 image_urls 中的序号，从 1 开始，保存时作为附件；为 0 时没有来源截图
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ParsedImageLine) Reset() {
+	*x = ParsedImageLine{}
+	mi := &file_proto_message_message_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParsedImageLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParsedImageLine) ProtoMessage() {}
+
+func (x *ParsedImageLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParsedImageLine.ProtoReflect.Descriptor instead.
+func (*ParsedImageLine) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ParsedImageLine) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ParsedImageLine) GetSenderName() string {
+	if x != nil {
+		return x.SenderName
+	}
+	return ""
+}
+
+func (x *ParsedImageLine) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ParsedImageLine) GetMsgAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MsgAt
+	}
+	return nil
+}
+
+func (x *ParsedImageLine) GetTimeText() string {
+	if x != nil {
+		return x.TimeText
+	}
+	return ""
+}
+
+func (x *ParsedImageLine) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *ParsedImageLine) GetDuplicateOf() string {
+	if x != nil {
+		return x.DuplicateOf
+	}
+	return ""
+}
+
+func (x *ParsedImageLine) GetAfterMessageId() string {
+	if x != nil {
+		return x.AfterMessageId
+	}
+	return ""
+}
+
+func (x *ParsedImageLine) GetImage() int32 {
+	if x != nil {
+		return x.Image
+	}
+	return 0
+}
+
+// 截图解析预览请求，截图按聊天记录从早到晚的顺序排列，最多 10 张
+type PreviewImageMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ImageUrls     []string               `protobuf:"bytes,2,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // 同 ParseImageMessagesRequest.time_zone
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewImageMessagesRequest) Reset() {
+	*x = PreviewImageMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewImageMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewImageMessagesRequest) ProtoMessage() {}
+
+func (x *PreviewImageMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewImageMessagesRequest.ProtoReflect.Descriptor instead.
+func (*PreviewImageMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *PreviewImageMessagesRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PreviewImageMessagesRequest) GetImageUrls() []string {
+	if x != nil {
+		return x.ImageUrls
+	}
+	return nil
+}
+
+func (x *PreviewImageMessagesRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type PreviewImageMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ParseId       string                 `protobuf:"bytes,1,opt,name=parse_id,json=parseId,proto3" json:"parse_id,omitempty"` // 未识别出聊天记录时为空
+	Lines         []*ParsedImageLine     `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // 超过该时间未确认的预览会被删除
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewImageMessagesResponse) Reset() {
+	*x = PreviewImageMessagesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewImageMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewImageMessagesResponse) ProtoMessage() {}
+
+func (x *PreviewImageMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewImageMessagesResponse.ProtoReflect.Descriptor instead.
+func (*PreviewImageMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PreviewImageMessagesResponse) GetParseId() string {
+	if x != nil {
+		return x.ParseId
+	}
+	return ""
+}
+
+func (x *PreviewImageMessagesResponse) GetLines() []*ParsedImageLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *PreviewImageMessagesResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type ConfirmImageMessagesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	ParseId string                 `protobuf:"bytes,1,opt,name=parse_id,json=parseId,proto3" json:"parse_id,omitempty"`
+	// 用户修正后的聊天记录，按顺序保存到 after_message_id 指定的位置，不需要的行直接去掉；为空时按建议的位置保存预览中不重复的行
+	// msg_at 为空时沿用上一行的时间
+	Lines         []*ParsedImageLine `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmImageMessagesRequest) Reset() {
+	*x = ConfirmImageMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmImageMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmImageMessagesRequest) ProtoMessage() {}
+
+func (x *ConfirmImageMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmImageMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmImageMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ConfirmImageMessagesRequest) GetParseId() string {
+	if x != nil {
+		return x.ParseId
+	}
+	return ""
+}
+
+func (x *ConfirmImageMessagesRequest) GetLines() []*ParsedImageLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+// 导入聊天记录请求，content 和 file_url 二选一
+// 支持的格式：
+//   - TEXT: 微信/QQ 导出的文本记录，每条消息以「2024-01-02 15:04:05 张三」或「张三 2024-01-02 15:04:05」开头，
+//     下面的行是消息内容；也支持「2024-01-02 15:04 张三: 内容」的单行格式
+//   - CSV: 第一行为表头 time,sender,content[,role]，没有表头时按 time,sender,content 解析
+//   - JSON: [{"sender": "张三", "role": "SELF", "content": "内容", "time": "2024-01-02 15:04:05"}]，
+//     也可以是 {"messages": [...]}；role 可选，time 支持常见日期格式、RFC3339 和秒级时间戳
+type ImportChatHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`                              // TEXT, CSV, JSON，不填时根据文件名和内容自动判断
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                            // 记录内容
+	FileUrl       string                 `protobuf:"bytes,4,opt,name=file_url,json=fileUrl,proto3" json:"file_url,omitempty"`             // 通过文件上传接口上传的记录文件
+	SelfNames     []string               `protobuf:"bytes,5,rep,name=self_names,json=selfNames,proto3" json:"self_names,omitempty"`       // 用户自己的发送人名称，其余发送人视为朋友
+	FriendNames   []string               `protobuf:"bytes,6,rep,name=friend_names,json=friendNames,proto3" json:"friend_names,omitempty"` // 朋友的发送人名称，只填写该项时其余发送人视为自己
+	DryRun        bool                   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`               // 只预览解析结果，不保存
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportChatHistoryRequest) Reset() {
+	*x = ImportChatHistoryRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportChatHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportChatHistoryRequest) ProtoMessage() {}
+
+func (x *ImportChatHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportChatHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ImportChatHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ImportChatHistoryRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ImportChatHistoryRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportChatHistoryRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ImportChatHistoryRequest) GetFileUrl() string {
+	if x != nil {
+		return x.FileUrl
+	}
+	return ""
+}
+
+func (x *ImportChatHistoryRequest) GetSelfNames() []string {
+	if x != nil {
+		return x.SelfNames
+	}
+	return nil
+}
+
+func (x *ImportChatHistoryRequest) GetFriendNames() []string {
+	if x != nil {
+		return x.FriendNames
+	}
+	return nil
+}
+
+func (x *ImportChatHistoryRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ImportChatHistoryResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Messages       []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`                                    // 新增的消息；dry_run 时为预览，没有 id
+	ImportedCount  int32                  `protobuf:"varint,2,opt,name=imported_count,json=importedCount,proto3" json:"imported_count,omitempty"`    // 新增（dry_run 时为将要新增）的消息数量
+	DuplicateCount int32                  `protobuf:"varint,3,opt,name=duplicate_count,json=duplicateCount,proto3" json:"duplicate_count,omitempty"` // 会话中已存在而跳过的消息数量
+	SkippedCount   int32                  `protobuf:"varint,4,opt,name=skipped_count,json=skippedCount,proto3" json:"skipped_count,omitempty"`       // 无法解析而跳过的行或记录数量
+	Senders        []string               `protobuf:"bytes,5,rep,name=senders,proto3" json:"senders,omitempty"`                                      // 记录中出现的全部发送人
+	UnknownSenders []string               `protobuf:"bytes,6,rep,name=unknown_senders,json=unknownSenders,proto3" json:"unknown_senders,omitempty"`  // 无法判断角色的发送人，需要通过 self_names/friend_names 指定
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportChatHistoryResponse) Reset() {
+	*x = ImportChatHistoryResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportChatHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportChatHistoryResponse) ProtoMessage() {}
+
+func (x *ImportChatHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportChatHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ImportChatHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ImportChatHistoryResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ImportChatHistoryResponse) GetImportedCount() int32 {
+	if x != nil {
+		return x.ImportedCount
+	}
+	return 0
+}
+
+func (x *ImportChatHistoryResponse) GetDuplicateCount() int32 {
+	if x != nil {
+		return x.DuplicateCount
+	}
+	return 0
+}
+
+func (x *ImportChatHistoryResponse) GetSkippedCount() int32 {
+	if x != nil {
+		return x.SkippedCount
+	}
+	return 0
+}
+
+func (x *ImportChatHistoryResponse) GetSenders() []string {
+	if x != nil {
+		return x.Senders
+	}
+	return nil
+}
+
+func (x *ImportChatHistoryResponse) GetUnknownSenders() []string {
+	if x != nil {
+		return x.UnknownSenders
+	}
+	return nil
+}
+
+// 导出会话请求
+// 导出 HISTORY 消息、当前分支上的 CONSULT 消息，翻译放在原消息旁边
+// JSON 格式的 messages 与 ImportChatHistory 的 JSON 格式兼容，重新导入时只导入 HISTORY 消息
+type ExportChatSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // MARKDOWN, HTML, JSON，默认 MARKDOWN
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChatSessionRequest) Reset() {
+	*x = ExportChatSessionRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChatSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChatSessionRequest) ProtoMessage() {}
+
+func (x *ExportChatSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChatSessionRequest.ProtoReflect.Descriptor instead.
+func (*ExportChatSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ExportChatSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ExportChatSessionRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type ExportChatSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`                        // 带签名的下载链接
+	FileUrl       string                 `protobuf:"bytes,2,opt,name=file_url,json=fileUrl,proto3" json:"file_url,omitempty"` // 导出文件在 OSS 中的路径
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	MessageCount  int32                  `protobuf:"varint,4,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"` // 导出的消息数量
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`           // 下载链接的过期时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChatSessionResponse) Reset() {
+	*x = ExportChatSessionResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChatSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChatSessionResponse) ProtoMessage() {}
+
+func (x *ExportChatSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChatSessionResponse.ProtoReflect.Descriptor instead.
+func (*ExportChatSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ExportChatSessionResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ExportChatSessionResponse) GetFileUrl() string {
+	if x != nil {
+		return x.FileUrl
+	}
+	return ""
+}
+
+func (x *ExportChatSessionResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportChatSessionResponse) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *ExportChatSessionResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// 移动消息请求，翻译、AI 回复等子消息会一起移动，子消息不能脱离父消息单独移动
+type MoveChatMessagesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Ids             []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	TargetSessionId string                 `protobuf:"bytes,2,opt,name=target_session_id,json=targetSessionId,proto3" json:"target_session_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MoveChatMessagesRequest) Reset() {
+	*x = MoveChatMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveChatMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveChatMessagesRequest) ProtoMessage() {}
+
+func (x *MoveChatMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveChatMessagesRequest.ProtoReflect.Descriptor instead.
+func (*MoveChatMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *MoveChatMessagesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *MoveChatMessagesRequest) GetTargetSessionId() string {
+	if x != nil {
+		return x.TargetSessionId
+	}
+	return ""
+}
+
+type MoveChatMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"` // 移动的全部消息，包括子消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveChatMessagesResponse) Reset() {
+	*x = MoveChatMessagesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveChatMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveChatMessagesResponse) ProtoMessage() {}
+
+func (x *MoveChatMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveChatMessagesResponse.ProtoReflect.Descriptor instead.
+func (*MoveChatMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *MoveChatMessagesResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// 复制消息请求，规则同移动，复制后的消息使用新的 id
+type CopyChatMessagesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Ids             []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	TargetSessionId string                 `protobuf:"bytes,2,opt,name=target_session_id,json=targetSessionId,proto3" json:"target_session_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CopyChatMessagesRequest) Reset() {
+	*x = CopyChatMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyChatMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyChatMessagesRequest) ProtoMessage() {}
+
+func (x *CopyChatMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyChatMessagesRequest.ProtoReflect.Descriptor instead.
+func (*CopyChatMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CopyChatMessagesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *CopyChatMessagesRequest) GetTargetSessionId() string {
+	if x != nil {
+		return x.TargetSessionId
+	}
+	return ""
+}
+
+type CopyChatMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"` // 复制出的全部消息，包括子消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyChatMessagesResponse) Reset() {
+	*x = CopyChatMessagesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyChatMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyChatMessagesResponse) ProtoMessage() {}
+
+func (x *CopyChatMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyChatMessagesResponse.ProtoReflect.Descriptor instead.
+func (*CopyChatMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CopyChatMessagesResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// 合并会话请求，合并后的消息按 msg_at 排序并使用新的 id
+type MergeChatSessionsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SourceSessionId string                 `protobuf:"bytes,1,opt,name=source_session_id,json=sourceSessionId,proto3" json:"source_session_id,omitempty"`
+	TargetSessionId string                 `protobuf:"bytes,2,opt,name=target_session_id,json=targetSessionId,proto3" json:"target_session_id,omitempty"`
+	ProfileFrom     string                 `protobuf:"bytes,3,opt,name=profile_from,json=profileFrom,proto3" json:"profile_from,omitempty"` // 合并后会话使用的 Profile：TARGET（默认）、SOURCE
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergeChatSessionsRequest) Reset() {
+	*x = MergeChatSessionsRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeChatSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeChatSessionsRequest) ProtoMessage() {}
+
+func (x *MergeChatSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeChatSessionsRequest.ProtoReflect.Descriptor instead.
+func (*MergeChatSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *MergeChatSessionsRequest) GetSourceSessionId() string {
+	if x != nil {
+		return x.SourceSessionId
+	}
+	return ""
+}
+
+func (x *MergeChatSessionsRequest) GetTargetSessionId() string {
+	if x != nil {
+		return x.TargetSessionId
+	}
+	return ""
+}
+
+func (x *MergeChatSessionsRequest) GetProfileFrom() string {
+	if x != nil {
+		return x.ProfileFrom
+	}
+	return ""
+}
+
+type MergeChatSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageCount  int32                  `protobuf:"varint,1,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"` // 合并后目标会话的消息数量
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeChatSessionsResponse) Reset() {
+	*x = MergeChatSessionsResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeChatSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeChatSessionsResponse) ProtoMessage() {}
+
+func (x *MergeChatSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeChatSessionsResponse.ProtoReflect.Descriptor instead.
+func (*MergeChatSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *MergeChatSessionsResponse) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+// 用户反馈 点赞/踩/评论
+type FeedbackToMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 会话ID
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 消息ID
+	Attitude      string                 `protobuf:"bytes,3,opt,name=attitude,proto3" json:"attitude,omitempty"`                    // 态度: up, down
+	Feedback      string                 `protobuf:"bytes,4,opt,name=feedback,proto3" json:"feedback,omitempty"`                    // 文字反馈内容
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                            // 标签
+	Reasons       []string               `protobuf:"bytes,6,rep,name=reasons,proto3" json:"reasons,omitempty"`                      // 原因分类: inaccurate, irrelevant, unhelpful, tone, too_long, too_short, unsafe, helpful, other
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedbackToMessageRequest) Reset() {
+	*x = FeedbackToMessageRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedbackToMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedbackToMessageRequest) ProtoMessage() {}
+
+func (x *FeedbackToMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedbackToMessageRequest.ProtoReflect.Descriptor instead.
+func (*FeedbackToMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *FeedbackToMessageRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *FeedbackToMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *FeedbackToMessageRequest) GetAttitude() string {
+	if x != nil {
+		return x.Attitude
+	}
+	return ""
+}
+
+func (x *FeedbackToMessageRequest) GetFeedback() string {
+	if x != nil {
+		return x.Feedback
+	}
+	return ""
+}
+
+func (x *FeedbackToMessageRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *FeedbackToMessageRequest) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type FeedbackToMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedbackToMessageResponse) Reset() {
+	*x = FeedbackToMessageResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedbackToMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedbackToMessageResponse) ProtoMessage() {}
+
+func (x *FeedbackToMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedbackToMessageResponse.ProtoReflect.Descriptor instead.
+func (*FeedbackToMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *FeedbackToMessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// 反馈报表请求，仅管理员可用
+type GetFeedbackReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // 反馈时间范围（可选）
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	GroupBy       []string               `protobuf:"bytes,3,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`       // 分组维度: prompt_key, ai_model, msg_type, day，默认全部
+	PromptKey     string                 `protobuf:"bytes,4,opt,name=prompt_key,json=promptKey,proto3" json:"prompt_key,omitempty"` // 只统计某个提示词（可选）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFeedbackReportRequest) Reset() {
+	*x = GetFeedbackReportRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeedbackReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeedbackReportRequest) ProtoMessage() {}
+
+func (x *GetFeedbackReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeedbackReportRequest.ProtoReflect.Descriptor instead.
+func (*GetFeedbackReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetFeedbackReportRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetFeedbackReportRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetFeedbackReportRequest) GetGroupBy() []string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+func (x *GetFeedbackReportRequest) GetPromptKey() string {
+	if x != nil {
+		return x.PromptKey
+	}
+	return ""
+}
+
+// 单个分组的统计结果，未参与分组的维度为空
+type FeedbackReportRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PromptKey     string                 `protobuf:"bytes,1,opt,name=prompt_key,json=promptKey,proto3" json:"prompt_key,omitempty"`
+	PromptVersion string                 `protobuf:"bytes,2,opt,name=prompt_version,json=promptVersion,proto3" json:"prompt_version,omitempty"`
+	AiModel       string                 `protobuf:"bytes,3,opt,name=ai_model,json=aiModel,proto3" json:"ai_model,omitempty"`
+	MsgType       string                 `protobuf:"bytes,4,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	Day           string                 `protobuf:"bytes,5,opt,name=day,proto3" json:"day,omitempty"` // YYYY-MM-DD
+	UpCount       int32                  `protobuf:"varint,6,opt,name=up_count,json=upCount,proto3" json:"up_count,omitempty"`
+	DownCount     int32                  `protobuf:"varint,7,opt,name=down_count,json=downCount,proto3" json:"down_count,omitempty"`
+	UpRate        float64                `protobuf:"fixed64,8,opt,name=up_rate,json=upRate,proto3" json:"up_rate,omitempty"`
+	DownRate      float64                `protobuf:"fixed64,9,opt,name=down_rate,json=downRate,proto3" json:"down_rate,omitempty"`
+	DownReasons   map[string]int32       `protobuf:"bytes,10,rep,name=down_reasons,json=downReasons,proto3" json:"down_reasons,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // 点踩原因的次数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedbackReportRow) Reset() {
+	*x = FeedbackReportRow{}
+	mi := &file_proto_message_message_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedbackReportRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedbackReportRow) ProtoMessage() {}
+
+func (x *FeedbackReportRow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedbackReportRow.ProtoReflect.Descriptor instead.
+func (*FeedbackReportRow) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *FeedbackReportRow) GetPromptKey() string {
+	if x != nil {
+		return x.PromptKey
+	}
+	return ""
+}
+
+func (x *FeedbackReportRow) GetPromptVersion() string {
+	if x != nil {
+		return x.PromptVersion
+	}
+	return ""
+}
+
+func (x *FeedbackReportRow) GetAiModel() string {
+	if x != nil {
+		return x.AiModel
+	}
+	return ""
+}
+
+func (x *FeedbackReportRow) GetMsgType() string {
+	if x != nil {
+		return x.MsgType
+	}
+	return ""
+}
+
+func (x *FeedbackReportRow) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *FeedbackReportRow) GetUpCount() int32 {
+	if x != nil {
+		return x.UpCount
+	}
+	return 0
+}
+
+func (x *FeedbackReportRow) GetDownCount() int32 {
+	if x != nil {
+		return x.DownCount
+	}
+	return 0
+}
+
+func (x *FeedbackReportRow) GetUpRate() float64 {
+	if x != nil {
+		return x.UpRate
+	}
+	return 0
+}
+
+func (x *FeedbackReportRow) GetDownRate() float64 {
+	if x != nil {
+		return x.DownRate
+	}
+	return 0
+}
+
+func (x *FeedbackReportRow) GetDownReasons() map[string]int32 {
+	if x != nil {
+		return x.DownReasons
+	}
+	return nil
+}
+
+type GetFeedbackReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*FeedbackReportRow   `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"` // 按日期倒序、点踩率倒序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFeedbackReportResponse) Reset() {
+	*x = GetFeedbackReportResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeedbackReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeedbackReportResponse) ProtoMessage() {}
+
+func (x *GetFeedbackReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeedbackReportResponse.ProtoReflect.Descriptor instead.
+func (*GetFeedbackReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetFeedbackReportResponse) GetRows() []*FeedbackReportRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// 回复建议请求
+type SuggestRepliesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 对方发送的聊天记录（FRIEND HISTORY）
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`                         // 建议数量，默认 3，最多 5
+	Tones         []string               `protobuf:"bytes,4,rep,name=tones,proto3" json:"tones,omitempty"`                          // 希望的语气（可选）：formal, warm, assertive, humorous, concise, apologetic
+	Hint          string                 `protobuf:"bytes,5,opt,name=hint,proto3" json:"hint,omitempty"`                            // 用户补充的回复意图（可选）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestRepliesRequest) Reset() {
+	*x = SuggestRepliesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestRepliesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRepliesRequest) ProtoMessage() {}
+
+func (x *SuggestRepliesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRepliesRequest.ProtoReflect.Descriptor instead.
+func (*SuggestRepliesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SuggestRepliesRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SuggestRepliesRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SuggestRepliesRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SuggestRepliesRequest) GetTones() []string {
+	if x != nil {
+		return x.Tones
+	}
+	return nil
+}
+
+func (x *SuggestRepliesRequest) GetHint() string {
+	if x != nil {
+		return x.Hint
+	}
+	return ""
+}
+
+// 一条回复建议
+type SuggestedReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Tone          string                 `protobuf:"bytes,2,opt,name=tone,proto3" json:"tone,omitempty"`           // 语气标签
+	Rationale     string                 `protobuf:"bytes,3,opt,name=rationale,proto3" json:"rationale,omitempty"` // 一句话说明为什么这样回复
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestedReply) Reset() {
+	*x = SuggestedReply{}
+	mi := &file_proto_message_message_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestedReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestedReply) ProtoMessage() {}
+
+func (x *SuggestedReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestedReply.ProtoReflect.Descriptor instead.
+func (*SuggestedReply) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SuggestedReply) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SuggestedReply) GetTone() string {
+	if x != nil {
+		return x.Tone
+	}
+	return ""
+}
+
+func (x *SuggestedReply) GetRationale() string {
+	if x != nil {
+		return x.Rationale
+	}
+	return ""
+}
+
+type SuggestRepliesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*SuggestedReply      `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestRepliesResponse) Reset() {
+	*x = SuggestRepliesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestRepliesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRepliesResponse) ProtoMessage() {}
+
+func (x *SuggestRepliesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRepliesResponse.ProtoReflect.Descriptor instead.
+func (*SuggestRepliesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SuggestRepliesResponse) GetSuggestions() []*SuggestedReply {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// 保存回复建议请求，保存为 SELF HISTORY 消息
+type SaveSuggestedReplyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 建议针对的消息
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                      // 可以是修改后的建议内容
+	Tone          string                 `protobuf:"bytes,4,opt,name=tone,proto3" json:"tone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveSuggestedReplyRequest) Reset() {
+	*x = SaveSuggestedReplyRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveSuggestedReplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveSuggestedReplyRequest) ProtoMessage() {}
+
+func (x *SaveSuggestedReplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveSuggestedReplyRequest.ProtoReflect.Descriptor instead.
+func (*SaveSuggestedReplyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SaveSuggestedReplyRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SaveSuggestedReplyRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SaveSuggestedReplyRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SaveSuggestedReplyRequest) GetTone() string {
+	if x != nil {
+		return x.Tone
+	}
+	return ""
+}
+
+type SaveSuggestedReplyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *ChatMessage           `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveSuggestedReplyResponse) Reset() {
+	*x = SaveSuggestedReplyResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveSuggestedReplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveSuggestedReplyResponse) ProtoMessage() {}
+
+func (x *SaveSuggestedReplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveSuggestedReplyResponse.ProtoReflect.Descriptor instead.
+func (*SaveSuggestedReplyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *SaveSuggestedReplyResponse) GetMessage() *ChatMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 会话情感走势请求
+type GetSessionSentimentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // 按消息时间过滤（可选）
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionSentimentRequest) Reset() {
+	*x = GetSessionSentimentRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionSentimentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionSentimentRequest) ProtoMessage() {}
+
+func (x *GetSessionSentimentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionSentimentRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionSentimentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetSessionSentimentRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *GetSessionSentimentRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetSessionSentimentRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+// 一条聊天记录的情感分析结果
+type MessageSentiment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`           // SELF, FRIEND
+	Sentiment     string                 `protobuf:"bytes,3,opt,name=sentiment,proto3" json:"sentiment,omitempty"` // positive, neutral, negative
+	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`       // -1 最消极，1 最积极
+	Emotions      []string               `protobuf:"bytes,5,rep,name=emotions,proto3" json:"emotions,omitempty"`   // joy, gratitude, affection, excitement, calm, surprise, confusion, anxiety, sadness, disappointment, frustration, anger
+	MsgAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=msg_at,json=msgAt,proto3" json:"msg_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageSentiment) Reset() {
+	*x = MessageSentiment{}
+	mi := &file_proto_message_message_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageSentiment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageSentiment) ProtoMessage() {}
+
+func (x *MessageSentiment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageSentiment.ProtoReflect.Descriptor instead.
+func (*MessageSentiment) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *MessageSentiment) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MessageSentiment) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *MessageSentiment) GetSentiment() string {
+	if x != nil {
+		return x.Sentiment
+	}
+	return ""
+}
+
+func (x *MessageSentiment) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *MessageSentiment) GetEmotions() []string {
+	if x != nil {
+		return x.Emotions
+	}
+	return nil
+}
+
+func (x *MessageSentiment) GetMsgAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MsgAt
+	}
+	return nil
+}
+
+// 某一天某一方的情感统计
+type SentimentDailyPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`   // YYYY-MM-DD
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"` // SELF, FRIEND
+	AvgScore      float32                `protobuf:"fixed32,3,opt,name=avg_score,json=avgScore,proto3" json:"avg_score,omitempty"`
+	MessageCount  int32                  `protobuf:"varint,4,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`
+	PositiveCount int32                  `protobuf:"varint,5,opt,name=positive_count,json=positiveCount,proto3" json:"positive_count,omitempty"`
+	NeutralCount  int32                  `protobuf:"varint,6,opt,name=neutral_count,json=neutralCount,proto3" json:"neutral_count,omitempty"`
+	NegativeCount int32                  `protobuf:"varint,7,opt,name=negative_count,json=negativeCount,proto3" json:"negative_count,omitempty"`
+	TopEmotion    string                 `protobuf:"bytes,8,opt,name=top_emotion,json=topEmotion,proto3" json:"top_emotion,omitempty"` // 出现最多的情绪，没有时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SentimentDailyPoint) Reset() {
+	*x = SentimentDailyPoint{}
+	mi := &file_proto_message_message_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SentimentDailyPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SentimentDailyPoint) ProtoMessage() {}
+
+func (x *SentimentDailyPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SentimentDailyPoint.ProtoReflect.Descriptor instead.
+func (*SentimentDailyPoint) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SentimentDailyPoint) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *SentimentDailyPoint) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *SentimentDailyPoint) GetAvgScore() float32 {
+	if x != nil {
+		return x.AvgScore
+	}
+	return 0
+}
+
+func (x *SentimentDailyPoint) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *SentimentDailyPoint) GetPositiveCount() int32 {
+	if x != nil {
+		return x.PositiveCount
+	}
+	return 0
+}
+
+func (x *SentimentDailyPoint) GetNeutralCount() int32 {
+	if x != nil {
+		return x.NeutralCount
+	}
+	return 0
+}
+
+func (x *SentimentDailyPoint) GetNegativeCount() int32 {
+	if x != nil {
+		return x.NegativeCount
+	}
+	return 0
+}
+
+func (x *SentimentDailyPoint) GetTopEmotion() string {
+	if x != nil {
+		return x.TopEmotion
+	}
+	return ""
+}
+
+// 情感转折点，某条消息的情感与此前同一方的情感差异明显
+type SentimentTurningPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Day           string                 `protobuf:"bytes,3,opt,name=day,proto3" json:"day,omitempty"`
+	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`
+	PreviousScore float32                `protobuf:"fixed32,5,opt,name=previous_score,json=previousScore,proto3" json:"previous_score,omitempty"` // 此前同一方最近几条消息的平均分
+	Delta         float32                `protobuf:"fixed32,6,opt,name=delta,proto3" json:"delta,omitempty"`                                      // score - previous_score
+	Emotions      []string               `protobuf:"bytes,7,rep,name=emotions,proto3" json:"emotions,omitempty"`
+	Content       string                 `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`
+	MsgAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=msg_at,json=msgAt,proto3" json:"msg_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SentimentTurningPoint) Reset() {
+	*x = SentimentTurningPoint{}
+	mi := &file_proto_message_message_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SentimentTurningPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SentimentTurningPoint) ProtoMessage() {}
+
+func (x *SentimentTurningPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SentimentTurningPoint.ProtoReflect.Descriptor instead.
+func (*SentimentTurningPoint) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SentimentTurningPoint) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SentimentTurningPoint) GetRole() string {
 	if x != nil {
-		return x.Content
+		return x.Role
 	}
 	return ""
 }
 
-func (x *SendConsultMessageRequest) GetTargetId() string {
-	if x != nil && x.TargetId != nil {
-		return *x.TargetId
+func (x *SentimentTurningPoint) GetDay() string {
+	if x != nil {
+		return x.Day
 	}
 	return ""
 }
 
-type SendConsultMessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Consult       *ChatMessage           `protobuf:"bytes,1,opt,name=consult,proto3" json:"consult,omitempty"` // 创建的咨询消息
-	Reply         *ChatMessage           `protobuf:"bytes,2,opt,name=reply,proto3" json:"reply,omitempty"`     // 回复的消息
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *SendConsultMessageResponse) Reset() {
-	*x = SendConsultMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[10]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SentimentTurningPoint) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
 }
 
-func (x *SendConsultMessageResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SentimentTurningPoint) GetPreviousScore() float32 {
+	if x != nil {
+		return x.PreviousScore
+	}
+	return 0
 }
 
-func (*SendConsultMessageResponse) ProtoMessage() {}
-
-func (x *SendConsultMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[10]
+func (x *SentimentTurningPoint) GetDelta() float32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Delta
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use SendConsultMessageResponse.ProtoReflect.Descriptor instead.
-func (*SendConsultMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{10}
+func (x *SentimentTurningPoint) GetEmotions() []string {
+	if x != nil {
+		return x.Emotions
+	}
+	return nil
 }
 
-func (x *SendConsultMessageResponse) GetConsult() *ChatMessage {
+func (x *SentimentTurningPoint) GetContent() string {
 	if x != nil {
-		return x.Consult
+		return x.Content
 	}
-	return nil
+	return ""
 }
 
-func (x *SendConsultMessageResponse) GetReply() *ChatMessage {
+func (x *SentimentTurningPoint) GetMsgAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Reply
+		return x.MsgAt
 	}
 	return nil
 }
 
-// 解析图片消息请求（保持不变）
-type ParseImageMessagesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 改为 session_id，不再需要 profile_id
-	ImageUrl      string                 `protobuf:"bytes,2,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+type GetSessionSentimentResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Series        []*SentimentDailyPoint   `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`                                    // 按天、角色排序
+	TurningPoints []*SentimentTurningPoint `protobuf:"bytes,2,rep,name=turning_points,json=turningPoints,proto3" json:"turning_points,omitempty"` // 按消息时间排序
+	Messages      []*MessageSentiment      `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	PendingCount  int32                    `protobuf:"varint,4,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"` // 本次未分析完的消息数，大于 0 时可以稍后重新请求
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ParseImageMessagesRequest) Reset() {
-	*x = ParseImageMessagesRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[11]
+func (x *GetSessionSentimentResponse) Reset() {
+	*x = GetSessionSentimentResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ParseImageMessagesRequest) String() string {
+func (x *GetSessionSentimentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ParseImageMessagesRequest) ProtoMessage() {}
+func (*GetSessionSentimentResponse) ProtoMessage() {}
 
-func (x *ParseImageMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[11]
+func (x *GetSessionSentimentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,49 +4073,62 @@ func (x *ParseImageMessagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ParseImageMessagesRequest.ProtoReflect.Descriptor instead.
-func (*ParseImageMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetSessionSentimentResponse.ProtoReflect.Descriptor instead.
+func (*GetSessionSentimentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *ParseImageMessagesRequest) GetSessionId() string {
+func (x *GetSessionSentimentResponse) GetSeries() []*SentimentDailyPoint {
 	if x != nil {
-		return x.SessionId
+		return x.Series
 	}
-	return ""
+	return nil
 }
 
-func (x *ParseImageMessagesRequest) GetImageUrl() string {
+func (x *GetSessionSentimentResponse) GetTurningPoints() []*SentimentTurningPoint {
 	if x != nil {
-		return x.ImageUrl
+		return x.TurningPoints
 	}
-	return ""
+	return nil
 }
 
-type ParseImageMessagesResponse struct {
+func (x *GetSessionSentimentResponse) GetMessages() []*MessageSentiment {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetSessionSentimentResponse) GetPendingCount() int32 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+// 订阅消息变更请求
+type WatchChatMessagesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Messages      []*ChatMessage         `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	SessionIds    []string               `protobuf:"bytes,1,rep,name=session_ids,json=sessionIds,proto3" json:"session_ids,omitempty"` // 只订阅这些会话（可选，不填则订阅全部会话）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ParseImageMessagesResponse) Reset() {
-	*x = ParseImageMessagesResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[12]
+func (x *WatchChatMessagesRequest) Reset() {
+	*x = WatchChatMessagesRequest{}
+	mi := &file_proto_message_message_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ParseImageMessagesResponse) String() string {
+func (x *WatchChatMessagesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ParseImageMessagesResponse) ProtoMessage() {}
+func (*WatchChatMessagesRequest) ProtoMessage() {}
 
-func (x *ParseImageMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[12]
+func (x *WatchChatMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -771,59 +4139,44 @@ func (x *ParseImageMessagesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ParseImageMessagesResponse.ProtoReflect.Descriptor instead.
-func (*ParseImageMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *ParseImageMessagesResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
-}
-
-func (x *ParseImageMessagesResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+// Deprecated: Use WatchChatMessagesRequest.ProtoReflect.Descriptor instead.
+func (*WatchChatMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *ParseImageMessagesResponse) GetMessages() []*ChatMessage {
+func (x *WatchChatMessagesRequest) GetSessionIds() []string {
 	if x != nil {
-		return x.Messages
+		return x.SessionIds
 	}
 	return nil
 }
 
-// 用户反馈 点赞/踩/评论
-type FeedbackToMessageRequest struct {
+// 消息变更事件
+type ChatMessageEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 会话ID
-	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 消息ID
-	Attitude      string                 `protobuf:"bytes,3,opt,name=attitude,proto3" json:"attitude,omitempty"`                    // 态度: up, down
-	Feedback      string                 `protobuf:"bytes,4,opt,name=feedback,proto3" json:"feedback,omitempty"`                    // 文字反馈内容
-	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                            // 标签
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // CREATED, UPDATED, DELETED
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Message       *ChatMessage           `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // 变更后的消息，DELETED 时为删除前的消息
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FeedbackToMessageRequest) Reset() {
-	*x = FeedbackToMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[13]
+func (x *ChatMessageEvent) Reset() {
+	*x = ChatMessageEvent{}
+	mi := &file_proto_message_message_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FeedbackToMessageRequest) String() string {
+func (x *ChatMessageEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FeedbackToMessageRequest) ProtoMessage() {}
+func (*ChatMessageEvent) ProtoMessage() {}
 
-func (x *FeedbackToMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[13]
+func (x *ChatMessageEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -834,68 +4187,61 @@ func (x *FeedbackToMessageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FeedbackToMessageRequest.ProtoReflect.Descriptor instead.
-func (*FeedbackToMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{13}
-}
-
-func (x *FeedbackToMessageRequest) GetSessionId() string {
-	if x != nil {
-		return x.SessionId
-	}
-	return ""
+// Deprecated: Use ChatMessageEvent.ProtoReflect.Descriptor instead.
+func (*ChatMessageEvent) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *FeedbackToMessageRequest) GetMessageId() string {
+func (x *ChatMessageEvent) GetType() string {
 	if x != nil {
-		return x.MessageId
+		return x.Type
 	}
 	return ""
 }
 
-func (x *FeedbackToMessageRequest) GetAttitude() string {
+func (x *ChatMessageEvent) GetSessionId() string {
 	if x != nil {
-		return x.Attitude
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *FeedbackToMessageRequest) GetFeedback() string {
+func (x *ChatMessageEvent) GetMessage() *ChatMessage {
 	if x != nil {
-		return x.Feedback
+		return x.Message
 	}
-	return ""
+	return nil
 }
 
-func (x *FeedbackToMessageRequest) GetTags() []string {
+func (x *ChatMessageEvent) GetOccurredAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Tags
+		return x.OccurredAt
 	}
 	return nil
 }
 
-type FeedbackToMessageResponse struct {
+type WatchChatMessagesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Events        []*ChatMessageEvent    `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"` // 心跳帧为空
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FeedbackToMessageResponse) Reset() {
-	*x = FeedbackToMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[14]
+func (x *WatchChatMessagesResponse) Reset() {
+	*x = WatchChatMessagesResponse{}
+	mi := &file_proto_message_message_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FeedbackToMessageResponse) String() string {
+func (x *WatchChatMessagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FeedbackToMessageResponse) ProtoMessage() {}
+func (*WatchChatMessagesResponse) ProtoMessage() {}
 
-func (x *FeedbackToMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[14]
+func (x *WatchChatMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_message_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -906,16 +4252,16 @@ func (x *FeedbackToMessageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FeedbackToMessageResponse.ProtoReflect.Descriptor instead.
-func (*FeedbackToMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use WatchChatMessagesResponse.ProtoReflect.Descriptor instead.
+func (*WatchChatMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_message_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *FeedbackToMessageResponse) GetSuccess() bool {
+func (x *WatchChatMessagesResponse) GetEvents() []*ChatMessageEvent {
 	if x != nil {
-		return x.Success
+		return x.Events
 	}
-	return false
+	return nil
 }
 
 // 保留旧的消息类型定义以向后兼容
@@ -941,7 +4287,7 @@ type ConsultMessage struct {
 
 func (x *ConsultMessage) Reset() {
 	*x = ConsultMessage{}
-	mi := &file_proto_message_message_proto_msgTypes[15]
+	mi := &file_proto_message_message_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -953,7 +4299,7 @@ func (x *ConsultMessage) String() string {
 func (*ConsultMessage) ProtoMessage() {}
 
 func (x *ConsultMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[15]
+	mi := &file_proto_message_message_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -966,7 +4312,7 @@ func (x *ConsultMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConsultMessage.ProtoReflect.Descriptor instead.
 func (*ConsultMessage) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{15}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *ConsultMessage) GetId() string {
@@ -1070,7 +4416,7 @@ type ListConsultMessagesRequest struct {
 
 func (x *ListConsultMessagesRequest) Reset() {
 	*x = ListConsultMessagesRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[16]
+	mi := &file_proto_message_message_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1082,7 +4428,7 @@ func (x *ListConsultMessagesRequest) String() string {
 func (*ListConsultMessagesRequest) ProtoMessage() {}
 
 func (x *ListConsultMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[16]
+	mi := &file_proto_message_message_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1095,7 +4441,7 @@ func (x *ListConsultMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListConsultMessagesRequest.ProtoReflect.Descriptor instead.
 func (*ListConsultMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{16}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *ListConsultMessagesRequest) GetMsgType() string {
@@ -1151,7 +4497,7 @@ type ListConsultMessagesResponse struct {
 
 func (x *ListConsultMessagesResponse) Reset() {
 	*x = ListConsultMessagesResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[17]
+	mi := &file_proto_message_message_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1163,7 +4509,7 @@ func (x *ListConsultMessagesResponse) String() string {
 func (*ListConsultMessagesResponse) ProtoMessage() {}
 
 func (x *ListConsultMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[17]
+	mi := &file_proto_message_message_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1176,7 +4522,7 @@ func (x *ListConsultMessagesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListConsultMessagesResponse.ProtoReflect.Descriptor instead.
 func (*ListConsultMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{17}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *ListConsultMessagesResponse) GetMessages() []*ConsultMessage {
@@ -1203,7 +4549,7 @@ type UpdateConsultMessageRequest struct {
 
 func (x *UpdateConsultMessageRequest) Reset() {
 	*x = UpdateConsultMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[18]
+	mi := &file_proto_message_message_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1215,7 +4561,7 @@ func (x *UpdateConsultMessageRequest) String() string {
 func (*UpdateConsultMessageRequest) ProtoMessage() {}
 
 func (x *UpdateConsultMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[18]
+	mi := &file_proto_message_message_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1228,7 +4574,7 @@ func (x *UpdateConsultMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateConsultMessageRequest.ProtoReflect.Descriptor instead.
 func (*UpdateConsultMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{18}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *UpdateConsultMessageRequest) GetMessages() []*ConsultMessage {
@@ -1248,7 +4594,7 @@ type UpdateConsultMessageResponse struct {
 
 func (x *UpdateConsultMessageResponse) Reset() {
 	*x = UpdateConsultMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[19]
+	mi := &file_proto_message_message_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1260,7 +4606,7 @@ func (x *UpdateConsultMessageResponse) String() string {
 func (*UpdateConsultMessageResponse) ProtoMessage() {}
 
 func (x *UpdateConsultMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[19]
+	mi := &file_proto_message_message_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1273,7 +4619,7 @@ func (x *UpdateConsultMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateConsultMessageResponse.ProtoReflect.Descriptor instead.
 func (*UpdateConsultMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{19}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *UpdateConsultMessageResponse) GetMessages() []*ConsultMessage {
@@ -1293,7 +4639,7 @@ type RecallConsultMessageRequest struct {
 
 func (x *RecallConsultMessageRequest) Reset() {
 	*x = RecallConsultMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[20]
+	mi := &file_proto_message_message_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1305,7 +4651,7 @@ func (x *RecallConsultMessageRequest) String() string {
 func (*RecallConsultMessageRequest) ProtoMessage() {}
 
 func (x *RecallConsultMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[20]
+	mi := &file_proto_message_message_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1318,7 +4664,7 @@ func (x *RecallConsultMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RecallConsultMessageRequest.ProtoReflect.Descriptor instead.
 func (*RecallConsultMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{20}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *RecallConsultMessageRequest) GetIds() []string {
@@ -1337,7 +4683,7 @@ type RecallConsultMessageResponse struct {
 
 func (x *RecallConsultMessageResponse) Reset() {
 	*x = RecallConsultMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[21]
+	mi := &file_proto_message_message_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1349,7 +4695,7 @@ func (x *RecallConsultMessageResponse) String() string {
 func (*RecallConsultMessageResponse) ProtoMessage() {}
 
 func (x *RecallConsultMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[21]
+	mi := &file_proto_message_message_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1362,7 +4708,7 @@ func (x *RecallConsultMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RecallConsultMessageResponse.ProtoReflect.Descriptor instead.
 func (*RecallConsultMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{21}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{71}
 }
 
 // Deprecated: Marked as deprecated in proto/message/message.proto.
@@ -1377,7 +4723,7 @@ type ListFriendMessagesRequest struct {
 
 func (x *ListFriendMessagesRequest) Reset() {
 	*x = ListFriendMessagesRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[22]
+	mi := &file_proto_message_message_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1389,7 +4735,7 @@ func (x *ListFriendMessagesRequest) String() string {
 func (*ListFriendMessagesRequest) ProtoMessage() {}
 
 func (x *ListFriendMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[22]
+	mi := &file_proto_message_message_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1402,7 +4748,7 @@ func (x *ListFriendMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListFriendMessagesRequest.ProtoReflect.Descriptor instead.
 func (*ListFriendMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{22}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *ListFriendMessagesRequest) GetProfileId() string {
@@ -1437,7 +4783,7 @@ type ListFriendMessagesResponse struct {
 
 func (x *ListFriendMessagesResponse) Reset() {
 	*x = ListFriendMessagesResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[23]
+	mi := &file_proto_message_message_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1449,7 +4795,7 @@ func (x *ListFriendMessagesResponse) String() string {
 func (*ListFriendMessagesResponse) ProtoMessage() {}
 
 func (x *ListFriendMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[23]
+	mi := &file_proto_message_message_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1462,7 +4808,7 @@ func (x *ListFriendMessagesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListFriendMessagesResponse.ProtoReflect.Descriptor instead.
 func (*ListFriendMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{23}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *ListFriendMessagesResponse) GetMessages() []*ConsultMessage {
@@ -1489,7 +4835,7 @@ type CreateFriendMessageRequest struct {
 
 func (x *CreateFriendMessageRequest) Reset() {
 	*x = CreateFriendMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[24]
+	mi := &file_proto_message_message_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1501,7 +4847,7 @@ func (x *CreateFriendMessageRequest) String() string {
 func (*CreateFriendMessageRequest) ProtoMessage() {}
 
 func (x *CreateFriendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[24]
+	mi := &file_proto_message_message_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1514,7 +4860,7 @@ func (x *CreateFriendMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateFriendMessageRequest.ProtoReflect.Descriptor instead.
 func (*CreateFriendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{24}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{74}
 }
 
 func (x *CreateFriendMessageRequest) GetMessages() []*ConsultMessage {
@@ -1534,7 +4880,7 @@ type CreateFriendMessageResponse struct {
 
 func (x *CreateFriendMessageResponse) Reset() {
 	*x = CreateFriendMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[25]
+	mi := &file_proto_message_message_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1546,7 +4892,7 @@ func (x *CreateFriendMessageResponse) String() string {
 func (*CreateFriendMessageResponse) ProtoMessage() {}
 
 func (x *CreateFriendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[25]
+	mi := &file_proto_message_message_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1559,7 +4905,7 @@ func (x *CreateFriendMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateFriendMessageResponse.ProtoReflect.Descriptor instead.
 func (*CreateFriendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{25}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{75}
 }
 
 func (x *CreateFriendMessageResponse) GetMessages() []*ConsultMessage {
@@ -1579,7 +4925,7 @@ type UpdateFriendMessageRequest struct {
 
 func (x *UpdateFriendMessageRequest) Reset() {
 	*x = UpdateFriendMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[26]
+	mi := &file_proto_message_message_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1591,7 +4937,7 @@ func (x *UpdateFriendMessageRequest) String() string {
 func (*UpdateFriendMessageRequest) ProtoMessage() {}
 
 func (x *UpdateFriendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[26]
+	mi := &file_proto_message_message_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1604,7 +4950,7 @@ func (x *UpdateFriendMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateFriendMessageRequest.ProtoReflect.Descriptor instead.
 func (*UpdateFriendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{26}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{76}
 }
 
 func (x *UpdateFriendMessageRequest) GetMessages() []*ConsultMessage {
@@ -1624,7 +4970,7 @@ type UpdateFriendMessageResponse struct {
 
 func (x *UpdateFriendMessageResponse) Reset() {
 	*x = UpdateFriendMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[27]
+	mi := &file_proto_message_message_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1636,7 +4982,7 @@ func (x *UpdateFriendMessageResponse) String() string {
 func (*UpdateFriendMessageResponse) ProtoMessage() {}
 
 func (x *UpdateFriendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[27]
+	mi := &file_proto_message_message_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1649,7 +4995,7 @@ func (x *UpdateFriendMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateFriendMessageResponse.ProtoReflect.Descriptor instead.
 func (*UpdateFriendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{27}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{77}
 }
 
 func (x *UpdateFriendMessageResponse) GetMessages() []*ConsultMessage {
@@ -1669,7 +5015,7 @@ type DeleteFriendMessageRequest struct {
 
 func (x *DeleteFriendMessageRequest) Reset() {
 	*x = DeleteFriendMessageRequest{}
-	mi := &file_proto_message_message_proto_msgTypes[28]
+	mi := &file_proto_message_message_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1681,7 +5027,7 @@ func (x *DeleteFriendMessageRequest) String() string {
 func (*DeleteFriendMessageRequest) ProtoMessage() {}
 
 func (x *DeleteFriendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[28]
+	mi := &file_proto_message_message_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1694,7 +5040,7 @@ func (x *DeleteFriendMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteFriendMessageRequest.ProtoReflect.Descriptor instead.
 func (*DeleteFriendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{28}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{78}
 }
 
 func (x *DeleteFriendMessageRequest) GetIds() []string {
@@ -1713,7 +5059,7 @@ type DeleteFriendMessageResponse struct {
 
 func (x *DeleteFriendMessageResponse) Reset() {
 	*x = DeleteFriendMessageResponse{}
-	mi := &file_proto_message_message_proto_msgTypes[29]
+	mi := &file_proto_message_message_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1725,7 +5071,7 @@ func (x *DeleteFriendMessageResponse) String() string {
 func (*DeleteFriendMessageResponse) ProtoMessage() {}
 
 func (x *DeleteFriendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_message_proto_msgTypes[29]
+	mi := &file_proto_message_message_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1738,14 +5084,14 @@ func (x *DeleteFriendMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteFriendMessageResponse.ProtoReflect.Descriptor instead.
 func (*DeleteFriendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_message_proto_rawDescGZIP(), []int{29}
+	return file_proto_message_message_proto_rawDescGZIP(), []int{79}
 }
 
 var File_proto_message_message_proto protoreflect.FileDescriptor
 
 const file_proto_message_message_proto_rawDesc = "" +
 	"\n" +
-	"\x1bproto/message/message.proto\x12\amessage\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\"\xc0\x03\n" +
+	"\x1bproto/message/message.proto\x12\amessage\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\"\xab\x05\n" +
 	"\vChatMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
@@ -1762,8 +5108,33 @@ const file_proto_message_message_proto_rawDesc = "" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x120\n" +
-	"\x11translate_content\x18\f \x01(\tH\x00R\x10translateContent\x88\x01\x01B\x14\n" +
-	"\x12_translate_content\"\xd6\x01\n" +
+	"\x11translate_content\x18\f \x01(\tH\x00R\x10translateContent\x88\x01\x01\x12'\n" +
+	"\x0ftranslate_stale\x18\r \x01(\bR\x0etranslateStale\x12!\n" +
+	"\fbranch_index\x18\x0e \x01(\x05R\vbranchIndex\x12!\n" +
+	"\fbranch_count\x18\x0f \x01(\x05R\vbranchCount\x12\x1f\n" +
+	"\vsender_name\x18\x10 \x01(\tR\n" +
+	"senderName\x12\x1b\n" +
+	"\tuser_tags\x18\x11 \x03(\tR\buserTags\x12<\n" +
+	"\vattachments\x18\x12 \x03(\v2\x1a.message.MessageAttachmentR\vattachmentsB\x14\n" +
+	"\x12_translate_content\"\xdf\x01\n" +
+	"\x11MessageAttachment\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12@\n" +
+	"\x0eurl_expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\furlExpiresAt\x12\x1b\n" +
+	"\tfile_type\x18\x04 \x01(\tR\bfileType\x12#\n" +
+	"\roriginal_name\x18\x05 \x01(\tR\foriginalName\x12\x1b\n" +
+	"\tfile_size\x18\x06 \x01(\x03R\bfileSize\"\xf9\x01\n" +
+	"\x13ChatMessageRevision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\teditor_id\x18\x03 \x01(\tR\beditorId\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x19\n" +
+	"\bmsg_type\x18\x05 \x01(\tR\amsgType\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xba\x03\n" +
 	"\x17ListChatMessagesRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
@@ -1771,13 +5142,23 @@ const file_proto_message_message_proto_rawDesc = "" +
 	"\x05roles\x18\x03 \x03(\tR\x05roles\x12\x10\n" +
 	"\x03ids\x18\x04 \x03(\tR\x03ids\x12\x1d\n" +
 	"\n" +
-	"parent_ids\x18\x05 \x03(\tR\tparentIds\x12\x1b\n" +
+	"parent_ids\x18\x05 \x03(\tR\tparentIds\x12!\n" +
+	"\finclude_tags\x18\x06 \x03(\tR\vincludeTags\x12!\n" +
+	"\fexclude_tags\x18\a \x03(\tR\vexcludeTags\x12\x1b\n" +
 	"\tpage_size\x18\x15 \x01(\x05R\bpageSize\x12\x1d\n" +
 	"\n" +
-	"page_token\x18\x16 \x01(\tR\tpageToken\"t\n" +
+	"page_token\x18\x16 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tanchor_id\x18\x17 \x01(\tR\banchorId\x12;\n" +
+	"\vanchor_time\x18\x18 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"anchorTime\x12!\n" +
+	"\fbefore_count\x18\x19 \x01(\x05R\vbeforeCount\x12\x1f\n" +
+	"\vafter_count\x18\x1a \x01(\x05R\n" +
+	"afterCount\"\xbb\x01\n" +
 	"\x18ListChatMessagesResponse\x120\n" +
 	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"L\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12(\n" +
+	"\x10newer_page_token\x18\x03 \x01(\tR\x0enewerPageToken\x12\x1b\n" +
+	"\tanchor_id\x18\x04 \x01(\tR\banchorId\"L\n" +
 	"\x18CreateChatMessageRequest\x120\n" +
 	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\"M\n" +
 	"\x19CreateChatMessageResponse\x120\n" +
@@ -1785,29 +5166,180 @@ const file_proto_message_message_proto_rawDesc = "" +
 	"\x18UpdateChatMessageRequest\x120\n" +
 	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\"M\n" +
 	"\x19UpdateChatMessageResponse\x120\n" +
-	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\",\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\"<\n" +
+	"\x1bListMessageRevisionsRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"Z\n" +
+	"\x1cListMessageRevisionsResponse\x12:\n" +
+	"\trevisions\x18\x01 \x03(\v2\x1c.message.ChatMessageRevisionR\trevisions\"\\\n" +
+	"\x1aRollbackChatMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1f\n" +
+	"\vrevision_id\x18\x02 \x01(\tR\n" +
+	"revisionId\"M\n" +
+	"\x1bRollbackChatMessageResponse\x12.\n" +
+	"\amessage\x18\x01 \x01(\v2\x14.message.ChatMessageR\amessage\",\n" +
 	"\x18DeleteChatMessageRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\tR\x03ids\"@\n" +
 	"\x19DeleteChatMessageResponse\x12#\n" +
-	"\rdeleted_count\x18\x01 \x01(\x05R\fdeletedCount\"\x84\x01\n" +
+	"\rdeleted_count\x18\x01 \x01(\x05R\fdeletedCount\"\xc4\x01\n" +
 	"\x19SendConsultMessageRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12 \n" +
-	"\ttarget_id\x18\x04 \x01(\tH\x00R\btargetId\x88\x01\x01B\f\n" +
+	"\ttarget_id\x18\x04 \x01(\tH\x00R\btargetId\x88\x01\x01\x12\x1c\n" +
+	"\aedit_id\x18\x05 \x01(\tH\x01R\x06editId\x88\x01\x01\x12\x14\n" +
+	"\x05async\x18\x06 \x01(\bR\x05asyncB\f\n" +
+	"\n" +
+	"_target_idB\n" +
 	"\n" +
-	"_target_id\"x\n" +
+	"\b_edit_id\"\x8f\x01\n" +
 	"\x1aSendConsultMessageResponse\x12.\n" +
 	"\aconsult\x18\x01 \x01(\v2\x14.message.ChatMessageR\aconsult\x12*\n" +
-	"\x05reply\x18\x02 \x01(\v2\x14.message.ChatMessageR\x05reply\"W\n" +
+	"\x05reply\x18\x02 \x01(\v2\x14.message.ChatMessageR\x05reply\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"\x90\x01\n" +
+	"\x1cStreamConsultMessageResponse\x12.\n" +
+	"\aconsult\x18\x01 \x01(\v2\x14.message.ChatMessageR\aconsult\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\tR\x05delta\x12*\n" +
+	"\x05reply\x18\x03 \x01(\v2\x14.message.ChatMessageR\x05reply\"0\n" +
+	"\x0fListTagsRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"[\n" +
+	"\n" +
+	"MessageTag\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06system\x18\x02 \x01(\bR\x06system\x12#\n" +
+	"\rmessage_count\x18\x03 \x01(\x05R\fmessageCount\";\n" +
+	"\x10ListTagsResponse\x12'\n" +
+	"\x04tags\x18\x01 \x03(\v2\x13.message.MessageTagR\x04tags\"O\n" +
+	"\x18UpdateMessageTagsRequest\x12\x1f\n" +
+	"\vmessage_ids\x18\x01 \x03(\tR\n" +
+	"messageIds\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"M\n" +
+	"\x19UpdateMessageTagsResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\"\xc3\x02\n" +
+	"\x19SearchChatMessagesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x12\x19\n" +
+	"\bmsg_type\x18\x04 \x01(\tR\amsgType\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x129\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x15 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x16 \x01(\tR\tpageToken\"\xd6\x01\n" +
+	"\x14SearchChatMessageHit\x12.\n" +
+	"\amessage\x18\x01 \x01(\v2\x14.message.ChatMessageR\amessage\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\x12!\n" +
+	"\fsession_name\x18\x03 \x01(\tR\vsessionName\x12%\n" +
+	"\x0esession_avatar\x18\x04 \x01(\tR\rsessionAvatar\x12*\n" +
+	"\x11anchor_page_token\x18\x05 \x01(\tR\x0fanchorPageToken\"w\n" +
+	"\x1aSearchChatMessagesResponse\x121\n" +
+	"\x04hits\x18\x01 \x03(\v2\x1d.message.SearchChatMessageHitR\x04hits\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"Z\n" +
+	"\x1aListConsultBranchesRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"l\n" +
+	"\x1bListConsultBranchesResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\x12\x1b\n" +
+	"\tactive_id\x18\x02 \x01(\tR\bactiveId\"Z\n" +
+	"\x1aSelectConsultBranchRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"\x1d\n" +
+	"\x1bSelectConsultBranchResponse\"\x8a\x01\n" +
 	"\x19ParseImageMessagesRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1b\n" +
-	"\timage_url\x18\x02 \x01(\tR\bimageUrl\"\x82\x01\n" +
+	"\timage_url\x18\x02 \x01(\tR\bimageUrl\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x12\x14\n" +
+	"\x05async\x18\x04 \x01(\bR\x05async\"{\n" +
+	"\x1eParseImageMessagesBatchRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\x02 \x03(\tR\timageUrls\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\"\x99\x01\n" +
 	"\x1aParseImageMessagesResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
-	"\bmessages\x18\x03 \x03(\v2\x14.message.ChatMessageR\bmessages\"\xa4\x01\n" +
+	"\bmessages\x18\x03 \x03(\v2\x14.message.ChatMessageR\bmessages\x12\x15\n" +
+	"\x06job_id\x18\x04 \x01(\tR\x05jobId\"\xb1\x02\n" +
+	"\x0fParsedImageLine\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x1f\n" +
+	"\vsender_name\x18\x02 \x01(\tR\n" +
+	"senderName\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x121\n" +
+	"\x06msg_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05msgAt\x12\x1b\n" +
+	"\ttime_text\x18\x05 \x01(\tR\btimeText\x12\x1c\n" +
+	"\tduplicate\x18\x06 \x01(\bR\tduplicate\x12!\n" +
+	"\fduplicate_of\x18\a \x01(\tR\vduplicateOf\x12(\n" +
+	"\x10after_message_id\x18\b \x01(\tR\x0eafterMessageId\x12\x14\n" +
+	"\x05image\x18\t \x01(\x05R\x05image\"x\n" +
+	"\x1bPreviewImageMessagesRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\x02 \x03(\tR\timageUrls\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\"\xa4\x01\n" +
+	"\x1cPreviewImageMessagesResponse\x12\x19\n" +
+	"\bparse_id\x18\x01 \x01(\tR\aparseId\x12.\n" +
+	"\x05lines\x18\x02 \x03(\v2\x18.message.ParsedImageLineR\x05lines\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"h\n" +
+	"\x1bConfirmImageMessagesRequest\x12\x19\n" +
+	"\bparse_id\x18\x01 \x01(\tR\aparseId\x12.\n" +
+	"\x05lines\x18\x02 \x03(\v2\x18.message.ParsedImageLineR\x05lines\"\xe1\x01\n" +
+	"\x18ImportChatHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x19\n" +
+	"\bfile_url\x18\x04 \x01(\tR\afileUrl\x12\x1d\n" +
+	"\n" +
+	"self_names\x18\x05 \x03(\tR\tselfNames\x12!\n" +
+	"\ffriend_names\x18\x06 \x03(\tR\vfriendNames\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\"\x85\x02\n" +
+	"\x19ImportChatHistoryResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\x12%\n" +
+	"\x0eimported_count\x18\x02 \x01(\x05R\rimportedCount\x12'\n" +
+	"\x0fduplicate_count\x18\x03 \x01(\x05R\x0eduplicateCount\x12#\n" +
+	"\rskipped_count\x18\x04 \x01(\x05R\fskippedCount\x12\x18\n" +
+	"\asenders\x18\x05 \x03(\tR\asenders\x12'\n" +
+	"\x0funknown_senders\x18\x06 \x03(\tR\x0eunknownSenders\"Q\n" +
+	"\x18ExportChatSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"\xc4\x01\n" +
+	"\x19ExportChatSessionResponse\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x19\n" +
+	"\bfile_url\x18\x02 \x01(\tR\afileUrl\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12#\n" +
+	"\rmessage_count\x18\x04 \x01(\x05R\fmessageCount\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"W\n" +
+	"\x17MoveChatMessagesRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12*\n" +
+	"\x11target_session_id\x18\x02 \x01(\tR\x0ftargetSessionId\"L\n" +
+	"\x18MoveChatMessagesResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\"W\n" +
+	"\x17CopyChatMessagesRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12*\n" +
+	"\x11target_session_id\x18\x02 \x01(\tR\x0ftargetSessionId\"L\n" +
+	"\x18CopyChatMessagesResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.message.ChatMessageR\bmessages\"\x95\x01\n" +
+	"\x18MergeChatSessionsRequest\x12*\n" +
+	"\x11source_session_id\x18\x01 \x01(\tR\x0fsourceSessionId\x12*\n" +
+	"\x11target_session_id\x18\x02 \x01(\tR\x0ftargetSessionId\x12!\n" +
+	"\fprofile_from\x18\x03 \x01(\tR\vprofileFrom\"@\n" +
+	"\x19MergeChatSessionsResponse\x12#\n" +
+	"\rmessage_count\x18\x01 \x01(\x05R\fmessageCount\"\xbe\x01\n" +
 	"\x18FeedbackToMessageRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
@@ -1815,9 +5347,111 @@ const file_proto_message_message_proto_rawDesc = "" +
 	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1a\n" +
 	"\battitude\x18\x03 \x01(\tR\battitude\x12\x1a\n" +
 	"\bfeedback\x18\x04 \x01(\tR\bfeedback\x12\x12\n" +
-	"\x04tags\x18\x05 \x03(\tR\x04tags\"5\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x18\n" +
+	"\areasons\x18\x06 \x03(\tR\areasons\"5\n" +
 	"\x19FeedbackToMessageResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x9e\x03\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xc6\x01\n" +
+	"\x18GetFeedbackReportRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x19\n" +
+	"\bgroup_by\x18\x03 \x03(\tR\agroupBy\x12\x1d\n" +
+	"\n" +
+	"prompt_key\x18\x04 \x01(\tR\tpromptKey\"\xa1\x03\n" +
+	"\x11FeedbackReportRow\x12\x1d\n" +
+	"\n" +
+	"prompt_key\x18\x01 \x01(\tR\tpromptKey\x12%\n" +
+	"\x0eprompt_version\x18\x02 \x01(\tR\rpromptVersion\x12\x19\n" +
+	"\bai_model\x18\x03 \x01(\tR\aaiModel\x12\x19\n" +
+	"\bmsg_type\x18\x04 \x01(\tR\amsgType\x12\x10\n" +
+	"\x03day\x18\x05 \x01(\tR\x03day\x12\x19\n" +
+	"\bup_count\x18\x06 \x01(\x05R\aupCount\x12\x1d\n" +
+	"\n" +
+	"down_count\x18\a \x01(\x05R\tdownCount\x12\x17\n" +
+	"\aup_rate\x18\b \x01(\x01R\x06upRate\x12\x1b\n" +
+	"\tdown_rate\x18\t \x01(\x01R\bdownRate\x12N\n" +
+	"\fdown_reasons\x18\n" +
+	" \x03(\v2+.message.FeedbackReportRow.DownReasonsEntryR\vdownReasons\x1a>\n" +
+	"\x10DownReasonsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"K\n" +
+	"\x19GetFeedbackReportResponse\x12.\n" +
+	"\x04rows\x18\x01 \x03(\v2\x1a.message.FeedbackReportRowR\x04rows\"\x95\x01\n" +
+	"\x15SuggestRepliesRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\x12\x14\n" +
+	"\x05tones\x18\x04 \x03(\tR\x05tones\x12\x12\n" +
+	"\x04hint\x18\x05 \x01(\tR\x04hint\"\\\n" +
+	"\x0eSuggestedReply\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tone\x18\x02 \x01(\tR\x04tone\x12\x1c\n" +
+	"\trationale\x18\x03 \x01(\tR\trationale\"S\n" +
+	"\x16SuggestRepliesResponse\x129\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x17.message.SuggestedReplyR\vsuggestions\"\x87\x01\n" +
+	"\x19SaveSuggestedReplyRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tone\x18\x04 \x01(\tR\x04tone\"L\n" +
+	"\x1aSaveSuggestedReplyResponse\x12.\n" +
+	"\amessage\x18\x01 \x01(\v2\x14.message.ChatMessageR\amessage\"\xad\x01\n" +
+	"\x1aGetSessionSentimentRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"\xc8\x01\n" +
+	"\x10MessageSentiment\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1c\n" +
+	"\tsentiment\x18\x03 \x01(\tR\tsentiment\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\x12\x1a\n" +
+	"\bemotions\x18\x05 \x03(\tR\bemotions\x121\n" +
+	"\x06msg_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05msgAt\"\x91\x02\n" +
+	"\x13SentimentDailyPoint\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1b\n" +
+	"\tavg_score\x18\x03 \x01(\x02R\bavgScore\x12#\n" +
+	"\rmessage_count\x18\x04 \x01(\x05R\fmessageCount\x12%\n" +
+	"\x0epositive_count\x18\x05 \x01(\x05R\rpositiveCount\x12#\n" +
+	"\rneutral_count\x18\x06 \x01(\x05R\fneutralCount\x12%\n" +
+	"\x0enegative_count\x18\a \x01(\x05R\rnegativeCount\x12\x1f\n" +
+	"\vtop_emotion\x18\b \x01(\tR\n" +
+	"topEmotion\"\x98\x02\n" +
+	"\x15SentimentTurningPoint\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x10\n" +
+	"\x03day\x18\x03 \x01(\tR\x03day\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\x12%\n" +
+	"\x0eprevious_score\x18\x05 \x01(\x02R\rpreviousScore\x12\x14\n" +
+	"\x05delta\x18\x06 \x01(\x02R\x05delta\x12\x1a\n" +
+	"\bemotions\x18\a \x03(\tR\bemotions\x12\x18\n" +
+	"\acontent\x18\b \x01(\tR\acontent\x121\n" +
+	"\x06msg_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x05msgAt\"\xf6\x01\n" +
+	"\x1bGetSessionSentimentResponse\x124\n" +
+	"\x06series\x18\x01 \x03(\v2\x1c.message.SentimentDailyPointR\x06series\x12E\n" +
+	"\x0eturning_points\x18\x02 \x03(\v2\x1e.message.SentimentTurningPointR\rturningPoints\x125\n" +
+	"\bmessages\x18\x03 \x03(\v2\x19.message.MessageSentimentR\bmessages\x12#\n" +
+	"\rpending_count\x18\x04 \x01(\x05R\fpendingCount\";\n" +
+	"\x18WatchChatMessagesRequest\x12\x1f\n" +
+	"\vsession_ids\x18\x01 \x03(\tR\n" +
+	"sessionIds\"\xb2\x01\n" +
+	"\x10ChatMessageEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12.\n" +
+	"\amessage\x18\x03 \x01(\v2\x14.message.ChatMessageR\amessage\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"N\n" +
+	"\x19WatchChatMessagesResponse\x121\n" +
+	"\x06events\x18\x01 \x03(\v2\x19.message.ChatMessageEventR\x06events\"\x9e\x03\n" +
 	"\x0eConsultMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
@@ -1875,15 +5509,37 @@ const file_proto_message_message_proto_rawDesc = "" +
 	"\bmessages\x18\x01 \x03(\v2\x17.message.ConsultMessageR\bmessages:\x02\x18\x01\"2\n" +
 	"\x1aDeleteFriendMessageRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\tR\x03ids:\x02\x18\x01\"!\n" +
-	"\x1bDeleteFriendMessageResponse:\x02\x18\x012\xb9\b\n" +
+	"\x1bDeleteFriendMessageResponse:\x02\x18\x012\xdf\"\n" +
 	"\x12ChatMessageService\x12\x90\x01\n" +
 	"\x10ListChatMessages\x12 .message.ListChatMessagesRequest\x1a!.message.ListChatMessagesResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/message.ChatMessageService/ListChatMessages\x12\x94\x01\n" +
 	"\x11CreateChatMessage\x12!.message.CreateChatMessageRequest\x1a\".message.CreateChatMessageResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/CreateChatMessage\x12\x94\x01\n" +
-	"\x11UpdateChatMessage\x12!.message.UpdateChatMessageRequest\x1a\".message.UpdateChatMessageResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/UpdateChatMessage\x12\x94\x01\n" +
+	"\x11UpdateChatMessage\x12!.message.UpdateChatMessageRequest\x1a\".message.UpdateChatMessageResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/UpdateChatMessage\x12\xa0\x01\n" +
+	"\x14ListMessageRevisions\x12$.message.ListMessageRevisionsRequest\x1a%.message.ListMessageRevisionsResponse\";\x82\xd3\xe4\x93\x025:\x01*\"0/message.ChatMessageService/ListMessageRevisions\x12\x9c\x01\n" +
+	"\x13RollbackChatMessage\x12#.message.RollbackChatMessageRequest\x1a$.message.RollbackChatMessageResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//message.ChatMessageService/RollbackChatMessage\x12\x94\x01\n" +
 	"\x11DeleteChatMessage\x12!.message.DeleteChatMessageRequest\x1a\".message.DeleteChatMessageResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/DeleteChatMessage\x12\x98\x01\n" +
-	"\x12SendConsultMessage\x12\".message.SendConsultMessageRequest\x1a#.message.SendConsultMessageResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./message.ChatMessageService/SendConsultMessage\x12\x98\x01\n" +
-	"\x12ParseImageMessages\x12\".message.ParseImageMessagesRequest\x1a#.message.ParseImageMessagesResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./message.ChatMessageService/ParseImageMessages\x12\x94\x01\n" +
-	"\x11FeedbackToMessage\x12!.message.FeedbackToMessageRequest\x1a\".message.FeedbackToMessageResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/FeedbackToMessage2\xc8\x02\n" +
+	"\x12SendConsultMessage\x12\".message.SendConsultMessageRequest\x1a#.message.SendConsultMessageResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./message.ChatMessageService/SendConsultMessage\x12\xa0\x01\n" +
+	"\x14StreamConsultMessage\x12\".message.SendConsultMessageRequest\x1a%.message.StreamConsultMessageResponse\";\x82\xd3\xe4\x93\x025:\x01*\"0/message.ChatMessageService/StreamConsultMessage0\x01\x12p\n" +
+	"\bListTags\x12\x18.message.ListTagsRequest\x1a\x19.message.ListTagsResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/message.ChatMessageService/ListTags\x12\x8e\x01\n" +
+	"\x0eAddMessageTags\x12!.message.UpdateMessageTagsRequest\x1a\".message.UpdateMessageTagsResponse\"5\x82\xd3\xe4\x93\x02/:\x01*\"*/message.ChatMessageService/AddMessageTags\x12\x94\x01\n" +
+	"\x11RemoveMessageTags\x12!.message.UpdateMessageTagsRequest\x1a\".message.UpdateMessageTagsResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/RemoveMessageTags\x12\x98\x01\n" +
+	"\x12SearchChatMessages\x12\".message.SearchChatMessagesRequest\x1a#.message.SearchChatMessagesResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./message.ChatMessageService/SearchChatMessages\x12\x9c\x01\n" +
+	"\x13ListConsultBranches\x12#.message.ListConsultBranchesRequest\x1a$.message.ListConsultBranchesResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//message.ChatMessageService/ListConsultBranches\x12\x9c\x01\n" +
+	"\x13SelectConsultBranch\x12#.message.SelectConsultBranchRequest\x1a$.message.SelectConsultBranchResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//message.ChatMessageService/SelectConsultBranch\x12\x98\x01\n" +
+	"\x12ParseImageMessages\x12\".message.ParseImageMessagesRequest\x1a#.message.ParseImageMessagesResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./message.ChatMessageService/ParseImageMessages\x12\xa7\x01\n" +
+	"\x17ParseImageMessagesBatch\x12'.message.ParseImageMessagesBatchRequest\x1a#.message.ParseImageMessagesResponse\">\x82\xd3\xe4\x93\x028:\x01*\"3/message.ChatMessageService/ParseImageMessagesBatch\x12\xa0\x01\n" +
+	"\x14PreviewImageMessages\x12$.message.PreviewImageMessagesRequest\x1a%.message.PreviewImageMessagesResponse\";\x82\xd3\xe4\x93\x025:\x01*\"0/message.ChatMessageService/PreviewImageMessages\x12\x9e\x01\n" +
+	"\x14ConfirmImageMessages\x12$.message.ConfirmImageMessagesRequest\x1a#.message.ParseImageMessagesResponse\";\x82\xd3\xe4\x93\x025:\x01*\"0/message.ChatMessageService/ConfirmImageMessages\x12\x94\x01\n" +
+	"\x11ImportChatHistory\x12!.message.ImportChatHistoryRequest\x1a\".message.ImportChatHistoryResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/ImportChatHistory\x12\x94\x01\n" +
+	"\x11ExportChatSession\x12!.message.ExportChatSessionRequest\x1a\".message.ExportChatSessionResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/ExportChatSession\x12\x90\x01\n" +
+	"\x10MoveChatMessages\x12 .message.MoveChatMessagesRequest\x1a!.message.MoveChatMessagesResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/message.ChatMessageService/MoveChatMessages\x12\x90\x01\n" +
+	"\x10CopyChatMessages\x12 .message.CopyChatMessagesRequest\x1a!.message.CopyChatMessagesResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/message.ChatMessageService/CopyChatMessages\x12\x94\x01\n" +
+	"\x11MergeChatSessions\x12!.message.MergeChatSessionsRequest\x1a\".message.MergeChatSessionsResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/MergeChatSessions\x12\x94\x01\n" +
+	"\x11FeedbackToMessage\x12!.message.FeedbackToMessageRequest\x1a\".message.FeedbackToMessageResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/FeedbackToMessage\x12\x94\x01\n" +
+	"\x11GetFeedbackReport\x12!.message.GetFeedbackReportRequest\x1a\".message.GetFeedbackReportResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/GetFeedbackReport\x12\x88\x01\n" +
+	"\x0eSuggestReplies\x12\x1e.message.SuggestRepliesRequest\x1a\x1f.message.SuggestRepliesResponse\"5\x82\xd3\xe4\x93\x02/:\x01*\"*/message.ChatMessageService/SuggestReplies\x12\x98\x01\n" +
+	"\x12SaveSuggestedReply\x12\".message.SaveSuggestedReplyRequest\x1a#.message.SaveSuggestedReplyResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./message.ChatMessageService/SaveSuggestedReply\x12\x9c\x01\n" +
+	"\x13GetSessionSentiment\x12#.message.GetSessionSentimentRequest\x1a$.message.GetSessionSentimentResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//message.ChatMessageService/GetSessionSentiment\x12\x96\x01\n" +
+	"\x11WatchChatMessages\x12!.message.WatchChatMessagesRequest\x1a\".message.WatchChatMessagesResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/message.ChatMessageService/WatchChatMessages0\x012\xc8\x02\n" +
 	"\x15ConsultMessageService\x12b\n" +
 	"\x13ListConsultMessages\x12#.message.ListConsultMessagesRequest\x1a$.message.ListConsultMessagesResponse\"\x00\x12_\n" +
 	"\x12SendConsultMessage\x12\".message.SendConsultMessageRequest\x1a#.message.SendConsultMessageResponse\"\x00\x12e\n" +
@@ -1907,98 +5563,232 @@ func file_proto_message_message_proto_rawDescGZIP() []byte {
 	return file_proto_message_message_proto_rawDescData
 }
 
-var file_proto_message_message_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_proto_message_message_proto_msgTypes = make([]protoimpl.MessageInfo, 81)
 var file_proto_message_message_proto_goTypes = []any{
-	(*ChatMessage)(nil),                  // 0: message.ChatMessage
-	(*ListChatMessagesRequest)(nil),      // 1: message.ListChatMessagesRequest
-	(*ListChatMessagesResponse)(nil),     // 2: message.ListChatMessagesResponse
-	(*CreateChatMessageRequest)(nil),     // 3: message.CreateChatMessageRequest
-	(*CreateChatMessageResponse)(nil),    // 4: message.CreateChatMessageResponse
-	(*UpdateChatMessageRequest)(nil),     // 5: message.UpdateChatMessageRequest
-	(*UpdateChatMessageResponse)(nil),    // 6: message.UpdateChatMessageResponse
-	(*DeleteChatMessageRequest)(nil),     // 7: message.DeleteChatMessageRequest
-	(*DeleteChatMessageResponse)(nil),    // 8: message.DeleteChatMessageResponse
-	(*SendConsultMessageRequest)(nil),    // 9: message.SendConsultMessageRequest
-	(*SendConsultMessageResponse)(nil),   // 10: message.SendConsultMessageResponse
-	(*ParseImageMessagesRequest)(nil),    // 11: message.ParseImageMessagesRequest
-	(*ParseImageMessagesResponse)(nil),   // 12: message.ParseImageMessagesResponse
-	(*FeedbackToMessageRequest)(nil),     // 13: message.FeedbackToMessageRequest
-	(*FeedbackToMessageResponse)(nil),    // 14: message.FeedbackToMessageResponse
-	(*ConsultMessage)(nil),               // 15: message.ConsultMessage
-	(*ListConsultMessagesRequest)(nil),   // 16: message.ListConsultMessagesRequest
-	(*ListConsultMessagesResponse)(nil),  // 17: message.ListConsultMessagesResponse
-	(*UpdateConsultMessageRequest)(nil),  // 18: message.UpdateConsultMessageRequest
-	(*UpdateConsultMessageResponse)(nil), // 19: message.UpdateConsultMessageResponse
-	(*RecallConsultMessageRequest)(nil),  // 20: message.RecallConsultMessageRequest
-	(*RecallConsultMessageResponse)(nil), // 21: message.RecallConsultMessageResponse
-	(*ListFriendMessagesRequest)(nil),    // 22: message.ListFriendMessagesRequest
-	(*ListFriendMessagesResponse)(nil),   // 23: message.ListFriendMessagesResponse
-	(*CreateFriendMessageRequest)(nil),   // 24: message.CreateFriendMessageRequest
-	(*CreateFriendMessageResponse)(nil),  // 25: message.CreateFriendMessageResponse
-	(*UpdateFriendMessageRequest)(nil),   // 26: message.UpdateFriendMessageRequest
-	(*UpdateFriendMessageResponse)(nil),  // 27: message.UpdateFriendMessageResponse
-	(*DeleteFriendMessageRequest)(nil),   // 28: message.DeleteFriendMessageRequest
-	(*DeleteFriendMessageResponse)(nil),  // 29: message.DeleteFriendMessageResponse
-	(*timestamppb.Timestamp)(nil),        // 30: google.protobuf.Timestamp
+	(*ChatMessage)(nil),                    // 0: message.ChatMessage
+	(*MessageAttachment)(nil),              // 1: message.MessageAttachment
+	(*ChatMessageRevision)(nil),            // 2: message.ChatMessageRevision
+	(*ListChatMessagesRequest)(nil),        // 3: message.ListChatMessagesRequest
+	(*ListChatMessagesResponse)(nil),       // 4: message.ListChatMessagesResponse
+	(*CreateChatMessageRequest)(nil),       // 5: message.CreateChatMessageRequest
+	(*CreateChatMessageResponse)(nil),      // 6: message.CreateChatMessageResponse
+	(*UpdateChatMessageRequest)(nil),       // 7: message.UpdateChatMessageRequest
+	(*UpdateChatMessageResponse)(nil),      // 8: message.UpdateChatMessageResponse
+	(*ListMessageRevisionsRequest)(nil),    // 9: message.ListMessageRevisionsRequest
+	(*ListMessageRevisionsResponse)(nil),   // 10: message.ListMessageRevisionsResponse
+	(*RollbackChatMessageRequest)(nil),     // 11: message.RollbackChatMessageRequest
+	(*RollbackChatMessageResponse)(nil),    // 12: message.RollbackChatMessageResponse
+	(*DeleteChatMessageRequest)(nil),       // 13: message.DeleteChatMessageRequest
+	(*DeleteChatMessageResponse)(nil),      // 14: message.DeleteChatMessageResponse
+	(*SendConsultMessageRequest)(nil),      // 15: message.SendConsultMessageRequest
+	(*SendConsultMessageResponse)(nil),     // 16: message.SendConsultMessageResponse
+	(*StreamConsultMessageResponse)(nil),   // 17: message.StreamConsultMessageResponse
+	(*ListTagsRequest)(nil),                // 18: message.ListTagsRequest
+	(*MessageTag)(nil),                     // 19: message.MessageTag
+	(*ListTagsResponse)(nil),               // 20: message.ListTagsResponse
+	(*UpdateMessageTagsRequest)(nil),       // 21: message.UpdateMessageTagsRequest
+	(*UpdateMessageTagsResponse)(nil),      // 22: message.UpdateMessageTagsResponse
+	(*SearchChatMessagesRequest)(nil),      // 23: message.SearchChatMessagesRequest
+	(*SearchChatMessageHit)(nil),           // 24: message.SearchChatMessageHit
+	(*SearchChatMessagesResponse)(nil),     // 25: message.SearchChatMessagesResponse
+	(*ListConsultBranchesRequest)(nil),     // 26: message.ListConsultBranchesRequest
+	(*ListConsultBranchesResponse)(nil),    // 27: message.ListConsultBranchesResponse
+	(*SelectConsultBranchRequest)(nil),     // 28: message.SelectConsultBranchRequest
+	(*SelectConsultBranchResponse)(nil),    // 29: message.SelectConsultBranchResponse
+	(*ParseImageMessagesRequest)(nil),      // 30: message.ParseImageMessagesRequest
+	(*ParseImageMessagesBatchRequest)(nil), // 31: message.ParseImageMessagesBatchRequest
+	(*ParseImageMessagesResponse)(nil),     // 32: message.ParseImageMessagesResponse
+	(*ParsedImageLine)(nil),                // 33: message.ParsedImageLine
+	(*PreviewImageMessagesRequest)(nil),    // 34: message.PreviewImageMessagesRequest
+	(*PreviewImageMessagesResponse)(nil),   // 35: message.PreviewImageMessagesResponse
+	(*ConfirmImageMessagesRequest)(nil),    // 36: message.ConfirmImageMessagesRequest
+	(*ImportChatHistoryRequest)(nil),       // 37: message.ImportChatHistoryRequest
+	(*ImportChatHistoryResponse)(nil),      // 38: message.ImportChatHistoryResponse
+	(*ExportChatSessionRequest)(nil),       // 39: message.ExportChatSessionRequest
+	(*ExportChatSessionResponse)(nil),      // 40: message.ExportChatSessionResponse
+	(*MoveChatMessagesRequest)(nil),        // 41: message.MoveChatMessagesRequest
+	(*MoveChatMessagesResponse)(nil),       // 42: message.MoveChatMessagesResponse
+	(*CopyChatMessagesRequest)(nil),        // 43: message.CopyChatMessagesRequest
+	(*CopyChatMessagesResponse)(nil),       // 44: message.CopyChatMessagesResponse
+	(*MergeChatSessionsRequest)(nil),       // 45: message.MergeChatSessionsRequest
+	(*MergeChatSessionsResponse)(nil),      // 46: message.MergeChatSessionsResponse
+	(*FeedbackToMessageRequest)(nil),       // 47: message.FeedbackToMessageRequest
+	(*FeedbackToMessageResponse)(nil),      // 48: message.FeedbackToMessageResponse
+	(*GetFeedbackReportRequest)(nil),       // 49: message.GetFeedbackReportRequest
+	(*FeedbackReportRow)(nil),              // 50: message.FeedbackReportRow
+	(*GetFeedbackReportResponse)(nil),      // 51: message.GetFeedbackReportResponse
+	(*SuggestRepliesRequest)(nil),          // 52: message.SuggestRepliesRequest
+	(*SuggestedReply)(nil),                 // 53: message.SuggestedReply
+	(*SuggestRepliesResponse)(nil),         // 54: message.SuggestRepliesResponse
+	(*SaveSuggestedReplyRequest)(nil),      // 55: message.SaveSuggestedReplyRequest
+	(*SaveSuggestedReplyResponse)(nil),     // 56: message.SaveSuggestedReplyResponse
+	(*GetSessionSentimentRequest)(nil),     // 57: message.GetSessionSentimentRequest
+	(*MessageSentiment)(nil),               // 58: message.MessageSentiment
+	(*SentimentDailyPoint)(nil),            // 59: message.SentimentDailyPoint
+	(*SentimentTurningPoint)(nil),          // 60: message.SentimentTurningPoint
+	(*GetSessionSentimentResponse)(nil),    // 61: message.GetSessionSentimentResponse
+	(*WatchChatMessagesRequest)(nil),       // 62: message.WatchChatMessagesRequest
+	(*ChatMessageEvent)(nil),               // 63: message.ChatMessageEvent
+	(*WatchChatMessagesResponse)(nil),      // 64: message.WatchChatMessagesResponse
+	(*ConsultMessage)(nil),                 // 65: message.ConsultMessage
+	(*ListConsultMessagesRequest)(nil),     // 66: message.ListConsultMessagesRequest
+	(*ListConsultMessagesResponse)(nil),    // 67: message.ListConsultMessagesResponse
+	(*UpdateConsultMessageRequest)(nil),    // 68: message.UpdateConsultMessageRequest
+	(*UpdateConsultMessageResponse)(nil),   // 69: message.UpdateConsultMessageResponse
+	(*RecallConsultMessageRequest)(nil),    // 70: message.RecallConsultMessageRequest
+	(*RecallConsultMessageResponse)(nil),   // 71: message.RecallConsultMessageResponse
+	(*ListFriendMessagesRequest)(nil),      // 72: message.ListFriendMessagesRequest
+	(*ListFriendMessagesResponse)(nil),     // 73: message.ListFriendMessagesResponse
+	(*CreateFriendMessageRequest)(nil),     // 74: message.CreateFriendMessageRequest
+	(*CreateFriendMessageResponse)(nil),    // 75: message.CreateFriendMessageResponse
+	(*UpdateFriendMessageRequest)(nil),     // 76: message.UpdateFriendMessageRequest
+	(*UpdateFriendMessageResponse)(nil),    // 77: message.UpdateFriendMessageResponse
+	(*DeleteFriendMessageRequest)(nil),     // 78: message.DeleteFriendMessageRequest
+	(*DeleteFriendMessageResponse)(nil),    // 79: message.DeleteFriendMessageResponse
+	nil,                                    // 80: message.FeedbackReportRow.DownReasonsEntry
+	(*timestamppb.Timestamp)(nil),          // 81: google.protobuf.Timestamp
 }
 var file_proto_message_message_proto_depIdxs = []int32{
-	30, // 0: message.ChatMessage.msg_at:type_name -> google.protobuf.Timestamp
-	30, // 1: message.ChatMessage.created_at:type_name -> google.protobuf.Timestamp
-	30, // 2: message.ChatMessage.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 3: message.ListChatMessagesResponse.messages:type_name -> message.ChatMessage
-	0,  // 4: message.CreateChatMessageRequest.messages:type_name -> message.ChatMessage
-	0,  // 5: message.CreateChatMessageResponse.messages:type_name -> message.ChatMessage
-	0,  // 6: message.UpdateChatMessageRequest.messages:type_name -> message.ChatMessage
-	0,  // 7: message.UpdateChatMessageResponse.messages:type_name -> message.ChatMessage
-	0,  // 8: message.SendConsultMessageResponse.consult:type_name -> message.ChatMessage
-	0,  // 9: message.SendConsultMessageResponse.reply:type_name -> message.ChatMessage
-	0,  // 10: message.ParseImageMessagesResponse.messages:type_name -> message.ChatMessage
-	30, // 11: message.ConsultMessage.msg_at:type_name -> google.protobuf.Timestamp
-	30, // 12: message.ConsultMessage.created_at:type_name -> google.protobuf.Timestamp
-	30, // 13: message.ConsultMessage.updated_at:type_name -> google.protobuf.Timestamp
-	15, // 14: message.ListConsultMessagesResponse.messages:type_name -> message.ConsultMessage
-	15, // 15: message.UpdateConsultMessageRequest.messages:type_name -> message.ConsultMessage
-	15, // 16: message.UpdateConsultMessageResponse.messages:type_name -> message.ConsultMessage
-	15, // 17: message.ListFriendMessagesResponse.messages:type_name -> message.ConsultMessage
-	15, // 18: message.CreateFriendMessageRequest.messages:type_name -> message.ConsultMessage
-	15, // 19: message.CreateFriendMessageResponse.messages:type_name -> message.ConsultMessage
-	15, // 20: message.UpdateFriendMessageRequest.messages:type_name -> message.ConsultMessage
-	15, // 21: message.UpdateFriendMessageResponse.messages:type_name -> message.ConsultMessage
-	1,  // 22: message.ChatMessageService.ListChatMessages:input_type -> message.ListChatMessagesRequest
-	3,  // 23: message.ChatMessageService.CreateChatMessage:input_type -> message.CreateChatMessageRequest
-	5,  // 24: message.ChatMessageService.UpdateChatMessage:input_type -> message.UpdateChatMessageRequest
-	7,  // 25: message.ChatMessageService.DeleteChatMessage:input_type -> message.DeleteChatMessageRequest
-	9,  // 26: message.ChatMessageService.SendConsultMessage:input_type -> message.SendConsultMessageRequest
-	11, // 27: message.ChatMessageService.ParseImageMessages:input_type -> message.ParseImageMessagesRequest
-	13, // 28: message.ChatMessageService.FeedbackToMessage:input_type -> message.FeedbackToMessageRequest
-	16, // 29: message.ConsultMessageService.ListConsultMessages:input_type -> message.ListConsultMessagesRequest
-	9,  // 30: message.ConsultMessageService.SendConsultMessage:input_type -> message.SendConsultMessageRequest
-	20, // 31: message.ConsultMessageService.RecallConsultMessage:input_type -> message.RecallConsultMessageRequest
-	22, // 32: message.FriendMessageService.ListFriendMessages:input_type -> message.ListFriendMessagesRequest
-	24, // 33: message.FriendMessageService.CreateFriendMessage:input_type -> message.CreateFriendMessageRequest
-	26, // 34: message.FriendMessageService.UpdateFriendMessage:input_type -> message.UpdateFriendMessageRequest
-	28, // 35: message.FriendMessageService.DeleteFriendMessage:input_type -> message.DeleteFriendMessageRequest
-	11, // 36: message.FriendMessageService.ParseImageMessages:input_type -> message.ParseImageMessagesRequest
-	2,  // 37: message.ChatMessageService.ListChatMessages:output_type -> message.ListChatMessagesResponse
-	4,  // 38: message.ChatMessageService.CreateChatMessage:output_type -> message.CreateChatMessageResponse
-	6,  // 39: message.ChatMessageService.UpdateChatMessage:output_type -> message.UpdateChatMessageResponse
-	8,  // 40: message.ChatMessageService.DeleteChatMessage:output_type -> message.DeleteChatMessageResponse
-	10, // 41: message.ChatMessageService.SendConsultMessage:output_type -> message.SendConsultMessageResponse
-	12, // 42: message.ChatMessageService.ParseImageMessages:output_type -> message.ParseImageMessagesResponse
-	14, // 43: message.ChatMessageService.FeedbackToMessage:output_type -> message.FeedbackToMessageResponse
-	17, // 44: message.ConsultMessageService.ListConsultMessages:output_type -> message.ListConsultMessagesResponse
-	10, // 45: message.ConsultMessageService.SendConsultMessage:output_type -> message.SendConsultMessageResponse
-	21, // 46: message.ConsultMessageService.RecallConsultMessage:output_type -> message.RecallConsultMessageResponse
-	23, // 47: message.FriendMessageService.ListFriendMessages:output_type -> message.ListFriendMessagesResponse
-	25, // 48: message.FriendMessageService.CreateFriendMessage:output_type -> message.CreateFriendMessageResponse
-	27, // 49: message.FriendMessageService.UpdateFriendMessage:output_type -> message.UpdateFriendMessageResponse
-	29, // 50: message.FriendMessageService.DeleteFriendMessage:output_type -> message.DeleteFriendMessageResponse
-	12, // 51: message.FriendMessageService.ParseImageMessages:output_type -> message.ParseImageMessagesResponse
-	37, // [37:52] is the sub-list for method output_type
-	22, // [22:37] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	81, // 0: message.ChatMessage.msg_at:type_name -> google.protobuf.Timestamp
+	81, // 1: message.ChatMessage.created_at:type_name -> google.protobuf.Timestamp
+	81, // 2: message.ChatMessage.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: message.ChatMessage.attachments:type_name -> message.MessageAttachment
+	81, // 4: message.MessageAttachment.url_expires_at:type_name -> google.protobuf.Timestamp
+	81, // 5: message.ChatMessageRevision.created_at:type_name -> google.protobuf.Timestamp
+	81, // 6: message.ListChatMessagesRequest.anchor_time:type_name -> google.protobuf.Timestamp
+	0,  // 7: message.ListChatMessagesResponse.messages:type_name -> message.ChatMessage
+	0,  // 8: message.CreateChatMessageRequest.messages:type_name -> message.ChatMessage
+	0,  // 9: message.CreateChatMessageResponse.messages:type_name -> message.ChatMessage
+	0,  // 10: message.UpdateChatMessageRequest.messages:type_name -> message.ChatMessage
+	0,  // 11: message.UpdateChatMessageResponse.messages:type_name -> message.ChatMessage
+	2,  // 12: message.ListMessageRevisionsResponse.revisions:type_name -> message.ChatMessageRevision
+	0,  // 13: message.RollbackChatMessageResponse.message:type_name -> message.ChatMessage
+	0,  // 14: message.SendConsultMessageResponse.consult:type_name -> message.ChatMessage
+	0,  // 15: message.SendConsultMessageResponse.reply:type_name -> message.ChatMessage
+	0,  // 16: message.StreamConsultMessageResponse.consult:type_name -> message.ChatMessage
+	0,  // 17: message.StreamConsultMessageResponse.reply:type_name -> message.ChatMessage
+	19, // 18: message.ListTagsResponse.tags:type_name -> message.MessageTag
+	0,  // 19: message.UpdateMessageTagsResponse.messages:type_name -> message.ChatMessage
+	81, // 20: message.SearchChatMessagesRequest.start_time:type_name -> google.protobuf.Timestamp
+	81, // 21: message.SearchChatMessagesRequest.end_time:type_name -> google.protobuf.Timestamp
+	0,  // 22: message.SearchChatMessageHit.message:type_name -> message.ChatMessage
+	24, // 23: message.SearchChatMessagesResponse.hits:type_name -> message.SearchChatMessageHit
+	0,  // 24: message.ListConsultBranchesResponse.messages:type_name -> message.ChatMessage
+	0,  // 25: message.ParseImageMessagesResponse.messages:type_name -> message.ChatMessage
+	81, // 26: message.ParsedImageLine.msg_at:type_name -> google.protobuf.Timestamp
+	33, // 27: message.PreviewImageMessagesResponse.lines:type_name -> message.ParsedImageLine
+	81, // 28: message.PreviewImageMessagesResponse.expires_at:type_name -> google.protobuf.Timestamp
+	33, // 29: message.ConfirmImageMessagesRequest.lines:type_name -> message.ParsedImageLine
+	0,  // 30: message.ImportChatHistoryResponse.messages:type_name -> message.ChatMessage
+	81, // 31: message.ExportChatSessionResponse.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 32: message.MoveChatMessagesResponse.messages:type_name -> message.ChatMessage
+	0,  // 33: message.CopyChatMessagesResponse.messages:type_name -> message.ChatMessage
+	81, // 34: message.GetFeedbackReportRequest.start_time:type_name -> google.protobuf.Timestamp
+	81, // 35: message.GetFeedbackReportRequest.end_time:type_name -> google.protobuf.Timestamp
+	80, // 36: message.FeedbackReportRow.down_reasons:type_name -> message.FeedbackReportRow.DownReasonsEntry
+	50, // 37: message.GetFeedbackReportResponse.rows:type_name -> message.FeedbackReportRow
+	53, // 38: message.SuggestRepliesResponse.suggestions:type_name -> message.SuggestedReply
+	0,  // 39: message.SaveSuggestedReplyResponse.message:type_name -> message.ChatMessage
+	81, // 40: message.GetSessionSentimentRequest.start_time:type_name -> google.protobuf.Timestamp
+	81, // 41: message.GetSessionSentimentRequest.end_time:type_name -> google.protobuf.Timestamp
+	81, // 42: message.MessageSentiment.msg_at:type_name -> google.protobuf.Timestamp
+	81, // 43: message.SentimentTurningPoint.msg_at:type_name -> google.protobuf.Timestamp
+	59, // 44: message.GetSessionSentimentResponse.series:type_name -> message.SentimentDailyPoint
+	60, // 45: message.GetSessionSentimentResponse.turning_points:type_name -> message.SentimentTurningPoint
+	58, // 46: message.GetSessionSentimentResponse.messages:type_name -> message.MessageSentiment
+	0,  // 47: message.ChatMessageEvent.message:type_name -> message.ChatMessage
+	81, // 48: message.ChatMessageEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	63, // 49: message.WatchChatMessagesResponse.events:type_name -> message.ChatMessageEvent
+	81, // 50: message.ConsultMessage.msg_at:type_name -> google.protobuf.Timestamp
+	81, // 51: message.ConsultMessage.created_at:type_name -> google.protobuf.Timestamp
+	81, // 52: message.ConsultMessage.updated_at:type_name -> google.protobuf.Timestamp
+	65, // 53: message.ListConsultMessagesResponse.messages:type_name -> message.ConsultMessage
+	65, // 54: message.UpdateConsultMessageRequest.messages:type_name -> message.ConsultMessage
+	65, // 55: message.UpdateConsultMessageResponse.messages:type_name -> message.ConsultMessage
+	65, // 56: message.ListFriendMessagesResponse.messages:type_name -> message.ConsultMessage
+	65, // 57: message.CreateFriendMessageRequest.messages:type_name -> message.ConsultMessage
+	65, // 58: message.CreateFriendMessageResponse.messages:type_name -> message.ConsultMessage
+	65, // 59: message.UpdateFriendMessageRequest.messages:type_name -> message.ConsultMessage
+	65, // 60: message.UpdateFriendMessageResponse.messages:type_name -> message.ConsultMessage
+	3,  // 61: message.ChatMessageService.ListChatMessages:input_type -> message.ListChatMessagesRequest
+	5,  // 62: message.ChatMessageService.CreateChatMessage:input_type -> message.CreateChatMessageRequest
+	7,  // 63: message.ChatMessageService.UpdateChatMessage:input_type -> message.UpdateChatMessageRequest
+	9,  // 64: message.ChatMessageService.ListMessageRevisions:input_type -> message.ListMessageRevisionsRequest
+	11, // 65: message.ChatMessageService.RollbackChatMessage:input_type -> message.RollbackChatMessageRequest
+	13, // 66: message.ChatMessageService.DeleteChatMessage:input_type -> message.DeleteChatMessageRequest
+	15, // 67: message.ChatMessageService.SendConsultMessage:input_type -> message.SendConsultMessageRequest
+	15, // 68: message.ChatMessageService.StreamConsultMessage:input_type -> message.SendConsultMessageRequest
+	18, // 69: message.ChatMessageService.ListTags:input_type -> message.ListTagsRequest
+	21, // 70: message.ChatMessageService.AddMessageTags:input_type -> message.UpdateMessageTagsRequest
+	21, // 71: message.ChatMessageService.RemoveMessageTags:input_type -> message.UpdateMessageTagsRequest
+	23, // 72: message.ChatMessageService.SearchChatMessages:input_type -> message.SearchChatMessagesRequest
+	26, // 73: message.ChatMessageService.ListConsultBranches:input_type -> message.ListConsultBranchesRequest
+	28, // 74: message.ChatMessageService.SelectConsultBranch:input_type -> message.SelectConsultBranchRequest
+	30, // 75: message.ChatMessageService.ParseImageMessages:input_type -> message.ParseImageMessagesRequest
+	31, // 76: message.ChatMessageService.ParseImageMessagesBatch:input_type -> message.ParseImageMessagesBatchRequest
+	34, // 77: message.ChatMessageService.PreviewImageMessages:input_type -> message.PreviewImageMessagesRequest
+	36, // 78: message.ChatMessageService.ConfirmImageMessages:input_type -> message.ConfirmImageMessagesRequest
+	37, // 79: message.ChatMessageService.ImportChatHistory:input_type -> message.ImportChatHistoryRequest
+	39, // 80: message.ChatMessageService.ExportChatSession:input_type -> message.ExportChatSessionRequest
+	41, // 81: message.ChatMessageService.MoveChatMessages:input_type -> message.MoveChatMessagesRequest
+	43, // 82: message.ChatMessageService.CopyChatMessages:input_type -> message.CopyChatMessagesRequest
+	45, // 83: message.ChatMessageService.MergeChatSessions:input_type -> message.MergeChatSessionsRequest
+	47, // 84: message.ChatMessageService.FeedbackToMessage:input_type -> message.FeedbackToMessageRequest
+	49, // 85: message.ChatMessageService.GetFeedbackReport:input_type -> message.GetFeedbackReportRequest
+	52, // 86: message.ChatMessageService.SuggestReplies:input_type -> message.SuggestRepliesRequest
+	55, // 87: message.ChatMessageService.SaveSuggestedReply:input_type -> message.SaveSuggestedReplyRequest
+	57, // 88: message.ChatMessageService.GetSessionSentiment:input_type -> message.GetSessionSentimentRequest
+	62, // 89: message.ChatMessageService.WatchChatMessages:input_type -> message.WatchChatMessagesRequest
+	66, // 90: message.ConsultMessageService.ListConsultMessages:input_type -> message.ListConsultMessagesRequest
+	15, // 91: message.ConsultMessageService.SendConsultMessage:input_type -> message.SendConsultMessageRequest
+	70, // 92: message.ConsultMessageService.RecallConsultMessage:input_type -> message.RecallConsultMessageRequest
+	72, // 93: message.FriendMessageService.ListFriendMessages:input_type -> message.ListFriendMessagesRequest
+	74, // 94: message.FriendMessageService.CreateFriendMessage:input_type -> message.CreateFriendMessageRequest
+	76, // 95: message.FriendMessageService.UpdateFriendMessage:input_type -> message.UpdateFriendMessageRequest
+	78, // 96: message.FriendMessageService.DeleteFriendMessage:input_type -> message.DeleteFriendMessageRequest
+	30, // 97: message.FriendMessageService.ParseImageMessages:input_type -> message.ParseImageMessagesRequest
+	4,  // 98: message.ChatMessageService.ListChatMessages:output_type -> message.ListChatMessagesResponse
+	6,  // 99: message.ChatMessageService.CreateChatMessage:output_type -> message.CreateChatMessageResponse
+	8,  // 100: message.ChatMessageService.UpdateChatMessage:output_type -> message.UpdateChatMessageResponse
+	10, // 101: message.ChatMessageService.ListMessageRevisions:output_type -> message.ListMessageRevisionsResponse
+	12, // 102: message.ChatMessageService.RollbackChatMessage:output_type -> message.RollbackChatMessageResponse
+	14, // 103: message.ChatMessageService.DeleteChatMessage:output_type -> message.DeleteChatMessageResponse
+	16, // 104: message.ChatMessageService.SendConsultMessage:output_type -> message.SendConsultMessageResponse
+	17, // 105: message.ChatMessageService.StreamConsultMessage:output_type -> message.StreamConsultMessageResponse
+	20, // 106: message.ChatMessageService.ListTags:output_type -> message.ListTagsResponse
+	22, // 107: message.ChatMessageService.AddMessageTags:output_type -> message.UpdateMessageTagsResponse
+	22, // 108: message.ChatMessageService.RemoveMessageTags:output_type -> message.UpdateMessageTagsResponse
+	25, // 109: message.ChatMessageService.SearchChatMessages:output_type -> message.SearchChatMessagesResponse
+	27, // 110: message.ChatMessageService.ListConsultBranches:output_type -> message.ListConsultBranchesResponse
+	29, // 111: message.ChatMessageService.SelectConsultBranch:output_type -> message.SelectConsultBranchResponse
+	32, // 112: message.ChatMessageService.ParseImageMessages:output_type -> message.ParseImageMessagesResponse
+	32, // 113: message.ChatMessageService.ParseImageMessagesBatch:output_type -> message.ParseImageMessagesResponse
+	35, // 114: message.ChatMessageService.PreviewImageMessages:output_type -> message.PreviewImageMessagesResponse
+	32, // 115: message.ChatMessageService.ConfirmImageMessages:output_type -> message.ParseImageMessagesResponse
+	38, // 116: message.ChatMessageService.ImportChatHistory:output_type -> message.ImportChatHistoryResponse
+	40, // 117: message.ChatMessageService.ExportChatSession:output_type -> message.ExportChatSessionResponse
+	42, // 118: message.ChatMessageService.MoveChatMessages:output_type -> message.MoveChatMessagesResponse
+	44, // 119: message.ChatMessageService.CopyChatMessages:output_type -> message.CopyChatMessagesResponse
+	46, // 120: message.ChatMessageService.MergeChatSessions:output_type -> message.MergeChatSessionsResponse
+	48, // 121: message.ChatMessageService.FeedbackToMessage:output_type -> message.FeedbackToMessageResponse
+	51, // 122: message.ChatMessageService.GetFeedbackReport:output_type -> message.GetFeedbackReportResponse
+	54, // 123: message.ChatMessageService.SuggestReplies:output_type -> message.SuggestRepliesResponse
+	56, // 124: message.ChatMessageService.SaveSuggestedReply:output_type -> message.SaveSuggestedReplyResponse
+	61, // 125: message.ChatMessageService.GetSessionSentiment:output_type -> message.GetSessionSentimentResponse
+	64, // 126: message.ChatMessageService.WatchChatMessages:output_type -> message.WatchChatMessagesResponse
+	67, // 127: message.ConsultMessageService.ListConsultMessages:output_type -> message.ListConsultMessagesResponse
+	16, // 128: message.ConsultMessageService.SendConsultMessage:output_type -> message.SendConsultMessageResponse
+	71, // 129: message.ConsultMessageService.RecallConsultMessage:output_type -> message.RecallConsultMessageResponse
+	73, // 130: message.FriendMessageService.ListFriendMessages:output_type -> message.ListFriendMessagesResponse
+	75, // 131: message.FriendMessageService.CreateFriendMessage:output_type -> message.CreateFriendMessageResponse
+	77, // 132: message.FriendMessageService.UpdateFriendMessage:output_type -> message.UpdateFriendMessageResponse
+	79, // 133: message.FriendMessageService.DeleteFriendMessage:output_type -> message.DeleteFriendMessageResponse
+	32, // 134: message.FriendMessageService.ParseImageMessages:output_type -> message.ParseImageMessagesResponse
+	98, // [98:135] is the sub-list for method output_type
+	61, // [61:98] is the sub-list for method input_type
+	61, // [61:61] is the sub-list for extension type_name
+	61, // [61:61] is the sub-list for extension extendee
+	0,  // [0:61] is the sub-list for field type_name
 }
 
 func init() { file_proto_message_message_proto_init() }
@@ -2007,14 +5797,14 @@ func file_proto_message_message_proto_init() {
 		return
 	}
 	file_proto_message_message_proto_msgTypes[0].OneofWrappers = []any{}
-	file_proto_message_message_proto_msgTypes[9].OneofWrappers = []any{}
+	file_proto_message_message_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_message_message_proto_rawDesc), len(file_proto_message_message_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   30,
+			NumMessages:   81,
 			NumExtensions: 0,
 			NumServices:   3,
 		},
@@ -46,18 +46,84 @@ const (
 	// ChatMessageServiceUpdateChatMessageProcedure is the fully-qualified name of the
 	// ChatMessageService's UpdateChatMessage RPC.
 	ChatMessageServiceUpdateChatMessageProcedure = "/message.ChatMessageService/UpdateChatMessage"
+	// ChatMessageServiceListMessageRevisionsProcedure is the fully-qualified name of the
+	// ChatMessageService's ListMessageRevisions RPC.
+	ChatMessageServiceListMessageRevisionsProcedure = "/message.ChatMessageService/ListMessageRevisions"
+	// ChatMessageServiceRollbackChatMessageProcedure is the fully-qualified name of the
+	// ChatMessageService's RollbackChatMessage RPC.
+	ChatMessageServiceRollbackChatMessageProcedure = "/message.ChatMessageService/RollbackChatMessage"
 	// ChatMessageServiceDeleteChatMessageProcedure is the fully-qualified name of the
 	// ChatMessageService's DeleteChatMessage RPC.
 	ChatMessageServiceDeleteChatMessageProcedure = "/message.ChatMessageService/DeleteChatMessage"
 	// ChatMessageServiceSendConsultMessageProcedure is the fully-qualified name of the
 	// ChatMessageService's SendConsultMessage RPC.
 	ChatMessageServiceSendConsultMessageProcedure = "/message.ChatMessageService/SendConsultMessage"
+	// ChatMessageServiceStreamConsultMessageProcedure is the fully-qualified name of the
+	// ChatMessageService's StreamConsultMessage RPC.
+	ChatMessageServiceStreamConsultMessageProcedure = "/message.ChatMessageService/StreamConsultMessage"
+	// ChatMessageServiceListTagsProcedure is the fully-qualified name of the ChatMessageService's
+	// ListTags RPC.
+	ChatMessageServiceListTagsProcedure = "/message.ChatMessageService/ListTags"
+	// ChatMessageServiceAddMessageTagsProcedure is the fully-qualified name of the ChatMessageService's
+	// AddMessageTags RPC.
+	ChatMessageServiceAddMessageTagsProcedure = "/message.ChatMessageService/AddMessageTags"
+	// ChatMessageServiceRemoveMessageTagsProcedure is the fully-qualified name of the
+	// ChatMessageService's RemoveMessageTags RPC.
+	ChatMessageServiceRemoveMessageTagsProcedure = "/message.ChatMessageService/RemoveMessageTags"
+	// ChatMessageServiceSearchChatMessagesProcedure is the fully-qualified name of the
+	// ChatMessageService's SearchChatMessages RPC.
+	ChatMessageServiceSearchChatMessagesProcedure = "/message.ChatMessageService/SearchChatMessages"
+	// ChatMessageServiceListConsultBranchesProcedure is the fully-qualified name of the
+	// ChatMessageService's ListConsultBranches RPC.
+	ChatMessageServiceListConsultBranchesProcedure = "/message.ChatMessageService/ListConsultBranches"
+	// ChatMessageServiceSelectConsultBranchProcedure is the fully-qualified name of the
+	// ChatMessageService's SelectConsultBranch RPC.
+	ChatMessageServiceSelectConsultBranchProcedure = "/message.ChatMessageService/SelectConsultBranch"
 	// ChatMessageServiceParseImageMessagesProcedure is the fully-qualified name of the
 	// ChatMessageService's ParseImageMessages RPC.
 	ChatMessageServiceParseImageMessagesProcedure = "/message.ChatMessageService/ParseImageMessages"
+	// ChatMessageServiceParseImageMessagesBatchProcedure is the fully-qualified name of the
+	// ChatMessageService's ParseImageMessagesBatch RPC.
+	ChatMessageServiceParseImageMessagesBatchProcedure = "/message.ChatMessageService/ParseImageMessagesBatch"
+	// ChatMessageServicePreviewImageMessagesProcedure is the fully-qualified name of the
+	// ChatMessageService's PreviewImageMessages RPC.
+	ChatMessageServicePreviewImageMessagesProcedure = "/message.ChatMessageService/PreviewImageMessages"
+	// ChatMessageServiceConfirmImageMessagesProcedure is the fully-qualified name of the
+	// ChatMessageService's ConfirmImageMessages RPC.
+	ChatMessageServiceConfirmImageMessagesProcedure = "/message.ChatMessageService/ConfirmImageMessages"
+	// ChatMessageServiceImportChatHistoryProcedure is the fully-qualified name of the
+	// ChatMessageService's ImportChatHistory RPC.
+	ChatMessageServiceImportChatHistoryProcedure = "/message.ChatMessageService/ImportChatHistory"
+	// ChatMessageServiceExportChatSessionProcedure is the fully-qualified name of the
+	// ChatMessageService's ExportChatSession RPC.
+	ChatMessageServiceExportChatSessionProcedure = "/message.ChatMessageService/ExportChatSession"
+	// ChatMessageServiceMoveChatMessagesProcedure is the fully-qualified name of the
+	// ChatMessageService's MoveChatMessages RPC.
+	ChatMessageServiceMoveChatMessagesProcedure = "/message.ChatMessageService/MoveChatMessages"
+	// ChatMessageServiceCopyChatMessagesProcedure is the fully-qualified name of the
+	// ChatMessageService's CopyChatMessages RPC.
+	ChatMessageServiceCopyChatMessagesProcedure = "/message.ChatMessageService/CopyChatMessages"
+	// ChatMessageServiceMergeChatSessionsProcedure is the fully-qualified name of the
+	// ChatMessageService's MergeChatSessions RPC.
+	ChatMessageServiceMergeChatSessionsProcedure = "/message.ChatMessageService/MergeChatSessions"
 	// ChatMessageServiceFeedbackToMessageProcedure is the fully-qualified name of the
 	// ChatMessageService's FeedbackToMessage RPC.
 	ChatMessageServiceFeedbackToMessageProcedure = "/message.ChatMessageService/FeedbackToMessage"
+	// ChatMessageServiceGetFeedbackReportProcedure is the fully-qualified name of the
+	// ChatMessageService's GetFeedbackReport RPC.
+	ChatMessageServiceGetFeedbackReportProcedure = "/message.ChatMessageService/GetFeedbackReport"
+	// ChatMessageServiceSuggestRepliesProcedure is the fully-qualified name of the ChatMessageService's
+	// SuggestReplies RPC.
+	ChatMessageServiceSuggestRepliesProcedure = "/message.ChatMessageService/SuggestReplies"
+	// ChatMessageServiceSaveSuggestedReplyProcedure is the fully-qualified name of the
+	// ChatMessageService's SaveSuggestedReply RPC.
+	ChatMessageServiceSaveSuggestedReplyProcedure = "/message.ChatMessageService/SaveSuggestedReply"
+	// ChatMessageServiceGetSessionSentimentProcedure is the fully-qualified name of the
+	// ChatMessageService's GetSessionSentiment RPC.
+	ChatMessageServiceGetSessionSentimentProcedure = "/message.ChatMessageService/GetSessionSentiment"
+	// ChatMessageServiceWatchChatMessagesProcedure is the fully-qualified name of the
+	// ChatMessageService's WatchChatMessages RPC.
+	ChatMessageServiceWatchChatMessagesProcedure = "/message.ChatMessageService/WatchChatMessages"
 	// ConsultMessageServiceListConsultMessagesProcedure is the fully-qualified name of the
 	// ConsultMessageService's ListConsultMessages RPC.
 	ConsultMessageServiceListConsultMessagesProcedure = "/message.ConsultMessageService/ListConsultMessages"
@@ -95,18 +161,87 @@ type ChatMessageServiceClient interface {
 	// 更新消息
 	// POST /message.ChatMessageService/UpdateChatMessage
 	UpdateChatMessage(context.Context, *connect.Request[message.UpdateChatMessageRequest]) (*connect.Response[message.UpdateChatMessageResponse], error)
+	// 查询消息的修改记录
+	// POST /message.ChatMessageService/ListMessageRevisions
+	ListMessageRevisions(context.Context, *connect.Request[message.ListMessageRevisionsRequest]) (*connect.Response[message.ListMessageRevisionsResponse], error)
+	// 将消息回滚到某个修改记录
+	// POST /message.ChatMessageService/RollbackChatMessage
+	RollbackChatMessage(context.Context, *connect.Request[message.RollbackChatMessageRequest]) (*connect.Response[message.RollbackChatMessageResponse], error)
 	// 删除消息 - 合并原来的 RecallConsultMessage 和 DeleteFriendMessage
 	// POST /message.ChatMessageService/DeleteChatMessage
 	DeleteChatMessage(context.Context, *connect.Request[message.DeleteChatMessageRequest]) (*connect.Response[message.DeleteChatMessageResponse], error)
 	// 发送咨询消息 - 专门用于AI咨询回复
 	// POST /message.ChatMessageService/SendConsultMessage
 	SendConsultMessage(context.Context, *connect.Request[message.SendConsultMessageRequest]) (*connect.Response[message.SendConsultMessageResponse], error)
+	// 流式发送咨询消息 - 边生成边推送 AI 回复
+	// 第一帧返回 consult，中间帧返回 delta，最后一帧返回完整的 reply
+	// POST /message.ChatMessageService/StreamConsultMessage
+	StreamConsultMessage(context.Context, *connect.Request[message.SendConsultMessageRequest]) (*connect.ServerStreamForClient[message.StreamConsultMessageResponse], error)
+	// 列出用户使用过的标签及消息数
+	// POST /message.ChatMessageService/ListTags
+	ListTags(context.Context, *connect.Request[message.ListTagsRequest]) (*connect.Response[message.ListTagsResponse], error)
+	// 批量为消息添加用户标签
+	// POST /message.ChatMessageService/AddMessageTags
+	AddMessageTags(context.Context, *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error)
+	// 批量删除消息的用户标签，系统标签不能删除
+	// POST /message.ChatMessageService/RemoveMessageTags
+	RemoveMessageTags(context.Context, *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error)
+	// 全文搜索消息 - 支持跨会话或单会话搜索
+	// POST /message.ChatMessageService/SearchChatMessages
+	SearchChatMessages(context.Context, *connect.Request[message.SearchChatMessagesRequest]) (*connect.Response[message.SearchChatMessagesResponse], error)
+	// 查询咨询消息所在分支组的全部分支
+	// POST /message.ChatMessageService/ListConsultBranches
+	ListConsultBranches(context.Context, *connect.Request[message.ListConsultBranchesRequest]) (*connect.Response[message.ListConsultBranchesResponse], error)
+	// 选择咨询消息所在的分支作为当前分支
+	// POST /message.ChatMessageService/SelectConsultBranch
+	SelectConsultBranch(context.Context, *connect.Request[message.SelectConsultBranchRequest]) (*connect.Response[message.SelectConsultBranchResponse], error)
 	// 解析图片中的消息（保留原功能）
 	// POST /message.ChatMessageService/ParseImageMessages
 	ParseImageMessages(context.Context, *connect.Request[message.ParseImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error)
+	// 批量解析多张内容有重叠的截图，按顺序拼接成一份去重后的聊天记录
+	// POST /message.ChatMessageService/ParseImageMessagesBatch
+	ParseImageMessagesBatch(context.Context, *connect.Request[message.ParseImageMessagesBatchRequest]) (*connect.Response[message.ParseImageMessagesResponse], error)
+	// 解析截图但不保存，返回识别结果供用户修正，parse_id 在一段时间内有效
+	// POST /message.ChatMessageService/PreviewImageMessages
+	PreviewImageMessages(context.Context, *connect.Request[message.PreviewImageMessagesRequest]) (*connect.Response[message.PreviewImageMessagesResponse], error)
+	// 保存用户确认后的截图解析结果，每个 parse_id 只能确认一次
+	// POST /message.ChatMessageService/ConfirmImageMessages
+	ConfirmImageMessages(context.Context, *connect.Request[message.ConfirmImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error)
+	// 导入微信/QQ 文本记录、CSV、JSON 格式的聊天记录
+	// POST /message.ChatMessageService/ImportChatHistory
+	ImportChatHistory(context.Context, *connect.Request[message.ImportChatHistoryRequest]) (*connect.Response[message.ImportChatHistoryResponse], error)
+	// 导出会话为 Markdown、HTML 或 JSON 文件
+	// POST /message.ChatMessageService/ExportChatSession
+	ExportChatSession(context.Context, *connect.Request[message.ExportChatSessionRequest]) (*connect.Response[message.ExportChatSessionResponse], error)
+	// 移动消息到同一用户的另一个会话，子消息一起移动
+	// POST /message.ChatMessageService/MoveChatMessages
+	MoveChatMessages(context.Context, *connect.Request[message.MoveChatMessagesRequest]) (*connect.Response[message.MoveChatMessagesResponse], error)
+	// 复制消息到同一用户的另一个会话，子消息一起复制
+	// POST /message.ChatMessageService/CopyChatMessages
+	CopyChatMessages(context.Context, *connect.Request[message.CopyChatMessagesRequest]) (*connect.Response[message.CopyChatMessagesResponse], error)
+	// 合并两个会话，源会话的消息合并到目标会话后删除源会话
+	// POST /message.ChatMessageService/MergeChatSessions
+	MergeChatSessions(context.Context, *connect.Request[message.MergeChatSessionsRequest]) (*connect.Response[message.MergeChatSessionsResponse], error)
 	// 用户反馈 点赞/踩/评论
 	// POST /message.ChatMessageService/FeedbackToMessage
 	FeedbackToMessage(context.Context, *connect.Request[message.FeedbackToMessageRequest]) (*connect.Response[message.FeedbackToMessageResponse], error)
+	// 反馈报表，按提示词、模型、消息类型和日期统计点赞/点踩比例，仅管理员可用
+	// POST /message.ChatMessageService/GetFeedbackReport
+	GetFeedbackReport(context.Context, *connect.Request[message.GetFeedbackReportRequest]) (*connect.Response[message.GetFeedbackReportResponse], error)
+	// 回复建议 - 针对对方的一条聊天记录，生成多条不同语气的回复建议
+	// POST /message.ChatMessageService/SuggestReplies
+	SuggestReplies(context.Context, *connect.Request[message.SuggestRepliesRequest]) (*connect.Response[message.SuggestRepliesResponse], error)
+	// 保存选中的回复建议为自己发送的聊天记录
+	// POST /message.ChatMessageService/SaveSuggestedReply
+	SaveSuggestedReply(context.Context, *connect.Request[message.SaveSuggestedReplyRequest]) (*connect.Response[message.SaveSuggestedReplyResponse], error)
+	// 会话情感走势 - 分析聊天记录的情感和情绪，返回按天的走势和明显的转折点
+	// 分析结果按消息缓存，消息内容修改后重新分析
+	// POST /message.ChatMessageService/GetSessionSentiment
+	GetSessionSentiment(context.Context, *connect.Request[message.GetSessionSentimentRequest]) (*connect.Response[message.GetSessionSentimentResponse], error)
+	// 订阅消息变更 - 推送当前用户会话中消息的创建、更新和删除事件，用于多端同步
+	// 连接期间定时推送心跳帧，断开重连后客户端需要重新拉取消息
+	// POST /message.ChatMessageService/WatchChatMessages
+	WatchChatMessages(context.Context, *connect.Request[message.WatchChatMessagesRequest]) (*connect.ServerStreamForClient[message.WatchChatMessagesResponse], error)
 }
 
 // NewChatMessageServiceClient constructs a client for the message.ChatMessageService service. By
@@ -138,6 +273,18 @@ func NewChatMessageServiceClient(httpClient connect.HTTPClient, baseURL string,
 			connect.WithSchema(chatMessageServiceMethods.ByName("UpdateChatMessage")),
 			connect.WithClientOptions(opts...),
 		),
+		listMessageRevisions: connect.NewClient[message.ListMessageRevisionsRequest, message.ListMessageRevisionsResponse](
+			httpClient,
+			baseURL+ChatMessageServiceListMessageRevisionsProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ListMessageRevisions")),
+			connect.WithClientOptions(opts...),
+		),
+		rollbackChatMessage: connect.NewClient[message.RollbackChatMessageRequest, message.RollbackChatMessageResponse](
+			httpClient,
+			baseURL+ChatMessageServiceRollbackChatMessageProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("RollbackChatMessage")),
+			connect.WithClientOptions(opts...),
+		),
 		deleteChatMessage: connect.NewClient[message.DeleteChatMessageRequest, message.DeleteChatMessageResponse](
 			httpClient,
 			baseURL+ChatMessageServiceDeleteChatMessageProcedure,
@@ -150,30 +297,172 @@ func NewChatMessageServiceClient(httpClient connect.HTTPClient, baseURL string,
 			connect.WithSchema(chatMessageServiceMethods.ByName("SendConsultMessage")),
 			connect.WithClientOptions(opts...),
 		),
+		streamConsultMessage: connect.NewClient[message.SendConsultMessageRequest, message.StreamConsultMessageResponse](
+			httpClient,
+			baseURL+ChatMessageServiceStreamConsultMessageProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("StreamConsultMessage")),
+			connect.WithClientOptions(opts...),
+		),
+		listTags: connect.NewClient[message.ListTagsRequest, message.ListTagsResponse](
+			httpClient,
+			baseURL+ChatMessageServiceListTagsProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ListTags")),
+			connect.WithClientOptions(opts...),
+		),
+		addMessageTags: connect.NewClient[message.UpdateMessageTagsRequest, message.UpdateMessageTagsResponse](
+			httpClient,
+			baseURL+ChatMessageServiceAddMessageTagsProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("AddMessageTags")),
+			connect.WithClientOptions(opts...),
+		),
+		removeMessageTags: connect.NewClient[message.UpdateMessageTagsRequest, message.UpdateMessageTagsResponse](
+			httpClient,
+			baseURL+ChatMessageServiceRemoveMessageTagsProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("RemoveMessageTags")),
+			connect.WithClientOptions(opts...),
+		),
+		searchChatMessages: connect.NewClient[message.SearchChatMessagesRequest, message.SearchChatMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceSearchChatMessagesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("SearchChatMessages")),
+			connect.WithClientOptions(opts...),
+		),
+		listConsultBranches: connect.NewClient[message.ListConsultBranchesRequest, message.ListConsultBranchesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceListConsultBranchesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ListConsultBranches")),
+			connect.WithClientOptions(opts...),
+		),
+		selectConsultBranch: connect.NewClient[message.SelectConsultBranchRequest, message.SelectConsultBranchResponse](
+			httpClient,
+			baseURL+ChatMessageServiceSelectConsultBranchProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("SelectConsultBranch")),
+			connect.WithClientOptions(opts...),
+		),
 		parseImageMessages: connect.NewClient[message.ParseImageMessagesRequest, message.ParseImageMessagesResponse](
 			httpClient,
 			baseURL+ChatMessageServiceParseImageMessagesProcedure,
 			connect.WithSchema(chatMessageServiceMethods.ByName("ParseImageMessages")),
 			connect.WithClientOptions(opts...),
 		),
+		parseImageMessagesBatch: connect.NewClient[message.ParseImageMessagesBatchRequest, message.ParseImageMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceParseImageMessagesBatchProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ParseImageMessagesBatch")),
+			connect.WithClientOptions(opts...),
+		),
+		previewImageMessages: connect.NewClient[message.PreviewImageMessagesRequest, message.PreviewImageMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServicePreviewImageMessagesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("PreviewImageMessages")),
+			connect.WithClientOptions(opts...),
+		),
+		confirmImageMessages: connect.NewClient[message.ConfirmImageMessagesRequest, message.ParseImageMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceConfirmImageMessagesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ConfirmImageMessages")),
+			connect.WithClientOptions(opts...),
+		),
+		importChatHistory: connect.NewClient[message.ImportChatHistoryRequest, message.ImportChatHistoryResponse](
+			httpClient,
+			baseURL+ChatMessageServiceImportChatHistoryProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ImportChatHistory")),
+			connect.WithClientOptions(opts...),
+		),
+		exportChatSession: connect.NewClient[message.ExportChatSessionRequest, message.ExportChatSessionResponse](
+			httpClient,
+			baseURL+ChatMessageServiceExportChatSessionProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("ExportChatSession")),
+			connect.WithClientOptions(opts...),
+		),
+		moveChatMessages: connect.NewClient[message.MoveChatMessagesRequest, message.MoveChatMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceMoveChatMessagesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("MoveChatMessages")),
+			connect.WithClientOptions(opts...),
+		),
+		copyChatMessages: connect.NewClient[message.CopyChatMessagesRequest, message.CopyChatMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceCopyChatMessagesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("CopyChatMessages")),
+			connect.WithClientOptions(opts...),
+		),
+		mergeChatSessions: connect.NewClient[message.MergeChatSessionsRequest, message.MergeChatSessionsResponse](
+			httpClient,
+			baseURL+ChatMessageServiceMergeChatSessionsProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("MergeChatSessions")),
+			connect.WithClientOptions(opts...),
+		),
 		feedbackToMessage: connect.NewClient[message.FeedbackToMessageRequest, message.FeedbackToMessageResponse](
 			httpClient,
 			baseURL+ChatMessageServiceFeedbackToMessageProcedure,
 			connect.WithSchema(chatMessageServiceMethods.ByName("FeedbackToMessage")),
 			connect.WithClientOptions(opts...),
 		),
+		getFeedbackReport: connect.NewClient[message.GetFeedbackReportRequest, message.GetFeedbackReportResponse](
+			httpClient,
+			baseURL+ChatMessageServiceGetFeedbackReportProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("GetFeedbackReport")),
+			connect.WithClientOptions(opts...),
+		),
+		suggestReplies: connect.NewClient[message.SuggestRepliesRequest, message.SuggestRepliesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceSuggestRepliesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("SuggestReplies")),
+			connect.WithClientOptions(opts...),
+		),
+		saveSuggestedReply: connect.NewClient[message.SaveSuggestedReplyRequest, message.SaveSuggestedReplyResponse](
+			httpClient,
+			baseURL+ChatMessageServiceSaveSuggestedReplyProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("SaveSuggestedReply")),
+			connect.WithClientOptions(opts...),
+		),
+		getSessionSentiment: connect.NewClient[message.GetSessionSentimentRequest, message.GetSessionSentimentResponse](
+			httpClient,
+			baseURL+ChatMessageServiceGetSessionSentimentProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("GetSessionSentiment")),
+			connect.WithClientOptions(opts...),
+		),
+		watchChatMessages: connect.NewClient[message.WatchChatMessagesRequest, message.WatchChatMessagesResponse](
+			httpClient,
+			baseURL+ChatMessageServiceWatchChatMessagesProcedure,
+			connect.WithSchema(chatMessageServiceMethods.ByName("WatchChatMessages")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // chatMessageServiceClient implements ChatMessageServiceClient.
 type chatMessageServiceClient struct {
-	listChatMessages   *connect.Client[message.ListChatMessagesRequest, message.ListChatMessagesResponse]
-	createChatMessage  *connect.Client[message.CreateChatMessageRequest, message.CreateChatMessageResponse]
-	updateChatMessage  *connect.Client[message.UpdateChatMessageRequest, message.UpdateChatMessageResponse]
-	deleteChatMessage  *connect.Client[message.DeleteChatMessageRequest, message.DeleteChatMessageResponse]
-	sendConsultMessage *connect.Client[message.SendConsultMessageRequest, message.SendConsultMessageResponse]
-	parseImageMessages *connect.Client[message.ParseImageMessagesRequest, message.ParseImageMessagesResponse]
-	feedbackToMessage  *connect.Client[message.FeedbackToMessageRequest, message.FeedbackToMessageResponse]
+	listChatMessages        *connect.Client[message.ListChatMessagesRequest, message.ListChatMessagesResponse]
+	createChatMessage       *connect.Client[message.CreateChatMessageRequest, message.CreateChatMessageResponse]
+	updateChatMessage       *connect.Client[message.UpdateChatMessageRequest, message.UpdateChatMessageResponse]
+	listMessageRevisions    *connect.Client[message.ListMessageRevisionsRequest, message.ListMessageRevisionsResponse]
+	rollbackChatMessage     *connect.Client[message.RollbackChatMessageRequest, message.RollbackChatMessageResponse]
+	deleteChatMessage       *connect.Client[message.DeleteChatMessageRequest, message.DeleteChatMessageResponse]
+	sendConsultMessage      *connect.Client[message.SendConsultMessageRequest, message.SendConsultMessageResponse]
+	streamConsultMessage    *connect.Client[message.SendConsultMessageRequest, message.StreamConsultMessageResponse]
+	listTags                *connect.Client[message.ListTagsRequest, message.ListTagsResponse]
+	addMessageTags          *connect.Client[message.UpdateMessageTagsRequest, message.UpdateMessageTagsResponse]
+	removeMessageTags       *connect.Client[message.UpdateMessageTagsRequest, message.UpdateMessageTagsResponse]
+	searchChatMessages      *connect.Client[message.SearchChatMessagesRequest, message.SearchChatMessagesResponse]
+	listConsultBranches     *connect.Client[message.ListConsultBranchesRequest, message.ListConsultBranchesResponse]
+	selectConsultBranch     *connect.Client[message.SelectConsultBranchRequest, message.SelectConsultBranchResponse]
+	parseImageMessages      *connect.Client[message.ParseImageMessagesRequest, message.ParseImageMessagesResponse]
+	parseImageMessagesBatch *connect.Client[message.ParseImageMessagesBatchRequest, message.ParseImageMessagesResponse]
+	previewImageMessages    *connect.Client[message.PreviewImageMessagesRequest, message.PreviewImageMessagesResponse]
+	confirmImageMessages    *connect.Client[message.ConfirmImageMessagesRequest, message.ParseImageMessagesResponse]
+	importChatHistory       *connect.Client[message.ImportChatHistoryRequest, message.ImportChatHistoryResponse]
+	exportChatSession       *connect.Client[message.ExportChatSessionRequest, message.ExportChatSessionResponse]
+	moveChatMessages        *connect.Client[message.MoveChatMessagesRequest, message.MoveChatMessagesResponse]
+	copyChatMessages        *connect.Client[message.CopyChatMessagesRequest, message.CopyChatMessagesResponse]
+	mergeChatSessions       *connect.Client[message.MergeChatSessionsRequest, message.MergeChatSessionsResponse]
+	feedbackToMessage       *connect.Client[message.FeedbackToMessageRequest, message.FeedbackToMessageResponse]
+	getFeedbackReport       *connect.Client[message.GetFeedbackReportRequest, message.GetFeedbackReportResponse]
+	suggestReplies          *connect.Client[message.SuggestRepliesRequest, message.SuggestRepliesResponse]
+	saveSuggestedReply      *connect.Client[message.SaveSuggestedReplyRequest, message.SaveSuggestedReplyResponse]
+	getSessionSentiment     *connect.Client[message.GetSessionSentimentRequest, message.GetSessionSentimentResponse]
+	watchChatMessages       *connect.Client[message.WatchChatMessagesRequest, message.WatchChatMessagesResponse]
 }
 
 // ListChatMessages calls message.ChatMessageService.ListChatMessages.
@@ -191,6 +480,16 @@ func (c *chatMessageServiceClient) UpdateChatMessage(ctx context.Context, req *c
 	return c.updateChatMessage.CallUnary(ctx, req)
 }
 
+// ListMessageRevisions calls message.ChatMessageService.ListMessageRevisions.
+func (c *chatMessageServiceClient) ListMessageRevisions(ctx context.Context, req *connect.Request[message.ListMessageRevisionsRequest]) (*connect.Response[message.ListMessageRevisionsResponse], error) {
+	return c.listMessageRevisions.CallUnary(ctx, req)
+}
+
+// RollbackChatMessage calls message.ChatMessageService.RollbackChatMessage.
+func (c *chatMessageServiceClient) RollbackChatMessage(ctx context.Context, req *connect.Request[message.RollbackChatMessageRequest]) (*connect.Response[message.RollbackChatMessageResponse], error) {
+	return c.rollbackChatMessage.CallUnary(ctx, req)
+}
+
 // DeleteChatMessage calls message.ChatMessageService.DeleteChatMessage.
 func (c *chatMessageServiceClient) DeleteChatMessage(ctx context.Context, req *connect.Request[message.DeleteChatMessageRequest]) (*connect.Response[message.DeleteChatMessageResponse], error) {
 	return c.deleteChatMessage.CallUnary(ctx, req)
@@ -201,16 +500,116 @@ func (c *chatMessageServiceClient) SendConsultMessage(ctx context.Context, req *
 	return c.sendConsultMessage.CallUnary(ctx, req)
 }
 
+// StreamConsultMessage calls message.ChatMessageService.StreamConsultMessage.
+func (c *chatMessageServiceClient) StreamConsultMessage(ctx context.Context, req *connect.Request[message.SendConsultMessageRequest]) (*connect.ServerStreamForClient[message.StreamConsultMessageResponse], error) {
+	return c.streamConsultMessage.CallServerStream(ctx, req)
+}
+
+// ListTags calls message.ChatMessageService.ListTags.
+func (c *chatMessageServiceClient) ListTags(ctx context.Context, req *connect.Request[message.ListTagsRequest]) (*connect.Response[message.ListTagsResponse], error) {
+	return c.listTags.CallUnary(ctx, req)
+}
+
+// AddMessageTags calls message.ChatMessageService.AddMessageTags.
+func (c *chatMessageServiceClient) AddMessageTags(ctx context.Context, req *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	return c.addMessageTags.CallUnary(ctx, req)
+}
+
+// RemoveMessageTags calls message.ChatMessageService.RemoveMessageTags.
+func (c *chatMessageServiceClient) RemoveMessageTags(ctx context.Context, req *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	return c.removeMessageTags.CallUnary(ctx, req)
+}
+
+// SearchChatMessages calls message.ChatMessageService.SearchChatMessages.
+func (c *chatMessageServiceClient) SearchChatMessages(ctx context.Context, req *connect.Request[message.SearchChatMessagesRequest]) (*connect.Response[message.SearchChatMessagesResponse], error) {
+	return c.searchChatMessages.CallUnary(ctx, req)
+}
+
+// ListConsultBranches calls message.ChatMessageService.ListConsultBranches.
+func (c *chatMessageServiceClient) ListConsultBranches(ctx context.Context, req *connect.Request[message.ListConsultBranchesRequest]) (*connect.Response[message.ListConsultBranchesResponse], error) {
+	return c.listConsultBranches.CallUnary(ctx, req)
+}
+
+// SelectConsultBranch calls message.ChatMessageService.SelectConsultBranch.
+func (c *chatMessageServiceClient) SelectConsultBranch(ctx context.Context, req *connect.Request[message.SelectConsultBranchRequest]) (*connect.Response[message.SelectConsultBranchResponse], error) {
+	return c.selectConsultBranch.CallUnary(ctx, req)
+}
+
 // ParseImageMessages calls message.ChatMessageService.ParseImageMessages.
 func (c *chatMessageServiceClient) ParseImageMessages(ctx context.Context, req *connect.Request[message.ParseImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
 	return c.parseImageMessages.CallUnary(ctx, req)
 }
 
+// ParseImageMessagesBatch calls message.ChatMessageService.ParseImageMessagesBatch.
+func (c *chatMessageServiceClient) ParseImageMessagesBatch(ctx context.Context, req *connect.Request[message.ParseImageMessagesBatchRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
+	return c.parseImageMessagesBatch.CallUnary(ctx, req)
+}
+
+// PreviewImageMessages calls message.ChatMessageService.PreviewImageMessages.
+func (c *chatMessageServiceClient) PreviewImageMessages(ctx context.Context, req *connect.Request[message.PreviewImageMessagesRequest]) (*connect.Response[message.PreviewImageMessagesResponse], error) {
+	return c.previewImageMessages.CallUnary(ctx, req)
+}
+
+// ConfirmImageMessages calls message.ChatMessageService.ConfirmImageMessages.
+func (c *chatMessageServiceClient) ConfirmImageMessages(ctx context.Context, req *connect.Request[message.ConfirmImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
+	return c.confirmImageMessages.CallUnary(ctx, req)
+}
+
+// ImportChatHistory calls message.ChatMessageService.ImportChatHistory.
+func (c *chatMessageServiceClient) ImportChatHistory(ctx context.Context, req *connect.Request[message.ImportChatHistoryRequest]) (*connect.Response[message.ImportChatHistoryResponse], error) {
+	return c.importChatHistory.CallUnary(ctx, req)
+}
+
+// ExportChatSession calls message.ChatMessageService.ExportChatSession.
+func (c *chatMessageServiceClient) ExportChatSession(ctx context.Context, req *connect.Request[message.ExportChatSessionRequest]) (*connect.Response[message.ExportChatSessionResponse], error) {
+	return c.exportChatSession.CallUnary(ctx, req)
+}
+
+// MoveChatMessages calls message.ChatMessageService.MoveChatMessages.
+func (c *chatMessageServiceClient) MoveChatMessages(ctx context.Context, req *connect.Request[message.MoveChatMessagesRequest]) (*connect.Response[message.MoveChatMessagesResponse], error) {
+	return c.moveChatMessages.CallUnary(ctx, req)
+}
+
+// CopyChatMessages calls message.ChatMessageService.CopyChatMessages.
+func (c *chatMessageServiceClient) CopyChatMessages(ctx context.Context, req *connect.Request[message.CopyChatMessagesRequest]) (*connect.Response[message.CopyChatMessagesResponse], error) {
+	return c.copyChatMessages.CallUnary(ctx, req)
+}
+
+// MergeChatSessions calls message.ChatMessageService.MergeChatSessions.
+func (c *chatMessageServiceClient) MergeChatSessions(ctx context.Context, req *connect.Request[message.MergeChatSessionsRequest]) (*connect.Response[message.MergeChatSessionsResponse], error) {
+	return c.mergeChatSessions.CallUnary(ctx, req)
+}
+
 // FeedbackToMessage calls message.ChatMessageService.FeedbackToMessage.
 func (c *chatMessageServiceClient) FeedbackToMessage(ctx context.Context, req *connect.Request[message.FeedbackToMessageRequest]) (*connect.Response[message.FeedbackToMessageResponse], error) {
 	return c.feedbackToMessage.CallUnary(ctx, req)
 }
 
+// GetFeedbackReport calls message.ChatMessageService.GetFeedbackReport.
+func (c *chatMessageServiceClient) GetFeedbackReport(ctx context.Context, req *connect.Request[message.GetFeedbackReportRequest]) (*connect.Response[message.GetFeedbackReportResponse], error) {
+	return c.getFeedbackReport.CallUnary(ctx, req)
+}
+
+// SuggestReplies calls message.ChatMessageService.SuggestReplies.
+func (c *chatMessageServiceClient) SuggestReplies(ctx context.Context, req *connect.Request[message.SuggestRepliesRequest]) (*connect.Response[message.SuggestRepliesResponse], error) {
+	return c.suggestReplies.CallUnary(ctx, req)
+}
+
+// SaveSuggestedReply calls message.ChatMessageService.SaveSuggestedReply.
+func (c *chatMessageServiceClient) SaveSuggestedReply(ctx context.Context, req *connect.Request[message.SaveSuggestedReplyRequest]) (*connect.Response[message.SaveSuggestedReplyResponse], error) {
+	return c.saveSuggestedReply.CallUnary(ctx, req)
+}
+
+// GetSessionSentiment calls message.ChatMessageService.GetSessionSentiment.
+func (c *chatMessageServiceClient) GetSessionSentiment(ctx context.Context, req *connect.Request[message.GetSessionSentimentRequest]) (*connect.Response[message.GetSessionSentimentResponse], error) {
+	return c.getSessionSentiment.CallUnary(ctx, req)
+}
+
+// WatchChatMessages calls message.ChatMessageService.WatchChatMessages.
+func (c *chatMessageServiceClient) WatchChatMessages(ctx context.Context, req *connect.Request[message.WatchChatMessagesRequest]) (*connect.ServerStreamForClient[message.WatchChatMessagesResponse], error) {
+	return c.watchChatMessages.CallServerStream(ctx, req)
+}
+
 // ChatMessageServiceHandler is an implementation of the message.ChatMessageService service.
 type ChatMessageServiceHandler interface {
 	// 查询消息列表 - 合并原来的 ListConsultMessages 和 ListFriendMessages
@@ -222,18 +621,87 @@ type ChatMessageServiceHandler interface {
 	// 更新消息
 	// POST /message.ChatMessageService/UpdateChatMessage
 	UpdateChatMessage(context.Context, *connect.Request[message.UpdateChatMessageRequest]) (*connect.Response[message.UpdateChatMessageResponse], error)
+	// 查询消息的修改记录
+	// POST /message.ChatMessageService/ListMessageRevisions
+	ListMessageRevisions(context.Context, *connect.Request[message.ListMessageRevisionsRequest]) (*connect.Response[message.ListMessageRevisionsResponse], error)
+	// 将消息回滚到某个修改记录
+	// POST /message.ChatMessageService/RollbackChatMessage
+	RollbackChatMessage(context.Context, *connect.Request[message.RollbackChatMessageRequest]) (*connect.Response[message.RollbackChatMessageResponse], error)
 	// 删除消息 - 合并原来的 RecallConsultMessage 和 DeleteFriendMessage
 	// POST /message.ChatMessageService/DeleteChatMessage
 	DeleteChatMessage(context.Context, *connect.Request[message.DeleteChatMessageRequest]) (*connect.Response[message.DeleteChatMessageResponse], error)
 	// 发送咨询消息 - 专门用于AI咨询回复
 	// POST /message.ChatMessageService/SendConsultMessage
 	SendConsultMessage(context.Context, *connect.Request[message.SendConsultMessageRequest]) (*connect.Response[message.SendConsultMessageResponse], error)
+	// 流式发送咨询消息 - 边生成边推送 AI 回复
+	// 第一帧返回 consult，中间帧返回 delta，最后一帧返回完整的 reply
+	// POST /message.ChatMessageService/StreamConsultMessage
+	StreamConsultMessage(context.Context, *connect.Request[message.SendConsultMessageRequest], *connect.ServerStream[message.StreamConsultMessageResponse]) error
+	// 列出用户使用过的标签及消息数
+	// POST /message.ChatMessageService/ListTags
+	ListTags(context.Context, *connect.Request[message.ListTagsRequest]) (*connect.Response[message.ListTagsResponse], error)
+	// 批量为消息添加用户标签
+	// POST /message.ChatMessageService/AddMessageTags
+	AddMessageTags(context.Context, *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error)
+	// 批量删除消息的用户标签，系统标签不能删除
+	// POST /message.ChatMessageService/RemoveMessageTags
+	RemoveMessageTags(context.Context, *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error)
+	// 全文搜索消息 - 支持跨会话或单会话搜索
+	// POST /message.ChatMessageService/SearchChatMessages
+	SearchChatMessages(context.Context, *connect.Request[message.SearchChatMessagesRequest]) (*connect.Response[message.SearchChatMessagesResponse], error)
+	// 查询咨询消息所在分支组的全部分支
+	// POST /message.ChatMessageService/ListConsultBranches
+	ListConsultBranches(context.Context, *connect.Request[message.ListConsultBranchesRequest]) (*connect.Response[message.ListConsultBranchesResponse], error)
+	// 选择咨询消息所在的分支作为当前分支
+	// POST /message.ChatMessageService/SelectConsultBranch
+	SelectConsultBranch(context.Context, *connect.Request[message.SelectConsultBranchRequest]) (*connect.Response[message.SelectConsultBranchResponse], error)
 	// 解析图片中的消息（保留原功能）
 	// POST /message.ChatMessageService/ParseImageMessages
 	ParseImageMessages(context.Context, *connect.Request[message.ParseImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error)
+	// 批量解析多张内容有重叠的截图，按顺序拼接成一份去重后的聊天记录
+	// POST /message.ChatMessageService/ParseImageMessagesBatch
+	ParseImageMessagesBatch(context.Context, *connect.Request[message.ParseImageMessagesBatchRequest]) (*connect.Response[message.ParseImageMessagesResponse], error)
+	// 解析截图但不保存，返回识别结果供用户修正，parse_id 在一段时间内有效
+	// POST /message.ChatMessageService/PreviewImageMessages
+	PreviewImageMessages(context.Context, *connect.Request[message.PreviewImageMessagesRequest]) (*connect.Response[message.PreviewImageMessagesResponse], error)
+	// 保存用户确认后的截图解析结果，每个 parse_id 只能确认一次
+	// POST /message.ChatMessageService/ConfirmImageMessages
+	ConfirmImageMessages(context.Context, *connect.Request[message.ConfirmImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error)
+	// 导入微信/QQ 文本记录、CSV、JSON 格式的聊天记录
+	// POST /message.ChatMessageService/ImportChatHistory
+	ImportChatHistory(context.Context, *connect.Request[message.ImportChatHistoryRequest]) (*connect.Response[message.ImportChatHistoryResponse], error)
+	// 导出会话为 Markdown、HTML 或 JSON 文件
+	// POST /message.ChatMessageService/ExportChatSession
+	ExportChatSession(context.Context, *connect.Request[message.ExportChatSessionRequest]) (*connect.Response[message.ExportChatSessionResponse], error)
+	// 移动消息到同一用户的另一个会话，子消息一起移动
+	// POST /message.ChatMessageService/MoveChatMessages
+	MoveChatMessages(context.Context, *connect.Request[message.MoveChatMessagesRequest]) (*connect.Response[message.MoveChatMessagesResponse], error)
+	// 复制消息到同一用户的另一个会话，子消息一起复制
+	// POST /message.ChatMessageService/CopyChatMessages
+	CopyChatMessages(context.Context, *connect.Request[message.CopyChatMessagesRequest]) (*connect.Response[message.CopyChatMessagesResponse], error)
+	// 合并两个会话，源会话的消息合并到目标会话后删除源会话
+	// POST /message.ChatMessageService/MergeChatSessions
+	MergeChatSessions(context.Context, *connect.Request[message.MergeChatSessionsRequest]) (*connect.Response[message.MergeChatSessionsResponse], error)
 	// 用户反馈 点赞/踩/评论
 	// POST /message.ChatMessageService/FeedbackToMessage
 	FeedbackToMessage(context.Context, *connect.Request[message.FeedbackToMessageRequest]) (*connect.Response[message.FeedbackToMessageResponse], error)
+	// 反馈报表，按提示词、模型、消息类型和日期统计点赞/点踩比例，仅管理员可用
+	// POST /message.ChatMessageService/GetFeedbackReport
+	GetFeedbackReport(context.Context, *connect.Request[message.GetFeedbackReportRequest]) (*connect.Response[message.GetFeedbackReportResponse], error)
+	// 回复建议 - 针对对方的一条聊天记录，生成多条不同语气的回复建议
+	// POST /message.ChatMessageService/SuggestReplies
+	SuggestReplies(context.Context, *connect.Request[message.SuggestRepliesRequest]) (*connect.Response[message.SuggestRepliesResponse], error)
+	// 保存选中的回复建议为自己发送的聊天记录
+	// POST /message.ChatMessageService/SaveSuggestedReply
+	SaveSuggestedReply(context.Context, *connect.Request[message.SaveSuggestedReplyRequest]) (*connect.Response[message.SaveSuggestedReplyResponse], error)
+	// 会话情感走势 - 分析聊天记录的情感和情绪，返回按天的走势和明显的转折点
+	// 分析结果按消息缓存，消息内容修改后重新分析
+	// POST /message.ChatMessageService/GetSessionSentiment
+	GetSessionSentiment(context.Context, *connect.Request[message.GetSessionSentimentRequest]) (*connect.Response[message.GetSessionSentimentResponse], error)
+	// 订阅消息变更 - 推送当前用户会话中消息的创建、更新和删除事件，用于多端同步
+	// 连接期间定时推送心跳帧，断开重连后客户端需要重新拉取消息
+	// POST /message.ChatMessageService/WatchChatMessages
+	WatchChatMessages(context.Context, *connect.Request[message.WatchChatMessagesRequest], *connect.ServerStream[message.WatchChatMessagesResponse]) error
 }
 
 // NewChatMessageServiceHandler builds an HTTP handler from the service implementation. It returns
@@ -261,6 +729,18 @@ func NewChatMessageServiceHandler(svc ChatMessageServiceHandler, opts ...connect
 		connect.WithSchema(chatMessageServiceMethods.ByName("UpdateChatMessage")),
 		connect.WithHandlerOptions(opts...),
 	)
+	chatMessageServiceListMessageRevisionsHandler := connect.NewUnaryHandler(
+		ChatMessageServiceListMessageRevisionsProcedure,
+		svc.ListMessageRevisions,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ListMessageRevisions")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceRollbackChatMessageHandler := connect.NewUnaryHandler(
+		ChatMessageServiceRollbackChatMessageProcedure,
+		svc.RollbackChatMessage,
+		connect.WithSchema(chatMessageServiceMethods.ByName("RollbackChatMessage")),
+		connect.WithHandlerOptions(opts...),
+	)
 	chatMessageServiceDeleteChatMessageHandler := connect.NewUnaryHandler(
 		ChatMessageServiceDeleteChatMessageProcedure,
 		svc.DeleteChatMessage,
@@ -273,18 +753,138 @@ func NewChatMessageServiceHandler(svc ChatMessageServiceHandler, opts ...connect
 		connect.WithSchema(chatMessageServiceMethods.ByName("SendConsultMessage")),
 		connect.WithHandlerOptions(opts...),
 	)
+	chatMessageServiceStreamConsultMessageHandler := connect.NewServerStreamHandler(
+		ChatMessageServiceStreamConsultMessageProcedure,
+		svc.StreamConsultMessage,
+		connect.WithSchema(chatMessageServiceMethods.ByName("StreamConsultMessage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceListTagsHandler := connect.NewUnaryHandler(
+		ChatMessageServiceListTagsProcedure,
+		svc.ListTags,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ListTags")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceAddMessageTagsHandler := connect.NewUnaryHandler(
+		ChatMessageServiceAddMessageTagsProcedure,
+		svc.AddMessageTags,
+		connect.WithSchema(chatMessageServiceMethods.ByName("AddMessageTags")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceRemoveMessageTagsHandler := connect.NewUnaryHandler(
+		ChatMessageServiceRemoveMessageTagsProcedure,
+		svc.RemoveMessageTags,
+		connect.WithSchema(chatMessageServiceMethods.ByName("RemoveMessageTags")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceSearchChatMessagesHandler := connect.NewUnaryHandler(
+		ChatMessageServiceSearchChatMessagesProcedure,
+		svc.SearchChatMessages,
+		connect.WithSchema(chatMessageServiceMethods.ByName("SearchChatMessages")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceListConsultBranchesHandler := connect.NewUnaryHandler(
+		ChatMessageServiceListConsultBranchesProcedure,
+		svc.ListConsultBranches,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ListConsultBranches")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceSelectConsultBranchHandler := connect.NewUnaryHandler(
+		ChatMessageServiceSelectConsultBranchProcedure,
+		svc.SelectConsultBranch,
+		connect.WithSchema(chatMessageServiceMethods.ByName("SelectConsultBranch")),
+		connect.WithHandlerOptions(opts...),
+	)
 	chatMessageServiceParseImageMessagesHandler := connect.NewUnaryHandler(
 		ChatMessageServiceParseImageMessagesProcedure,
 		svc.ParseImageMessages,
 		connect.WithSchema(chatMessageServiceMethods.ByName("ParseImageMessages")),
 		connect.WithHandlerOptions(opts...),
 	)
+	chatMessageServiceParseImageMessagesBatchHandler := connect.NewUnaryHandler(
+		ChatMessageServiceParseImageMessagesBatchProcedure,
+		svc.ParseImageMessagesBatch,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ParseImageMessagesBatch")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServicePreviewImageMessagesHandler := connect.NewUnaryHandler(
+		ChatMessageServicePreviewImageMessagesProcedure,
+		svc.PreviewImageMessages,
+		connect.WithSchema(chatMessageServiceMethods.ByName("PreviewImageMessages")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceConfirmImageMessagesHandler := connect.NewUnaryHandler(
+		ChatMessageServiceConfirmImageMessagesProcedure,
+		svc.ConfirmImageMessages,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ConfirmImageMessages")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceImportChatHistoryHandler := connect.NewUnaryHandler(
+		ChatMessageServiceImportChatHistoryProcedure,
+		svc.ImportChatHistory,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ImportChatHistory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceExportChatSessionHandler := connect.NewUnaryHandler(
+		ChatMessageServiceExportChatSessionProcedure,
+		svc.ExportChatSession,
+		connect.WithSchema(chatMessageServiceMethods.ByName("ExportChatSession")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceMoveChatMessagesHandler := connect.NewUnaryHandler(
+		ChatMessageServiceMoveChatMessagesProcedure,
+		svc.MoveChatMessages,
+		connect.WithSchema(chatMessageServiceMethods.ByName("MoveChatMessages")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceCopyChatMessagesHandler := connect.NewUnaryHandler(
+		ChatMessageServiceCopyChatMessagesProcedure,
+		svc.CopyChatMessages,
+		connect.WithSchema(chatMessageServiceMethods.ByName("CopyChatMessages")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceMergeChatSessionsHandler := connect.NewUnaryHandler(
+		ChatMessageServiceMergeChatSessionsProcedure,
+		svc.MergeChatSessions,
+		connect.WithSchema(chatMessageServiceMethods.ByName("MergeChatSessions")),
+		connect.WithHandlerOptions(opts...),
+	)
 	chatMessageServiceFeedbackToMessageHandler := connect.NewUnaryHandler(
 		ChatMessageServiceFeedbackToMessageProcedure,
 		svc.FeedbackToMessage,
 		connect.WithSchema(chatMessageServiceMethods.ByName("FeedbackToMessage")),
 		connect.WithHandlerOptions(opts...),
 	)
+	chatMessageServiceGetFeedbackReportHandler := connect.NewUnaryHandler(
+		ChatMessageServiceGetFeedbackReportProcedure,
+		svc.GetFeedbackReport,
+		connect.WithSchema(chatMessageServiceMethods.ByName("GetFeedbackReport")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceSuggestRepliesHandler := connect.NewUnaryHandler(
+		ChatMessageServiceSuggestRepliesProcedure,
+		svc.SuggestReplies,
+		connect.WithSchema(chatMessageServiceMethods.ByName("SuggestReplies")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceSaveSuggestedReplyHandler := connect.NewUnaryHandler(
+		ChatMessageServiceSaveSuggestedReplyProcedure,
+		svc.SaveSuggestedReply,
+		connect.WithSchema(chatMessageServiceMethods.ByName("SaveSuggestedReply")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceGetSessionSentimentHandler := connect.NewUnaryHandler(
+		ChatMessageServiceGetSessionSentimentProcedure,
+		svc.GetSessionSentiment,
+		connect.WithSchema(chatMessageServiceMethods.ByName("GetSessionSentiment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	chatMessageServiceWatchChatMessagesHandler := connect.NewServerStreamHandler(
+		ChatMessageServiceWatchChatMessagesProcedure,
+		svc.WatchChatMessages,
+		connect.WithSchema(chatMessageServiceMethods.ByName("WatchChatMessages")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/message.ChatMessageService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ChatMessageServiceListChatMessagesProcedure:
@@ -293,14 +893,58 @@ func NewChatMessageServiceHandler(svc ChatMessageServiceHandler, opts ...connect
 			chatMessageServiceCreateChatMessageHandler.ServeHTTP(w, r)
 		case ChatMessageServiceUpdateChatMessageProcedure:
 			chatMessageServiceUpdateChatMessageHandler.ServeHTTP(w, r)
+		case ChatMessageServiceListMessageRevisionsProcedure:
+			chatMessageServiceListMessageRevisionsHandler.ServeHTTP(w, r)
+		case ChatMessageServiceRollbackChatMessageProcedure:
+			chatMessageServiceRollbackChatMessageHandler.ServeHTTP(w, r)
 		case ChatMessageServiceDeleteChatMessageProcedure:
 			chatMessageServiceDeleteChatMessageHandler.ServeHTTP(w, r)
 		case ChatMessageServiceSendConsultMessageProcedure:
 			chatMessageServiceSendConsultMessageHandler.ServeHTTP(w, r)
+		case ChatMessageServiceStreamConsultMessageProcedure:
+			chatMessageServiceStreamConsultMessageHandler.ServeHTTP(w, r)
+		case ChatMessageServiceListTagsProcedure:
+			chatMessageServiceListTagsHandler.ServeHTTP(w, r)
+		case ChatMessageServiceAddMessageTagsProcedure:
+			chatMessageServiceAddMessageTagsHandler.ServeHTTP(w, r)
+		case ChatMessageServiceRemoveMessageTagsProcedure:
+			chatMessageServiceRemoveMessageTagsHandler.ServeHTTP(w, r)
+		case ChatMessageServiceSearchChatMessagesProcedure:
+			chatMessageServiceSearchChatMessagesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceListConsultBranchesProcedure:
+			chatMessageServiceListConsultBranchesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceSelectConsultBranchProcedure:
+			chatMessageServiceSelectConsultBranchHandler.ServeHTTP(w, r)
 		case ChatMessageServiceParseImageMessagesProcedure:
 			chatMessageServiceParseImageMessagesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceParseImageMessagesBatchProcedure:
+			chatMessageServiceParseImageMessagesBatchHandler.ServeHTTP(w, r)
+		case ChatMessageServicePreviewImageMessagesProcedure:
+			chatMessageServicePreviewImageMessagesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceConfirmImageMessagesProcedure:
+			chatMessageServiceConfirmImageMessagesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceImportChatHistoryProcedure:
+			chatMessageServiceImportChatHistoryHandler.ServeHTTP(w, r)
+		case ChatMessageServiceExportChatSessionProcedure:
+			chatMessageServiceExportChatSessionHandler.ServeHTTP(w, r)
+		case ChatMessageServiceMoveChatMessagesProcedure:
+			chatMessageServiceMoveChatMessagesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceCopyChatMessagesProcedure:
+			chatMessageServiceCopyChatMessagesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceMergeChatSessionsProcedure:
+			chatMessageServiceMergeChatSessionsHandler.ServeHTTP(w, r)
 		case ChatMessageServiceFeedbackToMessageProcedure:
 			chatMessageServiceFeedbackToMessageHandler.ServeHTTP(w, r)
+		case ChatMessageServiceGetFeedbackReportProcedure:
+			chatMessageServiceGetFeedbackReportHandler.ServeHTTP(w, r)
+		case ChatMessageServiceSuggestRepliesProcedure:
+			chatMessageServiceSuggestRepliesHandler.ServeHTTP(w, r)
+		case ChatMessageServiceSaveSuggestedReplyProcedure:
+			chatMessageServiceSaveSuggestedReplyHandler.ServeHTTP(w, r)
+		case ChatMessageServiceGetSessionSentimentProcedure:
+			chatMessageServiceGetSessionSentimentHandler.ServeHTTP(w, r)
+		case ChatMessageServiceWatchChatMessagesProcedure:
+			chatMessageServiceWatchChatMessagesHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -322,6 +966,14 @@ func (UnimplementedChatMessageServiceHandler) UpdateChatMessage(context.Context,
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.UpdateChatMessage is not implemented"))
 }
 
+func (UnimplementedChatMessageServiceHandler) ListMessageRevisions(context.Context, *connect.Request[message.ListMessageRevisionsRequest]) (*connect.Response[message.ListMessageRevisionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ListMessageRevisions is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) RollbackChatMessage(context.Context, *connect.Request[message.RollbackChatMessageRequest]) (*connect.Response[message.RollbackChatMessageResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.RollbackChatMessage is not implemented"))
+}
+
 func (UnimplementedChatMessageServiceHandler) DeleteChatMessage(context.Context, *connect.Request[message.DeleteChatMessageRequest]) (*connect.Response[message.DeleteChatMessageResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.DeleteChatMessage is not implemented"))
 }
@@ -330,14 +982,94 @@ func (UnimplementedChatMessageServiceHandler) SendConsultMessage(context.Context
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.SendConsultMessage is not implemented"))
 }
 
+func (UnimplementedChatMessageServiceHandler) StreamConsultMessage(context.Context, *connect.Request[message.SendConsultMessageRequest], *connect.ServerStream[message.StreamConsultMessageResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.StreamConsultMessage is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) ListTags(context.Context, *connect.Request[message.ListTagsRequest]) (*connect.Response[message.ListTagsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ListTags is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) AddMessageTags(context.Context, *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.AddMessageTags is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) RemoveMessageTags(context.Context, *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.RemoveMessageTags is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) SearchChatMessages(context.Context, *connect.Request[message.SearchChatMessagesRequest]) (*connect.Response[message.SearchChatMessagesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.SearchChatMessages is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) ListConsultBranches(context.Context, *connect.Request[message.ListConsultBranchesRequest]) (*connect.Response[message.ListConsultBranchesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ListConsultBranches is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) SelectConsultBranch(context.Context, *connect.Request[message.SelectConsultBranchRequest]) (*connect.Response[message.SelectConsultBranchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.SelectConsultBranch is not implemented"))
+}
+
 func (UnimplementedChatMessageServiceHandler) ParseImageMessages(context.Context, *connect.Request[message.ParseImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ParseImageMessages is not implemented"))
 }
 
+func (UnimplementedChatMessageServiceHandler) ParseImageMessagesBatch(context.Context, *connect.Request[message.ParseImageMessagesBatchRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ParseImageMessagesBatch is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) PreviewImageMessages(context.Context, *connect.Request[message.PreviewImageMessagesRequest]) (*connect.Response[message.PreviewImageMessagesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.PreviewImageMessages is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) ConfirmImageMessages(context.Context, *connect.Request[message.ConfirmImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ConfirmImageMessages is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) ImportChatHistory(context.Context, *connect.Request[message.ImportChatHistoryRequest]) (*connect.Response[message.ImportChatHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ImportChatHistory is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) ExportChatSession(context.Context, *connect.Request[message.ExportChatSessionRequest]) (*connect.Response[message.ExportChatSessionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.ExportChatSession is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) MoveChatMessages(context.Context, *connect.Request[message.MoveChatMessagesRequest]) (*connect.Response[message.MoveChatMessagesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.MoveChatMessages is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) CopyChatMessages(context.Context, *connect.Request[message.CopyChatMessagesRequest]) (*connect.Response[message.CopyChatMessagesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.CopyChatMessages is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) MergeChatSessions(context.Context, *connect.Request[message.MergeChatSessionsRequest]) (*connect.Response[message.MergeChatSessionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.MergeChatSessions is not implemented"))
+}
+
 func (UnimplementedChatMessageServiceHandler) FeedbackToMessage(context.Context, *connect.Request[message.FeedbackToMessageRequest]) (*connect.Response[message.FeedbackToMessageResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.FeedbackToMessage is not implemented"))
 }
 
+func (UnimplementedChatMessageServiceHandler) GetFeedbackReport(context.Context, *connect.Request[message.GetFeedbackReportRequest]) (*connect.Response[message.GetFeedbackReportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.GetFeedbackReport is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) SuggestReplies(context.Context, *connect.Request[message.SuggestRepliesRequest]) (*connect.Response[message.SuggestRepliesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.SuggestReplies is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) SaveSuggestedReply(context.Context, *connect.Request[message.SaveSuggestedReplyRequest]) (*connect.Response[message.SaveSuggestedReplyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.SaveSuggestedReply is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) GetSessionSentiment(context.Context, *connect.Request[message.GetSessionSentimentRequest]) (*connect.Response[message.GetSessionSentimentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.GetSessionSentiment is not implemented"))
+}
+
+func (UnimplementedChatMessageServiceHandler) WatchChatMessages(context.Context, *connect.Request[message.WatchChatMessagesRequest], *connect.ServerStream[message.WatchChatMessagesResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("message.ChatMessageService.WatchChatMessages is not implemented"))
+}
+
 // ConsultMessageServiceClient is a client for the message.ConsultMessageService service.
 //
 // Deprecated: do not use.
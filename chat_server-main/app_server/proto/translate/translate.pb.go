@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -242,6 +243,7 @@ type TranslateV2Request struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	ChatSessionId   string                 `protobuf:"bytes,1,opt,name=chat_session_id,json=chatSessionId,proto3" json:"chat_session_id,omitempty"`
 	TargetMessageId string                 `protobuf:"bytes,2,opt,name=target_message_id,json=targetMessageId,proto3" json:"target_message_id,omitempty"`
+	Async           bool                   `protobuf:"varint,3,opt,name=async,proto3" json:"async,omitempty"` // 在后台执行，立即返回 job_id，通过 JobService.GetJob 获取结果
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -290,10 +292,18 @@ func (x *TranslateV2Request) GetTargetMessageId() string {
 	return ""
 }
 
+func (x *TranslateV2Request) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
 type TranslateV2Response struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	NewMessageId  string                 `protobuf:"bytes,1,opt,name=new_message_id,json=newMessageId,proto3" json:"new_message_id,omitempty"`
 	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // async 为 true 时返回
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -342,11 +352,178 @@ func (x *TranslateV2Response) GetContent() string {
 	return ""
 }
 
+func (x *TranslateV2Response) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type TranslateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatSessionId string                 `protobuf:"bytes,1,opt,name=chat_session_id,json=chatSessionId,proto3" json:"chat_session_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // 消息时间范围（可选）
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Roles         []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`  // 要翻译的消息角色（可选），默认只翻译 FRIEND
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"` // 最多翻译的消息数（可选），默认且最多 100，按时间从早到晚取
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranslateSessionRequest) Reset() {
+	*x = TranslateSessionRequest{}
+	mi := &file_proto_translate_translate_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranslateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslateSessionRequest) ProtoMessage() {}
+
+func (x *TranslateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_translate_translate_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslateSessionRequest.ProtoReflect.Descriptor instead.
+func (*TranslateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_translate_translate_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TranslateSessionRequest) GetChatSessionId() string {
+	if x != nil {
+		return x.ChatSessionId
+	}
+	return ""
+}
+
+func (x *TranslateSessionRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *TranslateSessionRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *TranslateSessionRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *TranslateSessionRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TranslateSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`                                    // 待翻译的消息总数
+	Done          int32                  `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`                                      // 已处理的消息数，包括翻译失败的
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`            // 本帧对应的原消息ID，第一帧为空
+	NewMessageId  string                 `protobuf:"bytes,4,opt,name=new_message_id,json=newMessageId,proto3" json:"new_message_id,omitempty"` // 翻译消息的ID
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`                                 // 翻译结果
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                                     // 翻译失败的原因，为空表示成功
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranslateSessionResponse) Reset() {
+	*x = TranslateSessionResponse{}
+	mi := &file_proto_translate_translate_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranslateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslateSessionResponse) ProtoMessage() {}
+
+func (x *TranslateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_translate_translate_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslateSessionResponse.ProtoReflect.Descriptor instead.
+func (*TranslateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_translate_translate_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TranslateSessionResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *TranslateSessionResponse) GetDone() int32 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *TranslateSessionResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *TranslateSessionResponse) GetNewMessageId() string {
+	if x != nil {
+		return x.NewMessageId
+	}
+	return ""
+}
+
+func (x *TranslateSessionResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *TranslateSessionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_proto_translate_translate_proto protoreflect.FileDescriptor
 
 const file_proto_translate_translate_proto_rawDesc = "" +
 	"\n" +
-	"\x1fproto/translate/translate.proto\x12\ttranslate\x1a\x1cgoogle/api/annotations.proto\"j\n" +
+	"\x1fproto/translate/translate.proto\x12\ttranslate\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\"j\n" +
 	"\x10TranslateRequest\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12\x12\n" +
 	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
@@ -359,17 +536,35 @@ const file_proto_translate_translate_proto_rawDesc = "" +
 	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
 	"\x02to\x18\x03 \x01(\tR\x02to\":\n" +
 	"\x1eTranslateFriendMessageResponse\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\tR\acontent\"h\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\"~\n" +
 	"\x12TranslateV2Request\x12&\n" +
 	"\x0fchat_session_id\x18\x01 \x01(\tR\rchatSessionId\x12*\n" +
-	"\x11target_message_id\x18\x02 \x01(\tR\x0ftargetMessageId\"U\n" +
+	"\x11target_message_id\x18\x02 \x01(\tR\x0ftargetMessageId\x12\x14\n" +
+	"\x05async\x18\x03 \x01(\bR\x05async\"l\n" +
 	"\x13TranslateV2Response\x12$\n" +
 	"\x0enew_message_id\x18\x01 \x01(\tR\fnewMessageId\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\tR\acontent2\xbe\x03\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"\xdf\x01\n" +
+	"\x17TranslateSessionRequest\x12&\n" +
+	"\x0fchat_session_id\x18\x01 \x01(\tR\rchatSessionId\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x14\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xb9\x01\n" +
+	"\x18TranslateSessionResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\x05R\x04done\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12$\n" +
+	"\x0enew_message_id\x18\x04 \x01(\tR\fnewMessageId\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error2\xd7\x04\n" +
 	"\x10TranslateService\x12x\n" +
 	"\tTranslate\x12\x1b.translate.TranslateRequest\x1a\x1c.translate.TranslateResponse\"0\x82\xd3\xe4\x93\x02*:\x01*\"%/translate.TranslateService/Translate\x12\xac\x01\n" +
 	"\x16TranslateFriendMessage\x12(.translate.TranslateFriendMessageRequest\x1a).translate.TranslateFriendMessageResponse\"=\x82\xd3\xe4\x93\x027:\x01*\"2/translate.TranslateService/TranslateFriendMessage\x12\x80\x01\n" +
-	"\vTranslateV2\x12\x1d.translate.TranslateV2Request\x1a\x1e.translate.TranslateV2Response\"2\x82\xd3\xe4\x93\x02,:\x01*\"'/translate.TranslateService/TranslateV2B\x1cZ\x1aapp_server/proto/translateb\x06proto3"
+	"\vTranslateV2\x12\x1d.translate.TranslateV2Request\x1a\x1e.translate.TranslateV2Response\"2\x82\xd3\xe4\x93\x02,:\x01*\"'/translate.TranslateService/TranslateV2\x12\x96\x01\n" +
+	"\x10TranslateSession\x12\".translate.TranslateSessionRequest\x1a#.translate.TranslateSessionResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/translate.TranslateService/TranslateSession0\x01B\x1cZ\x1aapp_server/proto/translateb\x06proto3"
 
 var (
 	file_proto_translate_translate_proto_rawDescOnce sync.Once
@@ -383,7 +578,7 @@ func file_proto_translate_translate_proto_rawDescGZIP() []byte {
 	return file_proto_translate_translate_proto_rawDescData
 }
 
-var file_proto_translate_translate_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_translate_translate_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_proto_translate_translate_proto_goTypes = []any{
 	(*TranslateRequest)(nil),               // 0: translate.TranslateRequest
 	(*TranslateResponse)(nil),              // 1: translate.TranslateResponse
@@ -391,19 +586,26 @@ var file_proto_translate_translate_proto_goTypes = []any{
 	(*TranslateFriendMessageResponse)(nil), // 3: translate.TranslateFriendMessageResponse
 	(*TranslateV2Request)(nil),             // 4: translate.TranslateV2Request
 	(*TranslateV2Response)(nil),            // 5: translate.TranslateV2Response
+	(*TranslateSessionRequest)(nil),        // 6: translate.TranslateSessionRequest
+	(*TranslateSessionResponse)(nil),       // 7: translate.TranslateSessionResponse
+	(*timestamppb.Timestamp)(nil),          // 8: google.protobuf.Timestamp
 }
 var file_proto_translate_translate_proto_depIdxs = []int32{
-	0, // 0: translate.TranslateService.Translate:input_type -> translate.TranslateRequest
-	2, // 1: translate.TranslateService.TranslateFriendMessage:input_type -> translate.TranslateFriendMessageRequest
-	4, // 2: translate.TranslateService.TranslateV2:input_type -> translate.TranslateV2Request
-	1, // 3: translate.TranslateService.Translate:output_type -> translate.TranslateResponse
-	3, // 4: translate.TranslateService.TranslateFriendMessage:output_type -> translate.TranslateFriendMessageResponse
-	5, // 5: translate.TranslateService.TranslateV2:output_type -> translate.TranslateV2Response
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	8, // 0: translate.TranslateSessionRequest.start_time:type_name -> google.protobuf.Timestamp
+	8, // 1: translate.TranslateSessionRequest.end_time:type_name -> google.protobuf.Timestamp
+	0, // 2: translate.TranslateService.Translate:input_type -> translate.TranslateRequest
+	2, // 3: translate.TranslateService.TranslateFriendMessage:input_type -> translate.TranslateFriendMessageRequest
+	4, // 4: translate.TranslateService.TranslateV2:input_type -> translate.TranslateV2Request
+	6, // 5: translate.TranslateService.TranslateSession:input_type -> translate.TranslateSessionRequest
+	1, // 6: translate.TranslateService.Translate:output_type -> translate.TranslateResponse
+	3, // 7: translate.TranslateService.TranslateFriendMessage:output_type -> translate.TranslateFriendMessageResponse
+	5, // 8: translate.TranslateService.TranslateV2:output_type -> translate.TranslateV2Response
+	7, // 9: translate.TranslateService.TranslateSession:output_type -> translate.TranslateSessionResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_proto_translate_translate_proto_init() }
@@ -417,7 +619,7 @@ func file_proto_translate_translate_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_translate_translate_proto_rawDesc), len(file_proto_translate_translate_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
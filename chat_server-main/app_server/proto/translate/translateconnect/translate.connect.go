@@ -42,6 +42,9 @@ const (
 	// TranslateServiceTranslateV2Procedure is the fully-qualified name of the TranslateService's
 	// TranslateV2 RPC.
 	TranslateServiceTranslateV2Procedure = "/translate.TranslateService/TranslateV2"
+	// TranslateServiceTranslateSessionProcedure is the fully-qualified name of the TranslateService's
+	// TranslateSession RPC.
+	TranslateServiceTranslateSessionProcedure = "/translate.TranslateService/TranslateSession"
 )
 
 // TranslateServiceClient is a client for the translate.TranslateService service.
@@ -49,6 +52,10 @@ type TranslateServiceClient interface {
 	Translate(context.Context, *connect.Request[translate.TranslateRequest]) (*connect.Response[translate.TranslateResponse], error)
 	TranslateFriendMessage(context.Context, *connect.Request[translate.TranslateFriendMessageRequest]) (*connect.Response[translate.TranslateFriendMessageResponse], error)
 	TranslateV2(context.Context, *connect.Request[translate.TranslateV2Request]) (*connect.Response[translate.TranslateV2Response], error)
+	// 批量翻译会话中还没有翻译的聊天记录，第一帧返回待翻译的总数，之后每处理完一条消息返回一帧
+	// 单条消息翻译失败时在该帧的 error 中返回，不影响其他消息
+	// POST /translate.TranslateService/TranslateSession
+	TranslateSession(context.Context, *connect.Request[translate.TranslateSessionRequest]) (*connect.ServerStreamForClient[translate.TranslateSessionResponse], error)
 }
 
 // NewTranslateServiceClient constructs a client for the translate.TranslateService service. By
@@ -80,6 +87,12 @@ func NewTranslateServiceClient(httpClient connect.HTTPClient, baseURL string, op
 			connect.WithSchema(translateServiceMethods.ByName("TranslateV2")),
 			connect.WithClientOptions(opts...),
 		),
+		translateSession: connect.NewClient[translate.TranslateSessionRequest, translate.TranslateSessionResponse](
+			httpClient,
+			baseURL+TranslateServiceTranslateSessionProcedure,
+			connect.WithSchema(translateServiceMethods.ByName("TranslateSession")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -88,6 +101,7 @@ type translateServiceClient struct {
 	translate              *connect.Client[translate.TranslateRequest, translate.TranslateResponse]
 	translateFriendMessage *connect.Client[translate.TranslateFriendMessageRequest, translate.TranslateFriendMessageResponse]
 	translateV2            *connect.Client[translate.TranslateV2Request, translate.TranslateV2Response]
+	translateSession       *connect.Client[translate.TranslateSessionRequest, translate.TranslateSessionResponse]
 }
 
 // Translate calls translate.TranslateService.Translate.
@@ -105,11 +119,20 @@ func (c *translateServiceClient) TranslateV2(ctx context.Context, req *connect.R
 	return c.translateV2.CallUnary(ctx, req)
 }
 
+// TranslateSession calls translate.TranslateService.TranslateSession.
+func (c *translateServiceClient) TranslateSession(ctx context.Context, req *connect.Request[translate.TranslateSessionRequest]) (*connect.ServerStreamForClient[translate.TranslateSessionResponse], error) {
+	return c.translateSession.CallServerStream(ctx, req)
+}
+
 // TranslateServiceHandler is an implementation of the translate.TranslateService service.
 type TranslateServiceHandler interface {
 	Translate(context.Context, *connect.Request[translate.TranslateRequest]) (*connect.Response[translate.TranslateResponse], error)
 	TranslateFriendMessage(context.Context, *connect.Request[translate.TranslateFriendMessageRequest]) (*connect.Response[translate.TranslateFriendMessageResponse], error)
 	TranslateV2(context.Context, *connect.Request[translate.TranslateV2Request]) (*connect.Response[translate.TranslateV2Response], error)
+	// 批量翻译会话中还没有翻译的聊天记录，第一帧返回待翻译的总数，之后每处理完一条消息返回一帧
+	// 单条消息翻译失败时在该帧的 error 中返回，不影响其他消息
+	// POST /translate.TranslateService/TranslateSession
+	TranslateSession(context.Context, *connect.Request[translate.TranslateSessionRequest], *connect.ServerStream[translate.TranslateSessionResponse]) error
 }
 
 // NewTranslateServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -137,6 +160,12 @@ func NewTranslateServiceHandler(svc TranslateServiceHandler, opts ...connect.Han
 		connect.WithSchema(translateServiceMethods.ByName("TranslateV2")),
 		connect.WithHandlerOptions(opts...),
 	)
+	translateServiceTranslateSessionHandler := connect.NewServerStreamHandler(
+		TranslateServiceTranslateSessionProcedure,
+		svc.TranslateSession,
+		connect.WithSchema(translateServiceMethods.ByName("TranslateSession")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/translate.TranslateService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case TranslateServiceTranslateProcedure:
@@ -145,6 +174,8 @@ func NewTranslateServiceHandler(svc TranslateServiceHandler, opts ...connect.Han
 			translateServiceTranslateFriendMessageHandler.ServeHTTP(w, r)
 		case TranslateServiceTranslateV2Procedure:
 			translateServiceTranslateV2Handler.ServeHTTP(w, r)
+		case TranslateServiceTranslateSessionProcedure:
+			translateServiceTranslateSessionHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -165,3 +196,7 @@ func (UnimplementedTranslateServiceHandler) TranslateFriendMessage(context.Conte
 func (UnimplementedTranslateServiceHandler) TranslateV2(context.Context, *connect.Request[translate.TranslateV2Request]) (*connect.Response[translate.TranslateV2Response], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("translate.TranslateService.TranslateV2 is not implemented"))
 }
+
+func (UnimplementedTranslateServiceHandler) TranslateSession(context.Context, *connect.Request[translate.TranslateSessionRequest], *connect.ServerStream[translate.TranslateSessionResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("translate.TranslateService.TranslateSession is not implemented"))
+}
@@ -0,0 +1,575 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/trash/trash.proto
+
+package trash
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 回收站中的条目，过期后会被物理删除
+type TrashItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`                                      // MESSAGE, SESSION, PROFILE
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`                                    // 消息内容摘要、会话名称或 Profile 名称
+	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`           // 消息所属的会话
+	MessageCount  int32                  `protobuf:"varint,5,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"` // 会话中随会话一起删除的消息数量
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashItem) Reset() {
+	*x = TrashItem{}
+	mi := &file_proto_trash_trash_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashItem) ProtoMessage() {}
+
+func (x *TrashItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashItem.ProtoReflect.Descriptor instead.
+func (*TrashItem) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TrashItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TrashItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TrashItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TrashItem) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *TrashItem) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *TrashItem) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *TrashItem) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// 要恢复或彻底删除的条目
+type TrashItemRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // MESSAGE, SESSION, PROFILE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashItemRef) Reset() {
+	*x = TrashItemRef{}
+	mi := &file_proto_trash_trash_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashItemRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashItemRef) ProtoMessage() {}
+
+func (x *TrashItemRef) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashItemRef.ProtoReflect.Descriptor instead.
+func (*TrashItemRef) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TrashItemRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TrashItemRef) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`                            // 为空时返回全部类型
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // 上一页返回的 next_page_token
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 默认 20，最大 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_proto_trash_trash_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListTrashRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListTrashRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTrashRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TrashItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 为空表示没有更多
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_proto_trash_trash_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTrashResponse) GetItems() []*TrashItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListTrashResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RestoreItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TrashItemRef        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreItemsRequest) Reset() {
+	*x = RestoreItemsRequest{}
+	mi := &file_proto_trash_trash_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreItemsRequest) ProtoMessage() {}
+
+func (x *RestoreItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreItemsRequest.ProtoReflect.Descriptor instead.
+func (*RestoreItemsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RestoreItemsRequest) GetItems() []*TrashItemRef {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type RestoreItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RestoredCount int32                  `protobuf:"varint,1,opt,name=restored_count,json=restoredCount,proto3" json:"restored_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreItemsResponse) Reset() {
+	*x = RestoreItemsResponse{}
+	mi := &file_proto_trash_trash_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreItemsResponse) ProtoMessage() {}
+
+func (x *RestoreItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreItemsResponse.ProtoReflect.Descriptor instead.
+func (*RestoreItemsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RestoreItemsResponse) GetRestoredCount() int32 {
+	if x != nil {
+		return x.RestoredCount
+	}
+	return 0
+}
+
+type PurgeItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TrashItemRef        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"` // 清空回收站，忽略 items
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeItemsRequest) Reset() {
+	*x = PurgeItemsRequest{}
+	mi := &file_proto_trash_trash_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeItemsRequest) ProtoMessage() {}
+
+func (x *PurgeItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeItemsRequest.ProtoReflect.Descriptor instead.
+func (*PurgeItemsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PurgeItemsRequest) GetItems() []*TrashItemRef {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PurgeItemsRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type PurgeItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurgedCount   int32                  `protobuf:"varint,1,opt,name=purged_count,json=purgedCount,proto3" json:"purged_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeItemsResponse) Reset() {
+	*x = PurgeItemsResponse{}
+	mi := &file_proto_trash_trash_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeItemsResponse) ProtoMessage() {}
+
+func (x *PurgeItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trash_trash_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeItemsResponse.ProtoReflect.Descriptor instead.
+func (*PurgeItemsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trash_trash_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PurgeItemsResponse) GetPurgedCount() int32 {
+	if x != nil {
+		return x.PurgedCount
+	}
+	return 0
+}
+
+var File_proto_trash_trash_proto protoreflect.FileDescriptor
+
+const file_proto_trash_trash_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/trash/trash.proto\x12\x05trash\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xff\x01\n" +
+	"\tTrashItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x12#\n" +
+	"\rmessage_count\x18\x05 \x01(\x05R\fmessageCount\x129\n" +
+	"\n" +
+	"deleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"2\n" +
+	"\fTrashItemRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"b\n" +
+	"\x10ListTrashRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"c\n" +
+	"\x11ListTrashResponse\x12&\n" +
+	"\x05items\x18\x01 \x03(\v2\x10.trash.TrashItemR\x05items\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"@\n" +
+	"\x13RestoreItemsRequest\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.trash.TrashItemRefR\x05items\"=\n" +
+	"\x14RestoreItemsResponse\x12%\n" +
+	"\x0erestored_count\x18\x01 \x01(\x05R\rrestoredCount\"P\n" +
+	"\x11PurgeItemsRequest\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.trash.TrashItemRefR\x05items\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"7\n" +
+	"\x12PurgeItemsResponse\x12!\n" +
+	"\fpurged_count\x18\x01 \x01(\x05R\vpurgedCount2\xdc\x02\n" +
+	"\fTrashService\x12h\n" +
+	"\tListTrash\x12\x17.trash.ListTrashRequest\x1a\x18.trash.ListTrashResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/trash.TrashService/ListTrash\x12t\n" +
+	"\fRestoreItems\x12\x1a.trash.RestoreItemsRequest\x1a\x1b.trash.RestoreItemsResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /trash.TrashService/RestoreItems\x12l\n" +
+	"\n" +
+	"PurgeItems\x12\x18.trash.PurgeItemsRequest\x1a\x19.trash.PurgeItemsResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/trash.TrashService/PurgeItemsB\x18Z\x16app_server/proto/trashb\x06proto3"
+
+var (
+	file_proto_trash_trash_proto_rawDescOnce sync.Once
+	file_proto_trash_trash_proto_rawDescData []byte
+)
+
+func file_proto_trash_trash_proto_rawDescGZIP() []byte {
+	file_proto_trash_trash_proto_rawDescOnce.Do(func() {
+		file_proto_trash_trash_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_trash_trash_proto_rawDesc), len(file_proto_trash_trash_proto_rawDesc)))
+	})
+	return file_proto_trash_trash_proto_rawDescData
+}
+
+var file_proto_trash_trash_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_trash_trash_proto_goTypes = []any{
+	(*TrashItem)(nil),             // 0: trash.TrashItem
+	(*TrashItemRef)(nil),          // 1: trash.TrashItemRef
+	(*ListTrashRequest)(nil),      // 2: trash.ListTrashRequest
+	(*ListTrashResponse)(nil),     // 3: trash.ListTrashResponse
+	(*RestoreItemsRequest)(nil),   // 4: trash.RestoreItemsRequest
+	(*RestoreItemsResponse)(nil),  // 5: trash.RestoreItemsResponse
+	(*PurgeItemsRequest)(nil),     // 6: trash.PurgeItemsRequest
+	(*PurgeItemsResponse)(nil),    // 7: trash.PurgeItemsResponse
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_proto_trash_trash_proto_depIdxs = []int32{
+	8, // 0: trash.TrashItem.deleted_at:type_name -> google.protobuf.Timestamp
+	8, // 1: trash.TrashItem.expires_at:type_name -> google.protobuf.Timestamp
+	0, // 2: trash.ListTrashResponse.items:type_name -> trash.TrashItem
+	1, // 3: trash.RestoreItemsRequest.items:type_name -> trash.TrashItemRef
+	1, // 4: trash.PurgeItemsRequest.items:type_name -> trash.TrashItemRef
+	2, // 5: trash.TrashService.ListTrash:input_type -> trash.ListTrashRequest
+	4, // 6: trash.TrashService.RestoreItems:input_type -> trash.RestoreItemsRequest
+	6, // 7: trash.TrashService.PurgeItems:input_type -> trash.PurgeItemsRequest
+	3, // 8: trash.TrashService.ListTrash:output_type -> trash.ListTrashResponse
+	5, // 9: trash.TrashService.RestoreItems:output_type -> trash.RestoreItemsResponse
+	7, // 10: trash.TrashService.PurgeItems:output_type -> trash.PurgeItemsResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_trash_trash_proto_init() }
+func file_proto_trash_trash_proto_init() {
+	if File_proto_trash_trash_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_trash_trash_proto_rawDesc), len(file_proto_trash_trash_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_trash_trash_proto_goTypes,
+		DependencyIndexes: file_proto_trash_trash_proto_depIdxs,
+		MessageInfos:      file_proto_trash_trash_proto_msgTypes,
+	}.Build()
+	File_proto_trash_trash_proto = out.File
+	file_proto_trash_trash_proto_goTypes = nil
+	file_proto_trash_trash_proto_depIdxs = nil
+}
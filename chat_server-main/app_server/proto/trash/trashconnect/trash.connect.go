@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: proto/trash/trash.proto
+
+package trashconnect
+
+import (
+	trash "app_server/proto/trash"
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// TrashServiceName is the fully-qualified name of the TrashService service.
+	TrashServiceName = "trash.TrashService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TrashServiceListTrashProcedure is the fully-qualified name of the TrashService's ListTrash RPC.
+	TrashServiceListTrashProcedure = "/trash.TrashService/ListTrash"
+	// TrashServiceRestoreItemsProcedure is the fully-qualified name of the TrashService's RestoreItems
+	// RPC.
+	TrashServiceRestoreItemsProcedure = "/trash.TrashService/RestoreItems"
+	// TrashServicePurgeItemsProcedure is the fully-qualified name of the TrashService's PurgeItems RPC.
+	TrashServicePurgeItemsProcedure = "/trash.TrashService/PurgeItems"
+)
+
+// TrashServiceClient is a client for the trash.TrashService service.
+type TrashServiceClient interface {
+	// POST /trash.TrashService/ListTrash
+	ListTrash(context.Context, *connect.Request[trash.ListTrashRequest]) (*connect.Response[trash.ListTrashResponse], error)
+	// POST /trash.TrashService/RestoreItems
+	RestoreItems(context.Context, *connect.Request[trash.RestoreItemsRequest]) (*connect.Response[trash.RestoreItemsResponse], error)
+	// POST /trash.TrashService/PurgeItems
+	PurgeItems(context.Context, *connect.Request[trash.PurgeItemsRequest]) (*connect.Response[trash.PurgeItemsResponse], error)
+}
+
+// NewTrashServiceClient constructs a client for the trash.TrashService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTrashServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) TrashServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	trashServiceMethods := trash.File_proto_trash_trash_proto.Services().ByName("TrashService").Methods()
+	return &trashServiceClient{
+		listTrash: connect.NewClient[trash.ListTrashRequest, trash.ListTrashResponse](
+			httpClient,
+			baseURL+TrashServiceListTrashProcedure,
+			connect.WithSchema(trashServiceMethods.ByName("ListTrash")),
+			connect.WithClientOptions(opts...),
+		),
+		restoreItems: connect.NewClient[trash.RestoreItemsRequest, trash.RestoreItemsResponse](
+			httpClient,
+			baseURL+TrashServiceRestoreItemsProcedure,
+			connect.WithSchema(trashServiceMethods.ByName("RestoreItems")),
+			connect.WithClientOptions(opts...),
+		),
+		purgeItems: connect.NewClient[trash.PurgeItemsRequest, trash.PurgeItemsResponse](
+			httpClient,
+			baseURL+TrashServicePurgeItemsProcedure,
+			connect.WithSchema(trashServiceMethods.ByName("PurgeItems")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// trashServiceClient implements TrashServiceClient.
+type trashServiceClient struct {
+	listTrash    *connect.Client[trash.ListTrashRequest, trash.ListTrashResponse]
+	restoreItems *connect.Client[trash.RestoreItemsRequest, trash.RestoreItemsResponse]
+	purgeItems   *connect.Client[trash.PurgeItemsRequest, trash.PurgeItemsResponse]
+}
+
+// ListTrash calls trash.TrashService.ListTrash.
+func (c *trashServiceClient) ListTrash(ctx context.Context, req *connect.Request[trash.ListTrashRequest]) (*connect.Response[trash.ListTrashResponse], error) {
+	return c.listTrash.CallUnary(ctx, req)
+}
+
+// RestoreItems calls trash.TrashService.RestoreItems.
+func (c *trashServiceClient) RestoreItems(ctx context.Context, req *connect.Request[trash.RestoreItemsRequest]) (*connect.Response[trash.RestoreItemsResponse], error) {
+	return c.restoreItems.CallUnary(ctx, req)
+}
+
+// PurgeItems calls trash.TrashService.PurgeItems.
+func (c *trashServiceClient) PurgeItems(ctx context.Context, req *connect.Request[trash.PurgeItemsRequest]) (*connect.Response[trash.PurgeItemsResponse], error) {
+	return c.purgeItems.CallUnary(ctx, req)
+}
+
+// TrashServiceHandler is an implementation of the trash.TrashService service.
+type TrashServiceHandler interface {
+	// POST /trash.TrashService/ListTrash
+	ListTrash(context.Context, *connect.Request[trash.ListTrashRequest]) (*connect.Response[trash.ListTrashResponse], error)
+	// POST /trash.TrashService/RestoreItems
+	RestoreItems(context.Context, *connect.Request[trash.RestoreItemsRequest]) (*connect.Response[trash.RestoreItemsResponse], error)
+	// POST /trash.TrashService/PurgeItems
+	PurgeItems(context.Context, *connect.Request[trash.PurgeItemsRequest]) (*connect.Response[trash.PurgeItemsResponse], error)
+}
+
+// NewTrashServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTrashServiceHandler(svc TrashServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	trashServiceMethods := trash.File_proto_trash_trash_proto.Services().ByName("TrashService").Methods()
+	trashServiceListTrashHandler := connect.NewUnaryHandler(
+		TrashServiceListTrashProcedure,
+		svc.ListTrash,
+		connect.WithSchema(trashServiceMethods.ByName("ListTrash")),
+		connect.WithHandlerOptions(opts...),
+	)
+	trashServiceRestoreItemsHandler := connect.NewUnaryHandler(
+		TrashServiceRestoreItemsProcedure,
+		svc.RestoreItems,
+		connect.WithSchema(trashServiceMethods.ByName("RestoreItems")),
+		connect.WithHandlerOptions(opts...),
+	)
+	trashServicePurgeItemsHandler := connect.NewUnaryHandler(
+		TrashServicePurgeItemsProcedure,
+		svc.PurgeItems,
+		connect.WithSchema(trashServiceMethods.ByName("PurgeItems")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/trash.TrashService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TrashServiceListTrashProcedure:
+			trashServiceListTrashHandler.ServeHTTP(w, r)
+		case TrashServiceRestoreItemsProcedure:
+			trashServiceRestoreItemsHandler.ServeHTTP(w, r)
+		case TrashServicePurgeItemsProcedure:
+			trashServicePurgeItemsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedTrashServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTrashServiceHandler struct{}
+
+func (UnimplementedTrashServiceHandler) ListTrash(context.Context, *connect.Request[trash.ListTrashRequest]) (*connect.Response[trash.ListTrashResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trash.TrashService.ListTrash is not implemented"))
+}
+
+func (UnimplementedTrashServiceHandler) RestoreItems(context.Context, *connect.Request[trash.RestoreItemsRequest]) (*connect.Response[trash.RestoreItemsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trash.TrashService.RestoreItems is not implemented"))
+}
+
+func (UnimplementedTrashServiceHandler) PurgeItems(context.Context, *connect.Request[trash.PurgeItemsRequest]) (*connect.Response[trash.PurgeItemsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trash.TrashService.PurgeItems is not implemented"))
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+)
+
+// 管理员用户ID，通过配置 admin.user_ids 设置
+var adminUserIDs = map[uint]bool{}
+
+func InitAdmins(userIDs []uint) {
+	adminUserIDs = make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		adminUserIDs[id] = true
+	}
+}
+
+// IsAdmin 当前用户是否是管理员
+func IsAdmin(ctx context.Context) bool {
+	userID, _ := ctx.Value(userIDKey).(uint)
+	return userID > 0 && adminUserIDs[userID]
+}
+
+// RequireAdmin 非管理员返回 PermissionDenied
+func RequireAdmin(ctx context.Context) error {
+	if !IsAdmin(ctx) {
+		return connect.NewError(connect.CodePermissionDenied, errors.New("admin only"))
+	}
+	return nil
+}
@@ -28,6 +28,36 @@ func AuthInterceptor(next connect.UnaryFunc) connect.UnaryFunc {
 	})
 }
 
+// Interceptor 同时支持 unary 和 server stream 的鉴权拦截器
+// connect.UnaryInterceptorFunc 不会作用于流式接口，流式接口需要使用该拦截器
+type Interceptor struct{}
+
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return AuthInterceptor(next)
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return connect.StreamingHandlerFunc(func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		userID, err := ParseUserID(conn.RequestHeader().Get("Authorization"))
+		if err != nil {
+			return err
+		}
+
+		// 设置用户ID到上下文
+		ctx = context.WithValue(ctx, userIDKey, userID)
+
+		return next(ctx, conn)
+	})
+}
+
 func ParseUserID(authToken string) (uint, error) {
 	authToken = strings.TrimPrefix(authToken, "Bearer ")
 	if authToken == "" {
@@ -54,7 +84,7 @@ func GetUserID(ctx context.Context) uint {
 	return ctx.Value(userIDKey).(uint)
 }
 
-// SetUserIDToContext 设置用户ID到上下文（仅用于测试）
+// SetUserIDToContext 设置用户ID到上下文，用于测试和后台任务
 func SetUserIDToContext(ctx context.Context, userID uint) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
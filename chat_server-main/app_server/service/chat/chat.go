@@ -2,10 +2,14 @@ package chat
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"app_server/domain"
+	"app_server/domain/trash"
 	"app_server/model"
 	"app_server/pkg/db"
 	"app_server/pkg/fn"
@@ -13,9 +17,12 @@ import (
 	"app_server/service/auth"
 
 	connect "connectrpc.com/connect"
-	"github.com/samber/lo"
+	"gorm.io/gorm"
 )
 
+// 手动设置的标题的最大长度
+const maxTitleLength = 50
+
 type ChatService struct{}
 
 func (s *ChatService) ListChatSessions(ctx context.Context, req *connect.Request[chat.ListChatSessionsRequest]) (*connect.Response[chat.ListChatSessionsResponse], error) {
@@ -43,25 +50,7 @@ func (s *ChatService) ListChatSessions(ctx context.Context, req *connect.Request
 	}
 
 	// 查询好友信息
-	profileIds := make([]uint, 0)
-	for _, chatSession := range chatSessions {
-		if chatSession.ProfileID != 0 {
-			profileIds = append(profileIds, chatSession.ProfileID)
-		}
-	}
-	if len(profileIds) > 0 {
-		profiles := make([]model.Profile, 0)
-		db.GetDB().Where("id IN (?)", profileIds).Find(&profiles)
-		profilesMap := lo.SliceToMap(profiles, func(profile model.Profile) (uint, model.Profile) {
-			return profile.ID, profile
-		})
-		for i, chatSession := range chatSessions {
-			if profile, ok := profilesMap[chatSession.ProfileID]; ok {
-				chatSessions[i].Name = profile.Name
-				chatSessions[i].Avatar = profile.Avatar
-			}
-		}
-	}
+	domain.FillChatSessionProfiles(chatSessions)
 
 	return connect.NewResponse(&chat.ListChatSessionsResponse{
 		Data: fn.Map(chatSessions, model.ChatSession.ToProto),
@@ -99,8 +88,10 @@ func (s *ChatService) DeleteChatSession(ctx context.Context, req *connect.Reques
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
-	// 删除会话
-	if err := db.GetDB().Where("id = ? AND user_id = ?", id, auth.GetUserID(ctx)).Delete(&model.ChatSession{}).Error; err != nil {
+	// 删除会话，会话中的消息一起放入回收站
+	if err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		return trash.DeleteSession(tx, auth.GetUserID(ctx), uint(id))
+	}); err != nil {
 		slog.Error("delete chat session error", "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
@@ -132,6 +123,14 @@ func (s *ChatService) UpdateChatSession(ctx context.Context, req *connect.Reques
 	if msg.Avatar != "" {
 		updates["avatar"] = msg.Avatar
 	}
+	// 手动设置的标题会固定下来，不会被自动生成的标题覆盖；传空字符串取消
+	if msg.Title != nil {
+		title := strings.TrimSpace(*msg.Title)
+		if utf8.RuneCountInString(title) > maxTitleLength {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("title must not exceed %d characters", maxTitleLength))
+		}
+		updates["pinned_title"] = title
+	}
 
 	// 更新数据库
 	if err := db.GetDB().Model(&chatSession).Updates(updates).Error; err != nil {
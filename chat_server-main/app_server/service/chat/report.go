@@ -0,0 +1,117 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"app_server/domain/report"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/chat"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"gorm.io/gorm"
+)
+
+// GenerateSessionReport 立即生成会话在指定时间段内的报告
+func (s *ChatService) GenerateSessionReport(ctx context.Context, req *connect.Request[chat.GenerateSessionReportRequest]) (*connect.Response[chat.GenerateSessionReportResponse], error) {
+	msg := req.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](msg.SessionId)
+	if sessionID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id is required"))
+	}
+	start, end, err := reportPeriod(msg, time.Now())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	sessionReport, err := report.Generate(ctx, userID, sessionID, start, end, model.ReportTriggerManual)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("session not found"))
+	}
+	if errors.Is(err, report.ErrNoMessages) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	if err != nil {
+		slog.Error("generate session report error", "error", err, "sessionID", sessionID)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&chat.GenerateSessionReportResponse{
+		Report: sessionReport.ToProto(),
+	}), nil
+}
+
+// ListSessionReports 查询会话的报告，按时间段从新到旧排序
+func (s *ChatService) ListSessionReports(ctx context.Context, req *connect.Request[chat.ListSessionReportsRequest]) (*connect.Response[chat.ListSessionReportsResponse], error) {
+	msg := req.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](msg.SessionId)
+	if sessionID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id is required"))
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	var chatSession model.ChatSession
+	if err := database.Where("id = ? AND user_id = ?", sessionID, userID).First(&chatSession).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("session not found"))
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	pageSize := int(msg.PageSize)
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	offset := fn.Atoi[int](msg.PageToken)
+
+	var reports []model.SessionReport
+	if err := database.Where("user_id = ? AND session_id = ?", userID, sessionID).
+		Order("period_start DESC, period_end DESC").
+		Offset(offset).
+		Limit(pageSize + 1).
+		Find(&reports).Error; err != nil {
+		slog.Error("list session reports error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	var nextPageToken string
+	if len(reports) > pageSize {
+		reports = reports[:pageSize]
+		nextPageToken = fn.Itoa(offset + pageSize)
+	}
+
+	return connect.NewResponse(&chat.ListSessionReportsResponse{
+		Data:          fn.Map(reports, model.SessionReport.ToProto),
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// reportPeriod 解析报告的时间段，默认截止到 now 的最近 7 天
+func reportPeriod(msg *chat.GenerateSessionReportRequest, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if msg.EndTime != nil {
+		end = msg.EndTime.AsTime()
+	}
+	start := end.Add(-report.DefaultPeriod)
+	if msg.StartTime != nil {
+		start = msg.StartTime.AsTime()
+	}
+
+	if !start.Before(end) {
+		return start, end, errors.New("start_time must be before end_time")
+	}
+	if end.Sub(start) > report.MaxPeriod {
+		return start, end, fmt.Errorf("period must not exceed %d days", int(report.MaxPeriod.Hours()/24))
+	}
+	return start, end, nil
+}
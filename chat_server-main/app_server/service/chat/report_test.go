@@ -0,0 +1,34 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"app_server/domain/report"
+	"app_server/proto/chat"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestReportPeriod(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+
+	start, end, err := reportPeriod(&chat.GenerateSessionReportRequest{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now, end)
+	assert.Equal(t, now.Add(-report.DefaultPeriod), start)
+
+	start, end, err = reportPeriod(&chat.GenerateSessionReportRequest{
+		StartTime: timestamppb.New(now.AddDate(0, 0, -3)),
+		EndTime:   timestamppb.New(now.AddDate(0, 0, -1)),
+	}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -3), start.UTC())
+	assert.Equal(t, now.AddDate(0, 0, -1), end.UTC())
+
+	_, _, err = reportPeriod(&chat.GenerateSessionReportRequest{StartTime: timestamppb.New(now)}, now)
+	assert.Error(t, err)
+	_, _, err = reportPeriod(&chat.GenerateSessionReportRequest{StartTime: timestamppb.New(now.AddDate(0, 0, -40))}, now)
+	assert.Error(t, err)
+}
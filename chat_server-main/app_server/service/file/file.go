@@ -107,6 +107,19 @@ func (s *Service) GetFileByHash(userID uint, fileHash string) (*model.UserFile,
 	return &file, nil
 }
 
+// GetFileByHashAndUsageType 根据文件哈希查找某种用途的文件（用于去重）
+func (s *Service) GetFileByHashAndUsageType(userID uint, fileHash, usageType string) (*model.UserFile, error) {
+	var file model.UserFile
+	err := s.db.Where("user_id = ? AND file_hash = ? AND usage_type = ? AND status = ?", userID, fileHash, usageType, model.FileStatusNormal).First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询文件失败: %w", err)
+	}
+	return &file, nil
+}
+
 // DeleteFile 删除文件（软删除）
 func (s *Service) DeleteFile(userID uint, fileID uint) error {
 	result := s.db.Model(&model.UserFile{}).
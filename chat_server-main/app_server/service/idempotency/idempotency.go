@@ -0,0 +1,288 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/service/auth"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed" // 响应来自第一次请求时设置为 true
+
+	maxKeyLength        = 128
+	defaultTTL          = 24 * time.Hour
+	defaultLockTimeout  = 2 * time.Minute // 超过该时间仍未完成的请求视为已中断，可以重新执行
+	defaultPollInterval = 200 * time.Millisecond
+)
+
+var conf Config
+
+type Config struct {
+	TTL          time.Duration `mapstructure:"ttl"`
+	LockTimeout  time.Duration `mapstructure:"lock_timeout"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
+
+func Init(cfg Config) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = defaultLockTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	conf = cfg
+}
+
+// Procedure 支持幂等的接口，记录响应类型以便重放时解码
+type Procedure struct {
+	name        string
+	newMsg      func() proto.Message
+	newResponse func(msg proto.Message) connect.AnyResponse
+}
+
+// For 声明一个支持幂等的接口，Res 为接口的响应类型
+func For[Res any, PRes interface {
+	*Res
+	proto.Message
+}](procedure string) Procedure {
+	return Procedure{
+		name:   procedure,
+		newMsg: func() proto.Message { return PRes(new(Res)) },
+		newResponse: func(msg proto.Message) connect.AnyResponse {
+			return connect.NewResponse[Res](msg.(PRes))
+		},
+	}
+}
+
+// Interceptor 对指定的接口，相同用户、接口和 Idempotency-Key 的请求只执行一次，重试时返回第一次的响应
+// 并发的重复请求会等待第一次请求完成；第一次请求失败时不保存结果，重试会重新执行
+// 流式接口的响应无法重放，不支持幂等，带 Idempotency-Key 的流式请求直接拒绝，避免客户端误以为请求不会重复执行
+// 需要放在鉴权拦截器之后
+type Interceptor struct {
+	procedures map[string]Procedure
+}
+
+func NewInterceptor(procedures ...Procedure) *Interceptor {
+	byName := make(map[string]Procedure, len(procedures))
+	for _, p := range procedures {
+		byName[p.name] = p
+	}
+	return &Interceptor{procedures: byName}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		key := req.Header().Get(HeaderKey)
+		procedure, ok := i.procedures[req.Spec().Procedure]
+		if key == "" || !ok || req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if len(key) > maxKeyLength {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("Idempotency-Key is too long"))
+		}
+
+		fingerprint, err := requestFingerprint(req.Any())
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		record, owned, err := acquire(ctx, model.IdempotencyRecord{
+			UserID:      auth.GetUserID(ctx),
+			Procedure:   procedure.name,
+			Key:         key,
+			Fingerprint: fingerprint,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return replay(procedure, record)
+		}
+
+		resp, err := next(ctx, req)
+		// 请求 ctx 可能已取消，保存结果时需要脱离
+		saveCtx := context.WithoutCancel(ctx)
+		if err != nil {
+			release(saveCtx, record)
+			return nil, err
+		}
+		if err := complete(saveCtx, record, resp); err != nil {
+			slog.Error("save idempotency record error", "error", err, "procedure", procedure.name)
+			release(saveCtx, record)
+		}
+		return resp, nil
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if conn.RequestHeader().Get(HeaderKey) != "" {
+			return connect.NewError(connect.CodeInvalidArgument, errors.New("Idempotency-Key is not supported for streaming procedures"))
+		}
+		return next(ctx, conn)
+	}
+}
+
+// acquire 尝试占用 key，返回 owned 为 true 时由当前请求执行，否则返回已完成的记录
+func acquire(ctx context.Context, record model.IdempotencyRecord) (model.IdempotencyRecord, bool, error) {
+	database := db.GetDB().WithContext(ctx)
+	for {
+		now := time.Now()
+		record.ID = 0
+		record.Status = model.IdempotencyStatusPending
+		record.ExpiresAt = now.Add(conf.TTL)
+		result := database.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return record, false, connect.NewError(connect.CodeInternal, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return record, true, nil
+		}
+
+		// key 已被占用，等待第一次请求完成
+		var existing model.IdempotencyRecord
+		err := database.Where("user_id = ? AND `procedure` = ? AND `key` = ?", record.UserID, record.Procedure, record.Key).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return record, false, connect.NewError(connect.CodeInternal, err)
+		}
+
+		switch decide(existing, record.Fingerprint, now) {
+		case actionReplay:
+			return existing, false, nil
+		case actionConflict:
+			return record, false, connect.NewError(connect.CodeInvalidArgument, errors.New("Idempotency-Key was used for a different request"))
+		case actionTakeOver:
+			// 记录已过期或第一次请求已中断，删除后重新占用；按 updated_at 删除避免误删其他请求刚占用的记录
+			if err := database.Where("id = ? AND updated_at = ?", existing.ID, existing.UpdatedAt).
+				Delete(&model.IdempotencyRecord{}).Error; err != nil {
+				return record, false, connect.NewError(connect.CodeInternal, err)
+			}
+		case actionWait:
+			select {
+			case <-ctx.Done():
+				return record, false, connect.NewError(connect.CodeCanceled, ctx.Err())
+			case <-time.After(conf.PollInterval):
+			}
+		}
+	}
+}
+
+type action int
+
+const (
+	actionReplay action = iota
+	actionWait
+	actionTakeOver
+	actionConflict
+)
+
+// decide 根据已存在的记录决定当前请求如何处理
+func decide(existing model.IdempotencyRecord, fingerprint string, now time.Time) action {
+	if now.After(existing.ExpiresAt) {
+		return actionTakeOver
+	}
+	if existing.Fingerprint != fingerprint {
+		return actionConflict
+	}
+	if existing.Status == model.IdempotencyStatusDone {
+		return actionReplay
+	}
+	if now.Sub(existing.UpdatedAt) > conf.LockTimeout {
+		return actionTakeOver
+	}
+	return actionWait
+}
+
+// complete 保存第一次请求的响应
+func complete(ctx context.Context, record model.IdempotencyRecord, resp connect.AnyResponse) error {
+	data, err := marshalResponse(resp)
+	if err != nil {
+		return err
+	}
+	return db.GetDB().WithContext(ctx).Model(&record).Updates(map[string]any{
+		"status":   model.IdempotencyStatusDone,
+		"response": data,
+	}).Error
+}
+
+func marshalResponse(resp connect.AnyResponse) ([]byte, error) {
+	msg, ok := resp.Any().(proto.Message)
+	if !ok {
+		return nil, errors.New("response is not a proto message")
+	}
+	return proto.Marshal(msg)
+}
+
+// release 第一次请求失败，删除记录，等待中的请求和之后的重试会重新执行
+func release(ctx context.Context, record model.IdempotencyRecord) {
+	if err := db.GetDB().WithContext(ctx).Delete(&record).Error; err != nil {
+		slog.Error("release idempotency record error", "error", err, "id", record.ID)
+	}
+}
+
+func replay(procedure Procedure, record model.IdempotencyRecord) (connect.AnyResponse, error) {
+	msg := procedure.newMsg()
+	if err := proto.Unmarshal(record.Response, msg); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp := procedure.newResponse(msg)
+	resp.Header().Set(HeaderReplayed, "true")
+	return resp, nil
+}
+
+// requestFingerprint 请求内容的哈希
+func requestFingerprint(msg any) (string, error) {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return "", errors.New("request is not a proto message")
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(protoMsg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// StartCleanupJob 在后台定期删除过期的记录，ctx 取消后退出
+func StartCleanupJob(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := db.GetDB().WithContext(ctx).Where("expires_at < ?", time.Now()).
+				Delete(&model.IdempotencyRecord{}).Error; err != nil {
+				slog.Error("cleanup idempotency records error", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
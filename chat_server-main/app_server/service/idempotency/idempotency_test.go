@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"app_server/model"
+	"app_server/proto/message"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecide(t *testing.T) {
+	Init(Config{})
+	now := time.Now()
+	record := model.IdempotencyRecord{
+		Fingerprint: "a",
+		Status:      model.IdempotencyStatusPending,
+		ExpiresAt:   now.Add(time.Hour),
+		UpdatedAt:   now.Add(-time.Second),
+	}
+
+	assert.Equal(t, actionWait, decide(record, "a", now))
+	assert.Equal(t, actionConflict, decide(record, "b", now))
+
+	// 第一次请求中断
+	assert.Equal(t, actionTakeOver, decide(record, "a", now.Add(defaultLockTimeout)))
+
+	record.Status = model.IdempotencyStatusDone
+	assert.Equal(t, actionReplay, decide(record, "a", now.Add(defaultLockTimeout)))
+
+	// 过期后可以用于新的请求
+	assert.Equal(t, actionTakeOver, decide(record, "b", now.Add(2*time.Hour)))
+}
+
+func TestReplay(t *testing.T) {
+	procedure := For[message.CreateChatMessageResponse]("/message.ChatMessageService/CreateChatMessage")
+	resp := procedure.newResponse(&message.CreateChatMessageResponse{
+		Messages: []*message.ChatMessage{{Id: "1", Content: "你好"}},
+	})
+
+	record := model.IdempotencyRecord{}
+	data, err := marshalResponse(resp)
+	assert.NoError(t, err)
+	record.Response = data
+
+	replayed, err := replay(procedure, record)
+	assert.NoError(t, err)
+	assert.Equal(t, "true", replayed.Header().Get(HeaderReplayed))
+	msg := replayed.Any().(*message.CreateChatMessageResponse)
+	assert.Equal(t, "你好", msg.Messages[0].Content)
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	a, err := requestFingerprint(&message.SendConsultMessageRequest{SessionId: "1", Content: "hi"})
+	assert.NoError(t, err)
+	b, _ := requestFingerprint(&message.SendConsultMessageRequest{SessionId: "1", Content: "hi"})
+	c, _ := requestFingerprint(&message.SendConsultMessageRequest{SessionId: "1", Content: "hello"})
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+
+	_, err = requestFingerprint("not proto")
+	assert.Error(t, err)
+}
+
+type fakeStreamConn struct {
+	connect.StreamingHandlerConn
+	header http.Header
+}
+
+func (c fakeStreamConn) RequestHeader() http.Header { return c.header }
+
+func TestWrapStreamingHandler(t *testing.T) {
+	var called bool
+	handler := NewInterceptor().WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		called = true
+		return nil
+	})
+
+	// 流式接口不支持幂等，带 Idempotency-Key 时拒绝
+	err := handler(context.Background(), fakeStreamConn{header: http.Header{HeaderKey: []string{"k1"}}})
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.False(t, called)
+
+	assert.NoError(t, handler(context.Background(), fakeStreamConn{header: http.Header{}}))
+	assert.True(t, called)
+}
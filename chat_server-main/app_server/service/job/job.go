@@ -0,0 +1,183 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"app_server/domain/aijob"
+	"app_server/model"
+	"app_server/pkg/fn"
+	jobpb "app_server/proto/job"
+	"app_server/proto/message"
+	"app_server/proto/translate"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type JobService struct{}
+
+// SubmitJob 提交后台任务
+func (s *JobService) SubmitJob(ctx context.Context, connectReq *connect.Request[jobpb.SubmitJobRequest]) (*connect.Response[jobpb.SubmitJobResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	var (
+		jobType string
+		payload proto.Message
+	)
+	switch r := req.Request.(type) {
+	case *jobpb.SubmitJobRequest_Consult:
+		jobType, payload = model.AIJobTypeConsult, r.Consult
+	case *jobpb.SubmitJobRequest_Translate:
+		jobType, payload = model.AIJobTypeTranslate, r.Translate
+	case *jobpb.SubmitJobRequest_ParseImage:
+		jobType, payload = model.AIJobTypeParseImage, r.ParseImage
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("request is required"))
+	}
+
+	job, err := Submit(ctx, userID, jobType, payload)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&jobpb.SubmitJobResponse{Job: job}), nil
+}
+
+// GetJob 查询任务状态和结果
+func (s *JobService) GetJob(ctx context.Context, connectReq *connect.Request[jobpb.GetJobRequest]) (*connect.Response[jobpb.GetJobResponse], error) {
+	userID := auth.GetUserID(ctx)
+
+	job, err := aijob.Get(ctx, userID, fn.Atoi[uint](connectReq.Msg.Id))
+	if err != nil {
+		return nil, jobError(err)
+	}
+	pbJob, err := jobToProto(job)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&jobpb.GetJobResponse{Job: pbJob}), nil
+}
+
+// CancelJob 取消任务
+func (s *JobService) CancelJob(ctx context.Context, connectReq *connect.Request[jobpb.CancelJobRequest]) (*connect.Response[jobpb.CancelJobResponse], error) {
+	userID := auth.GetUserID(ctx)
+
+	job, err := aijob.Cancel(ctx, userID, fn.Atoi[uint](connectReq.Msg.Id))
+	if err != nil {
+		return nil, jobError(err)
+	}
+	pbJob, err := jobToProto(job)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&jobpb.CancelJobResponse{Job: pbJob}), nil
+}
+
+// Submit 提交后台任务，请求中的 async 字段会被清除，避免执行时再次提交
+func Submit(ctx context.Context, userID uint, jobType string, req proto.Message) (*jobpb.Job, error) {
+	req = proto.Clone(req)
+	if field := req.ProtoReflect().Descriptor().Fields().ByName("async"); field != nil {
+		req.ProtoReflect().Clear(field)
+	}
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	job, err := aijob.Submit(ctx, userID, jobType, payload)
+	if err != nil {
+		slog.Error("submit ai job error", "error", err, "type", jobType)
+		return nil, jobError(err)
+	}
+	return jobToProto(job)
+}
+
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, aijob.ErrNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, aijob.ErrTooManyJobs):
+		return connect.NewError(connect.CodeResourceExhausted, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+// jobToProto 转换任务，成功的任务按类型解码结果
+func jobToProto(job model.AIJob) (*jobpb.Job, error) {
+	pbJob := &jobpb.Job{
+		Id:        fn.Itoa(job.ID),
+		Type:      job.Type,
+		Status:    job.Status,
+		Attempts:  int32(job.Attempts),
+		Error:     job.Error,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+	}
+	if job.StartedAt != nil {
+		pbJob.StartedAt = timestamppb.New(*job.StartedAt)
+	}
+	if job.FinishedAt != nil {
+		pbJob.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+	if job.Status == model.AIJobStatusPending {
+		pbJob.NextRunAt = timestamppb.New(job.NextRunAt)
+	}
+	if job.Status != model.AIJobStatusSucceeded {
+		return pbJob, nil
+	}
+
+	switch job.Type {
+	case model.AIJobTypeConsult:
+		result := &message.SendConsultMessageResponse{}
+		pbJob.Result = &jobpb.Job_Consult{Consult: result}
+		return pbJob, proto.Unmarshal(job.Result, result)
+	case model.AIJobTypeTranslate:
+		result := &translate.TranslateV2Response{}
+		pbJob.Result = &jobpb.Job_Translate{Translate: result}
+		return pbJob, proto.Unmarshal(job.Result, result)
+	case model.AIJobTypeParseImage:
+		result := &message.ParseImageMessagesResponse{}
+		pbJob.Result = &jobpb.Job_ParseImage{ParseImage: result}
+		return pbJob, proto.Unmarshal(job.Result, result)
+	}
+	return pbJob, nil
+}
+
+// UnaryHandler 将同步接口包装为任务的执行函数，接口返回参数错误等重试也不会成功的错误时不再重试
+func UnaryHandler[Req, Res any, PReq interface {
+	*Req
+	proto.Message
+}, PRes interface {
+	*Res
+	proto.Message
+}](call func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error)) aijob.Handler {
+	return func(ctx context.Context, userID uint, payload []byte) ([]byte, error) {
+		req := PReq(new(Req))
+		if err := proto.Unmarshal(payload, req); err != nil {
+			return nil, aijob.Permanent(err)
+		}
+		resp, err := call(auth.SetUserIDToContext(ctx, userID), connect.NewRequest((*Req)(req)))
+		if err != nil {
+			if !retryable(connect.CodeOf(err)) {
+				return nil, aijob.Permanent(err)
+			}
+			return nil, err
+		}
+		return proto.Marshal(PRes(resp.Msg))
+	}
+}
+
+// retryable 可能是临时故障的错误码
+func retryable(code connect.Code) bool {
+	switch code {
+	case connect.CodeUnknown, connect.CodeInternal, connect.CodeUnavailable, connect.CodeDeadlineExceeded,
+		connect.CodeResourceExhausted, connect.CodeAborted:
+		return true
+	}
+	return false
+}
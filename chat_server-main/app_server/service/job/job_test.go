@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app_server/domain/aijob"
+	"app_server/model"
+	"app_server/proto/translate"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestJobToProto(t *testing.T) {
+	result, _ := proto.Marshal(&translate.TranslateV2Response{NewMessageId: "7", Content: "他想约你周末见面"})
+	finishedAt := time.Now()
+	job := model.AIJob{ID: 3, Type: model.AIJobTypeTranslate, Status: model.AIJobStatusSucceeded, Attempts: 1, Result: result, FinishedAt: &finishedAt}
+
+	pbJob, err := jobToProto(job)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", pbJob.Id)
+	assert.NotNil(t, pbJob.FinishedAt)
+	assert.Nil(t, pbJob.NextRunAt)
+	assert.Equal(t, "他想约你周末见面", pbJob.GetTranslate().Content)
+
+	// 未成功的任务不返回结果
+	job.Status = model.AIJobStatusPending
+	pbJob, err = jobToProto(job)
+	assert.NoError(t, err)
+	assert.Nil(t, pbJob.Result)
+	assert.NotNil(t, pbJob.NextRunAt)
+}
+
+func TestUnaryHandler(t *testing.T) {
+	var gotUserID uint
+	var gotReq *translate.TranslateV2Request
+	var callErr error
+	handler := UnaryHandler(func(ctx context.Context, req *connect.Request[translate.TranslateV2Request]) (*connect.Response[translate.TranslateV2Response], error) {
+		gotUserID = auth.GetUserID(ctx)
+		gotReq = req.Msg
+		if callErr != nil {
+			return nil, callErr
+		}
+		return connect.NewResponse(&translate.TranslateV2Response{NewMessageId: "9"}), nil
+	})
+
+	payload, _ := proto.Marshal(&translate.TranslateV2Request{ChatSessionId: "1", TargetMessageId: "2"})
+	result, err := handler(context.Background(), 5, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), gotUserID)
+	assert.Equal(t, "2", gotReq.TargetMessageId)
+	var resp translate.TranslateV2Response
+	assert.NoError(t, proto.Unmarshal(result, &resp))
+	assert.Equal(t, "9", resp.NewMessageId)
+
+	// 参数错误不重试，内部错误可以重试
+	callErr = connect.NewError(connect.CodeNotFound, errors.New("消息未找到"))
+	_, err = handler(context.Background(), 5, payload)
+	assert.Equal(t, aijob.Permanent(callErr), err)
+
+	callErr = connect.NewError(connect.CodeInternal, errors.New("upstream timeout"))
+	_, err = handler(context.Background(), 5, payload)
+	assert.Equal(t, callErr, err)
+}
+
+func TestSubmitRequestTypes(t *testing.T) {
+	// 每种请求类型都能转换为任务类型，新增类型时需要同时处理结果的解码
+	for _, jobType := range []string{model.AIJobTypeConsult, model.AIJobTypeTranslate, model.AIJobTypeParseImage} {
+		pbJob, err := jobToProto(model.AIJob{Type: jobType, Status: model.AIJobStatusSucceeded})
+		assert.NoError(t, err)
+		assert.NotNil(t, pbJob.Result, jobType)
+	}
+}
@@ -1,28 +1,32 @@
 package message
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"app_server/domain/attachment"
+	"app_server/domain/msgevent"
+	"app_server/domain/summary"
 	"app_server/model"
-	"app_server/pkg/aiapi"
 	"app_server/pkg/db"
 	"app_server/pkg/fn"
 	"app_server/pkg/idgen"
 	"app_server/pkg/oai"
-	"app_server/pkg/ossc"
+	"app_server/pkg/openaic"
 	"app_server/proto/message"
 	"app_server/service/auth"
+	jobsvc "app_server/service/job"
 
 	connect "connectrpc.com/connect"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/samber/lo"
-	"github.com/samber/lo/mutable"
 	"github.com/sashabaranov/go-openai"
 	"gorm.io/gorm"
 )
@@ -67,28 +71,76 @@ func (s *ChatMessageService) ListChatMessages(ctx context.Context, connectReq *c
 		query = query.Where("parent_id IN ?", req.ParentIds)
 	}
 
-	// 处理分页
-	var lastID int
-	pageSize := int(req.PageSize)
-	if pageSize <= 0 {
-		pageSize = 1000 // 默认每页1000条
+	// 根据标签过滤
+	query = filterByTags(query, req.IncludeTags, req.ExcludeTags)
+	tagFiltered := len(req.IncludeTags) > 0 || len(req.ExcludeTags) > 0
+
+	// 翻译消息附加在原消息上返回，不单独占用分页；不在当前分支上的 CONSULT 消息不返回
+	tree, err := loadConsultTree(db.GetDB(), userID, sessionID)
+	if err != nil {
+		slog.Error("load consult tree error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	query = query.Where("msg_type <> ?", model.MessageTypeTranslate)
+	if inactiveIDs := tree.inactiveIDs(); len(inactiveIDs) > 0 {
+		query = query.Where("id NOT IN ?", inactiveIDs)
 	}
 
-	if req.PageToken != "" {
-		lastID, _ = strconv.Atoi(req.PageToken)
-		if lastID > 0 {
-			query = query.Where("id < ?", lastID)
+	// 处理分页
+	var page messagePage
+	var anchorID uint
+	anchored := req.AnchorId != "" || req.AnchorTime != nil
+	if anchored {
+		anchorID, err = resolveAnchor(query.Session(&gorm.Session{}), userID, sessionID, req)
+		if err != nil {
+			return nil, err
+		}
+		if anchorID > 0 {
+			older, moreOlder, err := fetchOlder(query.Session(&gorm.Session{}), anchorID, anchorCount(req.BeforeCount))
+			if err != nil {
+				slog.Error("list chat messages error", "error", err)
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			// 定位消息本身也在之后的消息中返回
+			newer, moreNewer, err := fetchNewer(query.Session(&gorm.Session{}), anchorID, anchorCount(req.AfterCount)+1)
+			if err != nil {
+				slog.Error("list chat messages error", "error", err)
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			page = buildMessagePage(older, moreOlder, newer, moreNewer)
+		}
+	} else {
+		pageSize := int(req.PageSize)
+		if pageSize <= 0 {
+			pageSize = defaultMessagePageSize
+		}
+		cursor := messageCursor{Direction: pageOlder}
+		if req.PageToken != "" {
+			if cursor, err = decodeMessageCursor(req.PageToken); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, err)
+			}
 		}
-	}
 
-	// 查询数据并排序
-	var dbMessages []model.ChatMessage
-	if err := query.Order("id DESC").Limit(pageSize).Find(&dbMessages).Error; err != nil {
-		slog.Error("list chat messages error", "error", err)
-		return nil, connect.NewError(connect.CodeInternal, err)
+		if cursor.Direction == pageNewer {
+			newer, more, err := fetchNewer(query, cursor.ID+1, pageSize)
+			if err != nil {
+				slog.Error("list chat messages error", "error", err)
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			page = buildMessagePage(nil, false, newer, more)
+		} else {
+			older, more, err := fetchOlder(query, cursor.ID, pageSize)
+			if err != nil {
+				slog.Error("list chat messages error", "error", err)
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			page = buildMessagePage(older, more, nil, false)
+		}
 	}
+	dbMessages := page.Messages
 
-	if len(dbMessages) == 0 {
+	// 新会话的第一页没有消息时返回引导消息，引导消息不参与分支；按标签过滤时没有消息不代表是新会话
+	if len(dbMessages) == 0 && !anchored && req.PageToken == "" && !tagFiltered {
 		// 从配置表加载新建会话引导消息
 		var guideConfig model.Config
 		err := db.GetDB().Where("k = ?", "guide_msg:on_new_chat").First(&guideConfig).Error
@@ -103,7 +155,6 @@ func (s *ChatMessageService) ListChatMessages(ctx context.Context, connectReq *c
 		// 转换为 proto 消息数组
 		baseTime := time.Now()
 
-		mutable.Reverse(dbMessages)
 		for i := range dbMessages {
 			dbMsg := &dbMessages[i]
 			dbMsg.UserID = userID
@@ -111,65 +162,68 @@ func (s *ChatMessageService) ListChatMessages(ctx context.Context, connectReq *c
 			dbMsg.Tags = append(dbMsg.Tags, "disable_interact")
 			dbMsg.MsgAt = baseTime
 		}
+		tree = nil
 	}
 
-	// 反转消息顺序，让较早的消息排在前面
-	mutable.Reverse(dbMessages)
-
-	// 从 dbMessages 中分离翻译消息和其他消息
-	translationMap := make(map[uint]model.ChatMessage) // parentID -> 最新翻译消息
-	aiConsultMap := make(map[uint]uint)                // parentID -> 最新 AI CONSULT 消息
+	// 查询本页消息的最新翻译，原消息和翻译总是一起返回
+	translationMap, err := loadLatestTranslations(db.GetDB(), userID, dbMessages)
+	if err != nil {
+		slog.Error("load translations error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
 	var filteredMessages []*message.ChatMessage
 
-	// 1. 第一遍遍历：收集所有翻译消息和 AI CONSULT 消息
-	for _, msg := range dbMessages {
-		if msg.MsgType == model.MessageTypeTranslate && msg.ParentID > 0 {
-			// 保留最新的翻译（ID 最大的）
-			if existing, ok := translationMap[msg.ParentID]; !ok || msg.ID > existing.ID {
-				translationMap[msg.ParentID] = msg
-			}
-		} else if msg.MsgType == model.MessageTypeConsult && msg.ParentID > 0 {
-			// 保留最新的 AI CONSULT 消息（ID 最大的）
-			if existingID, ok := aiConsultMap[msg.ParentID]; !ok || msg.ID > existingID {
-				aiConsultMap[msg.ParentID] = msg.ID
-			}
+	// 当前分支上的 CONSULT 消息
+	activeConsults := make(map[uint]bool)
+	if tree != nil {
+		for _, msg := range tree.activePath() {
+			activeConsults[msg.ID] = true
 		}
 	}
 
-	// 2. 第二遍遍历：处理消息，过滤旧的 AI CONSULT 消息
+	// 翻译生成之后原消息又被修改过的，标记翻译已过期
+	staleTranslations, err := findStaleTranslations(translationMap)
+	if err != nil {
+		slog.Error("find stale translations error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 处理消息，过滤不在当前分支上的 CONSULT 消息
 	for _, msg := range dbMessages {
-		// 跳过翻译类型的消息
-		if msg.MsgType == model.MessageTypeTranslate {
+		// 如果是 CONSULT 消息，检查是否在当前分支上
+		if tree != nil && msg.MsgType == model.MessageTypeConsult && !activeConsults[msg.ID] {
 			continue
 		}
 
-		// 如果是 AI CONSULT 消息，检查是否是最新的
-		if msg.MsgType == model.MessageTypeConsult && msg.ParentID > 0 {
-			// 如果不是最新的 AI CONSULT 消息，跳过
-			if latestID := aiConsultMap[msg.ParentID]; msg.ID != latestID {
-				continue
-			}
-		}
-
 		protoMsg := msg.ToProto()
 
+		// 标注消息在分支组中的位置，供前端切换分支
+		if tree != nil && msg.MsgType == model.MessageTypeConsult {
+			index, count := tree.branchPosition(msg)
+			protoMsg.BranchIndex = int32(index)
+			protoMsg.BranchCount = int32(count)
+		}
+
 		// 如果有对应的翻译，添加到 translate_content
 		if translation, ok := translationMap[msg.ID]; ok {
 			protoMsg.TranslateContent = &translation.Content
+			protoMsg.TranslateStale = staleTranslations[msg.ID]
 		}
 
 		filteredMessages = append(filteredMessages, protoMsg)
 	}
 
-	// 处理结果
-	var nextPageToken string
-	if len(dbMessages) == pageSize {
-		nextPageToken = strconv.Itoa(int(dbMessages[0].ID))
+	// 附件每次查询都重新签名访问地址
+	if err := fillAttachments(db.GetDB(), userID, filteredMessages); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
 	return connect.NewResponse(&message.ListChatMessagesResponse{
-		Messages:      filteredMessages,
-		NextPageToken: nextPageToken,
+		Messages:       filteredMessages,
+		NextPageToken:  page.Older,
+		NewerPageToken: page.Newer,
+		AnchorId:       lo.Ternary(anchorID > 0, fn.Itoa(anchorID), ""),
 	}), nil
 }
 
@@ -183,6 +237,23 @@ func (s *ChatMessageService) CreateChatMessage(ctx context.Context, connectReq *
 		return connect.NewResponse(&message.CreateChatMessageResponse{}), nil
 	}
 
+	// 系统标签只能由服务端设置，客户端只能设置用户标签
+	userTags := make([][]string, len(req.Messages))
+	fileIDs := make([][]uint, len(req.Messages))
+	for i, protoMsg := range req.Messages {
+		tags, err := normalizeUserTags(protoMsg.UserTags)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("messages[%d]: %w", i, err))
+		}
+		if len(tags) > maxMessageUserTags {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("messages[%d]: at most %d tags are allowed", i, maxMessageUserTags))
+		}
+		userTags[i] = tags
+		if fileIDs[i], err = attachmentFileIDs(protoMsg); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("messages[%d]: %w", i, err))
+		}
+	}
+
 	// 开启事务
 	tx := db.GetDB().Begin()
 	defer func() {
@@ -202,7 +273,7 @@ func (s *ChatMessageService) CreateChatMessage(ctx context.Context, connectReq *
 			Role:      protoMsg.Role,
 			MsgType:   protoMsg.MsgType,
 			Content:   protoMsg.Content,
-			Tags:      protoMsg.Tags,
+			UserTags:  userTags[i],
 			MsgAt:     time.Now(),
 		}
 
@@ -220,13 +291,27 @@ func (s *ChatMessageService) CreateChatMessage(ctx context.Context, connectReq *
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	// 添加附件
+	for i, dbMsg := range dbMessages {
+		if err := attachment.Attach(tx, userID, []uint{dbMsg.ID}, fileIDs[i]); err != nil {
+			tx.Rollback()
+			return nil, attachmentError(err)
+		}
+	}
+
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, dbMessages...)
+
 	// 转换为proto消息
 	protoMessages := fn.Map(dbMessages, model.ChatMessage.ToProto)
+	if err := fillAttachments(db.GetDB(), userID, protoMessages); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
 
 	return connect.NewResponse(&message.CreateChatMessageResponse{
 		Messages: protoMessages,
@@ -277,16 +362,10 @@ func (s *ChatMessageService) UpdateChatMessage(ctx context.Context, connectReq *
 		if protoMsg.MsgType != "" {
 			updates["msg_type"] = protoMsg.MsgType
 		}
-		if len(protoMsg.Tags) > 0 {
-			updates["tags"] = protoMsg.Tags
-		}
 
-		// 更新消息
-		var dbMessage model.ChatMessage
-		if err := tx.Model(&model.ChatMessage{}).
-			Where("id = ? AND user_id = ?", id, userID).
-			Updates(updates).
-			First(&dbMessage, id).Error; err != nil {
+		// 更新消息，同时保存修改前的内容
+		dbMessage, err := updateMessageWithRevision(tx, userID, uint(id), updates)
+		if err != nil {
 			tx.Rollback()
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
@@ -294,11 +373,19 @@ func (s *ChatMessageService) UpdateChatMessage(ctx context.Context, connectReq *
 		updatedMessages = append(updatedMessages, dbMessage)
 	}
 
+	// 内容变化后，覆盖这些消息的摘要需要重建
+	if err := summary.InvalidateByMessages(tx, updatedMessages); err != nil {
+		tx.Rollback()
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventUpdated, userID, updatedMessages...)
+
 	// 转换为proto消息
 	protoMessages := fn.Map(updatedMessages, model.ChatMessage.ToProto)
 
@@ -336,13 +423,38 @@ func (s *ChatMessageService) DeleteChatMessage(ctx context.Context, connectReq *
 	}
 
 	// 删除消息
-	result := db.GetDB().Where("id IN ? AND user_id = ?", ids, userID).Delete(&model.ChatMessage{})
-	if result.Error != nil {
-		return nil, connect.NewError(connect.CodeInternal, result.Error)
+	var deletedMessages []model.ChatMessage
+	var deletedCount int64
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id IN ? AND user_id = ?", ids, userID).Find(&deletedMessages).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ? AND user_id = ?", ids, userID).Delete(&model.ChatMessage{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deletedCount = result.RowsAffected
+
+		// 后续消息衔接到被删除消息的上一条，否则从中间删除后整段后续对话都会从当前分支上消失
+		for deletedID, prevID := range relinkPrevIDs(deletedMessages) {
+			if err := tx.Model(&model.ChatMessage{}).
+				Where("user_id = ? AND prev_id = ?", userID, deletedID).
+				Update("prev_id", prevID).Error; err != nil {
+				return err
+			}
+		}
+
+		// 覆盖这些消息的摘要需要重建
+		return summary.InvalidateByMessages(tx, deletedMessages)
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventDeleted, userID, deletedMessages...)
+
 	return connect.NewResponse(&message.DeleteChatMessageResponse{
-		DeletedCount: int32(result.RowsAffected),
+		DeletedCount: int32(deletedCount),
 	}), nil
 }
 
@@ -356,18 +468,21 @@ func (s *ChatMessageService) ParseImageMessages(ctx context.Context, connectReq
 	if sessionID == 0 || req.ImageUrl == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, nil)
 	}
-
-	// 获取图片公开访问链接
-	imageUrl := req.ImageUrl
-	publicURL, err := ossc.GetPublic().UserFileBucket().SignURL(imageUrl, "GET", 3600)
-	slog.Info("publicURL", "publicURL", publicURL)
+	loc, err := loadTimeZone(req.TimeZone)
 	if err != nil {
-		slog.Error("sign image url error", "error", err)
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Async {
+		job, err := jobsvc.Submit(ctx, userID, model.AIJobTypeParseImage, req)
+		if err != nil {
+			return nil, err
+		}
+		return connect.NewResponse(&message.ParseImageMessagesResponse{JobId: job.Id}), nil
 	}
 
 	// 调用火山API解析图片中的聊天记录
-	chatLines, err := aiapi.ParseImageChat(publicURL)
+	chatLines, err := parseImageLines(req.ImageUrl)
 	if err != nil {
 		slog.Error("parse image chat error", "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
@@ -381,29 +496,14 @@ func (s *ChatMessageService) ParseImageMessages(ctx context.Context, connectReq
 		}
 	}()
 
-	// 解析聊天记录并创建消息
-	now := time.Now()
-	var parsedMessages []model.ChatMessage
-
-	for _, line := range chatLines {
-		role, content, ok := aiapi.ParseChatLine(line)
-		if !ok {
-			continue
-		}
-
-		// 创建消息对象
-		msg := model.ChatMessage{
-			UserID:    userID,
-			SessionID: sessionID,
-			Role:      role,
-			MsgAt:     now,
-			MsgType:   "HISTORY",
-			Content:   content,
-			Tags:      []string{"parsed_from_image"},
-		}
-
-		parsedMessages = append(parsedMessages, msg)
-	}
+	// 解析聊天记录并创建消息，相对时间以截图的上传时间为参照
+	ref := imageUploadTime(tx, userID, req.ImageUrl, loc)
+	parsedMessages := imageLineMessages(chatLines, ref, model.ChatMessage{
+		UserID:    userID,
+		SessionID: sessionID,
+		MsgType:   "HISTORY",
+		Tags:      []string{"parsed_from_image"},
+	})
 
 	// 如果没有解析出有效消息
 	if len(parsedMessages) == 0 {
@@ -438,20 +538,35 @@ func (s *ChatMessageService) ParseImageMessages(ctx context.Context, connectReq
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	// 原截图作为附件保存在解析出的消息上
+	if err := attachSourceImage(tx, userID, req.ImageUrl, dbMessages); err != nil {
+		tx.Rollback()
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, dbMessages...)
+
+	protoMessages := fn.Map(dbMessages, model.ChatMessage.ToProto)
+	if err := fillAttachments(db.GetDB(), userID, protoMessages); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
 	return connect.NewResponse(&message.ParseImageMessagesResponse{
 		Success:  true,
 		Message:  "解析成功",
-		Messages: fn.Map(dbMessages, model.ChatMessage.ToProto),
+		Messages: protoMessages,
 	}), nil
 }
 
 // buildChatHistoryWithExclude 构建聊天历史记录，支持排除某个消息之后的内容（用于 regenerate）
-func (s *ChatMessageService) buildChatHistoryWithExclude(ctx context.Context, allMessages []model.ChatMessage, userProfile, friendProfile *model.Profile) []openai.ChatCompletionMessage {
+// historySummary 为较早消息的滚动摘要，为空时表示 allMessages 已包含完整历史
+func (s *ChatMessageService) buildChatHistoryWithExclude(allMessages []model.ChatMessage, systemPrompt, historySummary string, userProfile, friendProfile *model.Profile) []openai.ChatCompletionMessage {
 	// 处理翻译消息去重 - 保留最新的翻译
 	translationMap := make(map[uint]model.ChatMessage) // parentID -> 最新翻译
 	var filteredMessages []model.ChatMessage
@@ -471,8 +586,7 @@ func (s *ChatMessageService) buildChatHistoryWithExclude(ctx context.Context, al
 	// 构建 OpenAI 消息列表
 	var openaiMessages []openai.ChatCompletionMessage
 
-	// 1. 获取系统提示词
-	systemPrompt := s.getSystemPrompt(ctx, friendProfile)
+	// 1. 系统提示词
 	openaiMessages = append(openaiMessages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: systemPrompt})
 
 	// 2. 添加用户和朋友的 profile 信息作为上下文
@@ -506,7 +620,15 @@ func (s *ChatMessageService) buildChatHistoryWithExclude(ctx context.Context, al
 		}
 	}
 
-	// 3. 处理消息历史
+	// 3. 添加较早历史的摘要
+	if historySummary != "" {
+		openaiMessages = append(openaiMessages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleUser,
+			Content: "此前的聊天记录和咨询摘要：\n" + historySummary,
+		})
+	}
+
+	// 4. 处理消息历史
 	var currentHistoryBatch []string
 
 	for _, msg := range filteredMessages {
@@ -546,25 +668,31 @@ func (s *ChatMessageService) buildChatHistoryWithExclude(ctx context.Context, al
 	return openaiMessages
 }
 
+// promptRef 生成消息使用的提示词来源，保存在 AI 消息上用于反馈分析
+type promptRef struct {
+	Key     string
+	Version string
+}
+
 // getSystemPrompt 获取系统提示词，按优先级从不同来源获取
-func (s *ChatMessageService) getSystemPrompt(ctx context.Context, friendProfile *model.Profile) string {
+func (s *ChatMessageService) getSystemPrompt(ctx context.Context, friendProfile *model.Profile) (string, promptRef) {
 	// 1. 优先从 friendProfile.Prompt 获取
 	if friendProfile != nil && friendProfile.Prompt != "" {
-		return friendProfile.Prompt
+		return friendProfile.Prompt, promptRef{Key: fmt.Sprintf("profile:%d", friendProfile.ID)}
 	}
 
 	// 2. 从 config 表获取
 	var config model.Config
-	if err := db.GetDB().Model(&model.Config{}).
+	if err := db.GetDB().WithContext(ctx).Model(&model.Config{}).
 		Where("k = ?", "prompt:consult:default").
 		First(&config).Error; err == nil && config.Value != "" {
-		return config.Value
+		return config.Value, promptRef{Key: config.Key, Version: config.Version}
 	}
 
 	// 3. 使用默认值
 	return `你是一个专业的职场沟通顾问，帮助用户更好地理解和回应领导或者上司的消息。
 你的任务是基于对话历史，为用户提供专业、有帮助的回复建议。
-回复要简洁明了，易于理解，并且具有高情商。`
+回复要简洁明了，易于理解，并且具有高情商。`, promptRef{Key: "prompt:consult:default", Version: "builtin"}
 }
 
 // callAIForReply 调用 AI 生成回复
@@ -586,34 +714,34 @@ func (s *ChatMessageService) formatPromptForLog(openaiMessages []openai.ChatComp
 	return oai.FormatMessagesForLog(openaiMessages)
 }
 
-// SendConsultMessage 发送咨询消息 - 专门用于AI咨询回复
-func (s *ChatMessageService) SendConsultMessage(ctx context.Context, connectReq *connect.Request[message.SendConsultMessageRequest]) (*connect.Response[message.SendConsultMessageResponse], error) {
-	req := connectReq.Msg
-	userID := auth.GetUserID(ctx)
-
-	// 验证参数
-	sessionID := fn.Atoi[uint](req.SessionId)
-	if sessionID == 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, nil)
-	}
+// consultContext 咨询请求的上下文，SendConsultMessage 和 StreamConsultMessage 共用
+type consultContext struct {
+	sessionID      uint
+	userConsultMsg model.ChatMessage
+	openaiMessages []openai.ChatCompletionMessage
+	regenerate     bool         // regenerate 模式下咨询消息已存在，不需要再保存
+	resetGroup     *branchGroup // 生成新分支的分支组，保存后切换到新分支
+	prompt         promptRef
+}
 
-	database := db.GetDB()
+// loadSessionParticipants 查询会话和双方的 Profile，Profile 不存在时返回空的 Profile
+func loadSessionParticipants(database *gorm.DB, userID, sessionID uint) (model.ChatSession, model.Profile, model.Profile, error) {
+	var chatSession model.ChatSession
+	var userProfile, friendProfile model.Profile
 
 	// 获取会话信息以获取 profile 信息
-	var chatSession model.ChatSession
 	if err := database.Model(&model.ChatSession{}).
 		Where("id = ? AND user_id = ?", sessionID, userID).
 		First(&chatSession).Error; err != nil {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("会话未找到"))
+		return chatSession, userProfile, friendProfile, connect.NewError(connect.CodeNotFound, fmt.Errorf("会话未找到"))
 	}
 
 	// 获取 user profile
 	var user model.User
-	var userProfile model.Profile
 	if err := database.Model(&model.User{}).
 		Where("id = ?", userID).
 		First(&user).Error; err != nil {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("用户未找到"))
+		return chatSession, userProfile, friendProfile, connect.NewError(connect.CodeNotFound, fmt.Errorf("用户未找到"))
 	}
 	if err := database.Model(&model.Profile{}).
 		Where("user_id = ? AND id = ?", userID, user.ProfileID).
@@ -622,7 +750,6 @@ func (s *ChatMessageService) SendConsultMessage(ctx context.Context, connectReq
 	}
 
 	// 获取 friend profile
-	var friendProfile model.Profile
 	if chatSession.ProfileID > 0 {
 		if err := database.Model(&model.Profile{}).
 			Where("id = ?", chatSession.ProfileID).
@@ -630,6 +757,23 @@ func (s *ChatMessageService) SendConsultMessage(ctx context.Context, connectReq
 			slog.Warn("未找到好友Profile", "profileID", chatSession.ProfileID)
 		}
 	}
+	return chatSession, userProfile, friendProfile, nil
+}
+
+// prepareConsult 校验咨询请求并构建发送给 AI 的消息列表
+func (s *ChatMessageService) prepareConsult(ctx context.Context, userID uint, req *message.SendConsultMessageRequest) (*consultContext, error) {
+	// 验证参数
+	sessionID := fn.Atoi[uint](req.SessionId)
+	if sessionID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, nil)
+	}
+
+	database := db.GetDB()
+
+	_, userProfile, friendProfile, err := loadSessionParticipants(database, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
 
 	// 获取当前会话的所有消息
 	var allMessages []model.ChatMessage
@@ -640,40 +784,72 @@ func (s *ChatMessageService) SendConsultMessage(ctx context.Context, connectReq
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	// 加载用户选择过的分支，只有当前分支上的咨询消息参与构建历史
+	var selections []model.ChatBranchSelection
+	if err := database.Where("user_id = ? AND session_id = ?", userID, sessionID).
+		Find(&selections).Error; err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	tree := newConsultTree(allMessages, selections)
+	path := tree.activePath()
+	pathIndex := func(id uint) int {
+		return slices.IndexFunc(path, func(msg model.ChatMessage) bool { return msg.ID == id })
+	}
+
 	var userConsultMsg model.ChatMessage
+	var cutID uint       // 非 0 时 id 大于等于 cutID 的聊天记录不参与构建历史
+	pathEnd := len(path) // path[:pathEnd] 参与构建历史
+	var resetGroup *branchGroup
 
-	// 判断是否是 regenerate 操作
+	// 判断是否是 regenerate 或编辑操作
 	targetID := fn.Atoi[uint](req.GetTargetId())
-	if targetID > 0 { // regenerate 模式
-		// 验证 target_id 是最后一条AI CONSULT消息
-		var wasLast bool
-		var targetMsg model.ChatMessage
-		for i := range allMessages {
-			msg := allMessages[len(allMessages)-1-i]
-			if msg.MsgType == model.MessageTypeConsult && msg.Role == model.MessageRoleAI {
-				wasLast = msg.ID == targetID
-				targetMsg = msg
-				break
-			}
-		}
-		if !wasLast {
-			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("target_id must be the last message"))
+	editID := fn.Atoi[uint](req.GetEditId())
+	switch {
+	case targetID > 0: // regenerate 模式，为当前分支上的任意一条 AI 回复生成兄弟分支
+		targetMsg, ok := tree.byID[targetID]
+		if !ok || targetMsg.Role != model.MessageRoleAI || targetMsg.ParentID == 0 || pathIndex(targetID) < 0 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("target_id must be an AI reply on the active branch"))
 		}
+		userConsultMsg = tree.byID[targetMsg.ParentID]
+		pathEnd = pathIndex(userConsultMsg.ID) + 1
+		cutID = targetID
+		resetGroup = &branchGroup{ID: userConsultMsg.ID, Role: model.MessageRoleAI}
 
-		// 查找lastUserConsultMsg
-		userConsultMsg, _ = lo.Find(allMessages, func(msg model.ChatMessage) bool {
-			return msg.ID == targetMsg.ParentID
-		})
-		if userConsultMsg.ID == 0 {
-			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("target_id must be the last message"))
+		// 重新生成较早的回复时，已有摘要可能包含之后的内容
+		if err := summary.InvalidateByMessages(database, []model.ChatMessage{targetMsg}); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	case editID > 0: // 编辑模式，为当前分支上的某条用户咨询创建兄弟分支
+		if req.Content == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("content is required"))
 		}
+		editMsg, ok := tree.byID[editID]
+		idx := pathIndex(editID)
+		if !ok || editMsg.Role == model.MessageRoleAI || idx < 0 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("edit_id must be a user consult on the active branch"))
+		}
+		group, _ := tree.groupOf(editMsg)
+		userConsultMsg = model.ChatMessage{
+			UserID:    userID,
+			SessionID: sessionID,
+			ParentID:  group.ID, // 指向分支组的原始咨询
+			Role:      model.MessageRoleUser,
+			MsgType:   model.MessageTypeConsult,
+			Content:   req.Content,
+			MsgAt:     time.Now(),
+		}
+		if idx > 0 {
+			userConsultMsg.PrevID = path[idx-1].ID
+		}
+		userConsultMsg.ID = idgen.Uint()
+		pathEnd = idx
+		cutID = editID
+		resetGroup = &group
 
-		// 大于等于 target_id 的消息都不参与构建历史
-		allMessages = fn.Filter(allMessages, func(msg model.ChatMessage) bool {
-			return msg.ID < targetID
-		})
-	} else {
-		// 正常模式
+		if err := summary.InvalidateByMessages(database, []model.ChatMessage{editMsg}); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	default: // 正常模式，接在当前分支的最后
 		if req.Content == "" {
 			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("content is required"))
 		}
@@ -687,55 +863,173 @@ func (s *ChatMessageService) SendConsultMessage(ctx context.Context, connectReq
 			Content:   req.Content,
 			MsgAt:     time.Now(),
 		}
+		if len(path) > 0 {
+			userConsultMsg.PrevID = path[len(path)-1].ID
+		}
 		userConsultMsg.ID = idgen.Uint()
-		allMessages = append(allMessages, userConsultMsg)
 	}
 
-	// 构建聊天历史
-	openaiMessages := s.buildChatHistoryWithExclude(ctx, allMessages, &userProfile, &friendProfile)
-
-	// 调用 AI 生成回复
-	replyContent, err := s.callAIForReply(ctx, openaiMessages)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+	// 组装参与构建历史的消息：聊天记录和当前分支上的咨询，按 id 排序
+	historyMessages := fn.Filter(allMessages, func(msg model.ChatMessage) bool {
+		return msg.MsgType != model.MessageTypeConsult && (cutID == 0 || msg.ID < cutID)
+	})
+	historyMessages = append(historyMessages, path[:pathEnd]...)
+	if targetID == 0 {
+		historyMessages = append(historyMessages, userConsultMsg)
 	}
+	slices.SortFunc(historyMessages, func(a, b model.ChatMessage) int { return cmp.Compare(a.ID, b.ID) })
+
+	// 历史超出模型预算时，较早的消息用滚动摘要代替
+	historySummary, recentMessages := summary.Compact(ctx, userID, sessionID, historyMessages, "")
+	systemPrompt, prompt := s.getSystemPrompt(ctx, &friendProfile)
+
+	return &consultContext{
+		sessionID:      sessionID,
+		userConsultMsg: userConsultMsg,
+		openaiMessages: s.buildChatHistoryWithExclude(recentMessages, systemPrompt, historySummary, &userProfile, &friendProfile),
+		regenerate:     targetID > 0,
+		resetGroup:     resetGroup,
+		prompt:         prompt,
+	}, nil
+}
 
-	// 3. 创建回复消息
+// saveConsultReply 保存 AI 回复，正常模式下同时保存用户的咨询消息
+// 新生成的分支会成为当前分支
+func (s *ChatMessageService) saveConsultReply(ctx context.Context, cc *consultContext, replyContent string, tags ...string) (model.ChatMessage, error) {
 	replyMsg := model.ChatMessage{
-		UserID:    userID,
-		SessionID: sessionID,
-		ParentID:  userConsultMsg.ID,
+		UserID:    cc.userConsultMsg.UserID,
+		SessionID: cc.sessionID,
+		ParentID:  cc.userConsultMsg.ID,
+		PrevID:    cc.userConsultMsg.ID,
 		Role:      model.MessageRoleAI,
 		MsgType:   model.MessageTypeConsult,
 		Content:   replyContent,
-		Tags:      []string{"ai_reply"},
+		Tags:      append([]string{"ai_reply"}, tags...),
 		MsgAt:     time.Now(),
+
+		PromptKey:     cc.prompt.Key,
+		PromptVersion: cc.prompt.Version,
+		AIModel:       openaic.Model.Chat,
 	}
 	replyMsg.ID = idgen.Uint()
 	createMsgs := []model.ChatMessage{replyMsg}
-	if targetID == 0 {
-		createMsgs = append([]model.ChatMessage{userConsultMsg}, createMsgs...)
+	if !cc.regenerate {
+		createMsgs = append([]model.ChatMessage{cc.userConsultMsg}, createMsgs...)
+	}
+	err := db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&createMsgs).Error; err != nil {
+			return err
+		}
+		if cc.resetGroup == nil {
+			return nil
+		}
+		// 分支组没有选择时默认使用最新的消息；选择记录有唯一索引，需要物理删除
+		return tx.Unscoped().Where("user_id = ? AND session_id = ? AND group_id = ? AND role = ?",
+			cc.userConsultMsg.UserID, cc.sessionID, cc.resetGroup.ID, cc.resetGroup.Role).
+			Delete(&model.ChatBranchSelection{}).Error
+	})
+	if err != nil {
+		return model.ChatMessage{}, err
+	}
+	msgevent.Publish(ctx, msgevent.EventCreated, cc.userConsultMsg.UserID, createMsgs...)
+	return createMsgs[len(createMsgs)-1], nil
+}
+
+// SendConsultMessage 发送咨询消息 - 专门用于AI咨询回复
+func (s *ChatMessageService) SendConsultMessage(ctx context.Context, connectReq *connect.Request[message.SendConsultMessageRequest]) (*connect.Response[message.SendConsultMessageResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	if req.Async {
+		job, err := jobsvc.Submit(ctx, userID, model.AIJobTypeConsult, req)
+		if err != nil {
+			return nil, err
+		}
+		return connect.NewResponse(&message.SendConsultMessageResponse{JobId: job.Id}), nil
 	}
-	if err := database.Create(&createMsgs).Error; err != nil {
+
+	cc, err := s.prepareConsult(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 调用 AI 生成回复
+	replyContent, err := s.callAIForReply(ctx, cc.openaiMessages)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 创建回复消息
+	replyMsg, err := s.saveConsultReply(ctx, cc, replyContent)
+	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
 	// 输出调试日志
-	log.Printf("SendConsultMessage success\n%s\n [last_reply]:\n%s\n", s.formatPromptForLog(openaiMessages), replyContent)
+	log.Printf("SendConsultMessage success\n%s\n [last_reply]:\n%s\n", s.formatPromptForLog(cc.openaiMessages), replyContent)
 
 	slog.Info("SendConsultMessage success",
-		"consultId", userConsultMsg.ID,
+		"consultId", cc.userConsultMsg.ID,
 		"replyId", replyMsg.ID,
 		"content", req.Content,
 		"reply", replyContent)
 
 	return connect.NewResponse(&message.SendConsultMessageResponse{
-		Consult: userConsultMsg.ToProto(),
+		Consult: cc.userConsultMsg.ToProto(),
 		Reply:   replyMsg.ToProto(),
 	}), nil
 }
 
-// FeedbackToMessage 用户反馈 - 将 attitude 更新到消息的 tags 中
+// StreamConsultMessage 流式发送咨询消息 - 边生成边推送 AI 回复
+// 客户端中途断开时，已生成的部分回复会被保存并打上 interrupted 标签
+func (s *ChatMessageService) StreamConsultMessage(ctx context.Context, connectReq *connect.Request[message.SendConsultMessageRequest], stream *connect.ServerStream[message.StreamConsultMessageResponse]) error {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	cc, err := s.prepareConsult(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+
+	// 先推送咨询消息，客户端可以立即展示
+	if err := stream.Send(&message.StreamConsultMessageResponse{Consult: cc.userConsultMsg.ToProto()}); err != nil {
+		return err
+	}
+
+	// 调用 AI 流式生成回复，每段增量内容直接推送给客户端
+	replyContent, err := oai.Get().CreateChatCompletionStream(ctx, oai.ChatCompletionRequest{
+		Messages: cc.openaiMessages,
+	}, func(delta string) error {
+		return stream.Send(&message.StreamConsultMessageResponse{Delta: delta})
+	})
+	if err != nil {
+		slog.Error("AI stream completion error", "error", err, "received", len(replyContent))
+		if replyContent == "" {
+			return connect.NewError(connect.CodeInternal, err)
+		}
+
+		// 客户端断开或生成中断，保存已生成的部分回复；请求 ctx 可能已取消，保存时需要脱离
+		if _, saveErr := s.saveConsultReply(context.WithoutCancel(ctx), cc, replyContent, model.MessageTagInterrupted); saveErr != nil {
+			slog.Error("save interrupted consult reply error", "error", saveErr)
+		}
+		return connect.NewError(connect.CodeAborted, err)
+	}
+
+	replyMsg, err := s.saveConsultReply(ctx, cc, replyContent)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	slog.Info("StreamConsultMessage success",
+		"consultId", cc.userConsultMsg.ID,
+		"replyId", replyMsg.ID,
+		"content", req.Content,
+		"reply", replyContent)
+
+	return stream.Send(&message.StreamConsultMessageResponse{Reply: replyMsg.ToProto()})
+}
+
+// FeedbackToMessage 用户反馈 - 将 attitude 更新到消息的 tags 中，并保存到反馈表
 func (s *ChatMessageService) FeedbackToMessage(ctx context.Context, connectReq *connect.Request[message.FeedbackToMessageRequest]) (*connect.Response[message.FeedbackToMessageResponse], error) {
 	req := connectReq.Msg
 	userID := auth.GetUserID(ctx)
@@ -750,6 +1044,12 @@ func (s *ChatMessageService) FeedbackToMessage(ctx context.Context, connectReq *
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("attitude must be one of: %v", validAttitudes))
 	}
 
+	// 验证原因分类
+	reasons := lo.Uniq(req.Reasons)
+	if invalid, ok := lo.Find(reasons, func(reason string) bool { return !lo.Contains(model.FeedbackReasons, reason) }); ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid reason: %s, must be one of: %v", invalid, model.FeedbackReasons))
+	}
+
 	// 转换消息ID
 	messageID, err := strconv.ParseUint(req.MessageId, 10, 64)
 	if err != nil {
@@ -778,17 +1078,28 @@ func (s *ChatMessageService) FeedbackToMessage(ctx context.Context, connectReq *
 	filteredTags = append(filteredTags, req.Attitude)
 	dbMessage.Tags = filteredTags
 
-	// 更新消息的 tags
-	if err := db.GetDB().Model(&model.ChatMessage{}).
-		Where("id = ?", messageID).
-		Updates(&dbMessage).Error; err != nil {
+	err = db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 更新消息的 tags
+		if err := tx.Model(&model.ChatMessage{}).
+			Where("id = ?", messageID).
+			Updates(&dbMessage).Error; err != nil {
+			return err
+		}
+
+		// 保存结构化反馈，记录生成消息使用的提示词和模型
+		return saveMessageFeedback(tx, userID, dbMessage, req.Attitude, reasons, req.Feedback)
+	})
+	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventUpdated, userID, dbMessage)
+
 	slog.Info("FeedbackToMessage success",
 		"userId", userID,
 		"messageId", messageID,
 		"attitude", req.Attitude,
+		"reasons", reasons,
 		"updatedTags", filteredTags)
 
 	return connect.NewResponse(&message.FeedbackToMessageResponse{
@@ -0,0 +1,293 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"app_server/domain/summary"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"gorm.io/gorm"
+)
+
+// 咨询消息组成一棵对话树：
+//   - 用户咨询的 ParentID 为 0 时是一个分支组的原始消息，编辑后重新生成的咨询 ParentID 指向原始消息，与原始消息互为兄弟分支
+//   - AI 回复的 ParentID 指向所回复的用户咨询，同一条咨询的多次回复互为兄弟分支
+//   - 新消息的 PrevID 指向对话中的上一条消息；旧数据 PrevID 为 0，按 id 顺序线性衔接
+//
+// 每个分支组默认使用最新的消息，用户选择过的分支保存在 ChatBranchSelection 中
+
+// branchGroup 分支组的键
+type branchGroup struct {
+	ID   uint
+	Role string
+}
+
+type consultTree struct {
+	byID       map[uint]model.ChatMessage
+	roots      []model.ChatMessage          // ParentID 为 0 的咨询消息，按 id 升序
+	edits      map[uint][]model.ChatMessage // 原始咨询ID -> 编辑后的咨询
+	replies    map[uint][]model.ChatMessage // 咨询ID -> AI 回复
+	selections map[branchGroup]uint
+}
+
+// newConsultTree 用会话中的 CONSULT 消息构建对话树，consults 需按 id 升序
+func newConsultTree(consults []model.ChatMessage, selections []model.ChatBranchSelection) *consultTree {
+	t := &consultTree{
+		byID:       make(map[uint]model.ChatMessage),
+		edits:      make(map[uint][]model.ChatMessage),
+		replies:    make(map[uint][]model.ChatMessage),
+		selections: make(map[branchGroup]uint),
+	}
+	for _, msg := range consults {
+		if msg.MsgType != model.MessageTypeConsult {
+			continue
+		}
+		t.byID[msg.ID] = msg
+		switch {
+		case msg.ParentID == 0:
+			t.roots = append(t.roots, msg)
+		case msg.Role == model.MessageRoleAI:
+			t.replies[msg.ParentID] = append(t.replies[msg.ParentID], msg)
+		default:
+			t.edits[msg.ParentID] = append(t.edits[msg.ParentID], msg)
+		}
+	}
+	for _, selection := range selections {
+		t.selections[branchGroup{ID: selection.GroupID, Role: selection.Role}] = selection.ActiveID
+	}
+	return t
+}
+
+// groupOf 返回消息所在的分支组及组内的全部消息
+func (t *consultTree) groupOf(msg model.ChatMessage) (branchGroup, []model.ChatMessage) {
+	switch {
+	case msg.ParentID == 0 && msg.Role == model.MessageRoleAI:
+		// 旧数据中没有对应咨询的 AI 消息，单独成组
+		return branchGroup{ID: msg.ID, Role: model.MessageRoleAI}, []model.ChatMessage{msg}
+	case msg.ParentID == 0:
+		return branchGroup{ID: msg.ID, Role: model.MessageRoleUser}, append([]model.ChatMessage{msg}, t.edits[msg.ID]...)
+	case msg.Role == model.MessageRoleAI:
+		return branchGroup{ID: msg.ParentID, Role: model.MessageRoleAI}, t.replies[msg.ParentID]
+	default:
+		return t.groupOf(t.byID[msg.ParentID])
+	}
+}
+
+// activeOf 返回分支组中当前生效的消息
+func (t *consultTree) activeOf(group branchGroup, members []model.ChatMessage) model.ChatMessage {
+	if activeID, ok := t.selections[group]; ok {
+		for _, member := range members {
+			if member.ID == activeID {
+				return member
+			}
+		}
+	}
+	return members[len(members)-1]
+}
+
+// isLegacy 旧数据没有 PrevID，只能按 id 顺序衔接
+func isLegacy(msg model.ChatMessage) bool {
+	return msg.PrevID == 0 && (msg.ParentID == 0 || msg.Role == model.MessageRoleAI)
+}
+
+// activePath 沿着当前生效的分支返回咨询消息，结果按 id 升序
+func (t *consultTree) activePath() []model.ChatMessage {
+	var path []model.ChatMessage
+	visited := make(map[uint]bool)
+	var last model.ChatMessage
+	var lastRootID uint
+
+	for {
+		// 优先找明确衔接在上一条消息之后的分支组，旧数据再按 id 顺序衔接
+		var next *model.ChatMessage
+		for i, root := range t.roots {
+			if last.ID != 0 && root.PrevID == last.ID {
+				next = &t.roots[i]
+				break
+			}
+		}
+		if next == nil && (last.ID == 0 || isLegacy(last)) {
+			for i, root := range t.roots {
+				if root.PrevID == 0 && root.ID > lastRootID {
+					next = &t.roots[i]
+					break
+				}
+			}
+		}
+		if next == nil || visited[next.ID] {
+			break
+		}
+		visited[next.ID] = true
+		lastRootID = next.ID
+
+		last = t.activeOf(t.groupOf(*next))
+		path = append(path, last)
+
+		if last.Role != model.MessageRoleAI {
+			if replies := t.replies[last.ID]; len(replies) > 0 {
+				last = t.activeOf(branchGroup{ID: last.ID, Role: model.MessageRoleAI}, replies)
+				path = append(path, last)
+			}
+		}
+	}
+
+	return path
+}
+
+// relinkPrevIDs 删除消息后，PrevID 指向被删除消息的后续消息需要改为指向的上一条消息
+// 返回 被删除消息ID -> 向前跳过所有被删除消息后的 PrevID，连续删除的多条消息会衔接到最近的未删除消息
+func relinkPrevIDs(deleted []model.ChatMessage) map[uint]uint {
+	prevOf := make(map[uint]uint, len(deleted))
+	for _, msg := range deleted {
+		prevOf[msg.ID] = msg.PrevID
+	}
+	relinked := make(map[uint]uint, len(deleted))
+	for id, prevID := range prevOf {
+		// 删除的消息数量有限，按删除数量限制步数以防数据中有环
+		for steps := 0; steps < len(prevOf); steps++ {
+			next, ok := prevOf[prevID]
+			if !ok {
+				break
+			}
+			prevID = next
+		}
+		relinked[id] = prevID
+	}
+	return relinked
+}
+
+// inactiveIDs 不在当前分支上的咨询消息
+func (t *consultTree) inactiveIDs() []uint {
+	active := make(map[uint]bool)
+	for _, msg := range t.activePath() {
+		active[msg.ID] = true
+	}
+	var ids []uint
+	for id := range t.byID {
+		if !active[id] {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// branchPosition 返回消息在所在分支组中的位置（从 1 开始）和分支数量
+func (t *consultTree) branchPosition(msg model.ChatMessage) (index, count int) {
+	_, members := t.groupOf(msg)
+	return slices.IndexFunc(members, func(member model.ChatMessage) bool { return member.ID == msg.ID }) + 1, len(members)
+}
+
+// loadConsultTree 查询会话中的全部 CONSULT 消息和分支选择，构建对话树
+func loadConsultTree(tx *gorm.DB, userID, sessionID uint) (*consultTree, error) {
+	var consults []model.ChatMessage
+	if err := tx.Model(&model.ChatMessage{}).
+		Select("id", "parent_id", "prev_id", "role", "msg_type").
+		Where("user_id = ? AND session_id = ? AND msg_type = ?", userID, sessionID, model.MessageTypeConsult).
+		Order("id ASC").
+		Find(&consults).Error; err != nil {
+		return nil, err
+	}
+
+	var selections []model.ChatBranchSelection
+	if err := tx.Where("user_id = ? AND session_id = ?", userID, sessionID).
+		Find(&selections).Error; err != nil {
+		return nil, err
+	}
+
+	return newConsultTree(consults, selections), nil
+}
+
+// ListConsultBranches 查询某条咨询消息所在分支组的全部分支
+func (s *ChatMessageService) ListConsultBranches(ctx context.Context, connectReq *connect.Request[message.ListConsultBranchesRequest]) (*connect.Response[message.ListConsultBranchesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	messageID := fn.Atoi[uint](req.MessageId)
+	if sessionID == 0 || messageID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and message_id are required"))
+	}
+
+	tree, err := loadConsultTree(db.GetDB(), userID, sessionID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	msg, ok := tree.byID[messageID]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("consult message not found"))
+	}
+	group, members := tree.groupOf(msg)
+
+	// 树中只有结构字段，这里查询完整内容
+	var dbMessages []model.ChatMessage
+	if err := db.GetDB().Where("id IN ? AND user_id = ?", fn.Map(members, func(m model.ChatMessage) uint { return m.ID }), userID).
+		Order("id ASC").
+		Find(&dbMessages).Error; err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.ListConsultBranchesResponse{
+		Messages: fn.Map(dbMessages, model.ChatMessage.ToProto),
+		ActiveId: fn.Itoa(tree.activeOf(group, members).ID),
+	}), nil
+}
+
+// SelectConsultBranch 选择某条咨询消息所在的分支作为当前分支
+func (s *ChatMessageService) SelectConsultBranch(ctx context.Context, connectReq *connect.Request[message.SelectConsultBranchRequest]) (*connect.Response[message.SelectConsultBranchResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	messageID := fn.Atoi[uint](req.MessageId)
+	if sessionID == 0 || messageID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and message_id are required"))
+	}
+
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		tree, err := loadConsultTree(tx, userID, sessionID)
+		if err != nil {
+			return err
+		}
+		msg, ok := tree.byID[messageID]
+		if !ok {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("consult message not found"))
+		}
+		group, members := tree.groupOf(msg)
+
+		if err := saveBranchSelection(tx, userID, sessionID, group, messageID); err != nil {
+			return err
+		}
+
+		// 切换分支后，摘要中可能包含了其他分支的内容
+		return summary.InvalidateByMessages(tx, members)
+	})
+	if err != nil {
+		if connectErr, ok := err.(*connect.Error); ok {
+			return nil, connectErr
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.SelectConsultBranchResponse{}), nil
+}
+
+// saveBranchSelection 保存分支组当前选择的消息
+func saveBranchSelection(tx *gorm.DB, userID, sessionID uint, group branchGroup, activeID uint) error {
+	selection := model.ChatBranchSelection{
+		UserID:    userID,
+		SessionID: sessionID,
+		GroupID:   group.ID,
+		Role:      group.Role,
+	}
+	return tx.Where(selection).
+		Assign(model.ChatBranchSelection{ActiveID: activeID}).
+		FirstOrCreate(&selection).Error
+}
@@ -0,0 +1,189 @@
+package message
+
+import (
+	"testing"
+
+	"app_server/model"
+	"app_server/pkg/fn"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func consultMsg(id, parentID, prevID uint, role string) model.ChatMessage {
+	return model.ChatMessage{
+		Model:    gorm.Model{ID: id},
+		ParentID: parentID,
+		PrevID:   prevID,
+		Role:     role,
+		MsgType:  model.MessageTypeConsult,
+	}
+}
+
+// TestConsultTreeActivePath 测试对话树当前分支的计算
+func TestConsultTreeActivePath(t *testing.T) {
+	user, ai := model.MessageRoleUser, model.MessageRoleAI
+
+	tests := []struct {
+		name       string
+		consults   []model.ChatMessage
+		selections []model.ChatBranchSelection
+		want       []uint
+	}{
+		{
+			name: "legacy_linear",
+			consults: []model.ChatMessage{
+				consultMsg(1, 0, 0, user),
+				consultMsg(2, 1, 0, ai),
+				consultMsg(3, 0, 0, user),
+				consultMsg(4, 3, 0, ai),
+			},
+			want: []uint{1, 2, 3, 4},
+		},
+		{
+			name: "legacy_regenerate_uses_newest_reply",
+			consults: []model.ChatMessage{
+				consultMsg(1, 0, 0, user),
+				consultMsg(2, 1, 0, ai),
+				consultMsg(3, 1, 0, ai),
+			},
+			want: []uint{1, 3},
+		},
+		{
+			name: "legacy_orphan_ai",
+			consults: []model.ChatMessage{
+				consultMsg(1, 0, 0, ai),
+				consultMsg(2, 0, 0, user),
+				consultMsg(3, 2, 0, ai),
+			},
+			want: []uint{1, 2, 3},
+		},
+		{
+			name: "edit_creates_new_branch",
+			consults: []model.ChatMessage{
+				consultMsg(1, 0, 0, user),
+				consultMsg(2, 1, 1, ai),
+				consultMsg(3, 0, 2, user),
+				consultMsg(4, 3, 3, ai),
+				consultMsg(5, 1, 0, user), // 编辑第一条咨询
+				consultMsg(6, 5, 5, ai),
+			},
+			want: []uint{5, 6},
+		},
+		{
+			name: "select_original_branch",
+			consults: []model.ChatMessage{
+				consultMsg(1, 0, 0, user),
+				consultMsg(2, 1, 1, ai),
+				consultMsg(3, 0, 2, user),
+				consultMsg(4, 3, 3, ai),
+				consultMsg(5, 1, 0, user),
+				consultMsg(6, 5, 5, ai),
+				consultMsg(7, 0, 6, user), // 新分支上继续对话
+			},
+			selections: []model.ChatBranchSelection{
+				{GroupID: 1, Role: user, ActiveID: 1},
+			},
+			want: []uint{1, 2, 3, 4},
+		},
+		{
+			name: "continue_on_edited_branch",
+			consults: []model.ChatMessage{
+				consultMsg(1, 0, 0, user),
+				consultMsg(2, 1, 1, ai),
+				consultMsg(3, 0, 2, user),
+				consultMsg(4, 1, 0, user),
+				consultMsg(5, 4, 4, ai),
+				consultMsg(6, 0, 5, user),
+			},
+			want: []uint{4, 5, 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newConsultTree(tt.consults, tt.selections)
+			got := fn.Map(tree.activePath(), func(msg model.ChatMessage) uint { return msg.ID })
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestConsultTreeBranchPosition 测试分支位置和数量
+func TestConsultTreeBranchPosition(t *testing.T) {
+	user, ai := model.MessageRoleUser, model.MessageRoleAI
+	tree := newConsultTree([]model.ChatMessage{
+		consultMsg(1, 0, 0, user),
+		consultMsg(2, 1, 1, ai),
+		consultMsg(3, 1, 1, ai),
+		consultMsg(4, 1, 0, user),
+	}, nil)
+
+	index, count := tree.branchPosition(tree.byID[3])
+	assert.Equal(t, 2, index)
+	assert.Equal(t, 2, count)
+
+	index, count = tree.branchPosition(tree.byID[4])
+	assert.Equal(t, 2, index)
+	assert.Equal(t, 2, count)
+}
+
+// TestConsultTreeInactiveIDs 测试不在当前分支上的消息
+func TestConsultTreeInactiveIDs(t *testing.T) {
+	user, ai := model.MessageRoleUser, model.MessageRoleAI
+	tree := newConsultTree([]model.ChatMessage{
+		consultMsg(1, 0, 0, user),
+		consultMsg(2, 1, 0, ai),
+		consultMsg(3, 1, 0, ai),
+	}, nil)
+
+	assert.Equal(t, []uint{2}, tree.inactiveIDs())
+}
+
+// TestRelinkPrevIDs 测试从中间删除消息后，后续对话仍在当前分支上
+func TestRelinkPrevIDs(t *testing.T) {
+	user, ai := model.MessageRoleUser, model.MessageRoleAI
+	consults := []model.ChatMessage{
+		consultMsg(1, 0, 0, user),
+		consultMsg(2, 1, 1, ai),
+		consultMsg(3, 0, 2, user),
+		consultMsg(4, 3, 3, ai),
+		consultMsg(5, 0, 4, user),
+		consultMsg(6, 5, 5, ai),
+	}
+
+	tests := []struct {
+		name       string
+		deletedIDs []uint
+		want       []uint
+	}{
+		{name: "delete_middle_ai", deletedIDs: []uint{2}, want: []uint{1, 3, 4, 5, 6}},
+		{name: "delete_middle_turn", deletedIDs: []uint{3, 4}, want: []uint{1, 2, 5, 6}},
+		{name: "delete_first_turn", deletedIDs: []uint{1, 2}, want: []uint{3, 4, 5, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := make(map[uint]bool)
+			for _, id := range tt.deletedIDs {
+				deleted[id] = true
+			}
+			deletedMsgs := fn.Filter(consults, func(msg model.ChatMessage) bool { return deleted[msg.ID] })
+			relinked := relinkPrevIDs(deletedMsgs)
+
+			var remaining []model.ChatMessage
+			for _, msg := range consults {
+				if deleted[msg.ID] {
+					continue
+				}
+				if prevID, ok := relinked[msg.PrevID]; ok {
+					msg.PrevID = prevID
+				}
+				remaining = append(remaining, msg)
+			}
+
+			path := newConsultTree(remaining, nil).activePath()
+			assert.Equal(t, tt.want, fn.Map(path, func(msg model.ChatMessage) uint { return msg.ID }))
+		})
+	}
+}
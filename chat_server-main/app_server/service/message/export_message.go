@@ -0,0 +1,211 @@
+package message
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/idgen"
+	"app_server/pkg/ossc"
+	"app_server/proto/message"
+	"app_server/service/auth"
+	"app_server/service/file"
+
+	connect "connectrpc.com/connect"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	exportBatchSize  = 500            // 每批查询的消息数量
+	exportLinkExpire = 24 * time.Hour // 导出文件下载链接的有效期
+)
+
+// ExportChatSession 导出会话为 Markdown、HTML 或 JSON 文件，上传到 OSS 后返回下载链接
+func (s *ChatMessageService) ExportChatSession(ctx context.Context, connectReq *connect.Request[message.ExportChatSessionRequest]) (*connect.Response[message.ExportChatSessionResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	if sessionID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id is required"))
+	}
+	format := strings.ToUpper(req.Format)
+	if format == "" {
+		format = ExportFormatMarkdown
+	}
+	renderer, err := newSessionRenderer(format)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	database := db.GetDB().WithContext(ctx)
+
+	header, err := loadExportHeader(database, userID, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("会话未找到"))
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 先写到临时文件，再上传到 OSS
+	tmpFile, err := os.CreateTemp("", "chat-export-*"+renderer.Ext())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	count, err := writeSessionExport(database, bufio.NewWriter(tmpFile), renderer, header)
+	if err != nil {
+		slog.Error("write session export error", "error", err, "sessionID", sessionID)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	stat, err := tmpFile.Stat()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	filename := fmt.Sprintf("%s-%s%s", header.Session.Name, header.ExportedAt.Format("20060102150405"), renderer.Ext())
+	objectKey := fmt.Sprintf("user/%d/%s/%s/%s%s", userID, model.UsageTypeChatExport,
+		header.ExportedAt.Format("2006/01/02"), idgen.Base36(), renderer.Ext())
+	if err := ossc.Get().UserFileBucket().PutObjectFromFile(objectKey, tmpFile.Name(),
+		oss.ContentType(renderer.ContentType()),
+		oss.ContentDisposition(fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(filename))),
+	); err != nil {
+		slog.Error("upload session export error", "error", err, "sessionID", sessionID)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 记录文件，按用途类型设置过期时间
+	if _, err := file.NewService().CreateFileRecord(userID, filename, stat.Size(), renderer.ContentType(),
+		renderer.Ext(), objectKey, "", model.UsageTypeChatExport); err != nil {
+		slog.Error("create export file record error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	signedURL, err := ossc.GetPublic().UserFileBucket().SignURL(objectKey, "GET", int64(exportLinkExpire.Seconds()))
+	if err != nil {
+		slog.Error("sign export url error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.ExportChatSessionResponse{
+		Url:          signedURL,
+		FileUrl:      objectKey,
+		Filename:     filename,
+		MessageCount: int32(count),
+		ExpiresAt:    timestamppb.New(header.ExportedAt.Add(exportLinkExpire)),
+	}), nil
+}
+
+// loadExportHeader 查询会话和双方的 Profile
+func loadExportHeader(tx *gorm.DB, userID, sessionID uint) (exportHeader, error) {
+	header := exportHeader{ExportedAt: time.Now()}
+	if err := tx.Where("id = ? AND user_id = ?", sessionID, userID).First(&header.Session).Error; err != nil {
+		return header, err
+	}
+
+	var user model.User
+	if err := tx.Where("id = ?", userID).First(&user).Error; err == nil && user.ProfileID > 0 {
+		var userProfile model.Profile
+		if err := tx.Where("user_id = ? AND id = ?", userID, user.ProfileID).First(&userProfile).Error; err == nil {
+			header.UserProfile = &userProfile
+		}
+	}
+	if header.Session.ProfileID > 0 {
+		var friendProfile model.Profile
+		if err := tx.Where("id = ?", header.Session.ProfileID).First(&friendProfile).Error; err == nil {
+			header.FriendProfile = &friendProfile
+		}
+	}
+	return header, nil
+}
+
+// writeSessionExport 分批查询会话消息并逐条写出，翻译放在原消息旁边，咨询只导出当前分支
+// 返回写出的消息数量
+func writeSessionExport(tx *gorm.DB, w *bufio.Writer, renderer sessionRenderer, header exportHeader) (int, error) {
+	userID, sessionID := header.Session.UserID, header.Session.ID
+
+	tree, err := loadConsultTree(tx, userID, sessionID)
+	if err != nil {
+		return 0, err
+	}
+	activeConsults := make(map[uint]bool)
+	for _, msg := range tree.activePath() {
+		activeConsults[msg.ID] = true
+	}
+
+	if err := renderer.WriteHeader(w, header); err != nil {
+		return 0, err
+	}
+
+	var count int
+	var lastID uint
+	for {
+		var batch []model.ChatMessage
+		if err := tx.Where("user_id = ? AND session_id = ? AND msg_type IN ? AND id > ?", userID, sessionID,
+			[]string{model.MessageTypeHistory, model.MessageTypeConsult}, lastID).
+			Order("id ASC").
+			Limit(exportBatchSize).
+			Find(&batch).Error; err != nil {
+			return count, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		lastID = batch[len(batch)-1].ID
+		lastBatch := len(batch) < exportBatchSize
+
+		batch = fn.Filter(batch, func(msg model.ChatMessage) bool {
+			return msg.MsgType != model.MessageTypeConsult || activeConsults[msg.ID]
+		})
+
+		// 查询这一批消息的最新翻译
+		var translations []model.ChatMessage
+		if len(batch) > 0 {
+			if err := tx.Select("id", "parent_id", "content").
+				Where("user_id = ? AND session_id = ? AND msg_type = ? AND parent_id IN ?", userID, sessionID,
+					model.MessageTypeTranslate, fn.Map(batch, func(msg model.ChatMessage) uint { return msg.ID })).
+				Order("id ASC").
+				Find(&translations).Error; err != nil {
+				return count, err
+			}
+		}
+		translationMap := make(map[uint]string)
+		for _, translation := range translations {
+			translationMap[translation.ParentID] = translation.Content
+		}
+
+		for _, msg := range batch {
+			if err := renderer.WriteMessage(w, exportedMessage{
+				Msg:         msg,
+				Sender:      exportSenderName(msg, header.UserProfile, header.FriendProfile),
+				Translation: translationMap[msg.ID],
+			}); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if lastBatch {
+			break
+		}
+	}
+
+	if err := renderer.WriteFooter(w); err != nil {
+		return count, err
+	}
+	return count, w.Flush()
+}
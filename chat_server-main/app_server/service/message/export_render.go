@@ -0,0 +1,263 @@
+package message
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"app_server/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// 支持导出的格式
+const (
+	ExportFormatMarkdown = "MARKDOWN"
+	ExportFormatHTML     = "HTML"
+	ExportFormatJSON     = "JSON"
+)
+
+// exportHeader 导出文件的头部信息
+type exportHeader struct {
+	Session       model.ChatSession
+	UserProfile   *model.Profile
+	FriendProfile *model.Profile
+	ExportedAt    time.Time
+}
+
+// exportedMessage 导出的单条消息，翻译放在原消息旁边
+type exportedMessage struct {
+	Msg         model.ChatMessage
+	Sender      string
+	Translation string
+}
+
+// sessionRenderer 按格式逐条写出会话内容，不需要一次性持有全部消息
+type sessionRenderer interface {
+	Ext() string
+	ContentType() string
+	WriteHeader(w io.Writer, header exportHeader) error
+	WriteMessage(w io.Writer, msg exportedMessage) error
+	WriteFooter(w io.Writer) error
+}
+
+func newSessionRenderer(format string) (sessionRenderer, error) {
+	switch format {
+	case ExportFormatMarkdown:
+		return &markdownRenderer{}, nil
+	case ExportFormatHTML:
+		return &htmlRenderer{}, nil
+	case ExportFormatJSON:
+		return &jsonRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// exportSenderName 导出时显示的发送人名称
+func exportSenderName(msg model.ChatMessage, userProfile, friendProfile *model.Profile) string {
+	switch msg.Role {
+	case model.MessageRoleSelf:
+		if userProfile != nil && userProfile.Name != "" {
+			return userProfile.Name
+		}
+		return "我"
+	case model.MessageRoleFriend:
+		if friendProfile != nil && friendProfile.Name != "" {
+			return friendProfile.Name
+		}
+		return "朋友"
+	case model.MessageRoleUser:
+		return "我（咨询）"
+	default:
+		return msg.RoleCnString()
+	}
+}
+
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// markdownRenderer 导出 Markdown
+type markdownRenderer struct{}
+
+func (r *markdownRenderer) Ext() string         { return ".md" }
+func (r *markdownRenderer) ContentType() string { return "text/markdown; charset=utf-8" }
+
+func (r *markdownRenderer) WriteHeader(w io.Writer, header exportHeader) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", header.Session.Name)
+	if p := header.FriendProfile; p != nil && p.ID > 0 {
+		fmt.Fprintf(&sb, "## %s\n\n", p.Name)
+		for _, line := range p.FormatPropertyLines() {
+			fmt.Fprintf(&sb, "- %s\n", line)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "> 导出时间：%s\n\n---\n\n", header.ExportedAt.Format(exportTimeLayout))
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *markdownRenderer) WriteMessage(w io.Writer, msg exportedMessage) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s** · %s", msg.Sender, msg.Msg.MsgAt.Format(exportTimeLayout))
+	if msg.Msg.MsgType == model.MessageTypeConsult {
+		sb.WriteString(" · 咨询")
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(markdownQuote(msg.Msg.Content))
+	if msg.Translation != "" {
+		sb.WriteString(">\n> **AI解读：**\n")
+		sb.WriteString(markdownQuote(msg.Translation))
+	}
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *markdownRenderer) WriteFooter(w io.Writer) error {
+	return nil
+}
+
+// markdownQuote 将多行文本转为 Markdown 引用块
+func markdownQuote(s string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		sb.WriteString("> ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// htmlRenderer 导出不依赖外部资源的单文件 HTML
+type htmlRenderer struct{}
+
+func (r *htmlRenderer) Ext() string         { return ".html" }
+func (r *htmlRenderer) ContentType() string { return "text/html; charset=utf-8" }
+
+const htmlExportStyle = `body{max-width:720px;margin:0 auto;padding:16px;font-family:-apple-system,"PingFang SC","Microsoft YaHei",sans-serif;background:#f5f5f5;color:#222}
+.profile{background:#fff;border-radius:8px;padding:12px 16px;margin-bottom:16px}
+.profile li{list-style:none;color:#666}
+.msg{margin:12px 0;display:flex;flex-direction:column}
+.msg .meta{font-size:12px;color:#999;margin-bottom:4px}
+.msg .bubble{background:#fff;border-radius:8px;padding:8px 12px;white-space:pre-wrap;word-break:break-word;max-width:80%}
+.msg.SELF,.msg.USER{align-items:flex-end}
+.msg.SELF .bubble{background:#95ec69}
+.msg.CONSULT .bubble{background:#e8f0fe}
+.msg .translation{font-size:13px;color:#555;border-top:1px dashed #ccc;margin-top:6px;padding-top:6px}
+.footer{text-align:center;color:#999;font-size:12px;margin-top:24px}`
+
+func (r *htmlRenderer) WriteHeader(w io.Writer, header exportHeader) error {
+	var sb strings.Builder
+	title := html.EscapeString(header.Session.Name)
+	fmt.Fprintf(&sb, "<!DOCTYPE html>\n<html lang=\"zh-CN\">\n<head>\n<meta charset=\"utf-8\">\n<meta name=\"viewport\" content=\"width=device-width,initial-scale=1\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n<h1>%s</h1>\n", title, htmlExportStyle, title)
+	if p := header.FriendProfile; p != nil && p.ID > 0 {
+		fmt.Fprintf(&sb, "<div class=\"profile\">\n<h2>%s</h2>\n<ul>\n", html.EscapeString(p.Name))
+		for _, line := range p.FormatPropertyLines() {
+			fmt.Fprintf(&sb, "<li>%s</li>\n", html.EscapeString(line))
+		}
+		sb.WriteString("</ul>\n</div>\n")
+	}
+	fmt.Fprintf(&sb, "<!-- exported at %s -->\n", header.ExportedAt.Format(time.RFC3339))
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *htmlRenderer) WriteMessage(w io.Writer, msg exportedMessage) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<div class=\"msg %s %s\">\n<div class=\"meta\">%s · %s</div>\n<div class=\"bubble\">%s",
+		msg.Msg.Role, msg.Msg.MsgType,
+		html.EscapeString(msg.Sender), msg.Msg.MsgAt.Format(exportTimeLayout),
+		html.EscapeString(msg.Msg.Content))
+	if msg.Translation != "" {
+		fmt.Fprintf(&sb, "<div class=\"translation\">AI解读：%s</div>", html.EscapeString(msg.Translation))
+	}
+	sb.WriteString("</div>\n</div>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *htmlRenderer) WriteFooter(w io.Writer) error {
+	_, err := io.WriteString(w, "<div class=\"footer\">— END —</div>\n</body>\n</html>\n")
+	return err
+}
+
+// jsonRenderer 导出 JSON，messages 与 ImportChatHistory 的 JSON 格式兼容，可以重新导入
+type jsonRenderer struct {
+	count int
+}
+
+// exportJSONProfile 导出的 Profile 信息
+type exportJSONProfile struct {
+	Name   string           `json:"name"`
+	Gender string           `json:"gender,omitempty"`
+	Age    int              `json:"age,omitempty"`
+	Intro  string           `json:"intro,omitempty"`
+	Custom []model.Property `json:"custom,omitempty"`
+}
+
+// exportJSONMessage 导出的单条消息，在导入格式的基础上增加了消息类型和翻译
+type exportJSONMessage struct {
+	Sender      string   `json:"sender"`
+	Role        string   `json:"role"`
+	Content     string   `json:"content"`
+	Time        string   `json:"time"`
+	MsgType     string   `json:"msg_type"`
+	Tags        []string `json:"tags,omitempty"`
+	UserTags    []string `json:"user_tags,omitempty"`
+	Translation string   `json:"translation,omitempty"`
+}
+
+func (r *jsonRenderer) Ext() string         { return ".json" }
+func (r *jsonRenderer) ContentType() string { return "application/json; charset=utf-8" }
+
+func (r *jsonRenderer) WriteHeader(w io.Writer, header exportHeader) error {
+	session, err := jsoniter.MarshalToString(header.Session.Name)
+	if err != nil {
+		return err
+	}
+	profile := "null"
+	if p := header.FriendProfile; p != nil && p.ID > 0 {
+		if profile, err = jsoniter.MarshalToString(exportJSONProfile{
+			Name:   p.Name,
+			Gender: p.Gender,
+			Age:    p.Age,
+			Intro:  p.Intro,
+			Custom: p.Custom,
+		}); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "{\n\"version\": 1,\n\"session\": %s,\n\"profile\": %s,\n\"exported_at\": %q,\n\"messages\": [", session, profile, header.ExportedAt.Format(time.RFC3339))
+	return err
+}
+
+func (r *jsonRenderer) WriteMessage(w io.Writer, msg exportedMessage) error {
+	data, err := jsoniter.MarshalToString(exportJSONMessage{
+		Sender:      msg.Sender,
+		Role:        msg.Msg.Role,
+		Content:     msg.Msg.Content,
+		Time:        msg.Msg.MsgAt.Format(time.RFC3339),
+		MsgType:     msg.Msg.MsgType,
+		Tags:        msg.Msg.Tags,
+		UserTags:    msg.Msg.UserTags,
+		Translation: msg.Translation,
+	})
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if r.count == 0 {
+		sep = "\n"
+	}
+	r.count++
+	_, err = io.WriteString(w, sep+data)
+	return err
+}
+
+func (r *jsonRenderer) WriteFooter(w io.Writer) error {
+	_, err := io.WriteString(w, "\n]\n}\n")
+	return err
+}
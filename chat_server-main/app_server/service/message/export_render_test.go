@@ -0,0 +1,80 @@
+package message
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func exportTestMessages() []exportedMessage {
+	msgAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)
+	return []exportedMessage{
+		{
+			Msg:         model.ChatMessage{Role: model.MessageRoleFriend, MsgType: model.MessageTypeHistory, Content: "在吗", MsgAt: msgAt},
+			Sender:      "小美",
+			Translation: "想和你聊聊",
+		},
+		{
+			Msg:    model.ChatMessage{Role: model.MessageRoleUser, MsgType: model.MessageTypeConsult, Content: "她是什么意思？", MsgAt: msgAt},
+			Sender: "我（咨询）",
+		},
+	}
+}
+
+func renderExport(t *testing.T, renderer sessionRenderer) string {
+	var buf bytes.Buffer
+	header := exportHeader{
+		Session:       model.ChatSession{Name: "小美"},
+		FriendProfile: &model.Profile{Model: gorm.Model{ID: 1}, Name: "小美", Age: 20},
+		ExportedAt:    time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local),
+	}
+	assert.NoError(t, renderer.WriteHeader(&buf, header))
+	for _, msg := range exportTestMessages() {
+		assert.NoError(t, renderer.WriteMessage(&buf, msg))
+	}
+	assert.NoError(t, renderer.WriteFooter(&buf))
+	return buf.String()
+}
+
+// TestJSONExportReimport 测试导出的 JSON 可以重新导入，且只导入 HISTORY 消息
+func TestJSONExportReimport(t *testing.T) {
+	content := renderExport(t, &jsonRenderer{})
+
+	assert.Equal(t, ImportFormatJSON, detectImportFormat("", content))
+	lines, skipped, err := parseJSONExport(content)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, "小美", lines[0].Sender)
+		assert.Equal(t, model.MessageRoleFriend, lines[0].Role)
+		assert.Equal(t, "在吗", lines[0].Content)
+		assert.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local).Unix(), lines[0].MsgAt.Unix())
+	}
+}
+
+// TestMarkdownExport 测试 Markdown 导出中翻译紧跟原消息
+func TestMarkdownExport(t *testing.T) {
+	content := renderExport(t, &markdownRenderer{})
+
+	assert.Contains(t, content, "# 小美\n")
+	assert.Contains(t, content, "- 年龄:20岁\n")
+	assert.Contains(t, content, "**小美** · 2024-01-02 15:04:05\n\n> 在吗\n>\n> **AI解读：**\n> 想和你聊聊\n")
+	assert.Contains(t, content, "**我（咨询）** · 2024-01-02 15:04:05 · 咨询\n")
+}
+
+// TestHTMLExportEscape 测试 HTML 导出会转义消息内容
+func TestHTMLExportEscape(t *testing.T) {
+	var buf bytes.Buffer
+	err := (&htmlRenderer{}).WriteMessage(&buf, exportedMessage{
+		Msg:    model.ChatMessage{Role: model.MessageRoleSelf, MsgType: model.MessageTypeHistory, Content: "<script>alert(1)</script>"},
+		Sender: "我",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "&lt;script&gt;alert(1)&lt;/script&gt;")
+	assert.NotContains(t, buf.String(), "<script>")
+}
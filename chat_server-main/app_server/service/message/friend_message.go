@@ -2,15 +2,16 @@ package message
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strconv"
 	"time"
 
+	"app_server/domain/msgevent"
+	"app_server/domain/sentiment"
 	"app_server/model"
-	"app_server/pkg/aiapi"
 	"app_server/pkg/db"
 	"app_server/pkg/fn"
-	"app_server/pkg/ossc"
 	"app_server/proto/message"
 	"app_server/service/auth"
 
@@ -187,6 +188,10 @@ func (s *FriendMessageService) UpdateFriendMessage(ctx context.Context, connectR
 			tx.Rollback()
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
+		if err := sentiment.InvalidateByMessages(tx, dbMessage.ID); err != nil {
+			tx.Rollback()
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
 
 		updatedMessages = append(updatedMessages, dbMessage)
 	}
@@ -245,6 +250,9 @@ func (s *FriendMessageService) ParseImageMessages(ctx context.Context, connectRe
 	if sessionID == 0 || req.ImageUrl == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, nil)
 	}
+	if req.Async {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("async is not supported, use ChatMessageService"))
+	}
 
 	// 获取会话信息以确定profileID
 	var chatSession model.ChatSession
@@ -252,18 +260,13 @@ func (s *FriendMessageService) ParseImageMessages(ctx context.Context, connectRe
 		return nil, connect.NewError(connect.CodeNotFound, err)
 	}
 	profileID := chatSession.ProfileID
-
-	// 获取图片公开访问链接
-	imageUrl := req.ImageUrl
-	publicURL, err := ossc.GetPublic().UserFileBucket().SignURL(imageUrl, "GET", 3600)
-	slog.Info("publicURL", "publicURL", publicURL)
+	loc, err := loadTimeZone(req.TimeZone)
 	if err != nil {
-		slog.Error("sign image url error", "error", err)
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
 	// 调用火山API解析图片中的聊天记录
-	chatLines, err := aiapi.ParseImageChat(publicURL)
+	chatLines, err := parseImageLines(req.ImageUrl)
 	if err != nil {
 		slog.Error("parse image chat error", "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
@@ -277,31 +280,15 @@ func (s *FriendMessageService) ParseImageMessages(ctx context.Context, connectRe
 		}
 	}()
 
-	// 解析聊天记录并创建消息
-	now := time.Now()
-
-	var parsedMessages []model.ChatMessage
-
-	for _, line := range chatLines {
-		role, content, ok := aiapi.ParseChatLine(line)
-		if !ok {
-			continue
-		}
-
-		// 创建消息对象
-		msg := model.ChatMessage{
-			UserID:    userID,
-			SessionID: sessionID,
-			ProfileID: profileID,
-			Role:      role,
-			MsgAt:     now,
-			MsgType:   "HISTORY",
-			Content:   content,
-			Tags:      []string{"friend_message"},
-		}
-
-		parsedMessages = append(parsedMessages, msg)
-	}
+	// 解析聊天记录并创建消息，相对时间以截图的上传时间为参照
+	ref := imageUploadTime(tx, userID, req.ImageUrl, loc)
+	parsedMessages := imageLineMessages(chatLines, ref, model.ChatMessage{
+		UserID:    userID,
+		SessionID: sessionID,
+		ProfileID: profileID,
+		MsgType:   "HISTORY",
+		Tags:      []string{"friend_message"},
+	})
 
 	// 如果没有解析出有效消息
 	if len(parsedMessages) == 0 {
@@ -333,14 +320,28 @@ func (s *FriendMessageService) ParseImageMessages(ctx context.Context, connectRe
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	// 原截图作为附件保存在解析出的消息上
+	if err := attachSourceImage(tx, userID, req.ImageUrl, dbMessages); err != nil {
+		tx.Rollback()
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, dbMessages...)
+
+	protoMessages := fn.Map(dbMessages, model.ChatMessage.ToProto)
+	if err := fillAttachments(db.GetDB(), userID, protoMessages); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
 	return connect.NewResponse(&message.ParseImageMessagesResponse{
 		Success:  true,
 		Message:  "解析成功",
-		Messages: fn.Map(dbMessages, model.ChatMessage.ToProto),
+		Messages: protoMessages,
 	}), nil
 }
@@ -0,0 +1,203 @@
+package message
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 重叠对齐的打分：匹配的行加分，只出现在一边的行扣分
+const (
+	alignMatchScore    = 2
+	alignGapPenalty    = 1
+	alignMinSimilarity = 0.8 // 识别结果有细微差异时，相似度达到该值仍视为同一行
+)
+
+// chatLine 从截图中识别出的一行聊天记录
+type chatLine struct {
+	Role    string
+	Sender  string // 发送人显示名称
+	Time    string // 截图中显示的时间分隔文字
+	Content string
+	Image   int // 来源截图的序号，从 1 开始，0 表示未知；重叠部分取较早的截图
+}
+
+// alignOp 对齐结果中的一步，A、B 为行号，只出现在一边时另一边为 -1
+type alignOp struct {
+	A, B int
+}
+
+// alignOverlap 将 a 的末尾和 b 的开头对齐，用于拼接内容有重叠的相邻截图
+// 返回 a 中重叠部分的起点 start、b 中重叠部分的终点 end，以及覆盖 a[start:] 和 b[:end] 的对齐步骤
+// 没有重叠时 start 为 len(a)，end 为 0
+func alignOverlap(a, b []chatLine) (start int, ops []alignOp, end int) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return n, nil, 0
+	}
+
+	// score[i][j] 为 a[:i] 的某个后缀与 b[:j] 对齐的最高分，a 开头不重叠的部分不扣分
+	score := make([][]int, n+1)
+	for i := range score {
+		score[i] = make([]int, m+1)
+	}
+	for j := 1; j <= m; j++ {
+		score[0][j] = -j * alignGapPenalty
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			best := max(score[i-1][j], score[i][j-1]) - alignGapPenalty
+			if sameLine(a[i-1], b[j-1]) {
+				best = max(best, score[i-1][j-1]+alignMatchScore)
+			}
+			score[i][j] = best
+		}
+	}
+
+	// 重叠部分必须延续到 a 的末尾，b 结尾不重叠的部分不扣分
+	end = 0
+	for j := 1; j <= m; j++ {
+		if score[n][j] > score[n][end] {
+			end = j
+		}
+	}
+	if score[n][end] <= 0 {
+		return n, nil, 0
+	}
+
+	// 回溯对齐步骤，直到 b 的开头
+	i, j := n, end
+	for j > 0 {
+		switch {
+		case i > 0 && sameLine(a[i-1], b[j-1]) && score[i][j] == score[i-1][j-1]+alignMatchScore:
+			i, j = i-1, j-1
+			ops = append(ops, alignOp{A: i, B: j})
+		case i > 0 && score[i][j] == score[i-1][j]-alignGapPenalty:
+			i--
+			ops = append(ops, alignOp{A: i, B: -1})
+		default:
+			j--
+			ops = append(ops, alignOp{A: -1, B: j})
+		}
+	}
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	return i, ops, end
+}
+
+// stitchLines 拼接两段按顺序识别的聊天记录，重叠部分只保留一份
+// 同一行两边识别结果不同时保留较长的内容，另一边漏识别的行会补上
+func stitchLines(a, b []chatLine) []chatLine {
+	start, ops, end := alignOverlap(a, b)
+	merged := make([]chatLine, 0, len(a)+len(b))
+	merged = append(merged, a[:start]...)
+	for _, op := range ops {
+		switch {
+		case op.A >= 0 && op.B >= 0:
+			merged = append(merged, mergeLine(a[op.A], b[op.B]))
+		case op.A >= 0:
+			merged = append(merged, a[op.A])
+		default:
+			merged = append(merged, b[op.B])
+		}
+	}
+	return append(merged, b[end:]...)
+}
+
+// newLine b 中没有出现在 a 末尾的一行，After 为该行在 a 中的位置，即排在 a[After] 之后，排在 a 最前面时为 -1
+type newLine struct {
+	chatLine
+	After int
+}
+
+// newLines 返回 b 中没有出现在 a 末尾的行，用于和数据库中已有的记录去重
+// 落在两个重叠行之间的行保留在 a 中对应的位置，重叠部分之后的行排在 a 的最后
+func newLines(a, b []chatLine) []newLine {
+	start, ops, end := alignOverlap(a, b)
+	var lines []newLine
+	after := start - 1
+	for _, op := range ops {
+		switch {
+		case op.A >= 0:
+			after = op.A
+		default:
+			lines = append(lines, newLine{chatLine: b[op.B], After: after})
+		}
+	}
+	for _, line := range b[end:] {
+		lines = append(lines, newLine{chatLine: line, After: len(a) - 1})
+	}
+	return lines
+}
+
+// matchedLines 返回 b 中与 a 末尾重叠的行，b 的行号 -> a 的行号
+func matchedLines(a, b []chatLine) map[int]int {
+	_, ops, _ := alignOverlap(a, b)
+	matched := make(map[int]int)
+	for _, op := range ops {
+		if op.A >= 0 && op.B >= 0 {
+			matched[op.B] = op.A
+		}
+	}
+	return matched
+}
+
+// mergeLine 合并同一行的两个识别结果，保留较长的内容，发送人和时间只在一边识别出时也保留
+// 时间分隔在截图边缘时可能只有一张截图中识别得到
+func mergeLine(a, b chatLine) chatLine {
+	line := a
+	if len([]rune(b.Content)) > len([]rune(a.Content)) {
+		line.Content = b.Content
+	}
+	if line.Sender == "" {
+		line.Sender = b.Sender
+	}
+	if line.Time == "" {
+		line.Time = b.Time
+	}
+	if line.Image == 0 {
+		line.Image = b.Image
+	}
+	return line
+}
+
+// sameLine 判断两行是否为同一条消息，角色必须相同，内容忽略空白和标点后足够相似
+func sameLine(a, b chatLine) bool {
+	if a.Role != b.Role {
+		return false
+	}
+	return similarity(normalizeLine(a.Content), normalizeLine(b.Content)) >= alignMinSimilarity
+}
+
+func normalizeLine(content string) []rune {
+	return []rune(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, content))
+}
+
+// similarity 基于编辑距离的相似度，1 表示完全相同
+func similarity(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(b)])/float64(max(len(a), len(b)))
+}
@@ -0,0 +1,108 @@
+package message
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func friendLine(content string) chatLine {
+	return chatLine{Role: model.MessageRoleFriend, Content: content}
+}
+
+func selfLine(content string) chatLine {
+	return chatLine{Role: model.MessageRoleSelf, Content: content}
+}
+
+func TestStitchLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []chatLine
+		want []chatLine
+	}{
+		{
+			name: "no_overlap",
+			a:    []chatLine{friendLine("在吗"), selfLine("在的")},
+			b:    []chatLine{friendLine("明天开会"), selfLine("收到")},
+			want: []chatLine{friendLine("在吗"), selfLine("在的"), friendLine("明天开会"), selfLine("收到")},
+		},
+		{
+			name: "overlap_keeps_repeated_lines",
+			a:    []chatLine{friendLine("方案看了吗"), selfLine("好的"), friendLine("下午给我"), selfLine("好的")},
+			b:    []chatLine{friendLine("下午给我"), selfLine("好的"), friendLine("辛苦"), selfLine("好的")},
+			want: []chatLine{friendLine("方案看了吗"), selfLine("好的"), friendLine("下午给我"), selfLine("好的"), friendLine("辛苦"), selfLine("好的")},
+		},
+		{
+			name: "ocr_differences_and_missed_line",
+			a:    []chatLine{friendLine("项目进度怎么样"), selfLine("还差接口联调，预计周三完成"), friendLine("好")},
+			b:    []chatLine{selfLine("还差接口联调,预计周三完成。"), friendLine("有风险提前说"), friendLine("好"), selfLine("明白")},
+			want: []chatLine{friendLine("项目进度怎么样"), selfLine("还差接口联调,预计周三完成。"), friendLine("有风险提前说"), friendLine("好"), selfLine("明白")},
+		},
+		{
+			name: "truncated_first_line",
+			a:    []chatLine{friendLine("周五之前把报告发我，我需要汇总"), selfLine("没问题")},
+			b:    []chatLine{friendLine("周五之前把报告发我，我需要汇"), selfLine("没问题"), friendLine("谢谢")},
+			want: []chatLine{friendLine("周五之前把报告发我，我需要汇总"), selfLine("没问题"), friendLine("谢谢")},
+		},
+		{
+			name: "empty_first",
+			a:    nil,
+			b:    []chatLine{friendLine("你好")},
+			want: []chatLine{friendLine("你好")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stitchLines(tt.a, tt.b))
+		})
+	}
+}
+
+func TestNewLines(t *testing.T) {
+	existing := []chatLine{friendLine("在吗"), selfLine("在"), friendLine("文件收到了吗")}
+
+	assert.Equal(t, []newLine{{chatLine: selfLine("收到了"), After: 2}},
+		newLines(existing, []chatLine{selfLine("在"), friendLine("文件收到了吗"), selfLine("收到了")}))
+	assert.Empty(t, newLines(existing, []chatLine{selfLine("在"), friendLine("文件收到了吗")}))
+	assert.Equal(t, []newLine{{chatLine: friendLine("新话题"), After: 2}}, newLines(existing, []chatLine{friendLine("新话题")}))
+}
+
+func TestNewLinesKeepsGapPosition(t *testing.T) {
+	existing := []chatLine{friendLine("在吗"), selfLine("在"), friendLine("文件发你了"), selfLine("好的")}
+
+	// 已有记录中漏掉的一行排在它前面的重叠行之后，而不是追加到最后
+	lines := newLines(existing, []chatLine{
+		selfLine("在"),
+		selfLine("稍等一下"),
+		friendLine("文件发你了"),
+		selfLine("好的"),
+		friendLine("看完回复我"),
+	})
+	assert.Equal(t, []newLine{
+		{chatLine: selfLine("稍等一下"), After: 1},
+		{chatLine: friendLine("看完回复我"), After: 3},
+	}, lines)
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, similarity([]rune("你好"), []rune("你好")))
+	assert.InDelta(t, 0.5, similarity([]rune("你好"), []rune("你们")), 1e-9)
+	assert.Equal(t, 0.0, similarity([]rune("abc"), []rune("")))
+	assert.True(t, sameLine(selfLine("OK, 好的!"), selfLine("ok好的")))
+	assert.False(t, sameLine(selfLine("好的"), friendLine("好的")))
+}
+
+func TestStitchLinesKeepsSenderAndTime(t *testing.T) {
+	a := []chatLine{friendLine("在吗"), {Role: model.MessageRoleSelf, Content: "明天上午十点开会"}}
+	b := []chatLine{{Role: model.MessageRoleSelf, Sender: "我", Time: "昨天 14:32", Content: "明天上午十点开会。"}, friendLine("好")}
+
+	got := stitchLines(a, b)
+	assert.Equal(t, []chatLine{
+		friendLine("在吗"),
+		{Role: model.MessageRoleSelf, Sender: "我", Time: "昨天 14:32", Content: "明天上午十点开会。"},
+		friendLine("好"),
+	}, got)
+}
@@ -0,0 +1,289 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"app_server/domain/msgevent"
+	"app_server/model"
+	"app_server/pkg/aiapi"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/idgen"
+	"app_server/pkg/ossc"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"github.com/samber/lo/mutable"
+	"gorm.io/gorm"
+)
+
+const (
+	maxBatchImages   = 10
+	batchDedupWindow = 50 // 与数据库中最近的多少条聊天记录去重
+)
+
+// ParseImageMessagesBatch 并发解析多张截图，对齐相邻截图的重叠部分后保存为一份聊天记录
+func (s *ChatMessageService) ParseImageMessagesBatch(ctx context.Context, connectReq *connect.Request[message.ParseImageMessagesBatchRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	imageURLs := lo.Compact(req.ImageUrls)
+	if sessionID == 0 || len(imageURLs) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and image_urls are required"))
+	}
+	if len(imageURLs) > maxBatchImages {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("at most %d images are allowed", maxBatchImages))
+	}
+	loc, err := loadTimeZone(req.TimeZone)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	if err := checkSessionOwner(database, userID, sessionID); err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			return nil, connectErr
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	lines, err := parseImageBatch(imageURLs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if len(lines) == 0 {
+		return connect.NewResponse(&message.ParseImageMessagesResponse{
+			Message: "未能从图片中解析出有效聊天记录",
+		}), nil
+	}
+
+	// 截图开头可能和已保存的聊天记录重叠
+	lastMessages, err := lastHistoryMessages(database, userID, sessionID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	unsaved := newLines(historyLines(lastMessages), lines)
+	if len(unsaved) == 0 {
+		return connect.NewResponse(&message.ParseImageMessagesResponse{
+			Message: "解析消息全部重复，没有新增消息",
+		}), nil
+	}
+
+	// 相对时间以最后一张截图的上传时间为参照
+	ref := imageUploadTime(database, userID, lo.LastOrEmpty(imageURLs), loc)
+	dbMessages := imageLineMessages(fn.Map(unsaved, func(line newLine) chatLine { return line.chatLine }), ref, model.ChatMessage{
+		UserID:    userID,
+		SessionID: sessionID,
+		MsgType:   model.MessageTypeHistory,
+		Tags:      []string{"parsed_from_image"},
+	})
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := placeGapMessages(tx, dbMessages, unsaved, lastMessages); err != nil {
+			return err
+		}
+		if err := tx.Create(&dbMessages).Error; err != nil {
+			return err
+		}
+		// 每条消息的原截图作为附件保存
+		return attachSourceImages(tx, userID, imageURLs, dbMessages, fn.Map(unsaved, func(line newLine) int { return line.Image }))
+	})
+	if err != nil {
+		slog.Error("create parsed image messages error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, dbMessages...)
+
+	protoMessages := fn.Map(dbMessages, model.ChatMessage.ToProto)
+	if err := fillAttachments(database, userID, protoMessages); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.ParseImageMessagesResponse{
+		Success:  true,
+		Message:  "解析成功",
+		Messages: protoMessages,
+	}), nil
+}
+
+// parseImageBatch 识别多张截图并按顺序逐张拼接，每行记录来源截图的序号
+func parseImageBatch(imageURLs []string) ([]chatLine, error) {
+	results, err := parseImagesConcurrently(imageURLs)
+	if err != nil {
+		return nil, err
+	}
+	return stitchImages(results), nil
+}
+
+// stitchImages 按顺序拼接每张截图的识别结果，results[i] 的行来自第 i+1 张截图
+func stitchImages(results [][]chatLine) []chatLine {
+	var lines []chatLine
+	for i, result := range results {
+		for j := range result {
+			result[j].Image = i + 1
+		}
+		lines = stitchLines(lines, result)
+	}
+	return lines
+}
+
+// placeGapMessages 为落在已有记录之间的消息分配前后两条记录之间的 id，使其按 id 排在截图中的位置，发送时间沿用前一条记录
+// msgs 与 lines 一一对应；两条记录之间的 id 不够分配或已被占用时，保持追加到会话最后
+func placeGapMessages(tx *gorm.DB, msgs []model.ChatMessage, lines []newLine, lastMessages []model.ChatMessage) error {
+	gaps := make(map[int][]int) // 排在 lastMessages[after] 之后 -> msgs 的下标
+	for i, line := range lines {
+		if line.After < len(lastMessages)-1 {
+			gaps[line.After] = append(gaps[line.After], i)
+		}
+	}
+
+	for after, indexes := range gaps {
+		next := lastMessages[after+1]
+		var afterID uint
+		msgAt := next.MsgAt
+		if after >= 0 {
+			afterID = lastMessages[after].ID
+			msgAt = lastMessages[after].MsgAt
+		} else {
+			// 排在去重窗口最前面时，位置为窗口之前的最后一条消息
+			var prevIDs []uint
+			if err := tx.Unscoped().Model(&model.ChatMessage{}).
+				Where("session_id = ? AND id < ?", next.SessionID, next.ID).
+				Order("id DESC").
+				Limit(1).
+				Pluck("id", &prevIDs).Error; err != nil {
+				return err
+			}
+			afterID = lo.FirstOrEmpty(prevIDs)
+		}
+
+		ids, err := gapIDs(tx, next.SessionID, afterID, len(indexes))
+		if err != nil {
+			return err
+		}
+		for k, i := range ids {
+			msgs[indexes[k]].ID = i
+			msgs[indexes[k]].MsgAt = msgAt
+		}
+	}
+	return nil
+}
+
+// gapIDs 为排在会话中 afterID 之后的 n 条消息分配 afterID 和下一条消息之间的 id，afterID 为 0 时排在会话最前面
+// afterID 之后没有消息，或两条消息之间的 id 不够分配、已被占用时返回空，保存时追加到会话最后
+func gapIDs(tx *gorm.DB, sessionID, afterID uint, n int) ([]uint, error) {
+	var nextIDs []uint
+	if err := tx.Unscoped().Model(&model.ChatMessage{}).
+		Where("session_id = ? AND id > ?", sessionID, afterID).
+		Order("id ASC").
+		Limit(1).
+		Pluck("id", &nextIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(nextIDs) == 0 {
+		return nil, nil
+	}
+	nextID := nextIDs[0]
+	prevID := afterID
+	if prevID == 0 {
+		// 排在最前面时使用下一条消息之前一秒内的 id，id 对应的时间仍接近相邻的消息
+		prevID = uint(idgen.FromTime(idgen.ToTime(int64(nextID)).Add(-time.Second)))
+	}
+
+	ids := idsBetween(prevID, nextID, n)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var used int64
+	if err := tx.Unscoped().Model(&model.ChatMessage{}).Where("id IN ?", ids).Count(&used).Error; err != nil {
+		return nil, err
+	}
+	if used > 0 {
+		return nil, nil
+	}
+	return ids, nil
+}
+
+// idsBetween 在 prev 和 next 之间均匀取 n 个 id，不够分配时返回空
+func idsBetween(prev, next uint, n int) []uint {
+	if n <= 0 || next <= prev {
+		return nil
+	}
+	step := (next - prev) / uint(n+1)
+	if step == 0 {
+		return nil
+	}
+	ids := make([]uint, n)
+	for i := range ids {
+		ids[i] = prev + step*uint(i+1)
+	}
+	return ids
+}
+
+// lastHistoryMessages 查询会话中最近的聊天记录，按 id 升序返回，用于和截图识别结果去重
+func lastHistoryMessages(tx *gorm.DB, userID, sessionID uint) ([]model.ChatMessage, error) {
+	var msgs []model.ChatMessage
+	if err := tx.Where("user_id = ? AND session_id = ? AND msg_type = ?", userID, sessionID, model.MessageTypeHistory).
+		Order("id DESC").
+		Limit(batchDedupWindow).
+		Find(&msgs).Error; err != nil {
+		return nil, err
+	}
+	mutable.Reverse(msgs)
+	return msgs, nil
+}
+
+func historyLines(msgs []model.ChatMessage) []chatLine {
+	return fn.Map(msgs, func(msg model.ChatMessage) chatLine {
+		return chatLine{Role: msg.Role, Content: msg.Content}
+	})
+}
+
+// parseImagesConcurrently 并发识别每张截图中的聊天记录，结果与截图顺序一致，任意一张失败时返回错误
+func parseImagesConcurrently(imageURLs []string) ([][]chatLine, error) {
+	results := make([][]chatLine, len(imageURLs))
+	errs := make([]error, len(imageURLs))
+
+	var wg sync.WaitGroup
+	for i, imageURL := range imageURLs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = parseImageLines(imageURL)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			slog.Error("parse image chat error", "error", err, "image", imageURLs[i])
+			return nil, fmt.Errorf("parse image %d: %w", i+1, err)
+		}
+	}
+	return results, nil
+}
+
+// parseImageLines 识别一张截图中的聊天记录
+func parseImageLines(imageURL string) ([]chatLine, error) {
+	publicURL, err := ossc.GetPublic().UserFileBucket().SignURL(imageURL, "GET", 3600)
+	if err != nil {
+		return nil, err
+	}
+	rawLines, err := aiapi.ParseImageChat(publicURL)
+	if err != nil {
+		return nil, err
+	}
+	return fn.Map(rawLines, func(line aiapi.ChatLine) chatLine {
+		return chatLine{Role: line.Role, Sender: line.Sender, Time: line.Time, Content: line.Content}
+	}), nil
+}
@@ -0,0 +1,33 @@
+package message
+
+import (
+	"testing"
+
+	"app_server/pkg/fn"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStitchImages(t *testing.T) {
+	lines := stitchImages([][]chatLine{
+		{friendLine("在吗"), selfLine("在")},
+		{selfLine("在"), friendLine("文件发你了")},
+		{friendLine("看完回复我")},
+	})
+
+	// 重叠的行取较早的截图
+	assert.Equal(t, []string{"在吗", "在", "文件发你了", "看完回复我"}, fn.Map(lines, func(line chatLine) string { return line.Content }))
+	assert.Equal(t, []int{1, 1, 2, 3}, fn.Map(lines, func(line chatLine) int { return line.Image }))
+
+	// 截图序号随未保存的行一起返回
+	unsaved := newLines([]chatLine{friendLine("在吗")}, lines)
+	assert.Equal(t, []int{1, 2, 3}, fn.Map(unsaved, func(line newLine) int { return line.Image }))
+}
+
+func TestIdsBetween(t *testing.T) {
+	assert.Equal(t, []uint{110, 120, 130}, idsBetween(100, 140, 3))
+	assert.Equal(t, []uint{101}, idsBetween(100, 102, 1))
+	assert.Empty(t, idsBetween(100, 101, 1))
+	assert.Empty(t, idsBetween(100, 102, 2))
+	assert.Empty(t, idsBetween(100, 100, 1))
+}
@@ -0,0 +1,308 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"app_server/domain/msgevent"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/idgen"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const imagePreviewTTL = 30 * time.Minute // 预览的有效期，过期未确认的会被删除
+
+// PreviewImageMessages 解析截图但不保存，标记与已有消息重复的行，用户修正后调用 ConfirmImageMessages 保存
+func (s *ChatMessageService) PreviewImageMessages(ctx context.Context, connectReq *connect.Request[message.PreviewImageMessagesRequest]) (*connect.Response[message.PreviewImageMessagesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	imageURLs := lo.Compact(req.ImageUrls)
+	if sessionID == 0 || len(imageURLs) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and image_urls are required"))
+	}
+	if len(imageURLs) > maxBatchImages {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("at most %d images are allowed", maxBatchImages))
+	}
+	loc, err := loadTimeZone(req.TimeZone)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	if err := checkSessionOwner(database, userID, sessionID); err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			return nil, connectErr
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	lines, err := parseImageBatch(imageURLs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if len(lines) == 0 {
+		return connect.NewResponse(&message.PreviewImageMessagesResponse{}), nil
+	}
+
+	lastMessages, err := lastHistoryMessages(database, userID, sessionID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	ref := imageUploadTime(database, userID, lo.LastOrEmpty(imageURLs), loc)
+	previewLines := buildPreviewLines(lines, assignMsgTimes(lines, ref), lastMessages)
+	if err := suggestPositions(database, userID, sessionID, previewLines); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	preview := model.ImageParsePreview{
+		ParseID:   idgen.Base36(),
+		UserID:    userID,
+		SessionID: sessionID,
+		ImageURLs: imageURLs,
+		Lines:     previewLines,
+		ExpiresAt: time.Now().Add(imagePreviewTTL),
+	}
+	if err := database.Create(&preview).Error; err != nil {
+		slog.Error("create image parse preview error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.PreviewImageMessagesResponse{
+		ParseId:   preview.ParseID,
+		Lines:     fn.Map(preview.Lines, model.ImageParseLine.ToProto),
+		ExpiresAt: timestamppb.New(preview.ExpiresAt),
+	}), nil
+}
+
+// ConfirmImageMessages 保存用户确认后的截图解析结果，每行保存到指定的位置，保存后预览失效
+func (s *ChatMessageService) ConfirmImageMessages(ctx context.Context, connectReq *connect.Request[message.ConfirmImageMessagesRequest]) (*connect.Response[message.ParseImageMessagesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	if req.ParseId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("parse_id is required"))
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	var preview model.ImageParsePreview
+	if err := database.Where("parse_id = ? AND user_id = ?", req.ParseId, userID).First(&preview).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("parse preview not found"))
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if time.Now().After(preview.ExpiresAt) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("parse preview expired"))
+	}
+
+	lines := lo.Filter(preview.Lines, func(line model.ImageParseLine, _ int) bool { return line.DuplicateOf == 0 })
+	if len(req.Lines) > 0 {
+		var err error
+		if lines, err = confirmedLines(req.Lines, preview.CreatedAt, len(preview.ImageURLs)); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no lines to save"))
+	}
+
+	dbMessages := fn.Map(lines, func(line model.ImageParseLine) model.ChatMessage {
+		return model.ChatMessage{
+			UserID:     userID,
+			SessionID:  preview.SessionID,
+			Role:       line.Role,
+			SenderName: line.SenderName,
+			MsgAt:      line.MsgAt,
+			MsgType:    model.MessageTypeHistory,
+			Content:    line.Content,
+			Tags:       []string{"parsed_from_image"},
+		}
+	})
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := checkSessionOwner(tx, userID, preview.SessionID); err != nil {
+			return err
+		}
+		// 先删除预览，删除成功的请求才能保存，避免并发重复确认
+		result := tx.Where("id = ?", preview.ID).Delete(&model.ImageParsePreview{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("parse preview not found"))
+		}
+		if err := placeConfirmedMessages(tx, preview.SessionID, dbMessages, lines); err != nil {
+			return err
+		}
+		if err := tx.Create(&dbMessages).Error; err != nil {
+			return err
+		}
+		// 每条消息的原截图作为附件保存
+		return attachSourceImages(tx, userID, preview.ImageURLs, dbMessages, fn.Map(lines, func(line model.ImageParseLine) int { return line.Image }))
+	})
+	if err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			return nil, connectErr
+		}
+		slog.Error("confirm image messages error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, dbMessages...)
+
+	protoMessages := fn.Map(dbMessages, model.ChatMessage.ToProto)
+	if err := fillAttachments(database, userID, protoMessages); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.ParseImageMessagesResponse{
+		Success:  true,
+		Message:  "解析成功",
+		Messages: protoMessages,
+	}), nil
+}
+
+// buildPreviewLines 生成预览结果，与最近已有消息重叠的行标记为重复
+func buildPreviewLines(lines []chatLine, times []time.Time, lastMessages []model.ChatMessage) []model.ImageParseLine {
+	matched := matchedLines(historyLines(lastMessages), lines)
+	return lo.Map(lines, func(line chatLine, i int) model.ImageParseLine {
+		previewLine := model.ImageParseLine{
+			Role:       line.Role,
+			SenderName: line.Sender,
+			Content:    line.Content,
+			MsgAt:      times[i],
+			TimeText:   line.Time,
+			Image:      line.Image,
+		}
+		if j, ok := matched[i]; ok {
+			previewLine.DuplicateOf = lastMessages[j].ID
+		}
+		return previewLine
+	})
+}
+
+// suggestPositions 按发送时间找到每行在会话中的位置，即发送时间不晚于该行的最后一条聊天记录
+func suggestPositions(tx *gorm.DB, userID, sessionID uint, lines []model.ImageParseLine) error {
+	positions := make(map[int64]uint) // 发送时间 -> 位置，同一时间只查询一次
+	for i, line := range lines {
+		key := line.MsgAt.UnixNano()
+		if id, ok := positions[key]; ok {
+			lines[i].AfterMessageID = id
+			continue
+		}
+		var ids []uint
+		if err := tx.Model(&model.ChatMessage{}).
+			Where("user_id = ? AND session_id = ? AND msg_type = ? AND msg_at <= ?", userID, sessionID, model.MessageTypeHistory, line.MsgAt).
+			Order("msg_at DESC, id DESC").
+			Limit(1).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		positions[key] = lo.FirstOrEmpty(ids)
+		lines[i].AfterMessageID = positions[key]
+	}
+	return nil
+}
+
+// placeConfirmedMessages 按每行的位置为消息分配 id，排在同一条消息之后的行按顺序排在一起
+// msgs 与 lines 一一对应，位置之后没有消息时保持追加到会话最后
+func placeConfirmedMessages(tx *gorm.DB, sessionID uint, msgs []model.ChatMessage, lines []model.ImageParseLine) error {
+	afterIDs := lo.Uniq(lo.Compact(fn.Map(lines, func(line model.ImageParseLine) uint { return line.AfterMessageID })))
+	if len(afterIDs) > 0 {
+		var count int64
+		if err := tx.Model(&model.ChatMessage{}).Where("session_id = ? AND id IN ?", sessionID, afterIDs).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(afterIDs) {
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("after_message_id not found in session"))
+		}
+	}
+
+	positions := make(map[uint][]int) // 位置 -> msgs 的下标
+	for i, line := range lines {
+		positions[line.AfterMessageID] = append(positions[line.AfterMessageID], i)
+	}
+	for afterID, indexes := range positions {
+		ids, err := gapIDs(tx, sessionID, afterID, len(indexes))
+		if err != nil {
+			return err
+		}
+		for k, id := range ids {
+			msgs[indexes[k]].ID = id
+		}
+	}
+	return nil
+}
+
+// confirmedLines 校验用户修正后的聊天记录，发送时间为空时沿用上一行的时间，第一行为空时使用 defaultAt
+// 位置为空时沿用上一行的位置，第一行为空时排在会话最前面；来源截图的序号不能超过 imageCount
+func confirmedLines(protoLines []*message.ParsedImageLine, defaultAt time.Time, imageCount int) ([]model.ImageParseLine, error) {
+	lines := make([]model.ImageParseLine, 0, len(protoLines))
+	msgAt := defaultAt
+	var afterID uint
+	for i, protoLine := range protoLines {
+		if protoLine.Role != model.MessageRoleFriend && protoLine.Role != model.MessageRoleSelf {
+			return nil, fmt.Errorf("lines[%d]: invalid role %q", i, protoLine.Role)
+		}
+		content := strings.TrimSpace(protoLine.Content)
+		if content == "" {
+			return nil, fmt.Errorf("lines[%d]: content is required", i)
+		}
+		if protoLine.MsgAt != nil {
+			msgAt = protoLine.MsgAt.AsTime()
+		}
+		if protoLine.Image < 0 || int(protoLine.Image) > imageCount {
+			return nil, fmt.Errorf("lines[%d]: invalid image %d", i, protoLine.Image)
+		}
+		if protoLine.AfterMessageId != "" {
+			if afterID = fn.Atoi[uint](protoLine.AfterMessageId); afterID == 0 {
+				return nil, fmt.Errorf("lines[%d]: invalid after_message_id %q", i, protoLine.AfterMessageId)
+			}
+		}
+		lines = append(lines, model.ImageParseLine{
+			Role:           protoLine.Role,
+			SenderName:     strings.TrimSpace(protoLine.SenderName),
+			Content:        content,
+			MsgAt:          msgAt,
+			AfterMessageID: afterID,
+			Image:          int(protoLine.Image),
+		})
+	}
+	return lines, nil
+}
+
+// StartImagePreviewCleanupJob 在后台定期删除过期未确认的截图解析预览，ctx 取消后退出
+func StartImagePreviewCleanupJob(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(imagePreviewTTL)
+		defer ticker.Stop()
+		for {
+			if err := db.GetDB().WithContext(ctx).Where("expires_at < ?", time.Now()).
+				Delete(&model.ImageParsePreview{}).Error; err != nil {
+				slog.Error("cleanup image parse previews error", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
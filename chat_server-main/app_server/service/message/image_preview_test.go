@@ -0,0 +1,71 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"app_server/model"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func TestBuildPreviewLines(t *testing.T) {
+	lastMessages := []model.ChatMessage{
+		{Model: gorm.Model{ID: 11}, Role: model.MessageRoleFriend, Content: "在吗"},
+		{Model: gorm.Model{ID: 12}, Role: model.MessageRoleSelf, Content: "在"},
+	}
+	lines := []chatLine{
+		selfLine("在"),
+		{Role: model.MessageRoleFriend, Sender: "小王", Time: "14:32", Content: "明天开会", Image: 2},
+	}
+	at := time.Date(2024, 3, 6, 14, 32, 0, 0, time.UTC)
+
+	got := buildPreviewLines(lines, []time.Time{at, at}, lastMessages)
+	assert.Equal(t, []model.ImageParseLine{
+		{Role: model.MessageRoleSelf, Content: "在", MsgAt: at, DuplicateOf: 12},
+		{Role: model.MessageRoleFriend, SenderName: "小王", Content: "明天开会", MsgAt: at, TimeText: "14:32", Image: 2},
+	}, got)
+
+	assert.True(t, got[0].ToProto().Duplicate)
+	assert.Equal(t, "12", got[0].ToProto().DuplicateOf)
+	assert.Empty(t, got[1].ToProto().DuplicateOf)
+}
+
+func TestConfirmedLines(t *testing.T) {
+	defaultAt := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+
+	lines, err := confirmedLines([]*message.ParsedImageLine{
+		{Role: model.MessageRoleFriend, Content: " 早 "},
+		{Role: model.MessageRoleSelf, SenderName: "我", Content: "早", MsgAt: timestamppb.New(at), Image: 1},
+		{Role: model.MessageRoleFriend, Content: "吃了吗", Image: 2},
+	}, defaultAt, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ImageParseLine{
+		{Role: model.MessageRoleFriend, Content: "早", MsgAt: defaultAt},
+		{Role: model.MessageRoleSelf, SenderName: "我", Content: "早", MsgAt: at, Image: 1},
+		{Role: model.MessageRoleFriend, Content: "吃了吗", MsgAt: at, Image: 2},
+	}, lines)
+
+	// 位置为空时沿用上一行的位置
+	lines, err = confirmedLines([]*message.ParsedImageLine{
+		{Role: model.MessageRoleFriend, Content: "在吗"},
+		{Role: model.MessageRoleSelf, Content: "在", AfterMessageId: "100"},
+		{Role: model.MessageRoleFriend, Content: "晚上吃饭吗"},
+	}, defaultAt, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{0, 100, 100}, fn.Map(lines, func(line model.ImageParseLine) uint { return line.AfterMessageID }))
+
+	_, err = confirmedLines([]*message.ParsedImageLine{{Role: model.MessageRoleSelf, Content: "x", AfterMessageId: "abc"}}, defaultAt, 2)
+	assert.Error(t, err)
+	_, err = confirmedLines([]*message.ParsedImageLine{{Role: model.MessageRoleSelf, Content: "x", Image: 3}}, defaultAt, 2)
+	assert.Error(t, err)
+	_, err = confirmedLines([]*message.ParsedImageLine{{Role: model.MessageRoleAI, Content: "x"}}, defaultAt, 2)
+	assert.Error(t, err)
+	_, err = confirmedLines([]*message.ParsedImageLine{{Role: model.MessageRoleSelf, Content: "  "}}, defaultAt, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package message
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"app_server/model"
+	"app_server/pkg/chattime"
+
+	"gorm.io/gorm"
+)
+
+// loadTimeZone 加载请求中的时区，为空时使用服务器时区
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time_zone: %s", name)
+	}
+	return loc, nil
+}
+
+// imageUploadTime 截图的上传时间，作为推断相对时间的参照，找不到上传记录时使用当前时间
+func imageUploadTime(tx *gorm.DB, userID uint, imageKey string, loc *time.Location) time.Time {
+	file, err := sourceImageFile(tx, userID, imageKey)
+	if err != nil {
+		return time.Now().In(loc)
+	}
+	return file.CreatedAt.In(loc)
+}
+
+// assignMsgTimes 推断每行的发送时间
+// 截图中的时间分隔只出现在一组消息之前，没有时间的行沿用上一行的时间，第一个时间之前的行使用第一个时间，
+// 全部无法识别时使用 ref
+func assignMsgTimes(lines []chatLine, ref time.Time) []time.Time {
+	times := make([]time.Time, len(lines))
+	var current time.Time
+	firstResolved := -1
+	for i, line := range lines {
+		if t, ok := chattime.Resolve(line.Time, ref); ok {
+			current = t
+			if firstResolved < 0 {
+				firstResolved = i
+			}
+		}
+		times[i] = current
+	}
+
+	leading := ref
+	if firstResolved >= 0 {
+		leading = times[firstResolved]
+	} else {
+		firstResolved = len(lines)
+	}
+	for i := range firstResolved {
+		times[i] = leading
+	}
+	return times
+}
+
+// imageLineMessages 将识别出的聊天记录转换为消息，template 中设置用户、会话等公共字段
+func imageLineMessages(lines []chatLine, ref time.Time, template model.ChatMessage) []model.ChatMessage {
+	times := assignMsgTimes(lines, ref)
+	msgs := make([]model.ChatMessage, len(lines))
+	for i, line := range lines {
+		msg := template
+		msg.Role = line.Role
+		msg.SenderName = line.Sender
+		msg.Content = line.Content
+		msg.MsgAt = times[i]
+		msg.Tags = slices.Clone(template.Tags)
+		msgs[i] = msg
+	}
+	return msgs
+}
@@ -0,0 +1,60 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignMsgTimes(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	ref := time.Date(2024, 3, 6, 15, 0, 0, 0, loc)
+
+	lines := []chatLine{
+		{Role: model.MessageRoleFriend, Content: "在吗"},
+		{Role: model.MessageRoleSelf, Content: "在的"},
+		{Role: model.MessageRoleFriend, Time: "昨天 14:32", Content: "明天开会"},
+		{Role: model.MessageRoleSelf, Content: "收到"},
+		{Role: model.MessageRoleFriend, Time: "看不清", Content: "辛苦"},
+		{Role: model.MessageRoleFriend, Time: "09:15", Content: "早"},
+	}
+	yesterday := time.Date(2024, 3, 5, 14, 32, 0, 0, loc)
+	today := time.Date(2024, 3, 6, 9, 15, 0, 0, loc)
+	assert.Equal(t, []time.Time{yesterday, yesterday, yesterday, yesterday, yesterday, today}, assignMsgTimes(lines, ref))
+
+	// 全部无法识别时使用参照时间
+	assert.Equal(t, []time.Time{ref, ref}, assignMsgTimes(lines[:2], ref))
+	assert.Empty(t, assignMsgTimes(nil, ref))
+}
+
+func TestImageLineMessages(t *testing.T) {
+	ref := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+	lines := []chatLine{
+		{Role: model.MessageRoleFriend, Sender: "小王", Time: "14:32", Content: "在吗"},
+		{Role: model.MessageRoleSelf, Content: "在的"},
+	}
+	msgs := imageLineMessages(lines, ref, model.ChatMessage{UserID: 1, SessionID: 2, MsgType: model.MessageTypeHistory, Tags: []string{"parsed_from_image"}})
+
+	at := time.Date(2024, 3, 6, 14, 32, 0, 0, time.UTC)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, "小王", msgs[0].SenderName)
+	assert.Equal(t, at, msgs[0].MsgAt)
+	assert.Equal(t, at, msgs[1].MsgAt)
+	assert.Equal(t, uint(2), msgs[1].SessionID)
+	assert.Equal(t, "在的", msgs[1].Content)
+
+	msgs[0].Tags[0] = "changed"
+	assert.Equal(t, "parsed_from_image", msgs[1].Tags[0])
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	loc, err := loadTimeZone("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	_, err = loadTimeZone("Mars/Olympus")
+	assert.Error(t, err)
+}
@@ -0,0 +1,179 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"app_server/domain/msgevent"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/ossc"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportBytes    = 10 << 20 // 单次导入的记录最大 10MB
+	maxImportMessages = 10000    // 单次导入的最大消息数量
+)
+
+// ImportChatHistory 导入微信/QQ 文本记录、CSV、JSON 格式的聊天记录，保存为 HISTORY 消息
+func (s *ChatMessageService) ImportChatHistory(ctx context.Context, connectReq *connect.Request[message.ImportChatHistoryRequest]) (*connect.Response[message.ImportChatHistoryResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	if sessionID == 0 || (req.Content == "" && req.FileUrl == "") {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and content or file_url are required"))
+	}
+
+	var session model.ChatSession
+	if err := db.GetDB().Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("session not found"))
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 读取记录内容
+	content := req.Content
+	if content == "" {
+		// 只允许读取用户自己上传的文件，以上传记录为准
+		var fileCount int64
+		if err := db.GetDB().Model(&model.UserFile{}).
+			Where("oss_key = ? AND user_id = ? AND status = ?", req.FileUrl, userID, model.FileStatusNormal).
+			Count(&fileCount).Error; err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		if fileCount == 0 {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("file_url is not accessible"))
+		}
+		object, err := ossc.Get().UserFileBucket().GetObject(req.FileUrl)
+		if err != nil {
+			slog.Error("get import file error", "error", err, "fileUrl", req.FileUrl)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		content, err = readImportContent(object, maxImportBytes)
+		object.Close()
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	} else if len(content) > maxImportBytes {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("import content is larger than %d bytes", maxImportBytes))
+	}
+
+	// 解析记录
+	format := strings.ToUpper(req.Format)
+	if format == "" {
+		format = detectImportFormat(path.Base(req.FileUrl), content)
+	}
+	lines, skipped, err := parseImportContent(format, content)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if len(lines) > maxImportMessages {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("too many messages, at most %d per import", maxImportMessages))
+	}
+
+	senders, unknownSenders := resolveImportRoles(lines, req.SelfNames, req.FriendNames)
+	resp := &message.ImportChatHistoryResponse{
+		SkippedCount:   int32(skipped),
+		Senders:        senders,
+		UnknownSenders: unknownSenders,
+	}
+	if len(unknownSenders) > 0 && !req.DryRun {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown senders, specify self_names or friend_names: %s", strings.Join(unknownSenders, ", ")))
+	}
+
+	// 没有时间的消息沿用上一条消息的时间，按时间排序后依次生成 id，保证列表中的顺序
+	now := time.Now()
+	msgs := make([]model.ChatMessage, 0, len(lines))
+	for i, line := range lines {
+		msgAt := line.MsgAt
+		if msgAt.IsZero() {
+			msgAt = now
+			if i > 0 {
+				msgAt = msgs[i-1].MsgAt
+			}
+		}
+		msgs = append(msgs, model.ChatMessage{
+			UserID:    userID,
+			SessionID: sessionID,
+			Role:      line.Role,
+			MsgType:   model.MessageTypeHistory,
+			Content:   line.Content,
+			Tags:      []string{model.MessageTagImported},
+			MsgAt:     msgAt,
+		})
+	}
+	slices.SortStableFunc(msgs, func(a, b model.ChatMessage) int { return a.MsgAt.Compare(b.MsgAt) })
+
+	// 跳过会话中已存在的消息
+	newMsgs, err := filterDuplicateImports(db.GetDB(), userID, sessionID, msgs)
+	if err != nil {
+		slog.Error("query existing messages error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp.DuplicateCount = int32(len(msgs) - len(newMsgs))
+	resp.ImportedCount = int32(len(newMsgs))
+
+	if req.DryRun || len(newMsgs) == 0 {
+		resp.Messages = fn.Map(newMsgs, func(msg model.ChatMessage) *message.ChatMessage {
+			protoMsg := msg.ToProto()
+			protoMsg.Id = ""
+			return protoMsg
+		})
+		return connect.NewResponse(resp), nil
+	}
+
+	if err := db.GetDB().CreateInBatches(&newMsgs, 500).Error; err != nil {
+		slog.Error("import chat history error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, newMsgs...)
+
+	slog.Info("chat history imported", "sessionID", sessionID, "format", format, "imported", len(newMsgs), "duplicate", resp.DuplicateCount)
+
+	resp.Messages = fn.Map(newMsgs, model.ChatMessage.ToProto)
+	return connect.NewResponse(resp), nil
+}
+
+// filterDuplicateImports 过滤会话中已存在的消息，角色、内容相同且时间在同一分钟内视为重复
+func filterDuplicateImports(tx *gorm.DB, userID, sessionID uint, msgs []model.ChatMessage) ([]model.ChatMessage, error) {
+	if len(msgs) == 0 {
+		return msgs, nil
+	}
+
+	dedupKey := func(msg model.ChatMessage) string {
+		return fmt.Sprintf("%s|%d|%s", msg.Role, msg.MsgAt.Truncate(time.Minute).Unix(), msg.Content)
+	}
+
+	// msgs 已按时间排序
+	var existing []model.ChatMessage
+	if err := tx.Model(&model.ChatMessage{}).
+		Select("role", "content", "msg_at").
+		Where("user_id = ? AND session_id = ? AND msg_type = ?", userID, sessionID, model.MessageTypeHistory).
+		Where("msg_at BETWEEN ? AND ?", msgs[0].MsgAt.Add(-time.Minute), msgs[len(msgs)-1].MsgAt.Add(time.Minute)).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	existingKeys := make(map[string]bool, len(existing))
+	for _, msg := range existing {
+		existingKeys[dedupKey(msg)] = true
+	}
+	return fn.Filter(msgs, func(msg model.ChatMessage) bool {
+		return !existingKeys[dedupKey(msg)]
+	}), nil
+}
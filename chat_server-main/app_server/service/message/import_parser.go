@@ -0,0 +1,376 @@
+package message
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"app_server/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// 支持导入的聊天记录格式
+const (
+	ImportFormatText = "TEXT" // 微信/QQ 导出的文本记录
+	ImportFormatCSV  = "CSV"
+	ImportFormatJSON = "JSON"
+)
+
+// importedLine 从导出记录中解析出的一条消息
+type importedLine struct {
+	Sender  string
+	Role    string // 记录中明确给出的角色，为空时按发送人判断
+	Content string
+	MsgAt   time.Time
+}
+
+var (
+	// 2024-01-02 15:04:05 张三 / [2024/1/2 15:04] 张三: 内容
+	textHeaderTimeFirst = regexp.MustCompile(`^\[?(\d{4}[-/.年]\d{1,2}[-/.月]\d{1,2}日?\s+\d{1,2}:\d{2}(?::\d{2})?)\]?\s+(.+)$`)
+	// 张三 2024-01-02 15:04:05
+	textHeaderSenderFirst = regexp.MustCompile(`^(.+?)\s+\(?(\d{4}[-/.年]\d{1,2}[-/.月]\d{1,2}日?\s+\d{1,2}:\d{2}(?::\d{2})?)\)?$`)
+	// QQ 导出的发送人后面带有号码或邮箱：张三(12345) / 张三<xx@qq.com>
+	qqSenderSuffix = regexp.MustCompile(`\s*(\(\d+\)|<[^<>]+>)$`)
+	// 单行记录中发送人和内容之间的分隔符
+	senderContentSep = regexp.MustCompile(`^([^:：]{1,32})[:：]\s*(.+)$`)
+)
+
+// detectImportFormat 根据文件名和内容判断记录格式
+func detectImportFormat(filename, content string) string {
+	switch {
+	case strings.HasSuffix(strings.ToLower(filename), ".csv"):
+		return ImportFormatCSV
+	case strings.HasSuffix(strings.ToLower(filename), ".json"):
+		return ImportFormatJSON
+	case strings.HasSuffix(strings.ToLower(filename), ".txt"):
+		return ImportFormatText
+	}
+
+	trimmed := strings.TrimSpace(strings.TrimPrefix(content, "\ufeff"))
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && jsoniter.Valid([]byte(trimmed)) {
+		return ImportFormatJSON
+	}
+	firstLine, _, _ := strings.Cut(trimmed, "\n")
+	if _, ok := csvHeaderIndex(strings.Split(strings.ToLower(firstLine), ",")); ok {
+		return ImportFormatCSV
+	}
+	return ImportFormatText
+}
+
+// parseImportContent 解析导出的聊天记录，返回解析出的消息和无法识别的行数
+func parseImportContent(format, content string) ([]importedLine, int, error) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	switch format {
+	case ImportFormatText:
+		lines, skipped := parseTextExport(content)
+		return lines, skipped, nil
+	case ImportFormatCSV:
+		return parseCSVExport(content)
+	case ImportFormatJSON:
+		return parseJSONExport(content)
+	default:
+		return nil, 0, fmt.Errorf("unsupported import format: %s", format)
+	}
+}
+
+// parseTextExport 解析微信/QQ 风格的文本记录
+// 每条消息以「时间 发送人」或「发送人 时间」开头，下面的行是消息内容，直到下一条消息；
+// 也支持「时间 发送人: 内容」的单行格式
+func parseTextExport(content string) ([]importedLine, int) {
+	var lines []importedLine
+	var skipped int
+	var current *importedLine
+
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(current.Content)
+			if current.Content != "" {
+				lines = append(lines, *current)
+			}
+			current = nil
+		}
+	}
+
+	for _, raw := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+
+		if msgAt, sender, ok := parseTextHeader(line); ok {
+			flush()
+			current = &importedLine{MsgAt: msgAt, Sender: sender}
+			// 单行格式：发送人和内容在同一行
+			if m := senderContentSep.FindStringSubmatch(sender); m != nil {
+				current.Sender = strings.TrimSpace(m[1])
+				current.Content = m[2]
+			}
+			current.Sender = qqSenderSuffix.ReplaceAllString(current.Sender, "")
+			continue
+		}
+
+		if line == "" {
+			if current != nil && current.Content != "" {
+				current.Content += "\n"
+			}
+			continue
+		}
+		if current == nil {
+			// 第一条消息之前的文件头等内容
+			skipped++
+			continue
+		}
+		if current.Content != "" && !strings.HasSuffix(current.Content, "\n") {
+			current.Content += "\n"
+		}
+		current.Content += line
+	}
+	flush()
+
+	return lines, skipped
+}
+
+// parseTextHeader 判断一行是否为消息头，返回时间和发送人
+func parseTextHeader(line string) (time.Time, string, bool) {
+	if m := textHeaderTimeFirst.FindStringSubmatch(line); m != nil {
+		if msgAt, err := parseImportTime(m[1]); err == nil {
+			return msgAt, strings.TrimSpace(m[2]), true
+		}
+	}
+	if m := textHeaderSenderFirst.FindStringSubmatch(line); m != nil {
+		if msgAt, err := parseImportTime(m[2]); err == nil {
+			return msgAt, strings.TrimSpace(m[1]), true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// csvHeaderIndex 识别 CSV 表头中各列的位置，至少需要 content 列和 sender、role 之一
+func csvHeaderIndex(header []string) (map[string]int, bool) {
+	aliases := map[string]string{
+		"time": "time", "msg_at": "time", "timestamp": "time", "时间": "time",
+		"sender": "sender", "name": "sender", "from": "sender", "发送人": "sender", "发送者": "sender",
+		"content": "content", "message": "content", "text": "content", "内容": "content", "消息": "content",
+		"role": "role", "角色": "role",
+	}
+	index := make(map[string]int)
+	for i, column := range header {
+		if key, ok := aliases[strings.ToLower(strings.TrimSpace(column))]; ok {
+			index[key] = i
+		}
+	}
+	_, hasSender := index["sender"]
+	_, hasRole := index["role"]
+	_, hasContent := index["content"]
+	return index, hasContent && (hasSender || hasRole)
+}
+
+// parseCSVExport 解析 CSV 记录，第一行为表头：time,sender,content[,role]
+// 没有可识别的表头时按 time,sender,content 的顺序解析
+func parseCSVExport(content string) ([]importedLine, int, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, 0, nil
+	}
+
+	index, ok := csvHeaderIndex(records[0])
+	if ok {
+		records = records[1:]
+	} else {
+		index = map[string]int{"time": 0, "sender": 1, "content": 2}
+	}
+	column := func(record []string, key string) string {
+		if i, ok := index[key]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var lines []importedLine
+	var skipped int
+	for _, record := range records {
+		line := importedLine{
+			Sender:  column(record, "sender"),
+			Role:    normalizeImportRole(column(record, "role")),
+			Content: column(record, "content"),
+		}
+		if timeStr := column(record, "time"); timeStr != "" {
+			msgAt, err := parseImportTime(timeStr)
+			if err != nil {
+				skipped++
+				continue
+			}
+			line.MsgAt = msgAt
+		}
+		if line.Content == "" || (line.Sender == "" && line.Role == "") {
+			skipped++
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, skipped, nil
+}
+
+// importJSONMessage JSON 格式的单条消息：
+//
+//	{"sender": "张三", "role": "SELF|FRIEND", "content": "内容", "time": "2024-01-02 15:04:05"}
+//
+// role 可选，不填时按发送人判断；time 支持常见日期格式、RFC3339 和秒级时间戳
+// msg_type 可选，ExportChatSession 导出的 CONSULT 消息不会被导入
+type importJSONMessage struct {
+	Sender  string              `json:"sender"`
+	Role    string              `json:"role"`
+	Content string              `json:"content"`
+	Time    jsoniter.RawMessage `json:"time"`
+	MsgType string              `json:"msg_type"`
+}
+
+// parseJSONExport 解析 JSON 记录，支持消息数组或 {"messages": [...]}
+func parseJSONExport(content string) ([]importedLine, int, error) {
+	var messages []importJSONMessage
+	if err := jsoniter.UnmarshalFromString(content, &messages); err != nil {
+		var wrapper struct {
+			Messages []importJSONMessage `json:"messages"`
+		}
+		if err := jsoniter.UnmarshalFromString(content, &wrapper); err != nil {
+			return nil, 0, fmt.Errorf("parse json: %w", err)
+		}
+		messages = wrapper.Messages
+	}
+
+	var lines []importedLine
+	var skipped int
+	for _, msg := range messages {
+		if msg.MsgType != "" && msg.MsgType != model.MessageTypeHistory {
+			skipped++
+			continue
+		}
+		line := importedLine{
+			Sender:  strings.TrimSpace(msg.Sender),
+			Role:    normalizeImportRole(msg.Role),
+			Content: strings.TrimSpace(msg.Content),
+		}
+		if len(msg.Time) > 0 && string(msg.Time) != "null" {
+			msgAt, err := parseImportJSONTime(msg.Time)
+			if err != nil {
+				skipped++
+				continue
+			}
+			line.MsgAt = msgAt
+		}
+		if line.Content == "" || (line.Sender == "" && line.Role == "") {
+			skipped++
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, skipped, nil
+}
+
+// parseImportJSONTime 解析 JSON 中的时间，支持字符串和秒级时间戳
+func parseImportJSONTime(raw jsoniter.RawMessage) (time.Time, error) {
+	var s string
+	if err := jsoniter.Unmarshal(raw, &s); err == nil {
+		return parseImportTime(s)
+	}
+	var ts int64
+	if err := jsoniter.Unmarshal(raw, &ts); err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %s", raw)
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// parseImportTime 解析导出记录中的时间，没有时区的按服务器本地时间处理
+func parseImportTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+
+	normalized := strings.NewReplacer("/", "-", ".", "-", "年", "-", "月", "-", "日", "").Replace(s)
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	for _, layout := range []string{"2006-1-2 15:04:05", "2006-1-2 15:04", "2006-1-2"} {
+		if t, err := time.ParseInLocation(layout, normalized, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s", s)
+}
+
+// normalizeImportRole 统一记录中的角色写法，无法识别时返回空
+func normalizeImportRole(role string) string {
+	switch strings.ToUpper(strings.TrimSpace(role)) {
+	case model.MessageRoleSelf, "ME", "自己", "我":
+		return model.MessageRoleSelf
+	case model.MessageRoleFriend, "OTHER", "朋友", "对方":
+		return model.MessageRoleFriend
+	default:
+		return ""
+	}
+}
+
+// resolveImportRoles 按 selfNames/friendNames 判断每条消息的角色，返回记录中出现的发送人和无法判断的发送人
+// 只给出一方名单时，其余发送人视为另一方
+func resolveImportRoles(lines []importedLine, selfNames, friendNames []string) (senders, unknown []string) {
+	roles := make(map[string]string)
+	for _, name := range selfNames {
+		roles[strings.TrimSpace(name)] = model.MessageRoleSelf
+	}
+	for _, name := range friendNames {
+		roles[strings.TrimSpace(name)] = model.MessageRoleFriend
+	}
+	fallback := ""
+	switch {
+	case len(selfNames) > 0:
+		fallback = model.MessageRoleFriend
+	case len(friendNames) > 0:
+		fallback = model.MessageRoleSelf
+	}
+
+	seen := make(map[string]bool)
+	unknownSeen := make(map[string]bool)
+	for i := range lines {
+		line := &lines[i]
+		if line.Sender != "" && !seen[line.Sender] {
+			seen[line.Sender] = true
+			senders = append(senders, line.Sender)
+		}
+		if line.Role != "" {
+			continue
+		}
+		if role, ok := roles[line.Sender]; ok {
+			line.Role = role
+		} else {
+			line.Role = fallback
+		}
+		if line.Role == "" && !unknownSeen[line.Sender] {
+			unknownSeen[line.Sender] = true
+			unknown = append(unknown, line.Sender)
+		}
+	}
+	return senders, unknown
+}
+
+// readImportContent 读取导入内容并限制大小
+func readImportContent(r io.Reader, limit int64) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > limit {
+		return "", fmt.Errorf("import file is larger than %d bytes", limit)
+	}
+	return string(data), nil
+}
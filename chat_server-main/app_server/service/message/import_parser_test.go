@@ -0,0 +1,94 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseTextExport 测试微信/QQ 文本记录的解析
+func TestParseTextExport(t *testing.T) {
+	content := "消息记录（此消息记录为文本格式，不支持重新导入）\n\n" +
+		"2024-01-02 15:04:05 张三\n你好\n在吗\n\n" +
+		"2024-01-02 15:05:00 李四(12345)\n在的\n" +
+		"[2024/1/2 15:06] 张三: 晚上一起吃饭？\n"
+
+	lines, skipped := parseTextExport(content)
+	assert.Equal(t, 1, skipped)
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "张三", lines[0].Sender)
+		assert.Equal(t, "你好\n在吗", lines[0].Content)
+		assert.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local), lines[0].MsgAt)
+		assert.Equal(t, "李四", lines[1].Sender)
+		assert.Equal(t, "在的", lines[1].Content)
+		assert.Equal(t, "张三", lines[2].Sender)
+		assert.Equal(t, "晚上一起吃饭？", lines[2].Content)
+		assert.Equal(t, time.Date(2024, 1, 2, 15, 6, 0, 0, time.Local), lines[2].MsgAt)
+	}
+}
+
+// TestParseCSVExport 测试 CSV 记录的解析
+func TestParseCSVExport(t *testing.T) {
+	content := "时间,发送人,内容\n2024-01-02 15:04:05,张三,\"你好,在吗\"\nbad time,李四,在的\n2024-01-02 15:05,李四,在的\n"
+
+	lines, skipped, err := parseCSVExport(content)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "你好,在吗", lines[0].Content)
+		assert.Equal(t, "李四", lines[1].Sender)
+	}
+}
+
+// TestParseJSONExport 测试 JSON 记录的解析
+func TestParseJSONExport(t *testing.T) {
+	content := `{"messages": [
+		{"sender": "张三", "role": "self", "content": "你好", "time": "2024-01-02T15:04:05+08:00"},
+		{"sender": "李四", "content": "在的", "time": 1704179100},
+		{"sender": "李四", "content": ""}
+	]}`
+
+	lines, skipped, err := parseJSONExport(content)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, model.MessageRoleSelf, lines[0].Role)
+		assert.Equal(t, "", lines[1].Role)
+		assert.Equal(t, int64(1704179100), lines[1].MsgAt.Unix())
+	}
+}
+
+// TestDetectImportFormat 测试记录格式的自动判断
+func TestDetectImportFormat(t *testing.T) {
+	assert.Equal(t, ImportFormatCSV, detectImportFormat("a.CSV", ""))
+	assert.Equal(t, ImportFormatJSON, detectImportFormat("", `[{"sender":"a","content":"b"}]`))
+	assert.Equal(t, ImportFormatCSV, detectImportFormat("", "time,sender,content\n"))
+	assert.Equal(t, ImportFormatText, detectImportFormat("", "[2024/1/2 15:06] 张三: 你好"))
+	assert.Equal(t, ImportFormatText, detectImportFormat("", "{张三} 你好"))
+}
+
+// TestResolveImportRoles 测试发送人和角色的对应
+func TestResolveImportRoles(t *testing.T) {
+	newLines := func() []importedLine {
+		return []importedLine{
+			{Sender: "张三"},
+			{Sender: "李四"},
+			{Sender: "王五", Role: model.MessageRoleSelf},
+		}
+	}
+
+	lines := newLines()
+	senders, unknown := resolveImportRoles(lines, []string{"张三"}, nil)
+	assert.Equal(t, []string{"张三", "李四", "王五"}, senders)
+	assert.Empty(t, unknown)
+	assert.Equal(t, model.MessageRoleSelf, lines[0].Role)
+	assert.Equal(t, model.MessageRoleFriend, lines[1].Role)
+
+	lines = newLines()
+	_, unknown = resolveImportRoles(lines, nil, nil)
+	assert.Equal(t, []string{"张三", "李四"}, unknown)
+	assert.Equal(t, model.MessageRoleSelf, lines[2].Role)
+}
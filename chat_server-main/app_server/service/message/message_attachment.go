@@ -0,0 +1,120 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"app_server/domain/attachment"
+	"app_server/model"
+	"app_server/pkg/fn"
+	"app_server/pkg/ossc"
+	"app_server/proto/message"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const attachmentURLExpire = time.Hour // 附件访问地址的有效期
+
+// attachmentFileIDs 消息中客户端填写的附件文件 id
+func attachmentFileIDs(protoMsg *message.ChatMessage) ([]uint, error) {
+	fileIDs := lo.Uniq(lo.Compact(fn.Map(protoMsg.Attachments, func(a *message.MessageAttachment) uint {
+		return fn.Atoi[uint](a.FileId)
+	})))
+	if len(fileIDs) > attachment.MaxPerMessage {
+		return nil, fmt.Errorf("at most %d attachments are allowed", attachment.MaxPerMessage)
+	}
+	return fileIDs, nil
+}
+
+// attachmentError 将添加附件的错误转换为 connect 错误
+func attachmentError(err error) error {
+	if errors.Is(err, attachment.ErrFileNotFound) {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return connect.NewError(connect.CodeInternal, err)
+}
+
+// fillAttachments 为消息填充附件，每次都重新签名访问地址
+func fillAttachments(tx *gorm.DB, userID uint, protoMsgs []*message.ChatMessage) error {
+	messageIDs := lo.Compact(fn.Map(protoMsgs, func(msg *message.ChatMessage) uint {
+		return fn.Atoi[uint](msg.Id)
+	}))
+	files, err := attachment.List(tx, userID, messageIDs)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	expiresAt := time.Now().Add(attachmentURLExpire)
+	urls := make(map[uint]string) // 同一文件只签名一次
+	for _, protoMsg := range protoMsgs {
+		for _, file := range files[fn.Atoi[uint](protoMsg.Id)] {
+			url, ok := urls[file.ID]
+			if !ok {
+				if url, err = ossc.GetPublic().UserFileBucket().SignURL(file.OssKey, "GET", int64(attachmentURLExpire.Seconds())); err != nil {
+					return err
+				}
+				urls[file.ID] = url
+			}
+			protoMsg.Attachments = append(protoMsg.Attachments, attachmentToProto(file, url, expiresAt))
+		}
+	}
+	return nil
+}
+
+func attachmentToProto(file model.UserFile, url string, expiresAt time.Time) *message.MessageAttachment {
+	return &message.MessageAttachment{
+		FileId:       fn.Itoa(file.ID),
+		Url:          url,
+		UrlExpiresAt: timestamppb.New(expiresAt),
+		FileType:     file.FileType,
+		OriginalName: file.OriginalName,
+		FileSize:     file.FileSize,
+	}
+}
+
+// sourceImageFile 按 OSS 路径查找截图的上传记录
+func sourceImageFile(tx *gorm.DB, userID uint, imageKey string) (model.UserFile, error) {
+	var file model.UserFile
+	err := tx.Where("user_id = ? AND oss_key = ? AND status = ?", userID, imageKey, model.FileStatusNormal).
+		Order("id DESC").
+		First(&file).Error
+	return file, err
+}
+
+// attachSourceImages 将每条消息的来源截图作为附件添加，images 与 msgs 一一对应
+// images 为截图在 imageKeys 中的序号，从 1 开始，为 0 时不添加
+func attachSourceImages(tx *gorm.DB, userID uint, imageKeys []string, msgs []model.ChatMessage, images []int) error {
+	for image, indexes := range sourceImageGroups(images, len(imageKeys)) {
+		if err := attachSourceImage(tx, userID, imageKeys[image-1], fn.Map(indexes, func(i int) model.ChatMessage { return msgs[i] })); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sourceImageGroups 按来源截图分组，返回截图序号 -> 消息下标，序号超出截图数量的不分组
+func sourceImageGroups(images []int, imageCount int) map[int][]int {
+	groups := make(map[int][]int)
+	for i, image := range images {
+		if image > 0 && image <= imageCount {
+			groups[image] = append(groups[image], i)
+		}
+	}
+	return groups
+}
+
+// attachSourceImage 将原截图作为附件添加到从截图解析出的消息，找不到上传记录时不添加
+func attachSourceImage(tx *gorm.DB, userID uint, imageKey string, msgs []model.ChatMessage) error {
+	file, err := sourceImageFile(tx, userID, imageKey)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return attachment.Attach(tx, userID, fn.Map(msgs, func(msg model.ChatMessage) uint { return msg.ID }), []uint{file.ID})
+}
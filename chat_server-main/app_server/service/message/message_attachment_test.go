@@ -0,0 +1,53 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"app_server/domain/attachment"
+	"app_server/model"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAttachmentFileIDs(t *testing.T) {
+	ids, err := attachmentFileIDs(&message.ChatMessage{Attachments: []*message.MessageAttachment{
+		{FileId: "3"}, {FileId: ""}, {FileId: "5"}, {FileId: "3"},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{3, 5}, ids)
+
+	tooMany := make([]*message.MessageAttachment, attachment.MaxPerMessage+1)
+	for i := range tooMany {
+		tooMany[i] = &message.MessageAttachment{FileId: fn.Itoa(i + 1)}
+	}
+	_, err = attachmentFileIDs(&message.ChatMessage{Attachments: tooMany})
+	assert.Error(t, err)
+}
+
+func TestAttachmentToProto(t *testing.T) {
+	expiresAt := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+	got := attachmentToProto(model.UserFile{
+		Model:        gorm.Model{ID: 7},
+		OriginalName: "screenshot.png",
+		FileSize:     1024,
+		FileType:     "image/png",
+		OssKey:       "user/1/chat_image/a.png",
+	}, "https://example.com/a.png?sig", expiresAt)
+
+	assert.Equal(t, "7", got.FileId)
+	assert.Equal(t, "https://example.com/a.png?sig", got.Url)
+	assert.Equal(t, expiresAt, got.UrlExpiresAt.AsTime())
+	assert.Equal(t, "image/png", got.FileType)
+	assert.Equal(t, "screenshot.png", got.OriginalName)
+	assert.Equal(t, int64(1024), got.FileSize)
+}
+
+func TestSourceImageGroups(t *testing.T) {
+	assert.Equal(t, map[int][]int{1: {0, 1}, 2: {3}}, sourceImageGroups([]int{1, 1, 0, 2, 3}, 2))
+	assert.Empty(t, sourceImageGroups([]int{0, 0}, 2))
+	assert.Empty(t, sourceImageGroups([]int{1}, 0))
+}
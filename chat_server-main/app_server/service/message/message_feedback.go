@@ -0,0 +1,182 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// saveMessageFeedback 保存用户对消息的反馈，attitude 为空时删除反馈
+func saveMessageFeedback(tx *gorm.DB, userID uint, msg model.ChatMessage, attitude string, reasons []string, comment string) error {
+	if attitude == "" {
+		// 反馈有唯一索引，需要物理删除
+		return tx.Unscoped().Where("user_id = ? AND message_id = ?", userID, msg.ID).
+			Delete(&model.MessageFeedback{}).Error
+	}
+
+	feedback := model.MessageFeedback{
+		UserID:        userID,
+		MessageID:     msg.ID,
+		SessionID:     msg.SessionID,
+		Attitude:      attitude,
+		Reasons:       reasons,
+		Comment:       comment,
+		MsgType:       msg.MsgType,
+		PromptKey:     msg.PromptKey,
+		PromptVersion: msg.PromptVersion,
+		AIModel:       msg.AIModel,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"attitude", "reasons", "comment", "updated_at"}),
+	}).Create(&feedback).Error
+}
+
+// 反馈报表支持的分组维度
+var feedbackReportDimensions = map[string][]string{
+	"prompt_key": {"prompt_key", "prompt_version"},
+	"ai_model":   {"ai_model"},
+	"msg_type":   {"msg_type"},
+	"day":        {"DATE_FORMAT(created_at, '%Y-%m-%d') AS day"},
+}
+
+var defaultFeedbackReportGroupBy = []string{"prompt_key", "ai_model", "msg_type", "day"}
+
+// feedbackReportRow 报表分组统计结果，未参与分组的维度为空
+type feedbackReportRow struct {
+	PromptKey     string
+	PromptVersion string
+	AIModel       string
+	MsgType       string
+	Day           string
+	UpCount       int
+	DownCount     int
+	Reasons       []string `gorm:"serializer:json"`
+}
+
+func (r feedbackReportRow) key() string {
+	return strings.Join([]string{r.PromptKey, r.PromptVersion, r.AIModel, r.MsgType, r.Day}, "\x00")
+}
+
+// GetFeedbackReport 按提示词、模型、消息类型和日期统计点赞/点踩比例，仅管理员可用
+func (s *ChatMessageService) GetFeedbackReport(ctx context.Context, connectReq *connect.Request[message.GetFeedbackReportRequest]) (*connect.Response[message.GetFeedbackReportResponse], error) {
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	req := connectReq.Msg
+
+	groupBy := lo.Uniq(req.GroupBy)
+	if len(groupBy) == 0 {
+		groupBy = defaultFeedbackReportGroupBy
+	}
+	var columns, groups []string
+	for _, dimension := range groupBy {
+		dimensionColumns, ok := feedbackReportDimensions[dimension]
+		if !ok {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid group_by: %s", dimension))
+		}
+		columns = append(columns, dimensionColumns...)
+		for _, column := range dimensionColumns {
+			// 分组使用列别名
+			_, alias, ok := strings.Cut(column, " AS ")
+			groups = append(groups, lo.Ternary(ok, alias, column))
+		}
+	}
+
+	query := db.GetDB().WithContext(ctx).Model(&model.MessageFeedback{})
+	if req.StartTime != nil {
+		query = query.Where("created_at >= ?", req.StartTime.AsTime())
+	}
+	if req.EndTime != nil {
+		query = query.Where("created_at < ?", req.EndTime.AsTime())
+	}
+	if req.PromptKey != "" {
+		query = query.Where("prompt_key = ?", req.PromptKey)
+	}
+
+	var rows []feedbackReportRow
+	if err := query.Session(&gorm.Session{}).
+		Select(append(slices.Clone(columns),
+			"SUM(attitude = 'up') AS up_count",
+			"SUM(attitude = 'down') AS down_count")).
+		Group(strings.Join(groups, ", ")).
+		Scan(&rows).Error; err != nil {
+		slog.Error("feedback report error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 点踩原因存储为 JSON 数组，逐条取出后在内存中计数
+	var reasonRows []feedbackReportRow
+	if err := query.Session(&gorm.Session{}).
+		Select(append(slices.Clone(columns), "reasons")).
+		Where("attitude = ?", model.FeedbackAttitudeDown).
+		Scan(&reasonRows).Error; err != nil {
+		slog.Error("feedback report reasons error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.GetFeedbackReportResponse{
+		Rows: buildFeedbackReport(rows, reasonRows),
+	}), nil
+}
+
+// buildFeedbackReport 计算每个分组的比例并汇总点踩原因，按日期倒序、点踩率倒序排列
+func buildFeedbackReport(rows, reasonRows []feedbackReportRow) []*message.FeedbackReportRow {
+	reasonCounts := make(map[string]map[string]int32)
+	for _, row := range reasonRows {
+		counts := reasonCounts[row.key()]
+		if counts == nil {
+			counts = make(map[string]int32)
+			reasonCounts[row.key()] = counts
+		}
+		for _, reason := range row.Reasons {
+			counts[reason]++
+		}
+	}
+
+	report := make([]*message.FeedbackReportRow, 0, len(rows))
+	for _, row := range rows {
+		total := row.UpCount + row.DownCount
+		if total == 0 {
+			continue
+		}
+		report = append(report, &message.FeedbackReportRow{
+			PromptKey:     row.PromptKey,
+			PromptVersion: row.PromptVersion,
+			AiModel:       row.AIModel,
+			MsgType:       row.MsgType,
+			Day:           row.Day,
+			UpCount:       int32(row.UpCount),
+			DownCount:     int32(row.DownCount),
+			UpRate:        float64(row.UpCount) / float64(total),
+			DownRate:      float64(row.DownCount) / float64(total),
+			DownReasons:   reasonCounts[row.key()],
+		})
+	}
+
+	slices.SortStableFunc(report, func(a, b *message.FeedbackReportRow) int {
+		if a.Day != b.Day {
+			return strings.Compare(b.Day, a.Day)
+		}
+		switch {
+		case a.DownRate > b.DownRate:
+			return -1
+		case a.DownRate < b.DownRate:
+			return 1
+		}
+		return strings.Compare(a.PromptKey, b.PromptKey)
+	})
+	return report
+}
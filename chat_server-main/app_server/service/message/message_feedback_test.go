@@ -0,0 +1,38 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBuildFeedbackReport 测试反馈报表的比例计算、原因汇总和排序
+func TestBuildFeedbackReport(t *testing.T) {
+	rows := []feedbackReportRow{
+		{PromptKey: "prompt:consult:default", PromptVersion: "v1", Day: "2024-01-01", UpCount: 3, DownCount: 1},
+		{PromptKey: "prompt:consult:default", PromptVersion: "v2", Day: "2024-01-02", UpCount: 1, DownCount: 3},
+		{PromptKey: "prompt:translate:to_user", Day: "2024-01-02", UpCount: 4},
+		{PromptKey: "empty", Day: "2024-01-03"},
+	}
+	reasonRows := []feedbackReportRow{
+		{PromptKey: "prompt:consult:default", PromptVersion: "v2", Day: "2024-01-02", Reasons: []string{"tone", "too_long"}},
+		{PromptKey: "prompt:consult:default", PromptVersion: "v2", Day: "2024-01-02", Reasons: []string{"tone"}},
+		{PromptKey: "prompt:consult:default", PromptVersion: "v2", Day: "2024-01-02"},
+	}
+
+	report := buildFeedbackReport(rows, reasonRows)
+	if !assert.Len(t, report, 3) {
+		return
+	}
+
+	assert.Equal(t, "v2", report[0].PromptVersion)
+	assert.Equal(t, 0.75, report[0].DownRate)
+	assert.Equal(t, 0.25, report[0].UpRate)
+	assert.Equal(t, map[string]int32{"tone": 2, "too_long": 1}, report[0].DownReasons)
+
+	assert.Equal(t, "prompt:translate:to_user", report[1].PromptKey)
+	assert.Equal(t, 1.0, report[1].UpRate)
+	assert.Nil(t, report[1].DownReasons)
+
+	assert.Equal(t, "2024-01-01", report[2].Day)
+}
@@ -0,0 +1,174 @@
+package message
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"github.com/samber/lo/mutable"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMessagePageSize = 1000
+	defaultAnchorCount     = 20
+	maxAnchorCount         = 200
+)
+
+// 翻页方向
+const (
+	pageOlder = "o" // id 更小的消息
+	pageNewer = "n" // id 更大的消息
+)
+
+var errInvalidPageToken = errors.New("invalid page_token")
+
+// messageCursor 消息列表分页游标，按消息 id 向前或向后翻页，不包含 ID 本身
+type messageCursor struct {
+	Direction string
+	ID        uint
+}
+
+func (c messageCursor) pageToken() string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%s_%d", c.Direction, c.ID))
+}
+
+// decodeMessageCursor 解析 pageToken 生成的分页游标
+func decodeMessageCursor(token string) (messageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return messageCursor{}, errInvalidPageToken
+	}
+	direction, rawID, ok := strings.Cut(string(data), "_")
+	if !ok || (direction != pageOlder && direction != pageNewer) {
+		return messageCursor{}, errInvalidPageToken
+	}
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil || id == 0 {
+		return messageCursor{}, errInvalidPageToken
+	}
+	return messageCursor{Direction: direction, ID: uint(id)}, nil
+}
+
+// anchorCount 定位查询一侧的消息数
+func anchorCount(count int32) int {
+	if count <= 0 {
+		return defaultAnchorCount
+	}
+	return min(int(count), maxAnchorCount)
+}
+
+// messagePage 一页消息，按 id 升序
+type messagePage struct {
+	Messages []model.ChatMessage
+	Older    string // 更早消息的游标
+	Newer    string // 更新消息的游标
+}
+
+// fetchOlder 查询 id 小于 beforeID（为 0 时不限制）的最近 limit 条消息，按 id 升序返回，并返回是否还有更早的消息
+func fetchOlder(query *gorm.DB, beforeID uint, limit int) ([]model.ChatMessage, bool, error) {
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	var msgs []model.ChatMessage
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&msgs).Error; err != nil {
+		return nil, false, err
+	}
+	more := len(msgs) > limit
+	msgs = msgs[:min(len(msgs), limit)]
+	mutable.Reverse(msgs)
+	return msgs, more, nil
+}
+
+// fetchNewer 查询 id 大于等于 fromID 的最早 limit 条消息，按 id 升序返回，并返回是否还有更新的消息
+func fetchNewer(query *gorm.DB, fromID uint, limit int) ([]model.ChatMessage, bool, error) {
+	var msgs []model.ChatMessage
+	if err := query.Where("id >= ?", fromID).Order("id ASC").Limit(limit + 1).Find(&msgs).Error; err != nil {
+		return nil, false, err
+	}
+	more := len(msgs) > limit
+	return msgs[:min(len(msgs), limit)], more, nil
+}
+
+// buildMessagePage 拼接定位消息之前和之后的消息，生成两个方向的游标
+func buildMessagePage(older []model.ChatMessage, moreOlder bool, newer []model.ChatMessage, moreNewer bool) messagePage {
+	page := messagePage{Messages: append(older, newer...)}
+	if len(page.Messages) == 0 {
+		return page
+	}
+	if moreOlder {
+		page.Older = messageCursor{Direction: pageOlder, ID: page.Messages[0].ID}.pageToken()
+	}
+	if moreNewer {
+		page.Newer = messageCursor{Direction: pageNewer, ID: lo.LastOrEmpty(page.Messages).ID}.pageToken()
+	}
+	return page
+}
+
+// resolveAnchor 确定定位查询的中心消息，没有任何消息时返回 0
+// 按时间定位时取该时间之后的第一条消息，都早于该时间时取最后一条消息
+func resolveAnchor(query *gorm.DB, userID, sessionID uint, req *message.ListChatMessagesRequest) (uint, error) {
+	if req.AnchorId != "" {
+		anchorID := fn.Atoi[uint](req.AnchorId)
+		var anchor model.ChatMessage
+		if err := db.GetDB().Where("id = ? AND user_id = ? AND session_id = ?", anchorID, userID, sessionID).
+			First(&anchor).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, connect.NewError(connect.CodeNotFound, fmt.Errorf("anchor message not found"))
+			}
+			return 0, connect.NewError(connect.CodeInternal, err)
+		}
+		// 翻译消息随原消息返回，定位到原消息
+		if anchor.MsgType == model.MessageTypeTranslate && anchor.ParentID > 0 {
+			return anchor.ParentID, nil
+		}
+		return anchor.ID, nil
+	}
+
+	var anchor model.ChatMessage
+	err := query.Session(&gorm.Session{}).Where("msg_at >= ?", req.AnchorTime.AsTime()).
+		Order("msg_at ASC, id ASC").
+		First(&anchor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = query.Session(&gorm.Session{}).Order("id DESC").First(&anchor).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, connect.NewError(connect.CodeInternal, err)
+	}
+	return anchor.ID, nil
+}
+
+// loadLatestTranslations 查询消息的最新翻译，返回 parentID -> 翻译消息
+func loadLatestTranslations(tx *gorm.DB, userID uint, msgs []model.ChatMessage) (map[uint]model.ChatMessage, error) {
+	translationMap := make(map[uint]model.ChatMessage)
+	parentIDs := fn.Map(lo.Filter(msgs, func(msg model.ChatMessage, _ int) bool { return msg.ID > 0 }),
+		func(msg model.ChatMessage) uint { return msg.ID })
+	if len(parentIDs) == 0 {
+		return translationMap, nil
+	}
+
+	var translations []model.ChatMessage
+	if err := tx.Where("user_id = ? AND msg_type = ? AND parent_id IN ?", userID, model.MessageTypeTranslate, parentIDs).
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	for _, msg := range translations {
+		// 保留最新的翻译（ID 最大的）
+		if existing, ok := translationMap[msg.ParentID]; !ok || msg.ID > existing.ID {
+			translationMap[msg.ParentID] = msg
+		}
+	}
+	return translationMap, nil
+}
@@ -0,0 +1,60 @@
+package message
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageCursor(t *testing.T) {
+	for _, cursor := range []messageCursor{
+		{Direction: pageOlder, ID: 123},
+		{Direction: pageNewer, ID: 456},
+	} {
+		token := cursor.pageToken()
+		assert.NotContains(t, token, "123")
+		decoded, err := decodeMessageCursor(token)
+		assert.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	}
+
+	for _, token := range []string{"abc", "789", messageCursor{Direction: "x", ID: 1}.pageToken(), messageCursor{Direction: pageNewer}.pageToken()} {
+		_, err := decodeMessageCursor(token)
+		assert.ErrorIs(t, err, errInvalidPageToken, token)
+	}
+}
+
+func TestBuildMessagePage(t *testing.T) {
+	newMessages := func(ids ...uint) []model.ChatMessage {
+		msgs := make([]model.ChatMessage, len(ids))
+		for i, id := range ids {
+			msgs[i].ID = id
+		}
+		return msgs
+	}
+
+	page := buildMessagePage(newMessages(1, 2), true, newMessages(3, 4), true)
+	assert.Len(t, page.Messages, 4)
+	older, err := decodeMessageCursor(page.Older)
+	assert.NoError(t, err)
+	assert.Equal(t, messageCursor{Direction: pageOlder, ID: 1}, older)
+	newer, err := decodeMessageCursor(page.Newer)
+	assert.NoError(t, err)
+	assert.Equal(t, messageCursor{Direction: pageNewer, ID: 4}, newer)
+
+	page = buildMessagePage(nil, false, newMessages(3, 4), false)
+	assert.Empty(t, page.Older)
+	assert.Empty(t, page.Newer)
+
+	page = buildMessagePage(nil, true, nil, true)
+	assert.Empty(t, page.Messages)
+	assert.Empty(t, page.Older)
+}
+
+func TestAnchorCount(t *testing.T) {
+	assert.Equal(t, defaultAnchorCount, anchorCount(0))
+	assert.Equal(t, 5, anchorCount(5))
+	assert.Equal(t, maxAnchorCount, anchorCount(1000))
+}
@@ -0,0 +1,166 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"app_server/domain/msgevent"
+	"app_server/domain/sentiment"
+	"app_server/domain/summary"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"gorm.io/gorm"
+)
+
+// ListMessageRevisions 查询消息的修改记录
+func (s *ChatMessageService) ListMessageRevisions(ctx context.Context, connectReq *connect.Request[message.ListMessageRevisionsRequest]) (*connect.Response[message.ListMessageRevisionsResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	messageID := fn.Atoi[uint](req.MessageId)
+	if messageID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("message_id is required"))
+	}
+
+	var revisions []model.ChatMessageRevision
+	if err := db.GetDB().Where("message_id = ? AND user_id = ?", messageID, userID).
+		Order("id DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.ListMessageRevisionsResponse{
+		Revisions: fn.Map(revisions, model.ChatMessageRevision.ToProto),
+	}), nil
+}
+
+// RollbackChatMessage 将消息回滚到某个修改记录，回滚本身也会生成一条修改记录
+// 只回滚内容和角色，系统标签由服务端维护，用户标签由用户单独修改，都不随回滚变化
+func (s *ChatMessageService) RollbackChatMessage(ctx context.Context, connectReq *connect.Request[message.RollbackChatMessageRequest]) (*connect.Response[message.RollbackChatMessageResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	messageID := fn.Atoi[uint](req.MessageId)
+	revisionID := fn.Atoi[uint](req.RevisionId)
+	if messageID == 0 || revisionID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("message_id and revision_id are required"))
+	}
+
+	var dbMessage model.ChatMessage
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		var revision model.ChatMessageRevision
+		if err := tx.Where("id = ? AND message_id = ? AND user_id = ?", revisionID, messageID, userID).
+			First(&revision).Error; err != nil {
+			return err
+		}
+
+		var err error
+		dbMessage, err = updateMessageWithRevision(tx, userID, messageID, map[string]any{
+			"content": revision.Content,
+			"role":    revision.Role,
+		})
+		if err != nil {
+			return err
+		}
+
+		return summary.InvalidateByMessages(tx, []model.ChatMessage{dbMessage})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("message or revision not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventUpdated, userID, dbMessage)
+
+	return connect.NewResponse(&message.RollbackChatMessageResponse{
+		Message: dbMessage.ToProto(),
+	}), nil
+}
+
+// updateMessageWithRevision 更新消息，如果有字段发生变化则先保存修改前的内容
+// updates 的 key 为 content、role、msg_type、tags
+func updateMessageWithRevision(tx *gorm.DB, editorID uint, messageID uint, updates map[string]any) (model.ChatMessage, error) {
+	var dbMessage model.ChatMessage
+	if err := tx.Where("id = ? AND user_id = ?", messageID, editorID).First(&dbMessage).Error; err != nil {
+		return dbMessage, err
+	}
+
+	revision := model.NewRevisionFromMessage(dbMessage, editorID)
+	var changed bool
+	for field, value := range updates {
+		switch field {
+		case "content":
+			if value.(string) != dbMessage.Content {
+				changed = true
+				revision.ContentChanged = true
+			}
+		case "role":
+			changed = changed || value.(string) != dbMessage.Role
+		case "msg_type":
+			changed = changed || value.(string) != dbMessage.MsgType
+		case "tags":
+			changed = changed || !slices.Equal(value.([]string), dbMessage.Tags)
+		}
+	}
+	if !changed {
+		return dbMessage, nil
+	}
+
+	if err := tx.Create(&revision).Error; err != nil {
+		return dbMessage, err
+	}
+
+	if err := tx.Model(&dbMessage).Updates(updates).Error; err != nil {
+		return dbMessage, err
+	}
+	if revision.ContentChanged {
+		if err := sentiment.InvalidateByMessages(tx, messageID); err != nil {
+			return dbMessage, err
+		}
+	}
+
+	return dbMessage, tx.First(&dbMessage, messageID).Error
+}
+
+// findStaleTranslations 找出翻译生成之后原消息内容又被修改过的父消息
+// translations 为 parentID -> 最新翻译
+func findStaleTranslations(translations map[uint]model.ChatMessage) (map[uint]bool, error) {
+	stale := make(map[uint]bool)
+	if len(translations) == 0 {
+		return stale, nil
+	}
+
+	parentIDs := make([]uint, 0, len(translations))
+	for parentID := range translations {
+		parentIDs = append(parentIDs, parentID)
+	}
+
+	var rows []struct {
+		MessageID     uint
+		LastChangedID uint
+	}
+	if err := db.GetDB().Model(&model.ChatMessageRevision{}).
+		Select("message_id, MAX(id) AS last_changed_id").
+		Where("message_id IN ? AND content_changed = ?", parentIDs, true).
+		Group("message_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// ID 由雪花算法生成，按时间递增，可以直接比较先后
+	for _, row := range rows {
+		if row.LastChangedID > translations[row.MessageID].ID {
+			stale[row.MessageID] = true
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,232 @@
+package message
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"app_server/domain/msgevent"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxTagLength       = 32  // 单个用户标签的最大字符数
+	maxMessageUserTags = 20  // 单条消息最多的用户标签数
+	maxTagMessages     = 500 // 批量修改标签时最多的消息数
+)
+
+// tagCondition 消息的系统标签或用户标签中包含指定标签，两个参数都传标签名
+const tagCondition = "(IFNULL(JSON_CONTAINS(tags, JSON_QUOTE(?)), 0) OR IFNULL(JSON_CONTAINS(user_tags, JSON_QUOTE(?)), 0))"
+
+// filterByTags 包含 include 中任一标签，且不包含 exclude 中任何标签
+func filterByTags(query *gorm.DB, include, exclude []string) *gorm.DB {
+	if len(include) > 0 {
+		tagQuery := db.GetDB()
+		for _, tag := range include {
+			tagQuery = tagQuery.Or(tagCondition, tag, tag)
+		}
+		query = query.Where(tagQuery)
+	}
+	for _, tag := range exclude {
+		query = query.Not(tagCondition, tag, tag)
+	}
+	return query
+}
+
+// ListTags 列出用户使用过的标签及消息数
+func (s *ChatMessageService) ListTags(ctx context.Context, connectReq *connect.Request[message.ListTagsRequest]) (*connect.Response[message.ListTagsResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	query := db.GetDB().WithContext(ctx).Model(&model.ChatMessage{}).
+		Select("id", "tags", "user_tags").
+		Where("user_id = ?", userID)
+	if req.SessionId != "" {
+		query = query.Where("session_id = ?", fn.Atoi[uint](req.SessionId))
+	}
+
+	counts := make(map[tagKey]int)
+	var batch []model.ChatMessage
+	err := query.FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		countTags(counts, batch)
+		return nil
+	}).Error
+	if err != nil {
+		slog.Error("list tags error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.ListTagsResponse{Tags: tagStats(counts)}), nil
+}
+
+// AddMessageTags 批量为消息添加用户标签
+func (s *ChatMessageService) AddMessageTags(ctx context.Context, connectReq *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	return updateMessageTags(ctx, connectReq.Msg, addTags)
+}
+
+// RemoveMessageTags 批量删除消息的用户标签
+func (s *ChatMessageService) RemoveMessageTags(ctx context.Context, connectReq *connect.Request[message.UpdateMessageTagsRequest]) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	return updateMessageTags(ctx, connectReq.Msg, func(current, tags []string) ([]string, error) {
+		return removeTags(current, tags), nil
+	})
+}
+
+// updateMessageTags 用 apply 计算每条消息新的用户标签并保存，只要有一条消息不存在或校验失败就都不修改
+func updateMessageTags(ctx context.Context, req *message.UpdateMessageTagsRequest, apply func(current, tags []string) ([]string, error)) (*connect.Response[message.UpdateMessageTagsResponse], error) {
+	userID := auth.GetUserID(ctx)
+
+	ids := lo.Uniq(lo.Compact(fn.Map(req.MessageIds, fn.Atoi[uint])))
+	if len(ids) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("message_ids is required"))
+	}
+	if len(ids) > maxTagMessages {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("at most %d messages are allowed", maxTagMessages))
+	}
+	tags, err := normalizeUserTags(req.Tags)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if len(tags) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("tags is required"))
+	}
+
+	var dbMessages, changedMessages []model.ChatMessage
+	err = db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND id IN ?", userID, ids).
+			Order("id").
+			Find(&dbMessages).Error; err != nil {
+			return err
+		}
+		if len(dbMessages) != len(ids) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("message not found"))
+		}
+
+		for i := range dbMessages {
+			userTags, err := apply(dbMessages[i].UserTags, tags)
+			if err != nil {
+				return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("message %d: %w", dbMessages[i].ID, err))
+			}
+			if slices.Equal(userTags, dbMessages[i].UserTags) {
+				continue
+			}
+			dbMessages[i].UserTags = userTags
+			if err := tx.Model(&dbMessages[i]).Select("user_tags").
+				Updates(&model.ChatMessage{UserTags: userTags}).Error; err != nil {
+				return err
+			}
+			changedMessages = append(changedMessages, dbMessages[i])
+		}
+		return nil
+	})
+	if err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			return nil, connectErr
+		}
+		slog.Error("update message tags error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	if len(changedMessages) > 0 {
+		msgevent.Publish(ctx, msgevent.EventUpdated, userID, changedMessages...)
+	}
+
+	return connect.NewResponse(&message.UpdateMessageTagsResponse{
+		Messages: fn.Map(dbMessages, model.ChatMessage.ToProto),
+	}), nil
+}
+
+// normalizeUserTags 去掉首尾空白和重复的标签，标签过长或使用了系统标签的名称时返回错误
+func normalizeUserTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		if model.IsSystemTag(tag) {
+			return nil, fmt.Errorf("tag %q is reserved", tag)
+		}
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// addTags 将 tags 中还没有的标签追加到 current 之后
+func addTags(current, tags []string) ([]string, error) {
+	result := slices.Clone(current)
+	for _, tag := range tags {
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	if len(result) > maxMessageUserTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxMessageUserTags)
+	}
+	return result, nil
+}
+
+// removeTags 从 current 中去掉 tags 中的标签
+func removeTags(current, tags []string) []string {
+	return lo.Filter(current, func(tag string, _ int) bool {
+		return !slices.Contains(tags, tag)
+	})
+}
+
+// tagKey 标签统计的键，系统标签和同名的用户标签分开统计
+type tagKey struct {
+	Tag    string
+	System bool
+}
+
+// countTags 统计每个标签的消息数，tags 字段中的为系统标签，user_tags 字段中的为用户标签
+func countTags(counts map[tagKey]int, messages []model.ChatMessage) {
+	for _, msg := range messages {
+		for _, tag := range lo.Uniq(msg.Tags) {
+			counts[tagKey{Tag: tag, System: true}]++
+		}
+		for _, tag := range lo.Uniq(msg.UserTags) {
+			counts[tagKey{Tag: tag}]++
+		}
+	}
+}
+
+// tagStats 按消息数从多到少排列标签，消息数相同时按名称排列，同名时系统标签在前
+func tagStats(counts map[tagKey]int) []*message.MessageTag {
+	stats := make([]*message.MessageTag, 0, len(counts))
+	for key, count := range counts {
+		stats = append(stats, &message.MessageTag{
+			Tag:          key.Tag,
+			System:       key.System,
+			MessageCount: int32(count),
+		})
+	}
+	slices.SortFunc(stats, func(a, b *message.MessageTag) int {
+		return cmp.Or(cmp.Compare(b.MessageCount, a.MessageCount), cmp.Compare(a.Tag, b.Tag), compareBool(b.System, a.System))
+	})
+	return stats
+}
+
+func compareBool(a, b bool) int {
+	return cmp.Compare(lo.Ternary(a, 1, 0), lo.Ternary(b, 1, 0))
+}
@@ -0,0 +1,77 @@
+package message
+
+import (
+	"strings"
+	"testing"
+
+	"app_server/model"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeUserTags(t *testing.T) {
+	tags, err := normalizeUserTags([]string{" 工作 ", "", "重要", "工作"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"工作", "重要"}, tags)
+
+	_, err = normalizeUserTags([]string{"ai_reply"})
+	assert.Error(t, err)
+	_, err = normalizeUserTags([]string{"imported"})
+	assert.Error(t, err)
+	_, err = normalizeUserTags([]string{"profile_12"})
+	assert.Error(t, err)
+
+	_, err = normalizeUserTags([]string{strings.Repeat("长", maxTagLength+1)})
+	assert.Error(t, err)
+
+	tags, err = normalizeUserTags([]string{strings.Repeat("长", maxTagLength)})
+	assert.NoError(t, err)
+	assert.Len(t, tags, 1)
+}
+
+func TestAddTags(t *testing.T) {
+	current := []string{"工作"}
+	tags, err := addTags(current, []string{"重要", "工作"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"工作", "重要"}, tags)
+	assert.Equal(t, []string{"工作"}, current)
+
+	full := make([]string, maxMessageUserTags)
+	for i := range full {
+		full[i] = strings.Repeat("a", i+1)
+	}
+	_, err = addTags(full, []string{"新标签"})
+	assert.Error(t, err)
+	_, err = addTags(full, []string{"a"})
+	assert.NoError(t, err)
+}
+
+func TestRemoveTags(t *testing.T) {
+	assert.Equal(t, []string{"重要"}, removeTags([]string{"工作", "重要"}, []string{"工作", "不存在"}))
+	assert.Empty(t, removeTags(nil, []string{"工作"}))
+}
+
+func TestTagStats(t *testing.T) {
+	counts := make(map[tagKey]int)
+	countTags(counts, []model.ChatMessage{
+		{Tags: []string{"ai_reply"}, UserTags: []string{"工作"}},
+		{Tags: []string{"demo", "formal"}, UserTags: []string{"工作", "重要"}},
+		{Tags: []string{"ai_reply"}, UserTags: []string{"formal"}},
+	})
+	assert.Equal(t, map[tagKey]int{
+		{Tag: "ai_reply", System: true}: 2,
+		{Tag: "工作"}:                     2,
+		{Tag: "demo", System: true}:     1,
+		{Tag: "formal", System: true}:   1,
+		{Tag: "formal"}:                 1,
+		{Tag: "重要"}:                     1,
+	}, counts)
+
+	// 不在 SystemTags 中的系统标签（如语气）按所在字段判断，同名的用户标签单独统计
+	stats := tagStats(counts)
+	assert.Equal(t, []string{"ai_reply", "工作", "demo", "formal", "formal", "重要"}, fn.Map(stats, func(stat *message.MessageTag) string { return stat.Tag }))
+	assert.Equal(t, []bool{true, false, true, true, false, false}, fn.Map(stats, func(stat *message.MessageTag) bool { return stat.System }))
+	assert.Equal(t, int32(2), stats[1].MessageCount)
+}
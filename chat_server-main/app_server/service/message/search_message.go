@@ -0,0 +1,206 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"app_server/domain"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+)
+
+const (
+	// ngramTokenSize 与 MySQL ngram_token_size 保持一致，短于该长度的关键词无法命中全文索引
+	ngramTokenSize = 2
+	// snippetRadius 命中片段在关键词前后保留的字符数
+	snippetRadius = 30
+)
+
+// SearchChatMessages 全文搜索消息 - 基于 content 的 ngram 全文索引，支持中文
+func (s *ChatMessageService) SearchChatMessages(ctx context.Context, connectReq *connect.Request[message.SearchChatMessagesRequest]) (*connect.Response[message.SearchChatMessagesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	terms := splitSearchTerms(req.Query)
+	if len(terms) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("query is required"))
+	}
+
+	// 构建查询条件，只能搜索自己的消息
+	query := db.GetDB().Model(&model.ChatMessage{}).Where("user_id = ?", userID)
+
+	// 关键词都足够长时使用全文索引，否则退化为 LIKE
+	if lo.EveryBy(terms, func(term string) bool { return utf8.RuneCountInString(term) >= ngramTokenSize }) {
+		query = query.Where("MATCH(content) AGAINST(? IN BOOLEAN MODE)", booleanModeQuery(terms))
+	} else {
+		for _, term := range terms {
+			query = query.Where("content LIKE ?", "%"+escapeLike(term)+"%")
+		}
+	}
+
+	// 限定会话
+	if req.SessionId != "" {
+		query = query.Where("session_id = ?", fn.Atoi[uint](req.SessionId))
+	}
+
+	// 处理角色过滤
+	if len(req.Roles) > 0 {
+		query = query.Where("role IN ?", req.Roles)
+	}
+
+	// 处理消息类型过滤
+	if req.MsgType != "" {
+		query = query.Where("msg_type = ?", req.MsgType)
+	}
+
+	// 包含任一标签
+	query = filterByTags(query, req.Tags, nil)
+
+	// 时间范围
+	if req.StartTime != nil && req.StartTime.IsValid() {
+		query = query.Where("msg_at >= ?", req.StartTime.AsTime())
+	}
+	if req.EndTime != nil && req.EndTime.IsValid() {
+		query = query.Where("msg_at <= ?", req.EndTime.AsTime())
+	}
+
+	// 处理分页
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = 20 // 默认每页20条
+	}
+	if lastID := fn.Atoi[uint](req.PageToken); lastID > 0 {
+		query = query.Where("id < ?", lastID)
+	}
+
+	var dbMessages []model.ChatMessage
+	if err := query.Order("id DESC").Limit(pageSize).Find(&dbMessages).Error; err != nil {
+		slog.Error("search chat messages error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 查询消息所属会话，用于展示会话名称和头像
+	sessionIDs := lo.Uniq(fn.Map(dbMessages, func(msg model.ChatMessage) uint { return msg.SessionID }))
+	var chatSessions []model.ChatSession
+	if len(sessionIDs) > 0 {
+		if err := db.GetDB().Where("id IN ? AND user_id = ?", sessionIDs, userID).Find(&chatSessions).Error; err != nil {
+			slog.Error("search chat messages load sessions error", "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		domain.FillChatSessionProfiles(chatSessions)
+	}
+	sessionMap := lo.SliceToMap(chatSessions, func(chatSession model.ChatSession) (uint, model.ChatSession) {
+		return chatSession.ID, chatSession
+	})
+
+	hits := make([]*message.SearchChatMessageHit, 0, len(dbMessages))
+	for _, msg := range dbMessages {
+		chatSession := sessionMap[msg.SessionID]
+		hits = append(hits, &message.SearchChatMessageHit{
+			Message:         msg.ToProto(),
+			Snippet:         buildSnippet(msg.Content, terms, snippetRadius),
+			SessionName:     chatSession.Name,
+			SessionAvatar:   chatSession.Avatar,
+			AnchorPageToken: messageCursor{Direction: pageOlder, ID: msg.ID + 1}.pageToken(),
+		})
+	}
+
+	var nextPageToken string
+	if len(dbMessages) == pageSize {
+		nextPageToken = strconv.Itoa(int(dbMessages[len(dbMessages)-1].ID))
+	}
+
+	return connect.NewResponse(&message.SearchChatMessagesResponse{
+		Hits:          hits,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// splitSearchTerms 按空白拆分关键词并去重
+func splitSearchTerms(query string) []string {
+	return lo.Uniq(strings.Fields(query))
+}
+
+// booleanModeQuery 构建 BOOLEAN MODE 查询串，每个关键词作为必须命中的短语，避免用户输入被当作操作符
+func booleanModeQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ReplaceAll(term, `"`, " ")
+		parts = append(parts, `+"`+term+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeLike 转义 LIKE 中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// buildSnippet 截取第一个命中关键词前后 radius 个字符，并用 <em></em> 高亮所有关键词
+// 原文会做 HTML 转义，客户端可以直接按富文本渲染
+func buildSnippet(content string, terms []string, radius int) string {
+	runes := []rune(content)
+	lower := []rune(strings.ToLower(content))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+
+	// 标记每个字符是否属于某个关键词
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		termRunes := []rune(strings.ToLower(term))
+		if len(termRunes) == 0 || len(termRunes) != utf8.RuneCountInString(term) {
+			continue
+		}
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) != string(termRunes) {
+				continue
+			}
+			for j := i; j < i+len(termRunes); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		first = 0
+	}
+
+	start := max(first-radius, 0)
+	end := min(first+radius, len(runes))
+	if end-start < 2*radius {
+		end = min(start+2*radius, len(runes))
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			sb.WriteString("<em>")
+		}
+		sb.WriteString(html.EscapeString(string(runes[i])))
+		if marked[i] && (i == end-1 || !marked[i+1]) {
+			sb.WriteString("</em>")
+		}
+	}
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
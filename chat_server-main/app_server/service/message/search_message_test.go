@@ -0,0 +1,73 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBuildSnippet 测试命中片段截取和高亮
+func TestBuildSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		terms   []string
+		radius  int
+		want    string
+	}{
+		{
+			name:    "short_content_single_term",
+			content: "明天上午开会",
+			terms:   []string{"开会"},
+			radius:  10,
+			want:    "明天上午<em>开会</em>",
+		},
+		{
+			name:    "multiple_terms",
+			content: "周报明天交，方案周五交",
+			terms:   []string{"周报", "方案"},
+			radius:  10,
+			want:    "<em>周报</em>明天交，<em>方案</em>周五交",
+		},
+		{
+			name:    "long_content_is_truncated",
+			content: "一二三四五六七八九十关键一二三四五六七八九十",
+			terms:   []string{"关键"},
+			radius:  3,
+			want:    "…八九十<em>关键</em>一…",
+		},
+		{
+			name:    "case_insensitive",
+			content: "Please send the PPT",
+			terms:   []string{"ppt"},
+			radius:  30,
+			want:    "Please send the <em>PPT</em>",
+		},
+		{
+			name:    "html_is_escaped",
+			content: "<b>加薪</b>",
+			terms:   []string{"加薪"},
+			radius:  30,
+			want:    "&lt;b&gt;<em>加薪</em>&lt;/b&gt;",
+		},
+		{
+			name:    "no_hit_returns_prefix",
+			content: "一二三四五六",
+			terms:   []string{"七"},
+			radius:  2,
+			want:    "一二三四…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildSnippet(tt.content, tt.terms, tt.radius))
+		})
+	}
+}
+
+// TestBooleanModeQuery 测试全文检索查询串构建
+func TestBooleanModeQuery(t *testing.T) {
+	assert.Equal(t, `+"加班" +"周末"`, booleanModeQuery(splitSearchTerms(" 加班  周末 加班")))
+	assert.Equal(t, `+"a b"`, booleanModeQuery([]string{`a"b`}))
+}
@@ -0,0 +1,200 @@
+package message
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"app_server/domain/sentiment"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	turningWindow     = 5   // 与此前同一方最近几条消息的平均分比较
+	turningMinHistory = 3   // 此前同一方至少有几条消息才判断转折
+	turningThreshold  = 0.8 // 分值变化超过该值视为转折
+	maxTurningPoints  = 10
+	sentimentDayFmt   = "2006-01-02"
+)
+
+// scoredMessage 带情感分析结果的聊天记录
+type scoredMessage struct {
+	msg    model.ChatMessage
+	result model.MessageSentiment
+}
+
+// GetSessionSentiment 分析会话中双方聊天记录的情感，返回按天的走势和转折点
+func (s *ChatMessageService) GetSessionSentiment(ctx context.Context, connectReq *connect.Request[message.GetSessionSentimentRequest]) (*connect.Response[message.GetSessionSentimentResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	if sessionID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id is required"))
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	if err := checkSessionOwner(database, userID, sessionID); err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			return nil, connectErr
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	query := database.Where("user_id = ? AND session_id = ? AND msg_type = ? AND role IN ?", userID, sessionID,
+		model.MessageTypeHistory, []string{model.MessageRoleSelf, model.MessageRoleFriend})
+	if req.StartTime != nil {
+		query = query.Where("msg_at >= ?", req.StartTime.AsTime())
+	}
+	if req.EndTime != nil {
+		query = query.Where("msg_at < ?", req.EndTime.AsTime())
+	}
+	var msgs []model.ChatMessage
+	if err := query.Order("msg_at ASC, id ASC").Find(&msgs).Error; err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	msgs = lo.Filter(msgs, func(msg model.ChatMessage, _ int) bool {
+		return strings.TrimSpace(msg.Content) != ""
+	})
+
+	results, err := sentiment.Classify(ctx, userID, msgs)
+	if err != nil {
+		slog.Error("classify session sentiment error", "error", err, "sessionID", sessionID)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	var scored []scoredMessage
+	for _, msg := range msgs {
+		if result, ok := results[msg.ID]; ok {
+			scored = append(scored, scoredMessage{msg: msg, result: result})
+		}
+	}
+
+	return connect.NewResponse(&message.GetSessionSentimentResponse{
+		Series:        buildSentimentSeries(scored),
+		TurningPoints: findTurningPoints(scored),
+		Messages: fn.Map(scored, func(item scoredMessage) *message.MessageSentiment {
+			return &message.MessageSentiment{
+				MessageId: fn.Itoa(item.msg.ID),
+				Role:      item.msg.Role,
+				Sentiment: item.result.Sentiment,
+				Score:     float32(item.result.Score),
+				Emotions:  item.result.Emotions,
+				MsgAt:     timestamppb.New(item.msg.MsgAt),
+			}
+		}),
+		PendingCount: int32(len(msgs) - len(scored)),
+	}), nil
+}
+
+// buildSentimentSeries 按天和角色汇总情感，按天、角色排序
+func buildSentimentSeries(scored []scoredMessage) []*message.SentimentDailyPoint {
+	type key struct{ day, role string }
+	points := make(map[key]*message.SentimentDailyPoint)
+	emotions := make(map[key]map[string]int)
+	for _, item := range scored {
+		k := key{day: item.msg.MsgAt.Format(sentimentDayFmt), role: item.msg.Role}
+		point, ok := points[k]
+		if !ok {
+			point = &message.SentimentDailyPoint{Day: k.day, Role: k.role}
+			points[k] = point
+			emotions[k] = make(map[string]int)
+		}
+		// 先累加总分，最后再求平均
+		point.AvgScore += float32(item.result.Score)
+		point.MessageCount++
+		switch item.result.Sentiment {
+		case model.SentimentPositive:
+			point.PositiveCount++
+		case model.SentimentNegative:
+			point.NegativeCount++
+		default:
+			point.NeutralCount++
+		}
+		for _, emotion := range item.result.Emotions {
+			emotions[k][emotion]++
+		}
+	}
+
+	series := make([]*message.SentimentDailyPoint, 0, len(points))
+	for k, point := range points {
+		point.AvgScore /= float32(point.MessageCount)
+		point.TopEmotion = topEmotion(emotions[k])
+		series = append(series, point)
+	}
+	slices.SortFunc(series, func(a, b *message.SentimentDailyPoint) int {
+		return cmp.Or(cmp.Compare(a.Day, b.Day), cmp.Compare(a.Role, b.Role))
+	})
+	return series
+}
+
+// topEmotion 出现次数最多的情绪，次数相同时按标签顺序取靠前的
+func topEmotion(counts map[string]int) string {
+	var top string
+	for _, emotion := range sentiment.Emotions {
+		if counts[emotion] > counts[top] {
+			top = emotion
+		}
+	}
+	return top
+}
+
+// findTurningPoints 找出情感与此前同一方最近几条消息差异明显的消息
+// 最多返回变化最大的 maxTurningPoints 个，按消息时间排序
+func findTurningPoints(scored []scoredMessage) []*message.SentimentTurningPoint {
+	type candidate struct {
+		index int
+		point *message.SentimentTurningPoint
+	}
+
+	var candidates []candidate
+	windows := make(map[string][]float64)
+	for i, item := range scored {
+		role := item.msg.Role
+		window := windows[role]
+		if len(window) >= turningMinHistory {
+			previous := lo.Sum(window) / float64(len(window))
+			if delta := item.result.Score - previous; math.Abs(delta) >= turningThreshold {
+				candidates = append(candidates, candidate{index: i, point: &message.SentimentTurningPoint{
+					MessageId:     fn.Itoa(item.msg.ID),
+					Role:          role,
+					Day:           item.msg.MsgAt.Format(sentimentDayFmt),
+					Score:         float32(item.result.Score),
+					PreviousScore: float32(previous),
+					Delta:         float32(delta),
+					Emotions:      item.result.Emotions,
+					Content:       item.msg.Content,
+					MsgAt:         timestamppb.New(item.msg.MsgAt),
+				}})
+			}
+		}
+		window = append(window, item.result.Score)
+		if len(window) > turningWindow {
+			window = window[1:]
+		}
+		windows[role] = window
+	}
+
+	if len(candidates) > maxTurningPoints {
+		slices.SortStableFunc(candidates, func(a, b candidate) int {
+			return cmp.Compare(math.Abs(float64(b.point.Delta)), math.Abs(float64(a.point.Delta)))
+		})
+		candidates = candidates[:maxTurningPoints]
+		slices.SortFunc(candidates, func(a, b candidate) int { return cmp.Compare(a.index, b.index) })
+	}
+	return fn.Map(candidates, func(c candidate) *message.SentimentTurningPoint { return c.point })
+}
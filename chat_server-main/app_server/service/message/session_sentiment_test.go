@@ -0,0 +1,85 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newScoredMessage(id uint, role string, at time.Time, score float64, emotions ...string) scoredMessage {
+	msg := model.ChatMessage{Role: role, Content: "内容", MsgAt: at}
+	msg.ID = id
+	return scoredMessage{msg: msg, result: model.MessageSentiment{
+		MessageID: id,
+		Sentiment: sentimentOfTestScore(score),
+		Score:     score,
+		Emotions:  emotions,
+	}}
+}
+
+func sentimentOfTestScore(score float64) string {
+	switch {
+	case score > 0:
+		return model.SentimentPositive
+	case score < 0:
+		return model.SentimentNegative
+	default:
+		return model.SentimentNeutral
+	}
+}
+
+func TestBuildSentimentSeries(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	scored := []scoredMessage{
+		newScoredMessage(1, model.MessageRoleFriend, day1, 0.6, "joy"),
+		newScoredMessage(2, model.MessageRoleSelf, day1, 0, "calm"),
+		newScoredMessage(3, model.MessageRoleFriend, day1.Add(time.Hour), -0.2, "anxiety", "joy"),
+		newScoredMessage(4, model.MessageRoleFriend, day2, -0.8, "anger"),
+	}
+
+	series := buildSentimentSeries(scored)
+	assert.Len(t, series, 3)
+
+	assert.Equal(t, "2024-05-01", series[0].Day)
+	assert.Equal(t, model.MessageRoleFriend, series[0].Role)
+	assert.InDelta(t, 0.2, series[0].AvgScore, 1e-6)
+	assert.Equal(t, int32(2), series[0].MessageCount)
+	assert.Equal(t, int32(1), series[0].PositiveCount)
+	assert.Equal(t, int32(1), series[0].NegativeCount)
+	assert.Equal(t, "joy", series[0].TopEmotion)
+
+	assert.Equal(t, model.MessageRoleSelf, series[1].Role)
+	assert.Equal(t, int32(1), series[1].NeutralCount)
+
+	assert.Equal(t, "2024-05-02", series[2].Day)
+	assert.Equal(t, "anger", series[2].TopEmotion)
+}
+
+func TestFindTurningPoints(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	var scored []scoredMessage
+	// 对方前几条消息情绪稳定，第 4 条突然转为消极；自己的消息变化不足以形成转折
+	for i, score := range []float64{0.5, 0.6, 0.4, -0.6, -0.7} {
+		scored = append(scored, newScoredMessage(uint(i+1), model.MessageRoleFriend, at.Add(time.Duration(i)*time.Minute), score))
+	}
+	scored = append(scored,
+		newScoredMessage(10, model.MessageRoleSelf, at, 0.2),
+		newScoredMessage(11, model.MessageRoleSelf, at, 0.3),
+		newScoredMessage(12, model.MessageRoleSelf, at, 0.1),
+		newScoredMessage(13, model.MessageRoleSelf, at, -0.4),
+	)
+
+	points := findTurningPoints(scored)
+	assert.Len(t, points, 2)
+	assert.Equal(t, "4", points[0].MessageId)
+	assert.InDelta(t, 0.5, points[0].PreviousScore, 1e-6)
+	assert.InDelta(t, -1.1, points[0].Delta, 1e-6)
+	// 第 5 条与前 4 条的平均分（0.225）相比仍然差异明显
+	assert.Equal(t, "5", points[1].MessageId)
+
+	assert.Empty(t, findTurningPoints(scored[:3]))
+}
@@ -0,0 +1,272 @@
+package message
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"app_server/domain/msgevent"
+	"app_server/domain/summary"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/oai"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"github.com/sashabaranov/go-openai"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSuggestCount = 3
+	maxSuggestCount     = 5
+)
+
+// 回复建议的语气
+const (
+	SuggestToneFormal     = "formal"
+	SuggestToneWarm       = "warm"
+	SuggestToneAssertive  = "assertive"
+	SuggestToneHumorous   = "humorous"
+	SuggestToneConcise    = "concise"
+	SuggestToneApologetic = "apologetic"
+)
+
+var suggestTones = []string{
+	SuggestToneFormal,
+	SuggestToneWarm,
+	SuggestToneAssertive,
+	SuggestToneHumorous,
+	SuggestToneConcise,
+	SuggestToneApologetic,
+}
+
+var suggestToneNames = map[string]string{
+	SuggestToneFormal:     "正式得体",
+	SuggestToneWarm:       "温和亲切",
+	SuggestToneAssertive:  "坚定有主见",
+	SuggestToneHumorous:   "幽默轻松",
+	SuggestToneConcise:    "简洁干脆",
+	SuggestToneApologetic: "诚恳致歉",
+}
+
+// SuggestReplies 针对对方的一条聊天记录生成多条不同语气的回复建议
+func (s *ChatMessageService) SuggestReplies(ctx context.Context, connectReq *connect.Request[message.SuggestRepliesRequest]) (*connect.Response[message.SuggestRepliesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	messageID := fn.Atoi[uint](req.MessageId)
+	if sessionID == 0 || messageID == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and message_id are required"))
+	}
+	count := int(req.Count)
+	if count <= 0 {
+		count = defaultSuggestCount
+	}
+	count = min(count, maxSuggestCount)
+	tones := lo.Uniq(req.Tones)
+	if invalid, ok := lo.Find(tones, func(tone string) bool { return !lo.Contains(suggestTones, tone) }); ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid tone: %s, must be one of: %v", invalid, suggestTones))
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	target, err := findSuggestTarget(database, userID, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	_, userProfile, friendProfile, err := loadSessionParticipants(database, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 目标消息及之前的聊天记录，加上当前分支上的咨询
+	tree, err := loadConsultTree(database, userID, sessionID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	var historyMessages []model.ChatMessage
+	if err := database.Where("user_id = ? AND session_id = ? AND msg_type IN ? AND id <= ?", userID, sessionID,
+		[]string{model.MessageTypeHistory, model.MessageTypeTranslate}, messageID).
+		Order("id ASC").
+		Find(&historyMessages).Error; err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	consultIDs := fn.Map(fn.Filter(tree.activePath(), func(msg model.ChatMessage) bool {
+		return msg.ID < messageID
+	}), func(msg model.ChatMessage) uint { return msg.ID })
+	if len(consultIDs) > 0 {
+		// 树中只有结构字段，这里查询完整内容
+		var consultMessages []model.ChatMessage
+		if err := database.Where("id IN ? AND user_id = ?", consultIDs, userID).
+			Find(&consultMessages).Error; err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		historyMessages = append(historyMessages, consultMessages...)
+	}
+	slices.SortFunc(historyMessages, func(a, b model.ChatMessage) int { return cmp.Compare(a.ID, b.ID) })
+
+	historySummary, recentMessages := summary.Compact(ctx, userID, sessionID, historyMessages, "")
+	openaiMessages := s.buildChatHistoryWithExclude(recentMessages, getSuggestPrompt(ctx), historySummary, &userProfile, &friendProfile)
+	openaiMessages = append(openaiMessages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: buildSuggestInstruction(target, friendProfile.Name, count, tones, req.Hint),
+	})
+
+	content, err := s.callAIForReply(ctx, openaiMessages)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	suggestions, err := parseSuggestions(content, count)
+	if err != nil {
+		slog.Error("parse suggested replies error", "error", err, "content", content)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&message.SuggestRepliesResponse{
+		Suggestions: suggestions,
+	}), nil
+}
+
+// SaveSuggestedReply 将选中的回复建议保存为自己发送的聊天记录
+func (s *ChatMessageService) SaveSuggestedReply(ctx context.Context, connectReq *connect.Request[message.SaveSuggestedReplyRequest]) (*connect.Response[message.SaveSuggestedReplyResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.SessionId)
+	messageID := fn.Atoi[uint](req.MessageId)
+	content := strings.TrimSpace(req.Content)
+	if sessionID == 0 || messageID == 0 || content == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id, message_id and content are required"))
+	}
+	tags := []string{model.MessageTagSuggested}
+	if req.Tone != "" {
+		if !lo.Contains(suggestTones, req.Tone) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid tone: %s", req.Tone))
+		}
+		tags = append(tags, req.Tone)
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	if _, err := findSuggestTarget(database, userID, sessionID, messageID); err != nil {
+		return nil, err
+	}
+
+	msg := model.ChatMessage{
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      model.MessageRoleSelf,
+		MsgType:   model.MessageTypeHistory,
+		Content:   content,
+		Tags:      tags,
+		MsgAt:     time.Now(),
+	}
+	if err := database.Create(&msg).Error; err != nil {
+		slog.Error("save suggested reply error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, msg)
+
+	return connect.NewResponse(&message.SaveSuggestedReplyResponse{
+		Message: msg.ToProto(),
+	}), nil
+}
+
+// findSuggestTarget 查询回复建议针对的消息，必须是对方发送的聊天记录
+func findSuggestTarget(tx *gorm.DB, userID, sessionID, messageID uint) (model.ChatMessage, error) {
+	var target model.ChatMessage
+	if err := tx.Where("id = ? AND user_id = ? AND session_id = ?", messageID, userID, sessionID).
+		First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return target, connect.NewError(connect.CodeNotFound, fmt.Errorf("消息未找到"))
+		}
+		return target, connect.NewError(connect.CodeInternal, err)
+	}
+	if target.MsgType != model.MessageTypeHistory || target.Role != model.MessageRoleFriend {
+		return target, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("message_id must be a FRIEND HISTORY message"))
+	}
+	return target, nil
+}
+
+// getSuggestPrompt 获取回复建议的系统提示词
+func getSuggestPrompt(ctx context.Context) string {
+	var config model.Config
+	if err := db.GetDB().WithContext(ctx).Model(&model.Config{}).
+		Where("k = ?", "prompt:suggest:default").
+		First(&config).Error; err == nil && config.Value != "" {
+		return config.Value
+	}
+
+	return `你是一个高情商的沟通顾问，帮助用户回复对方的消息。
+你需要结合双方的资料和聊天记录，理解对方的真实意图，站在用户的立场给出可以直接发送的回复。
+回复要符合用户平时说话的风格，自然、口语化，不要使用书面化的套话。`
+}
+
+// buildSuggestInstruction 构建要求模型按 JSON 格式输出回复建议的指令
+func buildSuggestInstruction(target model.ChatMessage, friendName string, count int, tones []string, hint string) string {
+	if friendName == "" {
+		friendName = "对方"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s刚刚发来：%s\n\n", friendName, target.Content)
+	fmt.Fprintf(&b, "请给出 %d 条不同语气的回复建议，", count)
+	names := fn.Map(lo.Ternary(len(tones) > 0, tones, suggestTones), func(tone string) string {
+		return fmt.Sprintf("%s（%s）", tone, suggestToneNames[tone])
+	})
+	fmt.Fprintf(&b, "语气从以下选择%s：%s。\n", lo.Ternary(len(tones) > 0, "", "最合适的几种"), strings.Join(names, "、"))
+	if hint = strings.TrimSpace(hint); hint != "" {
+		fmt.Fprintf(&b, "用户希望表达的意思：%s\n", hint)
+	}
+	b.WriteString(`只输出 JSON 数组，不要输出其他内容，格式如下：
+[{"content": "回复内容", "tone": "语气标签", "rationale": "一句话说明为什么这样回复"}]`)
+	return b.String()
+}
+
+// suggestion 模型输出的一条回复建议
+type suggestion struct {
+	Content   string `json:"content"`
+	Tone      string `json:"tone"`
+	Rationale string `json:"rationale"`
+}
+
+// parseSuggestions 解析模型输出的回复建议，丢弃空内容和未知语气，最多返回 count 条
+func parseSuggestions(content string, count int) ([]*message.SuggestedReply, error) {
+	items, err := fn.JsonUnmarshalStr[[]suggestion](oai.ExtractJSON(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid suggestions: %w", err)
+	}
+
+	var replies []*message.SuggestedReply
+	for _, item := range items {
+		item.Content = strings.TrimSpace(item.Content)
+		item.Tone = strings.ToLower(strings.TrimSpace(item.Tone))
+		if item.Content == "" {
+			continue
+		}
+		if !lo.Contains(suggestTones, item.Tone) {
+			item.Tone = ""
+		}
+		replies = append(replies, &message.SuggestedReply{
+			Content:   item.Content,
+			Tone:      item.Tone,
+			Rationale: strings.TrimSpace(item.Rationale),
+		})
+		if len(replies) == count {
+			break
+		}
+	}
+	if len(replies) == 0 {
+		return nil, errors.New("no suggestions")
+	}
+	return replies, nil
+}
@@ -0,0 +1,48 @@
+package message
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSuggestions(t *testing.T) {
+	content := "好的，以下是建议：\n```json\n[" +
+		`{"content": "收到，我马上处理", "tone": "Formal", "rationale": "表明态度"},` +
+		`{"content": "  ", "tone": "warm", "rationale": "空内容"},` +
+		`{"content": "没问题～", "tone": "cute", "rationale": "未知语气"},` +
+		`{"content": "好的", "tone": "concise", "rationale": "简短"}` +
+		"]\n```"
+
+	replies, err := parseSuggestions(content, 2)
+	assert.NoError(t, err)
+	assert.Len(t, replies, 2)
+	assert.Equal(t, "收到，我马上处理", replies[0].Content)
+	assert.Equal(t, SuggestToneFormal, replies[0].Tone)
+	assert.Equal(t, "没问题～", replies[1].Content)
+	assert.Equal(t, "", replies[1].Tone)
+
+	_, err = parseSuggestions("[]", 3)
+	assert.Error(t, err)
+	_, err = parseSuggestions("抱歉，无法生成", 3)
+	assert.Error(t, err)
+}
+
+func TestBuildSuggestInstruction(t *testing.T) {
+	target := model.ChatMessage{Content: "明天能交吗？"}
+
+	instruction := buildSuggestInstruction(target, "王总", 2, []string{SuggestToneWarm}, "想争取多一天")
+	assert.Contains(t, instruction, "王总刚刚发来：明天能交吗？")
+	assert.Contains(t, instruction, "2 条")
+	assert.Contains(t, instruction, "warm（温和亲切）")
+	assert.NotContains(t, instruction, "formal")
+	assert.Contains(t, instruction, "想争取多一天")
+
+	instruction = buildSuggestInstruction(target, "", 3, nil, "")
+	assert.Contains(t, instruction, "对方刚刚发来")
+	for _, tone := range suggestTones {
+		assert.Contains(t, instruction, tone)
+	}
+}
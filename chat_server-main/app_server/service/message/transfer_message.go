@@ -0,0 +1,426 @@
+package message
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"app_server/domain/attachment"
+	"app_server/domain/msgevent"
+	"app_server/domain/summary"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/pkg/idgen"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// 合并会话时 Profile 的来源
+const (
+	mergeProfileFromTarget = "TARGET"
+	mergeProfileFromSource = "SOURCE"
+)
+
+// MoveChatMessages 将消息移动到同一用户的另一个会话
+// 翻译、AI 回复等子消息会一起移动
+func (s *ChatMessageService) MoveChatMessages(ctx context.Context, connectReq *connect.Request[message.MoveChatMessagesRequest]) (*connect.Response[message.MoveChatMessagesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	targetSessionID := fn.Atoi[uint](req.TargetSessionId)
+	ids := fn.Map(req.Ids, fn.Atoi[uint])
+	if targetSessionID == 0 || len(ids) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("ids and target_session_id are required"))
+	}
+
+	var msgs, moved []model.ChatMessage
+	err := db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSessionOwner(tx, userID, targetSessionID); err != nil {
+			return err
+		}
+		var err error
+		msgs, err = collectTransferMessages(tx, userID, targetSessionID, ids)
+		if err != nil {
+			return err
+		}
+
+		movedIDs := make(map[uint]bool, len(msgs))
+		for _, msg := range msgs {
+			movedIDs[msg.ID] = true
+		}
+		allIDs := lo.Keys(movedIDs)
+		sourceSessionIDs := lo.Uniq(fn.Map(msgs, func(msg model.ChatMessage) uint { return msg.SessionID }))
+
+		// 上一条消息留在原会话的，在新会话中按 id 顺序衔接
+		brokenPrevIDs := fn.Map(fn.Filter(msgs, func(msg model.ChatMessage) bool {
+			return msg.PrevID > 0 && !movedIDs[msg.PrevID]
+		}), func(msg model.ChatMessage) uint { return msg.ID })
+		if len(brokenPrevIDs) > 0 {
+			if err := tx.Model(&model.ChatMessage{}).Where("id IN ?", brokenPrevIDs).Update("prev_id", 0).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&model.ChatMessage{}).Where("id IN ?", allIDs).Update("session_id", targetSessionID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.ChatMessageRevision{}).Where("message_id IN ?", allIDs).Update("session_id", targetSessionID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.MessageFeedback{}).Where("message_id IN ?", allIDs).Update("session_id", targetSessionID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.MessageSentiment{}).Where("message_id IN ?", allIDs).Update("session_id", targetSessionID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.ChatBranchSelection{}).
+			Where("user_id = ? AND session_id IN ? AND group_id IN ?", userID, sourceSessionIDs, allIDs).
+			Update("session_id", targetSessionID).Error; err != nil {
+			return err
+		}
+		if err := summary.InvalidateSessions(tx, append(sourceSessionIDs, targetSessionID)...); err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ?", allIDs).Order("id ASC").Find(&moved).Error
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	// 原会话中的消息按删除推送，目标会话中的消息按创建推送
+	msgevent.Publish(ctx, msgevent.EventDeleted, userID, msgs...)
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, moved...)
+
+	slog.Info("chat messages moved", "userID", userID, "targetSessionID", targetSessionID, "count", len(moved))
+
+	return connect.NewResponse(&message.MoveChatMessagesResponse{
+		Messages: fn.Map(moved, model.ChatMessage.ToProto),
+	}), nil
+}
+
+// CopyChatMessages 将消息复制到同一用户的另一个会话
+// 翻译、AI 回复等子消息会一起复制，复制后的消息使用新的 id
+func (s *ChatMessageService) CopyChatMessages(ctx context.Context, connectReq *connect.Request[message.CopyChatMessagesRequest]) (*connect.Response[message.CopyChatMessagesResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	targetSessionID := fn.Atoi[uint](req.TargetSessionId)
+	ids := fn.Map(req.Ids, fn.Atoi[uint])
+	if targetSessionID == 0 || len(ids) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("ids and target_session_id are required"))
+	}
+
+	var copied []model.ChatMessage
+	err := db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSessionOwner(tx, userID, targetSessionID); err != nil {
+			return err
+		}
+		msgs, err := collectTransferMessages(tx, userID, targetSessionID, ids)
+		if err != nil {
+			return err
+		}
+
+		// 按原 id 顺序生成新 id，保持消息的相对顺序
+		var idMap map[uint]uint
+		copied, idMap = remapMessages(msgs, targetSessionID)
+		if err := tx.CreateInBatches(&copied, 500).Error; err != nil {
+			return err
+		}
+		if err := copyBranchSelections(tx, userID, targetSessionID, idMap); err != nil {
+			return err
+		}
+		if err := attachment.Copy(tx, idMap); err != nil {
+			return err
+		}
+		return summary.InvalidateSessions(tx, targetSessionID)
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, copied...)
+
+	slog.Info("chat messages copied", "userID", userID, "targetSessionID", targetSessionID, "count", len(copied))
+
+	return connect.NewResponse(&message.CopyChatMessagesResponse{
+		Messages: fn.Map(copied, model.ChatMessage.ToProto),
+	}), nil
+}
+
+// MergeChatSessions 将源会话的全部消息合并到目标会话，合并后按 msg_at 重新排序，并删除源会话
+func (s *ChatMessageService) MergeChatSessions(ctx context.Context, connectReq *connect.Request[message.MergeChatSessionsRequest]) (*connect.Response[message.MergeChatSessionsResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sourceSessionID := fn.Atoi[uint](req.SourceSessionId)
+	targetSessionID := fn.Atoi[uint](req.TargetSessionId)
+	if sourceSessionID == 0 || targetSessionID == 0 || sourceSessionID == targetSessionID {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("source_session_id and target_session_id are required and must be different"))
+	}
+
+	var count int
+	var msgs, merged []model.ChatMessage
+	err := db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sessions []model.ChatSession
+		if err := tx.Where("id IN ? AND user_id = ?", []uint{sourceSessionID, targetSessionID}, userID).Find(&sessions).Error; err != nil {
+			return err
+		}
+		source, ok1 := lo.Find(sessions, func(session model.ChatSession) bool { return session.ID == sourceSessionID })
+		target, ok2 := lo.Find(sessions, func(session model.ChatSession) bool { return session.ID == targetSessionID })
+		if !ok1 || !ok2 {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("session not found"))
+		}
+
+		// 选择合并后会话使用的 Profile
+		switch req.ProfileFrom {
+		case "", mergeProfileFromTarget:
+		case mergeProfileFromSource:
+			if err := tx.Model(&target).Update("profile_id", source.ProfileID).Error; err != nil {
+				return err
+			}
+		default:
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid profile_from: %s", req.ProfileFrom))
+		}
+
+		// 合并前记录两个会话当前的咨询分支，源会话的咨询接在目标会话之后
+		targetTree, err := loadConsultTree(tx, userID, targetSessionID)
+		if err != nil {
+			return err
+		}
+		sourceTree, err := loadConsultTree(tx, userID, sourceSessionID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ? AND session_id IN ?", userID, []uint{sourceSessionID, targetSessionID}).
+			Find(&msgs).Error; err != nil {
+			return err
+		}
+		slices.SortStableFunc(msgs, func(a, b model.ChatMessage) int {
+			return cmp.Or(a.MsgAt.Compare(b.MsgAt), cmp.Compare(a.ID, b.ID))
+		})
+		count = len(msgs)
+		if count == 0 {
+			return tx.Delete(&source).Error
+		}
+
+		var idMap map[uint]uint
+		merged, idMap = remapMessages(msgs, targetSessionID)
+		if targetPath, sourcePath := targetTree.activePath(), sourceTree.activePath(); len(targetPath) > 0 && len(sourcePath) > 0 {
+			head := sourcePath[0]
+			if head.ParentID > 0 {
+				head = sourceTree.byID[head.ParentID]
+			}
+			for i := range merged {
+				if merged[i].ID == idMap[head.ID] {
+					merged[i].PrevID = idMap[targetPath[len(targetPath)-1].ID]
+				}
+			}
+		}
+
+		// 用新 id 重新写入消息，保证按 msg_at 排序
+		oldIDs := fn.Map(msgs, func(msg model.ChatMessage) uint { return msg.ID })
+		if err := tx.Unscoped().Where("id IN ?", oldIDs).Delete(&model.ChatMessage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&merged, 500).Error; err != nil {
+			return err
+		}
+
+		// 修改记录、反馈、情感分析和分支选择指向新的 id
+		var revisions []model.ChatMessageRevision
+		if err := tx.Where("message_id IN ?", oldIDs).Find(&revisions).Error; err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			if err := tx.Model(&revision).Updates(map[string]any{
+				"message_id": idMap[revision.MessageID],
+				"session_id": targetSessionID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		var feedbacks []model.MessageFeedback
+		if err := tx.Where("message_id IN ?", oldIDs).Find(&feedbacks).Error; err != nil {
+			return err
+		}
+		for _, feedback := range feedbacks {
+			if err := tx.Model(&feedback).Updates(map[string]any{
+				"message_id": idMap[feedback.MessageID],
+				"session_id": targetSessionID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		var sentiments []model.MessageSentiment
+		if err := tx.Where("message_id IN ?", oldIDs).Find(&sentiments).Error; err != nil {
+			return err
+		}
+		for _, sentiment := range sentiments {
+			if err := tx.Model(&sentiment).Updates(map[string]any{
+				"message_id": idMap[sentiment.MessageID],
+				"session_id": targetSessionID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if err := copyBranchSelections(tx, userID, targetSessionID, idMap); err != nil {
+			return err
+		}
+		// 附件复制到新的 id 后删除旧记录，文件仍被引用，不需要恢复过期时间
+		if err := attachment.Copy(tx, idMap); err != nil {
+			return err
+		}
+		if err := tx.Where("message_id IN ?", oldIDs).Delete(&model.ChatMessageAttachment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ? AND session_id = ?", userID, sourceSessionID).
+			Delete(&model.ChatBranchSelection{}).Error; err != nil {
+			return err
+		}
+
+		if err := summary.InvalidateSessions(tx, sourceSessionID, targetSessionID); err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	// 合并后的消息使用新的 id，旧消息按删除推送
+	msgevent.Publish(ctx, msgevent.EventDeleted, userID, msgs...)
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, merged...)
+
+	slog.Info("chat sessions merged", "userID", userID, "sourceSessionID", sourceSessionID, "targetSessionID", targetSessionID, "count", count)
+
+	return connect.NewResponse(&message.MergeChatSessionsResponse{
+		MessageCount: int32(count),
+	}), nil
+}
+
+// checkSessionOwner 检查会话是否属于用户
+func checkSessionOwner(tx *gorm.DB, userID, sessionID uint) error {
+	var session model.ChatSession
+	if err := tx.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("session not found"))
+		}
+		return err
+	}
+	return nil
+}
+
+// collectTransferMessages 查询要移动或复制的消息，并补全它们的子消息
+// 子消息不能脱离父消息单独移动
+func collectTransferMessages(tx *gorm.DB, userID, targetSessionID uint, ids []uint) ([]model.ChatMessage, error) {
+	var msgs []model.ChatMessage
+	if err := tx.Where("id IN ? AND user_id = ?", lo.Uniq(ids), userID).Find(&msgs).Error; err != nil {
+		return nil, err
+	}
+	if len(msgs) != len(lo.Uniq(ids)) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("message not found"))
+	}
+	if lo.ContainsBy(msgs, func(msg model.ChatMessage) bool { return msg.SessionID == targetSessionID }) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("messages are already in the target session"))
+	}
+
+	collected := make(map[uint]bool, len(msgs))
+	for _, msg := range msgs {
+		collected[msg.ID] = true
+	}
+	for frontier := lo.Keys(collected); len(frontier) > 0; {
+		var children []model.ChatMessage
+		if err := tx.Where("parent_id IN ? AND user_id = ?", frontier, userID).Find(&children).Error; err != nil {
+			return nil, err
+		}
+		frontier = nil
+		for _, child := range children {
+			if !collected[child.ID] {
+				collected[child.ID] = true
+				frontier = append(frontier, child.ID)
+				msgs = append(msgs, child)
+			}
+		}
+	}
+
+	for _, msg := range msgs {
+		if msg.ParentID > 0 && !collected[msg.ParentID] {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("message %d must be moved together with its parent %d", msg.ID, msg.ParentID))
+		}
+	}
+
+	slices.SortFunc(msgs, func(a, b model.ChatMessage) int { return cmp.Compare(a.ID, b.ID) })
+	return msgs, nil
+}
+
+// remapMessages 按 msgs 的顺序生成新 id 的消息副本，ParentID、PrevID 指向副本
+// 指向 msgs 之外的 PrevID 置为 0
+func remapMessages(msgs []model.ChatMessage, sessionID uint) ([]model.ChatMessage, map[uint]uint) {
+	idMap := make(map[uint]uint, len(msgs))
+	for _, msg := range msgs {
+		idMap[msg.ID] = idgen.Uint()
+	}
+
+	remapped := make([]model.ChatMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		remapped = append(remapped, model.ChatMessage{
+			Model:     gorm.Model{ID: idMap[msg.ID], CreatedAt: msg.CreatedAt, UpdatedAt: msg.UpdatedAt},
+			UserID:    msg.UserID,
+			SessionID: sessionID,
+			ParentID:  idMap[msg.ParentID],
+			PrevID:    idMap[msg.PrevID],
+			ProfileID: msg.ProfileID,
+			Role:      msg.Role,
+			MsgType:   msg.MsgType,
+			Content:   msg.Content,
+			Tags:      slices.Clone(msg.Tags),
+			MsgAt:     msg.MsgAt,
+
+			SenderName: msg.SenderName,
+			UserTags:   slices.Clone(msg.UserTags),
+
+			PromptKey:     msg.PromptKey,
+			PromptVersion: msg.PromptVersion,
+			AIModel:       msg.AIModel,
+		})
+	}
+	return remapped, idMap
+}
+
+// copyBranchSelections 为复制或合并后的消息复制分支选择
+func copyBranchSelections(tx *gorm.DB, userID, targetSessionID uint, idMap map[uint]uint) error {
+	var selections []model.ChatBranchSelection
+	if err := tx.Where("user_id = ? AND group_id IN ?", userID, lo.Keys(idMap)).Find(&selections).Error; err != nil {
+		return err
+	}
+	for _, selection := range selections {
+		activeID, ok := idMap[selection.ActiveID]
+		if !ok {
+			continue
+		}
+		if err := saveBranchSelection(tx, userID, targetSessionID, branchGroup{ID: idMap[selection.GroupID], Role: selection.Role}, activeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferError 将事务中的错误转换为 connect 错误
+func transferError(err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+	slog.Error("transfer chat messages error", "error", err)
+	return connect.NewError(connect.CodeInternal, err)
+}
@@ -0,0 +1,41 @@
+package message
+
+import (
+	"testing"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestRemapMessages 测试复制、合并时父子关系和上一条消息的重新映射
+func TestRemapMessages(t *testing.T) {
+	msgs := []model.ChatMessage{
+		{Model: gorm.Model{ID: 10}, SessionID: 1, MsgType: model.MessageTypeHistory, Tags: []string{"a"}, SenderName: "张三"},
+		{Model: gorm.Model{ID: 11}, SessionID: 1, ParentID: 10, MsgType: model.MessageTypeTranslate},
+		{Model: gorm.Model{ID: 20}, SessionID: 1, PrevID: 5, Role: model.MessageRoleUser, MsgType: model.MessageTypeConsult},
+		{Model: gorm.Model{ID: 21}, SessionID: 1, ParentID: 20, PrevID: 20, Role: model.MessageRoleAI, MsgType: model.MessageTypeConsult},
+	}
+
+	remapped, idMap := remapMessages(msgs, 2)
+	if !assert.Len(t, remapped, 4) {
+		return
+	}
+	for i, msg := range remapped {
+		assert.Equal(t, uint(2), msg.SessionID)
+		assert.Equal(t, idMap[msgs[i].ID], msg.ID)
+		if i > 0 {
+			assert.Greater(t, msg.ID, remapped[i-1].ID)
+		}
+	}
+	assert.Equal(t, remapped[0].ID, remapped[1].ParentID)
+	assert.Equal(t, uint(0), remapped[2].PrevID) // 上一条消息不在复制范围内
+	assert.Equal(t, remapped[2].ID, remapped[3].ParentID)
+	assert.Equal(t, remapped[2].ID, remapped[3].PrevID)
+	assert.Equal(t, "张三", remapped[0].SenderName)
+
+	// 标签不与原消息共享
+	remapped[0].Tags[0] = "b"
+	assert.Equal(t, "a", msgs[0].Tags[0])
+}
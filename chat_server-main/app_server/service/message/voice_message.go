@@ -0,0 +1,175 @@
+package message
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"app_server/domain/attachment"
+	"app_server/domain/msgevent"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/idgen"
+	"app_server/pkg/openaic"
+	"app_server/pkg/ossc"
+	"app_server/proto/message"
+	"app_server/service/file"
+
+	connect "connectrpc.com/connect"
+	"gorm.io/gorm"
+)
+
+var errNoSpeech = errors.New("no speech recognized")
+
+// VoiceMessageRequest 语音转写为聊天记录的请求
+type VoiceMessageRequest struct {
+	SessionID   uint
+	Role        string    // SELF 或 FRIEND
+	MsgAt       time.Time // 为空时使用当前时间
+	Filename    string
+	ContentType string
+	Audio       []byte
+}
+
+// voiceStore 语音消息用到的存储，测试时替换
+type voiceStore interface {
+	checkSession(ctx context.Context, userID, sessionID uint) error
+	// saveAudio 保存原音频，返回文件 id，相同的音频只保存一次
+	saveAudio(ctx context.Context, userID uint, req VoiceMessageRequest) (uint, error)
+	// createMessage 创建消息，并将音频作为附件添加到消息
+	createMessage(ctx context.Context, msg *model.ChatMessage, fileID uint) error
+	fillAttachments(ctx context.Context, userID uint, protoMsgs []*message.ChatMessage) error
+}
+
+var voiceMessageStore voiceStore = dbVoiceStore{}
+
+// CreateVoiceMessage 将语音转写为文字并保存为聊天记录，转写成功后才保存原音频
+func CreateVoiceMessage(ctx context.Context, userID uint, req VoiceMessageRequest) (*message.ChatMessage, error) {
+	if req.SessionID == 0 || len(req.Audio) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("session_id and audio are required"))
+	}
+	if req.Role != model.MessageRoleSelf && req.Role != model.MessageRoleFriend {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid role %q", req.Role))
+	}
+
+	store := voiceMessageStore
+	if err := store.checkSession(ctx, userID, req.SessionID); err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			return nil, connectErr
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	content, err := transcribeVoice(ctx, openaic.GetTranscriber(), req.Filename, req.Audio)
+	if err != nil {
+		if errors.Is(err, errNoSpeech) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		slog.Error("transcribe voice error", "error", err, "filename", req.Filename)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// 音频按聊天语音保存，保存消息失败时没有被引用，到期后清理
+	fileID, err := store.saveAudio(ctx, userID, req)
+	if err != nil {
+		slog.Error("save voice file error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	msgAt := req.MsgAt
+	if msgAt.IsZero() {
+		msgAt = time.Now()
+	}
+	dbMessage := model.ChatMessage{
+		UserID:    userID,
+		SessionID: req.SessionID,
+		Role:      req.Role,
+		MsgType:   model.MessageTypeHistory,
+		Content:   content,
+		Tags:      []string{model.MessageTagVoice},
+		MsgAt:     msgAt,
+	}
+	if err := store.createMessage(ctx, &dbMessage, fileID); err != nil {
+		slog.Error("create voice message error", "error", err)
+		return nil, attachmentError(err)
+	}
+
+	msgevent.Publish(ctx, msgevent.EventCreated, userID, dbMessage)
+
+	protoMsg := dbMessage.ToProto()
+	if err := store.fillAttachments(ctx, userID, []*message.ChatMessage{protoMsg}); err != nil {
+		slog.Error("fill attachments error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return protoMsg, nil
+}
+
+// transcribeVoice 转写语音，去掉首尾空白，没有识别出文字时返回错误
+func transcribeVoice(ctx context.Context, transcriber openaic.Transcriber, filename string, audio []byte) (string, error) {
+	if transcriber == nil {
+		return "", fmt.Errorf("transcriber is not configured")
+	}
+	text, err := transcriber.Transcribe(ctx, filename, bytes.NewReader(audio))
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errNoSpeech
+	}
+	return text, nil
+}
+
+// dbVoiceStore 使用数据库和 OSS 保存语音消息
+type dbVoiceStore struct{}
+
+func (dbVoiceStore) checkSession(ctx context.Context, userID, sessionID uint) error {
+	return checkSessionOwner(db.GetDB().WithContext(ctx), userID, sessionID)
+}
+
+func (dbVoiceStore) saveAudio(ctx context.Context, userID uint, req VoiceMessageRequest) (uint, error) {
+	fileHash, err := file.CalculateFileHash(bytes.NewReader(req.Audio))
+	if err != nil {
+		return 0, err
+	}
+	fileService := file.NewService()
+	// 只复用聊天语音，其他用途的文件过期时间不同
+	existingFile, err := fileService.GetFileByHashAndUsageType(userID, fileHash, model.UsageTypeChatAudio)
+	if err != nil {
+		return 0, err
+	}
+	if existingFile != nil {
+		return existingFile.ID, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(req.Filename))
+	objectKey := fmt.Sprintf("user/%d/%s/%s/%s%s", userID, model.UsageTypeChatAudio,
+		time.Now().Format("2006/01/02"), idgen.Base36(), ext)
+	if err := ossc.Get().UserFileBucket().PutObject(objectKey, bytes.NewReader(req.Audio)); err != nil {
+		return 0, fmt.Errorf("文件上传至OSS失败: %w", err)
+	}
+	userFile, err := fileService.CreateFileRecord(userID, req.Filename, int64(len(req.Audio)), req.ContentType, ext, objectKey, fileHash, model.UsageTypeChatAudio)
+	if err != nil {
+		return 0, err
+	}
+	return userFile.ID, nil
+}
+
+func (dbVoiceStore) createMessage(ctx context.Context, msg *model.ChatMessage, fileID uint) error {
+	return db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(msg).Error; err != nil {
+			return err
+		}
+		return attachment.Attach(tx, msg.UserID, []uint{msg.ID}, []uint{fileID})
+	})
+}
+
+func (dbVoiceStore) fillAttachments(ctx context.Context, userID uint, protoMsgs []*message.ChatMessage) error {
+	return fillAttachments(db.GetDB().WithContext(ctx), userID, protoMsgs)
+}
@@ -0,0 +1,114 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"app_server/model"
+	"app_server/pkg/fn"
+	"app_server/pkg/openaic"
+	"app_server/proto/message"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTranscriber 返回固定文字，并记录收到的音频
+type fakeTranscriber struct {
+	text     string
+	err      error
+	filename string
+	audio    []byte
+}
+
+func (t *fakeTranscriber) Transcribe(_ context.Context, filename string, audio io.Reader) (string, error) {
+	t.filename = filename
+	t.audio, _ = io.ReadAll(audio)
+	return t.text, t.err
+}
+
+func TestTranscribeVoice(t *testing.T) {
+	fake := &fakeTranscriber{text: "  明天上午十点开会 \n"}
+	text, err := transcribeVoice(context.Background(), fake, "voice.m4a", []byte("audio"))
+	assert.NoError(t, err)
+	assert.Equal(t, "明天上午十点开会", text)
+	assert.Equal(t, "voice.m4a", fake.filename)
+	assert.Equal(t, []byte("audio"), fake.audio)
+
+	_, err = transcribeVoice(context.Background(), &fakeTranscriber{text: " "}, "voice.m4a", []byte("audio"))
+	assert.ErrorIs(t, err, errNoSpeech)
+
+	_, err = transcribeVoice(context.Background(), &fakeTranscriber{err: errors.New("timeout")}, "voice.m4a", []byte("audio"))
+	assert.EqualError(t, err, "timeout")
+
+	_, err = transcribeVoice(context.Background(), nil, "voice.m4a", []byte("audio"))
+	assert.Error(t, err)
+}
+
+// fakeVoiceStore 在内存中保存语音消息和附件
+type fakeVoiceStore struct {
+	savedAudio  [][]byte
+	messages    []model.ChatMessage
+	attachments map[uint][]uint // 消息 id -> 文件 id
+}
+
+func (s *fakeVoiceStore) checkSession(context.Context, uint, uint) error { return nil }
+
+func (s *fakeVoiceStore) saveAudio(_ context.Context, _ uint, req VoiceMessageRequest) (uint, error) {
+	s.savedAudio = append(s.savedAudio, req.Audio)
+	return uint(100 + len(s.savedAudio)), nil
+}
+
+func (s *fakeVoiceStore) createMessage(_ context.Context, msg *model.ChatMessage, fileID uint) error {
+	msg.ID = uint(len(s.messages) + 1)
+	s.messages = append(s.messages, *msg)
+	s.attachments[msg.ID] = append(s.attachments[msg.ID], fileID)
+	return nil
+}
+
+func (s *fakeVoiceStore) fillAttachments(_ context.Context, _ uint, protoMsgs []*message.ChatMessage) error {
+	for _, protoMsg := range protoMsgs {
+		for _, fileID := range s.attachments[fn.Atoi[uint](protoMsg.Id)] {
+			protoMsg.Attachments = append(protoMsg.Attachments, &message.MessageAttachment{FileId: fn.Itoa(fileID)})
+		}
+	}
+	return nil
+}
+
+func TestCreateVoiceMessage(t *testing.T) {
+	store := &fakeVoiceStore{attachments: make(map[uint][]uint)}
+	defer func(old voiceStore) { voiceMessageStore = old }(voiceMessageStore)
+	voiceMessageStore = store
+	defer openaic.SetTranscriber(openaic.GetTranscriber())
+	openaic.SetTranscriber(&fakeTranscriber{text: " 晚上一起吃饭吧 "})
+
+	msg, err := CreateVoiceMessage(context.Background(), 1, VoiceMessageRequest{
+		SessionID: 2,
+		Role:      model.MessageRoleFriend,
+		Filename:  "voice.m4a",
+		Audio:     []byte("audio"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "晚上一起吃饭吧", msg.Content)
+	assert.Equal(t, model.MessageRoleFriend, msg.Role)
+	assert.Equal(t, model.MessageTypeHistory, msg.MsgType)
+	assert.Equal(t, []string{model.MessageTagVoice}, msg.Tags)
+	assert.Equal(t, []string{"101"}, fn.Map(msg.Attachments, (*message.MessageAttachment).GetFileId))
+	assert.Len(t, store.messages, 1)
+
+	// 转写失败时不保存音频和消息
+	openaic.SetTranscriber(&fakeTranscriber{text: "  "})
+	_, err = CreateVoiceMessage(context.Background(), 1, VoiceMessageRequest{
+		SessionID: 2,
+		Role:      model.MessageRoleSelf,
+		Filename:  "voice.m4a",
+		Audio:     []byte("silence"),
+	})
+	assert.Error(t, err)
+	assert.Len(t, store.savedAudio, 1)
+	assert.Len(t, store.messages, 1)
+
+	_, err = CreateVoiceMessage(context.Background(), 1, VoiceMessageRequest{SessionID: 2, Role: model.MessageRoleAI, Audio: []byte("audio")})
+	assert.Error(t, err)
+}
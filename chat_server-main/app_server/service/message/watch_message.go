@@ -0,0 +1,78 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app_server/domain/msgevent"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/message"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 没有事件时推送心跳帧的间隔，避免连接被网关断开
+const watchHeartbeatInterval = 30 * time.Second
+
+// WatchChatMessages 订阅当前用户会话中消息的创建、更新和删除事件
+func (s *ChatMessageService) WatchChatMessages(ctx context.Context, connectReq *connect.Request[message.WatchChatMessagesRequest], stream *connect.ServerStream[message.WatchChatMessagesResponse]) error {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionIDs := fn.Map(req.SessionIds, fn.Atoi[uint])
+	for _, sessionID := range sessionIDs {
+		if sessionID == 0 {
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid session_id"))
+		}
+		if err := checkSessionOwner(db.GetDB().WithContext(ctx), userID, sessionID); err != nil {
+			var connectErr *connect.Error
+			if errors.As(err, &connectErr) {
+				return connectErr
+			}
+			return connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	sub := msgevent.Subscribe(userID, sessionIDs)
+	defer msgevent.Unsubscribe(sub)
+
+	// 先推送一帧心跳，客户端据此确认订阅已建立
+	if err := stream.Send(&message.WatchChatMessagesResponse{}); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := stream.Send(&message.WatchChatMessagesResponse{}); err != nil {
+				return err
+			}
+		case event, ok := <-sub.C:
+			if !ok {
+				return connect.NewError(connect.CodeUnavailable, errors.New("subscriber too slow, please reconnect"))
+			}
+			if err := stream.Send(&message.WatchChatMessagesResponse{
+				Events: fn.Map(event.Messages, func(msg model.ChatMessage) *message.ChatMessageEvent {
+					return &message.ChatMessageEvent{
+						Type:       event.Type,
+						SessionId:  fn.Itoa(msg.SessionID),
+						Message:    msg.ToProto(),
+						OccurredAt: timestamppb.New(event.OccurredAt),
+					}
+				}),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
+	"app_server/domain/msgevent"
+	"app_server/domain/summary"
 	"app_server/model"
 	"app_server/pkg/db"
+	"app_server/pkg/fn"
 	"app_server/pkg/idgen"
 	"app_server/pkg/oai"
+	"app_server/pkg/openaic"
 	"app_server/proto/translate"
 	"app_server/service/auth"
+	jobsvc "app_server/service/job"
 
 	connect "connectrpc.com/connect"
 )
@@ -114,11 +120,17 @@ func (s *TranslateService) TranslateFriendMessage(ctx context.Context, req *conn
 		MsgType:   model.MessageTypeTranslate,
 		MsgAt:     time.Now(),
 		Tags:      []string{to},
+
+		PromptKey:     "prompt:translate:builtin",
+		PromptVersion: "builtin",
+		AIModel:       openaic.Model.Chat,
 	}
 	if err := db.GetDB().Create(&consultMsg).Error; err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	msgevent.Publish(ctx, msgevent.EventCreated, consultMsg.UserID, consultMsg)
+
 	return connect.NewResponse(&translate.TranslateFriendMessageResponse{
 		Content: translatedContent.Msg.Content,
 	}), nil
@@ -164,6 +176,14 @@ func (s *TranslateService) TranslateV2(ctx context.Context, req *connect.Request
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("chat_session_id和target_message_id不能为空"))
 	}
 
+	if req.Msg.Async {
+		job, err := jobsvc.Submit(ctx, userID, model.AIJobTypeTranslate, req.Msg)
+		if err != nil {
+			return nil, err
+		}
+		return connect.NewResponse(&translate.TranslateV2Response{JobId: job.Id}), nil
+	}
+
 	// 1. 查询目标消息
 	var targetMessage model.ChatMessage
 	if err := db.GetDB().Model(&model.ChatMessage{}).
@@ -172,57 +192,104 @@ func (s *TranslateService) TranslateV2(ctx context.Context, req *connect.Request
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("消息未找到"))
 	}
 
-	// 2. 查询chat_session获取friend_profile_id
-	var chatSession model.ChatSession
-	if err := db.GetDB().Model(&model.ChatSession{}).
-		Where("id = ? AND user_id = ?", chatSessionID, userID).
-		First(&chatSession).Error; err != nil {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("会话未找到"))
+	// 2. 查询会话和双方的 profile
+	env, err := loadTranslateEnv(userID, fn.Atoi[uint](chatSessionID))
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. 根据消息角色确定prompt key
-	var promptKey string
-	switch targetMessage.Role {
-	case model.MessageRoleSelf, model.MessageRoleUser:
-		promptKey = "prompt:translate:to_friend"
-	case model.MessageRoleFriend:
-		promptKey = "prompt:translate:to_user"
-	default:
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("不支持的消息角色: %s", targetMessage.Role))
+	promptKey, err := promptKeyFor(targetMessage.Role)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
 	// 4. 从config表加载prompt模板
-	var config model.Config
-	if err := db.GetDB().Model(&model.Config{}).
-		Where("k = ?", promptKey).
-		First(&config).Error; err != nil {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("未找到配置"))
+	config, err := loadPrompt(promptKey)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	consultMsg, err := translateMessage(ctx, env, targetMessage, promptKey, config)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
-	// 5. 查询user profile和friend profile
+	return connect.NewResponse(&translate.TranslateV2Response{
+		NewMessageId: fmt.Sprintf("%d", consultMsg.ID),
+		Content:      consultMsg.Content,
+	}), nil
+}
+
+// translateEnv 同一会话中翻译消息共用的用户和好友资料
+type translateEnv struct {
+	userID        uint
+	userProfile   model.Profile
+	friendProfile model.Profile
+}
+
+// loadTranslateEnv 查询会话和双方的 profile，profile 不存在时使用空资料
+func loadTranslateEnv(userID, sessionID uint) (*translateEnv, error) {
+	// 查询chat_session获取friend_profile_id
+	var chatSession model.ChatSession
+	if err := db.GetDB().Model(&model.ChatSession{}).
+		Where("id = ? AND user_id = ?", sessionID, userID).
+		First(&chatSession).Error; err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("会话未找到"))
+	}
+
+	// 查询user profile和friend profile
 	var user model.User
 	if err := db.GetDB().Model(&model.User{}).
 		Where("id = ?", userID).
 		First(&user).Error; err != nil {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("用户未找到"))
 	}
-	var userProfile model.Profile
+	env := &translateEnv{userID: userID}
 	if err := db.GetDB().Model(&model.Profile{}).
 		Where("user_id = ? AND id = ?", userID, user.ProfileID).
-		First(&userProfile).Error; err != nil {
+		First(&env.userProfile).Error; err != nil {
 		slog.Warn("未找到用户Profile", "userID", userID, "error", err)
 	}
 
-	var friendProfile model.Profile
 	if chatSession.ProfileID > 0 {
 		if err := db.GetDB().Model(&model.Profile{}).
 			Where("id = ?", chatSession.ProfileID).
-			First(&friendProfile).Error; err != nil {
+			First(&env.friendProfile).Error; err != nil {
 			slog.Warn("未找到好友Profile", "profileID", chatSession.ProfileID, "error", err)
 		}
 	}
+	return env, nil
+}
+
+// promptKeyFor 根据消息角色确定翻译方向对应的 prompt key
+func promptKeyFor(role string) (string, error) {
+	switch role {
+	case model.MessageRoleSelf, model.MessageRoleUser:
+		return "prompt:translate:to_friend", nil
+	case model.MessageRoleFriend:
+		return "prompt:translate:to_user", nil
+	default:
+		return "", fmt.Errorf("不支持的消息角色: %s", role)
+	}
+}
+
+// loadPrompt 从config表加载prompt模板
+func loadPrompt(promptKey string) (model.Config, error) {
+	var config model.Config
+	if err := db.GetDB().Model(&model.Config{}).
+		Where("k = ?", promptKey).
+		First(&config).Error; err != nil {
+		return config, fmt.Errorf("未找到配置")
+	}
+	return config, nil
+}
 
-	// 6. 查询前后24小时的对话历史
+// translateMessage 以前后24小时的对话为上下文翻译消息，翻译结果保存为目标消息的 TRANSLATE 子消息
+func translateMessage(ctx context.Context, env *translateEnv, targetMessage model.ChatMessage, promptKey string, config model.Config) (model.ChatMessage, error) {
+	userID := env.userID
+
+	// 查询前后24小时的对话历史
 	var chatMessages []model.ChatMessage
 	if err := db.GetDB().Model(&model.ChatMessage{}).
 		Where("session_id = ? AND user_id = ? AND msg_type = ? AND id BETWEEN ? AND ?",
@@ -231,26 +298,41 @@ func (s *TranslateService) TranslateV2(ctx context.Context, req *connect.Request
 			idgen.FromTime(targetMessage.CreatedAt.Add(24*time.Hour))).
 		Order("id ASC").
 		Find(&chatMessages).Error; err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return model.ChatMessage{}, err
 	}
 
-	// 7. 构建聊天上下文
+	// 构建聊天上下文，超出模型预算时以目标消息为中心保留前后最近的消息，前面被去掉的部分用会话摘要代替
 	var chatContext strings.Builder
+	if maxTokens := summary.MaxTokens(""); summary.EstimateMessagesTokens(chatMessages) > maxTokens {
+		var older []model.ChatMessage
+		if center := slices.IndexFunc(chatMessages, func(msg model.ChatMessage) bool {
+			return msg.ID == targetMessage.ID
+		}); center >= 0 {
+			older, chatMessages = summary.SplitAround(chatMessages, center, maxTokens/2)
+		} else {
+			older, chatMessages = summary.SplitRecent(chatMessages, maxTokens/2)
+		}
+		if len(older) > 0 {
+			if chatSummary := summary.Get(ctx, userID, targetMessage.SessionID); chatSummary != nil {
+				chatContext.WriteString("此前的聊天摘要：" + chatSummary.Content + "\n")
+			}
+		}
+	}
 	for _, msg := range chatMessages {
 		chatContext.WriteString(msg.HistoryCnString() + "\n")
 	}
 
-	// 8. 替换prompt模板中的变量
+	// 替换prompt模板中的变量
 	prompt := config.Value
 
 	// 构建user_profile和friend_profile字符串
-	userProfileStr := buildProfileString(&userProfile, "用户")
+	userProfileStr := buildProfileString(&env.userProfile, "用户")
 	// 动态获取对方名称，优先使用 profile 名称，否则使用默认值"对方"
 	friendName := "对方"
-	if friendProfile.Name != "" {
-		friendName = friendProfile.Name
+	if env.friendProfile.Name != "" {
+		friendName = env.friendProfile.Name
 	}
-	friendProfileStr := buildProfileString(&friendProfile, friendName)
+	friendProfileStr := buildProfileString(&env.friendProfile, friendName)
 
 	// 替换模板变量
 	prompt = strings.ReplaceAll(prompt, "{{user_profile}}", userProfileStr)
@@ -258,15 +340,15 @@ func (s *TranslateService) TranslateV2(ctx context.Context, req *connect.Request
 	prompt = strings.ReplaceAll(prompt, "{{chat_context}}", chatContext.String())
 	prompt = strings.ReplaceAll(prompt, "{{src_message}}", targetMessage.HistoryCnString())
 
-	// 9. 调用AI API进行翻译
+	// 调用AI API进行翻译
 	translatedContent, err := oai.Get().CreateChatCompletionSimple(ctx, prompt)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return model.ChatMessage{}, err
 	}
 
 	slog.Info("TranslateV2 completed", "original", targetMessage.Content, "translated", translatedContent)
 
-	// 11. 创建新的咨询消息
+	// 创建新的咨询消息
 	consultMsg := model.ChatMessage{
 		UserID:    targetMessage.UserID,
 		SessionID: targetMessage.SessionID,
@@ -275,13 +357,15 @@ func (s *TranslateService) TranslateV2(ctx context.Context, req *connect.Request
 		Role:      model.MessageRoleAI,
 		MsgType:   model.MessageTypeTranslate,
 		MsgAt:     time.Now(),
+
+		PromptKey:     promptKey,
+		PromptVersion: config.Version,
+		AIModel:       openaic.Model.Chat,
 	}
 	if err := db.GetDB().Create(&consultMsg).Error; err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return model.ChatMessage{}, err
 	}
 
-	return connect.NewResponse(&translate.TranslateV2Response{
-		NewMessageId: fmt.Sprintf("%d", consultMsg.ID),
-		Content:      translatedContent,
-	}), nil
+	msgevent.Publish(ctx, msgevent.EventCreated, consultMsg.UserID, consultMsg)
+	return consultMsg, nil
 }
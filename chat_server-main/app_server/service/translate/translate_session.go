@@ -0,0 +1,181 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	"app_server/proto/translate"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+)
+
+const (
+	translateSessionLimit       = 100 // 单次批量翻译最多的消息数
+	translateSessionConcurrency = 4   // 同时翻译的消息数
+)
+
+// translateResult 一条消息的翻译结果
+type translateResult struct {
+	Target model.ChatMessage
+	Reply  model.ChatMessage
+	Err    error
+}
+
+// TranslateSession 批量翻译会话中还没有翻译的聊天记录，每处理完一条消息推送一次进度
+func (s *TranslateService) TranslateSession(ctx context.Context, connectReq *connect.Request[translate.TranslateSessionRequest], stream *connect.ServerStream[translate.TranslateSessionResponse]) error {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	sessionID := fn.Atoi[uint](req.ChatSessionId)
+	if sessionID == 0 {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("chat_session_id不能为空"))
+	}
+	roles := lo.Uniq(req.Roles)
+	if len(roles) == 0 {
+		roles = []string{model.MessageRoleFriend}
+	}
+	promptKeys := make(map[string]string, len(roles)) // role -> prompt key
+	for _, role := range roles {
+		promptKey, err := promptKeyFor(role)
+		if err != nil {
+			return connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		promptKeys[role] = promptKey
+	}
+	limit := int(req.Limit)
+	if limit <= 0 || limit > translateSessionLimit {
+		limit = translateSessionLimit
+	}
+
+	env, err := loadTranslateEnv(userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// 每种翻译方向的 prompt 只加载一次，加载失败时对应角色的消息逐条报告失败
+	prompts := make(map[string]model.Config)
+	promptErrs := make(map[string]error)
+	for _, promptKey := range lo.Uniq(lo.Values(promptKeys)) {
+		if prompts[promptKey], err = loadPrompt(promptKey); err != nil {
+			promptErrs[promptKey] = err
+		}
+	}
+
+	// 查找范围内没有翻译的聊天记录
+	query := db.GetDB().WithContext(ctx).Model(&model.ChatMessage{}).
+		Where("user_id = ? AND session_id = ? AND msg_type = ? AND role IN ?", userID, sessionID, model.MessageTypeHistory, roles).
+		Where("NOT EXISTS (SELECT 1 FROM chat_message t WHERE t.parent_id = chat_message.id AND t.msg_type = ? AND t.deleted_at IS NULL)", model.MessageTypeTranslate)
+	if req.StartTime != nil && req.StartTime.IsValid() {
+		query = query.Where("msg_at >= ?", req.StartTime.AsTime())
+	}
+	if req.EndTime != nil && req.EndTime.IsValid() {
+		query = query.Where("msg_at <= ?", req.EndTime.AsTime())
+	}
+	var targets []model.ChatMessage
+	if err := query.Order("msg_at ASC, id ASC").Limit(limit).Find(&targets).Error; err != nil {
+		slog.Error("find untranslated messages error", "error", err)
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	total := int32(len(targets))
+	if err := stream.Send(&translate.TranslateSessionResponse{Total: total}); err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// 客户端断开后停止提交新的翻译
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var done int32
+	var sendErr error
+	results := translateConcurrently(ctx, targets, translateSessionConcurrency, func(ctx context.Context, target model.ChatMessage) (model.ChatMessage, error) {
+		promptKey := promptKeys[target.Role]
+		if err := promptErrs[promptKey]; err != nil {
+			return model.ChatMessage{}, err
+		}
+		return translateMessage(ctx, env, target, promptKey, prompts[promptKey])
+	})
+	for result := range results {
+		done++
+		if sendErr != nil {
+			continue
+		}
+		if sendErr = stream.Send(sessionProgress(result, total, done)); sendErr != nil {
+			cancel()
+		}
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	slog.Info("TranslateSession completed", "userID", userID, "sessionID", sessionID, "total", total)
+	return nil
+}
+
+// translateConcurrently 最多同时翻译 concurrency 条消息，结果按完成的先后返回，全部完成后关闭 channel
+// ctx 取消后不再开始新的翻译
+func translateConcurrently(ctx context.Context, targets []model.ChatMessage, concurrency int,
+	translateFn func(context.Context, model.ChatMessage) (model.ChatMessage, error)) <-chan translateResult {
+	results := make(chan translateResult)
+	go func() {
+		defer close(results)
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+		for _, target := range targets {
+			if !acquire(ctx, sem) {
+				break
+			}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				reply, err := translateFn(ctx, target)
+				results <- translateResult{Target: target, Reply: reply, Err: err}
+			}()
+		}
+		wg.Wait()
+	}()
+	return results
+}
+
+// acquire 占用一个并发名额，ctx 取消时返回 false
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sessionProgress 生成一条消息处理完成后的进度帧
+func sessionProgress(result translateResult, total, done int32) *translate.TranslateSessionResponse {
+	resp := &translate.TranslateSessionResponse{
+		Total:     total,
+		Done:      done,
+		MessageId: fn.Itoa(result.Target.ID),
+	}
+	if result.Err != nil {
+		slog.Error("translate session message error", "error", result.Err, "messageID", result.Target.ID)
+		resp.Error = result.Err.Error()
+		return resp
+	}
+	resp.NewMessageId = fn.Itoa(result.Reply.ID)
+	resp.Content = result.Reply.Content
+	return resp
+}
@@ -0,0 +1,92 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"app_server/model"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTranslateConcurrently(t *testing.T) {
+	targets := make([]model.ChatMessage, 10)
+	for i := range targets {
+		targets[i] = model.ChatMessage{Model: gorm.Model{ID: uint(i + 1)}, Content: "原文"}
+	}
+
+	var running, maxRunning atomic.Int32
+	results := translateConcurrently(context.Background(), targets, 3, func(_ context.Context, target model.ChatMessage) (model.ChatMessage, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if target.ID == 4 {
+			return model.ChatMessage{}, errors.New("AI 服务不可用")
+		}
+		return model.ChatMessage{Model: gorm.Model{ID: target.ID + 100}, Content: "译文"}, nil
+	})
+
+	seen := make(map[uint]translateResult)
+	for result := range results {
+		seen[result.Target.ID] = result
+	}
+	assert.Len(t, seen, 10)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.EqualError(t, seen[4].Err, "AI 服务不可用")
+	assert.NoError(t, seen[5].Err)
+	assert.Equal(t, uint(105), seen[5].Reply.ID)
+}
+
+func TestTranslateConcurrentlyCanceled(t *testing.T) {
+	targets := make([]model.ChatMessage, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var count int
+	for range translateConcurrently(ctx, targets, 2, func(ctx context.Context, _ model.ChatMessage) (model.ChatMessage, error) {
+		return model.ChatMessage{}, ctx.Err()
+	}) {
+		count++
+	}
+	assert.Zero(t, count)
+}
+
+func TestSessionProgress(t *testing.T) {
+	target := model.ChatMessage{Model: gorm.Model{ID: 7}}
+
+	resp := sessionProgress(translateResult{Target: target, Reply: model.ChatMessage{Model: gorm.Model{ID: 8}, Content: "译文"}}, 3, 1)
+	assert.Equal(t, int32(3), resp.Total)
+	assert.Equal(t, int32(1), resp.Done)
+	assert.Equal(t, "7", resp.MessageId)
+	assert.Equal(t, "8", resp.NewMessageId)
+	assert.Equal(t, "译文", resp.Content)
+	assert.Empty(t, resp.Error)
+
+	resp = sessionProgress(translateResult{Target: target, Err: errors.New("超时")}, 3, 2)
+	assert.Equal(t, "7", resp.MessageId)
+	assert.Empty(t, resp.NewMessageId)
+	assert.Equal(t, "超时", resp.Error)
+}
+
+func TestPromptKeyFor(t *testing.T) {
+	key, err := promptKeyFor(model.MessageRoleFriend)
+	assert.NoError(t, err)
+	assert.Equal(t, "prompt:translate:to_user", key)
+
+	key, err = promptKeyFor(model.MessageRoleSelf)
+	assert.NoError(t, err)
+	assert.Equal(t, "prompt:translate:to_friend", key)
+
+	_, err = promptKeyFor(model.MessageRoleAI)
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package trash
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trashCursor 回收站分页游标，按 (deleted_at, id) 倒序翻页
+type trashCursor struct {
+	DeletedAt time.Time
+	ID        uint
+}
+
+func (c trashCursor) pageToken() string {
+	return fmt.Sprintf("%d_%d", c.DeletedAt.UnixMicro(), c.ID)
+}
+
+func decodePageToken(token string) (*trashCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	micro, id, ok := strings.Cut(token, "_")
+	if !ok {
+		return nil, fmt.Errorf("invalid page_token")
+	}
+	deletedAt, err := strconv.ParseInt(micro, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page_token")
+	}
+	lastID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page_token")
+	}
+	return &trashCursor{DeletedAt: time.UnixMicro(deletedAt), ID: uint(lastID)}, nil
+}
+
+// paginateTrash 合并各类型的条目，按删除时间倒序取一页，还有更多时返回下一页游标
+func paginateTrash(items []trashEntry, pageSize int) ([]trashEntry, *trashCursor) {
+	slices.SortStableFunc(items, func(a, b trashEntry) int {
+		if c := b.DeletedAt.Compare(a.DeletedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(items) <= pageSize {
+		return items, nil
+	}
+	items = items[:pageSize]
+	last := items[len(items)-1]
+	return items, &trashCursor{DeletedAt: last.DeletedAt, ID: last.ID}
+}
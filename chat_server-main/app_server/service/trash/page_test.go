@@ -0,0 +1,51 @@
+package trash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageToken(t *testing.T) {
+	cursor := trashCursor{DeletedAt: time.UnixMicro(1700000000123456), ID: 42}
+	decoded, err := decodePageToken(cursor.pageToken())
+	assert.NoError(t, err)
+	assert.True(t, cursor.DeletedAt.Equal(decoded.DeletedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	decoded, err = decodePageToken("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	for _, token := range []string{"abc", "1_x", "x_1"} {
+		_, err := decodePageToken(token)
+		assert.Error(t, err, token)
+	}
+}
+
+func TestPaginateTrash(t *testing.T) {
+	now := time.Now()
+	items := []trashEntry{
+		{ID: 1, Type: "MESSAGE", DeletedAt: now.Add(-time.Hour)},
+		{ID: 5, Type: "SESSION", DeletedAt: now},
+		{ID: 3, Type: "PROFILE", DeletedAt: now},
+		{ID: 2, Type: "MESSAGE", DeletedAt: now.Add(-2 * time.Hour)},
+	}
+
+	page, next := paginateTrash(items, 3)
+	assert.Equal(t, []uint{5, 3, 1}, []uint{page[0].ID, page[1].ID, page[2].ID})
+	assert.NotNil(t, next)
+	assert.Equal(t, uint(1), next.ID)
+	assert.True(t, next.DeletedAt.Equal(now.Add(-time.Hour)))
+
+	page, next = paginateTrash(page, 3)
+	assert.Len(t, page, 3)
+	assert.Nil(t, next)
+}
+
+func TestTruncateTitle(t *testing.T) {
+	assert.Equal(t, "你好", truncateTitle("  你好\n"))
+	long := truncateTitle(string(make([]rune, 60)))
+	assert.Equal(t, titleMaxRunes+1, len([]rune(long)))
+}
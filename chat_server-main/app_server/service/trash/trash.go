@@ -0,0 +1,359 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"app_server/domain/summary"
+	"app_server/domain/trash"
+	"app_server/model"
+	"app_server/pkg/db"
+	"app_server/pkg/fn"
+	trashpb "app_server/proto/trash"
+	"app_server/service/auth"
+
+	connect "connectrpc.com/connect"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	titleMaxRunes   = 50 // 消息在回收站中展示的内容长度
+)
+
+type TrashService struct{}
+
+// ListTrash 按删除时间倒序列出回收站中的消息、会话和 Profile
+func (s *TrashService) ListTrash(ctx context.Context, connectReq *connect.Request[trashpb.ListTrashRequest]) (*connect.Response[trashpb.ListTrashResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	itemType := strings.ToUpper(req.Type)
+	if itemType != "" && !isItemType(itemType) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid type: %s", req.Type))
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+	cursor, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	database := db.GetDB().WithContext(ctx)
+	// 每种类型各取一页，合并后再截取
+	var items []trashEntry
+	for _, t := range []string{trash.ItemTypeMessage, trash.ItemTypeSession, trash.ItemTypeProfile} {
+		if itemType != "" && itemType != t {
+			continue
+		}
+		entries, err := listTrashEntries(database, userID, t, cursor, pageSize+1)
+		if err != nil {
+			slog.Error("list trash error", "error", err, "type", t)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		items = append(items, entries...)
+	}
+	items, next := paginateTrash(items, pageSize)
+
+	if err := fillSessionMessageCounts(database, items); err != nil {
+		slog.Error("count trash session messages error", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	retention := trash.Retention()
+	resp := &trashpb.ListTrashResponse{
+		Items: make([]*trashpb.TrashItem, 0, len(items)),
+	}
+	if next != nil {
+		resp.NextPageToken = next.pageToken()
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, &trashpb.TrashItem{
+			Id:           fn.Itoa(item.ID),
+			Type:         item.Type,
+			Title:        item.Title,
+			SessionId:    lo.Ternary(item.SessionID == 0, "", fn.Itoa(item.SessionID)),
+			MessageCount: int32(item.MessageCount),
+			DeletedAt:    timestamppb.New(item.DeletedAt),
+			ExpiresAt:    timestamppb.New(item.DeletedAt.Add(retention)),
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// RestoreItems 恢复回收站中的条目，会话会连同一起删除的消息一起恢复
+func (s *TrashService) RestoreItems(ctx context.Context, connectReq *connect.Request[trashpb.RestoreItemsRequest]) (*connect.Response[trashpb.RestoreItemsResponse], error) {
+	userID := auth.GetUserID(ctx)
+	refs, err := groupItemRefs(connectReq.Msg.Items)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var restored int64
+	err = db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先恢复会话，这样同一请求中属于这些会话的消息也能恢复
+		for _, id := range refs[trash.ItemTypeSession] {
+			var session model.ChatSession
+			if err := tx.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+				First(&session).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&model.ChatMessage{}).
+				Where("session_id = ? AND user_id = ? AND deleted_at = ?", id, userID, session.DeletedAt.Time).
+				Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&session).Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if err := summary.InvalidateSessions(tx, id); err != nil {
+				return err
+			}
+			restored++
+		}
+
+		if ids := refs[trash.ItemTypeMessage]; len(ids) > 0 {
+			var messages []model.ChatMessage
+			if err := tx.Unscoped().Where("id IN ? AND user_id = ? AND deleted_at IS NOT NULL", ids, userID).
+				Find(&messages).Error; err != nil {
+				return err
+			}
+			if len(messages) != len(ids) {
+				return gorm.ErrRecordNotFound
+			}
+			// 会话已删除的消息需要先恢复会话
+			sessionIDs := fn.Map(messages, func(msg model.ChatMessage) uint { return msg.SessionID })
+			var liveSessions int64
+			if err := tx.Model(&model.ChatSession{}).
+				Where("id IN ? AND user_id = ?", sessionIDs, userID).
+				Distinct("id").Count(&liveSessions).Error; err != nil {
+				return err
+			}
+			if int(liveSessions) != len(slices.Compact(slices.Sorted(slices.Values(sessionIDs)))) {
+				return errSessionDeleted
+			}
+			if err := tx.Unscoped().Model(&model.ChatMessage{}).
+				Where("id IN ? AND user_id = ?", ids, userID).
+				Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if err := summary.InvalidateByMessages(tx, messages); err != nil {
+				return err
+			}
+			restored += int64(len(messages))
+		}
+
+		if ids := refs[trash.ItemTypeProfile]; len(ids) > 0 {
+			result := tx.Unscoped().Model(&model.Profile{}).
+				Where("id IN ? AND user_id = ? AND deleted_at IS NOT NULL", ids, userID).
+				Update("deleted_at", nil)
+			if result.Error != nil {
+				return result.Error
+			}
+			if int(result.RowsAffected) != len(ids) {
+				return gorm.ErrRecordNotFound
+			}
+			restored += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, trashError("restore trash items error", err)
+	}
+
+	return connect.NewResponse(&trashpb.RestoreItemsResponse{
+		RestoredCount: int32(restored),
+	}), nil
+}
+
+// PurgeItems 彻底删除回收站中的条目，无法恢复
+func (s *TrashService) PurgeItems(ctx context.Context, connectReq *connect.Request[trashpb.PurgeItemsRequest]) (*connect.Response[trashpb.PurgeItemsResponse], error) {
+	req := connectReq.Msg
+	userID := auth.GetUserID(ctx)
+
+	refs, err := groupItemRefs(req.Items)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	purgers := []struct {
+		itemType string
+		table    any
+		purge    func(tx *gorm.DB, ids []uint) error
+	}{
+		{trash.ItemTypeSession, &model.ChatSession{}, trash.PurgeSessions},
+		{trash.ItemTypeMessage, &model.ChatMessage{}, trash.PurgeMessages},
+		{trash.ItemTypeProfile, &model.Profile{}, trash.PurgeProfiles},
+	}
+
+	var purged int
+	err = db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, p := range purgers {
+			// 只能删除自己回收站中的条目
+			query := tx.Unscoped().Model(p.table).Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+			if !req.All {
+				if len(refs[p.itemType]) == 0 {
+					continue
+				}
+				query = query.Where("id IN ?", refs[p.itemType])
+			}
+			var ids []uint
+			if err := query.Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if !req.All && len(ids) != len(refs[p.itemType]) {
+				return gorm.ErrRecordNotFound
+			}
+			if err := p.purge(tx, ids); err != nil {
+				return err
+			}
+			purged += len(ids)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, trashError("purge trash items error", err)
+	}
+
+	return connect.NewResponse(&trashpb.PurgeItemsResponse{
+		PurgedCount: int32(purged),
+	}), nil
+}
+
+// listTrashEntries 查询一种类型在游标之后的已删除条目
+func listTrashEntries(tx *gorm.DB, userID uint, itemType string, cursor *trashCursor, limit int) ([]trashEntry, error) {
+	var entries []trashEntry
+	var query *gorm.DB
+	switch itemType {
+	case trash.ItemTypeMessage:
+		// 随会话一起删除的消息在会话条目中展示
+		query = tx.Unscoped().Table("chat_message AS m").
+			Select("m.id, m.session_id, m.content AS title, m.deleted_at").
+			Joins("JOIN chat_session AS s ON s.id = m.session_id AND s.deleted_at IS NULL").
+			Where("m.user_id = ? AND m.deleted_at IS NOT NULL", userID)
+	case trash.ItemTypeSession:
+		query = tx.Unscoped().Table("chat_session AS m").
+			Select("m.id, m.name AS title, m.deleted_at").
+			Where("m.user_id = ? AND m.deleted_at IS NOT NULL", userID)
+	case trash.ItemTypeProfile:
+		query = tx.Unscoped().Table("profile AS m").
+			Select("m.id, m.name AS title, m.deleted_at").
+			Where("m.user_id = ? AND m.deleted_at IS NOT NULL", userID)
+	}
+	if cursor != nil {
+		query = query.Where("(m.deleted_at < ? OR (m.deleted_at = ? AND m.id < ?))", cursor.DeletedAt, cursor.DeletedAt, cursor.ID)
+	}
+	if err := query.Order("m.deleted_at DESC, m.id DESC").Limit(limit).Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Type = itemType
+		entries[i].Title = truncateTitle(entries[i].Title)
+	}
+	return entries, nil
+}
+
+// fillSessionMessageCounts 统计会话条目中随会话一起删除的消息数量
+func fillSessionMessageCounts(tx *gorm.DB, items []trashEntry) error {
+	var sessionIDs []uint
+	for _, item := range items {
+		if item.Type == trash.ItemTypeSession {
+			sessionIDs = append(sessionIDs, item.ID)
+		}
+	}
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	var counts []struct {
+		SessionID uint
+		Count     int
+	}
+	if err := tx.Unscoped().Table("chat_message AS m").
+		Select("m.session_id, COUNT(*) AS count").
+		Joins("JOIN chat_session AS s ON s.id = m.session_id AND m.deleted_at = s.deleted_at").
+		Where("m.session_id IN ?", sessionIDs).
+		Group("m.session_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+	countMap := make(map[uint]int, len(counts))
+	for _, c := range counts {
+		countMap[c.SessionID] = c.Count
+	}
+	for i := range items {
+		if items[i].Type == trash.ItemTypeSession {
+			items[i].MessageCount = countMap[items[i].ID]
+		}
+	}
+	return nil
+}
+
+// groupItemRefs 按类型分组要操作的条目 ID
+func groupItemRefs(items []*trashpb.TrashItemRef) (map[string][]uint, error) {
+	refs := make(map[string][]uint)
+	for _, item := range items {
+		itemType := strings.ToUpper(item.GetType())
+		if !isItemType(itemType) {
+			return nil, fmt.Errorf("invalid type: %s", item.GetType())
+		}
+		id := fn.Atoi[uint](item.GetId())
+		if id == 0 {
+			return nil, fmt.Errorf("invalid id: %s", item.GetId())
+		}
+		if !slices.Contains(refs[itemType], id) {
+			refs[itemType] = append(refs[itemType], id)
+		}
+	}
+	return refs, nil
+}
+
+func isItemType(itemType string) bool {
+	switch itemType {
+	case trash.ItemTypeMessage, trash.ItemTypeSession, trash.ItemTypeProfile:
+		return true
+	}
+	return false
+}
+
+func truncateTitle(title string) string {
+	runes := []rune(strings.TrimSpace(title))
+	if len(runes) <= titleMaxRunes {
+		return string(runes)
+	}
+	return string(runes[:titleMaxRunes]) + "…"
+}
+
+var errSessionDeleted = errors.New("消息所属的会话已删除，请先恢复会话")
+
+func trashError(msg string, err error) error {
+	switch {
+	case errors.Is(err, errSessionDeleted):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return connect.NewError(connect.CodeNotFound, fmt.Errorf("回收站中未找到条目"))
+	}
+	slog.Error(msg, "error", err)
+	return connect.NewError(connect.CodeInternal, err)
+}
+
+// trashEntry 回收站列表中的一条记录
+type trashEntry struct {
+	ID           uint
+	Type         string
+	Title        string
+	SessionID    uint
+	MessageCount int
+	DeletedAt    time.Time
+}